@@ -4,14 +4,28 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
 	"github.com/hideA88/game-server-watchdog/internal/bot"
+	"github.com/hideA88/game-server-watchdog/internal/eventwatch"
+	"github.com/hideA88/game-server-watchdog/internal/health"
+	"github.com/hideA88/game-server-watchdog/internal/history"
+	"github.com/hideA88/game-server-watchdog/internal/httpserver"
+	"github.com/hideA88/game-server-watchdog/internal/idle"
+	"github.com/hideA88/game-server-watchdog/internal/metrics"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/internal/scheduler"
+	"github.com/hideA88/game-server-watchdog/internal/supervisor"
+	"github.com/hideA88/game-server-watchdog/internal/watcher"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
 	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
@@ -48,7 +62,8 @@ func main() { //nolint:funlen // DI/初期化処理のため長い関数を許
 	}
 
 	// contextにloggerを設定
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	ctx = logging.WithContext(ctx, logger)
 
 	logger.Info(ctx, "Starting Game Server Watchdog",
@@ -75,8 +90,43 @@ func main() { //nolint:funlen // DI/初期化処理のため長い関数を許
 		logger.Info(ctx, "No Docker Compose project name configured")
 	}
 
+	// 統計情報のキャッシュ（有効な場合はストリームで受信した最新の統計情報を返す）
+	var statsSource docker.ComposeService = compose
+	if cfg.StatsCache {
+		cache := docker.NewStatsCache(compose, compose.StatsStreamer(), cfg.DockerComposePath, 0, 0)
+		go cache.Run(ctx)
+		statsSource = cache
+	}
+
+	// メトリクス（HTTPサーバーのアドレスが設定されている場合のみ）
+	// Docker APIの失敗を記録するため、以降はcomposeの代わりにcomposeServiceを使用する
+	var m *metrics.Metrics
+	if cfg.HTTPAddr != "" {
+		m = metrics.New()
+	}
+	composeService := m.InstrumentCompose(statsSource)
+
+	// サービスごとの停止方法（停止前のRCONコマンドはラベルと環境変数から接続先を探して送信する）
+	compose.SetStopPolicy(cfg, rcon.NewRunner(composeService, cfg.DockerComposePath, nil))
+
+	// サービス操作ロック（Discordからの操作・自動再起動・スケジュールで共有）
+	locks := oplock.New()
+
+	// 監査ログ（パスが設定されている場合のみ）
+	var auditLog audit.Store
+	if cfg.AuditLogPath != "" {
+		auditLog = audit.NewFileLog(cfg.AuditLogPath)
+	} else {
+		logger.Info(ctx, "No audit log configured, service operations will not be recorded")
+	}
+
+	// リソース使用率の履歴（常にメモリ上に記録し、パスが設定されている場合はファイルにも保存）
+	metricsHistory := history.NewStore()
+	recorder := history.NewRecorder(metricsHistory, monitor, composeService, cfg.DockerComposePath, cfg.HistoryPath, 0)
+	go recorder.Run(ctx)
+
 	// ボットの初期化
-	discordBot, err := bot.New(ctx, cfg, monitor, compose)
+	discordBot, err := bot.New(ctx, cfg, monitor, composeService, locks, auditLog, metricsHistory)
 	if err != nil {
 		logger.Error(ctx, "Error creating bot", logging.ErrorField(err))
 		os.Exit(1)
@@ -96,8 +146,79 @@ func main() { //nolint:funlen // DI/初期化処理のため長い関数を許
 		}
 	}()
 
+	// バックグラウンド監視の起動（アラートチャンネルが設定されている場合のみ）
+	var notifier notify.Notifier
+	var poller health.PollTracker
+	if cfg.AlertChannelID != "" {
+		notifier = discordBot.Notifier(cfg.AlertChannelID)
+		w := watcher.New(monitor, composeService, notifier, cfg, cfg.DockerComposePath, cfg.HealthCheckInterval)
+		poller = w
+
+		// Dockerのイベントを購読する場合、コンテナの状態変化はイベントで即座に通知する
+		if cfg.DockerEvents {
+			w.SkipContainerChanges()
+			events := eventwatch.New(compose, composeService, notifier, cfg.DockerComposePath, 0)
+			go events.Run(ctx)
+		}
+		go w.Run(ctx)
+	} else {
+		logger.Info(ctx, "No alert channel configured, background health check disabled")
+	}
+
+	// 自動再起動の起動（有効なサービスがある場合のみ）
+	if cfg.AutoRestartConfigured() {
+		policy := supervisor.Policy{
+			MaxAttempts: cfg.AutoRestartMaxAttempts,
+			Window:      cfg.AutoRestartWindow,
+			Retry: docker.RetryConfig{
+				BaseDelay: cfg.AutoRestartBackoff,
+				MaxDelay:  docker.MaxRetryDelay,
+				Backoff:   docker.ExponentialBackoff,
+			},
+		}
+		s := supervisor.New(composeService, notifier, auditLog, locks, cfg, policy, cfg.DockerComposePath, 0)
+		go s.Run(ctx)
+	}
+
+	// プレイヤーがいない場合の自動停止の起動（有効なサービスがある場合のみ）
+	if cfg.IdleShutdownConfigured() {
+		stopper := idle.New(composeService, nil, notifier, auditLog, locks, cfg, cfg.DockerComposePath, 0)
+		go stopper.Run(ctx)
+	}
+
+	// スケジュールの起動（スケジュールが設定されている場合のみ）
+	if len(cfg.ScheduledServices()) > 0 {
+		broadcaster := rcon.NewBroadcaster(rcon.NewRunner(composeService, cfg.DockerComposePath, nil), cfg)
+		sched := scheduler.New(composeService, notifier, auditLog, locks, cfg, broadcaster, cfg.DockerComposePath, 0)
+		go sched.Run(ctx)
+	}
+
+	// メトリクスとヘルスチェックを公開するHTTPサーバーの起動（アドレスが設定されている場合のみ）
+	if cfg.HTTPAddr != "" {
+		discordBot.SetCommandObserver(m)
+		collector := metrics.NewResourceCollector(ctx, monitor, composeService, cfg.DockerComposePath)
+		if err := m.Register(collector); err != nil {
+			logger.Error(ctx, "Error registering resource metrics", logging.ErrorField(err))
+			os.Exit(1)
+		}
+
+		// 監視が3回続けて成功しなかった場合は異常とみなす
+		checker := health.New(discordBot, composeService, poller, 3*cfg.HealthCheckInterval)
+
+		server := httpserver.New(cfg.HTTPAddr)
+		server.Handle("/metrics", m.Handler())
+		server.Handle("/healthz", http.HandlerFunc(checker.Healthz))
+		server.Handle("/readyz", http.HandlerFunc(checker.Readyz))
+		go server.Run(ctx)
+	} else {
+		logger.Info(ctx, "No HTTP address configured, metrics and health endpoints disabled")
+	}
+
 	// シグナル待ち
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
+
+	// バックグラウンド処理を停止
+	cancel()
 }
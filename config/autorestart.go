@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// minAutoRestartWindow は自動再起動の試行回数を数える期間の最小値
+const minAutoRestartWindow = time.Minute
+
+// AutoRestartEnabled は指定されたサービスで自動再起動が有効かを返します
+// 設定ファイルのサービスごとの指定がグローバル設定より優先されます
+func (c *Config) AutoRestartEnabled(service string) bool {
+	if enabled := c.Services[service].AutoRestart; enabled != nil {
+		return *enabled
+	}
+	return c.AutoRestart
+}
+
+// AutoRestartConfigured はいずれかのサービスで自動再起動が有効になり得るかを返します
+func (c *Config) AutoRestartConfigured() bool {
+	if c.AutoRestart {
+		return true
+	}
+	for _, service := range c.Services {
+		if service.AutoRestart != nil && *service.AutoRestart {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAutoRestart は自動再起動の設定を検証します
+func (c *Config) validateAutoRestart() []error {
+	if !c.AutoRestartConfigured() {
+		return nil
+	}
+
+	var errs []error
+	if c.AutoRestartMaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("WATCHDOG_AUTO_RESTART_MAX_ATTEMPTS must be at least 1: %d",
+			c.AutoRestartMaxAttempts))
+	}
+	if c.AutoRestartWindow < minAutoRestartWindow {
+		errs = append(errs, fmt.Errorf("WATCHDOG_AUTO_RESTART_WINDOW must be at least %v: %v",
+			minAutoRestartWindow, c.AutoRestartWindow))
+	}
+	if c.AutoRestartBackoff < 0 {
+		errs = append(errs, errors.New("WATCHDOG_AUTO_RESTART_BACKOFF must not be negative"))
+	}
+	return errs
+}
@@ -0,0 +1,64 @@
+package config
+
+import "testing"
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func TestConfig_AutoRestartEnabled(t *testing.T) {
+	services := map[string]ServiceConfig{
+		"minecraft": {AutoRestart: boolPtr(true)},
+		"terraria":  {AutoRestart: boolPtr(false)},
+	}
+
+	tests := []struct {
+		name    string
+		global  bool
+		service string
+		want    bool
+	}{
+		{name: "グローバル無効・サービス有効", global: false, service: "minecraft", want: true},
+		{name: "グローバル有効・サービス無効", global: true, service: "terraria", want: false},
+		{name: "グローバル有効・指定なし", global: true, service: "factorio", want: true},
+		{name: "グローバル無効・指定なし", global: false, service: "factorio", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{AutoRestart: tt.global, Services: services}
+			if got := cfg.AutoRestartEnabled(tt.service); got != tt.want {
+				t.Errorf("AutoRestartEnabled(%q) = %v, want %v", tt.service, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_AutoRestartConfigured(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   bool
+	}{
+		{name: "未設定", config: Config{}, want: false},
+		{name: "グローバル有効", config: Config{AutoRestart: true}, want: true},
+		{
+			name:   "サービスのみ有効",
+			config: Config{Services: map[string]ServiceConfig{"minecraft": {AutoRestart: boolPtr(true)}}},
+			want:   true,
+		},
+		{
+			name:   "サービスで無効のみ指定",
+			config: Config{Services: map[string]ServiceConfig{"minecraft": {AutoRestart: boolPtr(false)}}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.AutoRestartConfigured(); got != tt.want {
+				t.Errorf("AutoRestartConfigured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
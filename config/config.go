@@ -4,28 +4,68 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
 	"github.com/hideA88/game-server-watchdog/pkg/logging"
 )
 
 // Config holds the application configuration
 type Config struct {
-	DiscordToken             string        `envconfig:"DISCORD_TOKEN" required:"true"`
-	DebugMode                bool          `envconfig:"DEBUG_MODE" default:"false"`
-	LogLevel                 logging.Level `envconfig:"-"` // 環境変数から直接読み込まない
-	LogLevelStr              string        `envconfig:"LOG_LEVEL" default:""`
-	AllowedChannelIDs        []string      `envconfig:"ALLOWED_CHANNEL_IDS" separator:","`
-	AllowedUserIDs           []string      `envconfig:"ALLOWED_USER_IDS" separator:","`
-	DockerComposePath        string        `envconfig:"DOCKER_COMPOSE_PATH" default:"docker-compose.yml"`
-	DockerComposeProjectName string        `envconfig:"DOCKER_COMPOSE_PROJECT_NAME" default:""`
+	DiscordToken             string          `envconfig:"DISCORD_TOKEN" required:"true"`
+	GuildID                  string          `envconfig:"DISCORD_GUILD_ID" default:""`
+	DebugMode                bool            `envconfig:"DEBUG_MODE" default:"false"`
+	LogLevel                 logging.Level   `envconfig:"-"` // 環境変数から直接読み込まない
+	LogLevelStr              string          `envconfig:"LOG_LEVEL" default:""`
+	AllowedChannelIDs        []string        `envconfig:"ALLOWED_CHANNEL_IDS" separator:","`
+	AllowedUserIDs           []string        `envconfig:"ALLOWED_USER_IDS" separator:","`
+	DockerComposePath        string          `envconfig:"DOCKER_COMPOSE_PATH" default:"docker-compose.yml"`
+	DockerComposeProjectName string          `envconfig:"DOCKER_COMPOSE_PROJECT_NAME" default:""`
+	AlertChannelID           string          `envconfig:"WATCHDOG_ALERT_CHANNEL" default:""`
+	HealthCheckInterval      time.Duration   `envconfig:"WATCHDOG_CHECK_INTERVAL" default:"5m"`
+	CPUAlertThreshold        float64         `envconfig:"WATCHDOG_CPU_THRESHOLD" default:"85"`
+	MemoryAlertThreshold     float64         `envconfig:"WATCHDOG_MEM_THRESHOLD" default:"90"`
+	DiskAlertThreshold       float64         `envconfig:"WATCHDOG_DISK_THRESHOLD" default:"90"`
+	ConfigFile               string          `envconfig:"WATCHDOG_CONFIG_FILE" default:""`
+	AutoRestart              bool            `envconfig:"WATCHDOG_AUTO_RESTART" default:"false"`
+	AutoRestartMaxAttempts   int             `envconfig:"WATCHDOG_AUTO_RESTART_MAX_ATTEMPTS" default:"3"`
+	AutoRestartWindow        time.Duration   `envconfig:"WATCHDOG_AUTO_RESTART_WINDOW" default:"1h"`
+	AutoRestartBackoff       time.Duration   `envconfig:"WATCHDOG_AUTO_RESTART_BACKOFF" default:"10s"`
+	ScheduleWarnings         []time.Duration `envconfig:"WATCHDOG_SCHEDULE_WARNINGS" default:"15m,5m,1m"`
+	ConfirmActions           bool            `envconfig:"WATCHDOG_CONFIRM_ACTIONS" default:"true"`
+	ConfirmTimeout           time.Duration   `envconfig:"WATCHDOG_CONFIRM_TIMEOUT" default:"30s"`
+	EmptyWaitTimeout         time.Duration   `envconfig:"WATCHDOG_EMPTY_WAIT_TIMEOUT" default:"30m"`
+	IdleShutdown             time.Duration   `envconfig:"WATCHDOG_IDLE_SHUTDOWN" default:"0"`
+	AuditLogPath             string          `envconfig:"WATCHDOG_AUDIT_LOG" default:""`
+	HTTPAddr                 string          `envconfig:"WATCHDOG_HTTP_ADDR" default:""`
+	StatsCache               bool            `envconfig:"WATCHDOG_STATS_CACHE" default:"true"`
+	DockerEvents             bool            `envconfig:"WATCHDOG_DOCKER_EVENTS" default:"true"`
+	HistoryPath              string          `envconfig:"WATCHDOG_HISTORY_FILE" default:""`
+	DashboardPath            string          `envconfig:"WATCHDOG_DASHBOARD_FILE" default:""`
+	DashboardInterval        time.Duration   `envconfig:"WATCHDOG_DASHBOARD_INTERVAL" default:"1m"`
+	CommandTimeout           time.Duration   `envconfig:"WATCHDOG_COMMAND_TIMEOUT" default:"2m"`
+
+	// Permissions は設定ファイルから読み込む権限の割り当て
+	Permissions PermissionsConfig `envconfig:"-"`
+	// Services は設定ファイルから読み込むサービスごとの設定
+	Services map[string]ServiceConfig `envconfig:"-"`
 }
 
+const (
+	// minHealthCheckInterval はヘルスチェック間隔の最小値
+	minHealthCheckInterval = 10 * time.Second
+	// minDashboardInterval はダッシュボードの更新間隔の最小値（Discordのレート制限を避ける）
+	minDashboardInterval = 10 * time.Second
+)
+
 // Load は環境変数から設定を読み込みます
 func Load() (*Config, error) {
 	// .envファイルが存在する場合のみ読み込む
@@ -50,6 +90,15 @@ func Load() (*Config, error) {
 		cfg.LogLevel = logging.InfoLevel
 	}
 
+	// 設定ファイルの読み込み（指定されている場合のみ）
+	if cfg.ConfigFile != "" {
+		fc, err := loadFile(cfg.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.applyFile(fc)
+	}
+
 	// 設定の検証
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -88,6 +137,81 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// サーバーIDの検証
+	if c.GuildID != "" && !isValidDiscordID(c.GuildID) {
+		errs = append(errs, fmt.Errorf("invalid DISCORD_GUILD_ID: %s", c.GuildID))
+	}
+
+	// アラートチャンネルIDの検証
+	if c.AlertChannelID != "" && !isValidDiscordID(c.AlertChannelID) {
+		errs = append(errs, fmt.Errorf("invalid WATCHDOG_ALERT_CHANNEL: %s", c.AlertChannelID))
+	}
+
+	// ヘルスチェック間隔の検証
+	if c.HealthCheckInterval != 0 && c.HealthCheckInterval < minHealthCheckInterval {
+		errs = append(errs, fmt.Errorf("WATCHDOG_CHECK_INTERVAL must be at least %v: %v",
+			minHealthCheckInterval, c.HealthCheckInterval))
+	}
+
+	// ダッシュボードの更新間隔の検証
+	if c.DashboardInterval != 0 && c.DashboardInterval < minDashboardInterval {
+		errs = append(errs, fmt.Errorf("WATCHDOG_DASHBOARD_INTERVAL must be at least %v: %v",
+			minDashboardInterval, c.DashboardInterval))
+	}
+
+	// コマンドのタイムアウトの検証
+	if c.CommandTimeout < 0 {
+		errs = append(errs, fmt.Errorf("WATCHDOG_COMMAND_TIMEOUT must not be negative: %v", c.CommandTimeout))
+	}
+
+	// アラート閾値の検証
+	for _, threshold := range []struct {
+		name  string
+		value float64
+	}{
+		{"WATCHDOG_CPU_THRESHOLD", c.CPUAlertThreshold},
+		{"WATCHDOG_MEM_THRESHOLD", c.MemoryAlertThreshold},
+		{"WATCHDOG_DISK_THRESHOLD", c.DiskAlertThreshold},
+	} {
+		if err := validateThreshold(threshold.name, threshold.value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// スケジュールの事前通知の検証
+	errs = append(errs, validateWarnings("WATCHDOG_SCHEDULE_WARNINGS", c.ScheduleWarnings)...)
+
+	// 自動再起動設定の検証
+	errs = append(errs, c.validateAutoRestart()...)
+
+	// 停止・再起動の確認設定の検証
+	errs = append(errs, c.validateConfirm()...)
+
+	// プレイヤーがいない場合の自動停止設定の検証
+	errs = append(errs, c.validateIdleShutdown()...)
+
+	// HTTPサーバーのアドレスの検証
+	if c.HTTPAddr != "" {
+		if _, _, err := net.SplitHostPort(c.HTTPAddr); err != nil {
+			errs = append(errs, fmt.Errorf("invalid WATCHDOG_HTTP_ADDR: %w", err))
+		}
+	}
+
+	// 権限の割り当ての検証
+	errs = append(errs, c.validatePermissions()...)
+
+	// サービスごとの設定の検証
+	for _, service := range sortedServiceNames(c.Services) {
+		if !docker.IsValidServiceName(service) {
+			errs = append(errs, fmt.Errorf("invalid service name in config file: %s", service))
+			continue
+		}
+		errs = append(errs, validateOverride(service, c.Services[service].Thresholds)...)
+		errs = append(errs, validateSchedule(service, c.Services[service].Schedule)...)
+		errs = append(errs, validateRCON(service, c.Services[service].RCON)...)
+		errs = append(errs, validateStop(service, c.Services[service].Stop)...)
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -122,3 +246,13 @@ func isValidDiscordID(id string) bool {
 	_, err := strconv.ParseUint(id, 10, 64)
 	return err == nil
 }
+
+// sortedServiceNames はサービス名をソートして返します
+func sortedServiceNames(services map[string]ServiceConfig) []string {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hideA88/game-server-watchdog/pkg/logging"
 )
@@ -48,6 +49,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           []string{"987654321098765432", "987654321098765433"},
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -65,6 +81,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -93,6 +124,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           []string{},
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -110,6 +156,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 			setupFunc: func() {
@@ -138,6 +199,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -156,6 +232,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -174,6 +265,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -192,6 +298,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -210,6 +331,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -228,6 +364,21 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -246,9 +397,149 @@ func TestLoad(t *testing.T) {
 				AllowedUserIDs:           nil,
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
+		{
+			name: "アラートチャンネルと監視間隔を設定",
+			envVars: map[string]string{
+				"DISCORD_TOKEN":           "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				"WATCHDOG_ALERT_CHANNEL":  "123456789012345678",
+				"WATCHDOG_CHECK_INTERVAL": "1m",
+			},
+			want: &Config{
+				DiscordToken:             "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				DebugMode:                false,
+				LogLevel:                 logging.InfoLevel,
+				LogLevelStr:              "",
+				AllowedChannelIDs:        nil,
+				AllowedUserIDs:           nil,
+				DockerComposePath:        "docker-compose.yml",
+				DockerComposeProjectName: "",
+				AlertChannelID:           "123456789012345678",
+				HealthCheckInterval:      time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "統計情報のキャッシュを無効化",
+			envVars: map[string]string{
+				"DISCORD_TOKEN":        "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				"WATCHDOG_STATS_CACHE": "false",
+			},
+			want: &Config{
+				DiscordToken:           "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				LogLevel:               logging.InfoLevel,
+				DockerComposePath:      "docker-compose.yml",
+				HealthCheckInterval:    5 * time.Minute,
+				CPUAlertThreshold:      85,
+				MemoryAlertThreshold:   90,
+				DiskAlertThreshold:     90,
+				AutoRestartMaxAttempts: 3,
+				AutoRestartWindow:      time.Hour,
+				AutoRestartBackoff:     10 * time.Second,
+				ScheduleWarnings:       []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:         true,
+				ConfirmTimeout:         30 * time.Second,
+				EmptyWaitTimeout:       30 * time.Minute,
+				StatsCache:             false,
+				DockerEvents:           true,
+				DashboardInterval:      time.Minute,
+				CommandTimeout:         2 * time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "検証エラー（監視間隔が短すぎる）",
+			envVars: map[string]string{
+				"DISCORD_TOKEN":           "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				"WATCHDOG_CHECK_INTERVAL": "1s",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "アラート閾値を環境変数で設定",
+			envVars: map[string]string{
+				"DISCORD_TOKEN":           "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				"WATCHDOG_CPU_THRESHOLD":  "70",
+				"WATCHDOG_MEM_THRESHOLD":  "80.5",
+				"WATCHDOG_DISK_THRESHOLD": "95",
+			},
+			want: &Config{
+				DiscordToken:             "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				DebugMode:                false,
+				LogLevel:                 logging.InfoLevel,
+				LogLevelStr:              "",
+				AllowedChannelIDs:        nil,
+				AllowedUserIDs:           nil,
+				DockerComposePath:        "docker-compose.yml",
+				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        70,
+				MemoryAlertThreshold:     80.5,
+				DiskAlertThreshold:       95,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "検証エラー（閾値が範囲外）",
+			envVars: map[string]string{
+				"DISCORD_TOKEN":          "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				"WATCHDOG_CPU_THRESHOLD": "150",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "設定ファイルが存在しない",
+			envVars: map[string]string{
+				"DISCORD_TOKEN":        "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				"WATCHDOG_CONFIG_FILE": "/non/existent/watchdog.yml",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "検証エラー（短いトークン）",
 			envVars: map[string]string{
@@ -285,6 +576,14 @@ func TestLoad(t *testing.T) {
 				"DISCORD_TOKEN", "DEBUG_MODE", "LOG_LEVEL",
 				"ALLOWED_CHANNEL_IDS", "ALLOWED_USER_IDS",
 				"DOCKER_COMPOSE_PATH", "DOCKER_COMPOSE_PROJECT_NAME",
+				"WATCHDOG_ALERT_CHANNEL", "WATCHDOG_CHECK_INTERVAL",
+				"WATCHDOG_CPU_THRESHOLD", "WATCHDOG_MEM_THRESHOLD", "WATCHDOG_DISK_THRESHOLD",
+				"WATCHDOG_CONFIG_FILE", "WATCHDOG_AUTO_RESTART", "WATCHDOG_AUTO_RESTART_MAX_ATTEMPTS",
+				"WATCHDOG_AUTO_RESTART_WINDOW", "WATCHDOG_AUTO_RESTART_BACKOFF", "WATCHDOG_SCHEDULE_WARNINGS",
+				"WATCHDOG_CONFIRM_ACTIONS", "WATCHDOG_CONFIRM_TIMEOUT", "WATCHDOG_EMPTY_WAIT_TIMEOUT", "WATCHDOG_AUDIT_LOG",
+				"WATCHDOG_HTTP_ADDR",
+				"WATCHDOG_STATS_CACHE", "WATCHDOG_DOCKER_EVENTS", "WATCHDOG_HISTORY_FILE", "WATCHDOG_DASHBOARD_FILE",
+				"WATCHDOG_DASHBOARD_INTERVAL", "WATCHDOG_COMMAND_TIMEOUT", "DISCORD_GUILD_ID",
 			}
 			for _, key := range envKeys {
 				originalEnv[key] = os.Getenv(key)
@@ -389,6 +688,21 @@ ALLOWED_USER_IDS=987654321098765432,987654321098765433`,
 				AllowedUserIDs:           []string{"987654321098765432", "987654321098765433"},
 				DockerComposePath:        "docker-compose.yml",
 				DockerComposeProjectName: "",
+				HealthCheckInterval:      5 * time.Minute,
+				CPUAlertThreshold:        85,
+				MemoryAlertThreshold:     90,
+				DiskAlertThreshold:       90,
+				AutoRestartMaxAttempts:   3,
+				AutoRestartWindow:        time.Hour,
+				AutoRestartBackoff:       10 * time.Second,
+				ScheduleWarnings:         []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+				ConfirmActions:           true,
+				ConfirmTimeout:           30 * time.Second,
+				EmptyWaitTimeout:         30 * time.Minute,
+				StatsCache:               true,
+				DockerEvents:             true,
+				DashboardInterval:        time.Minute,
+				CommandTimeout:           2 * time.Minute,
 			},
 			wantErr: false,
 		},
@@ -401,6 +715,14 @@ ALLOWED_USER_IDS=987654321098765432,987654321098765433`,
 				"DISCORD_TOKEN", "DEBUG_MODE", "LOG_LEVEL",
 				"ALLOWED_CHANNEL_IDS", "ALLOWED_USER_IDS",
 				"DOCKER_COMPOSE_PATH", "DOCKER_COMPOSE_PROJECT_NAME",
+				"WATCHDOG_ALERT_CHANNEL", "WATCHDOG_CHECK_INTERVAL",
+				"WATCHDOG_CPU_THRESHOLD", "WATCHDOG_MEM_THRESHOLD", "WATCHDOG_DISK_THRESHOLD",
+				"WATCHDOG_CONFIG_FILE", "WATCHDOG_AUTO_RESTART", "WATCHDOG_AUTO_RESTART_MAX_ATTEMPTS",
+				"WATCHDOG_AUTO_RESTART_WINDOW", "WATCHDOG_AUTO_RESTART_BACKOFF", "WATCHDOG_SCHEDULE_WARNINGS",
+				"WATCHDOG_CONFIRM_ACTIONS", "WATCHDOG_CONFIRM_TIMEOUT", "WATCHDOG_EMPTY_WAIT_TIMEOUT", "WATCHDOG_AUDIT_LOG",
+				"WATCHDOG_HTTP_ADDR",
+				"WATCHDOG_STATS_CACHE", "WATCHDOG_DOCKER_EVENTS", "WATCHDOG_HISTORY_FILE", "WATCHDOG_DASHBOARD_FILE",
+				"WATCHDOG_DASHBOARD_INTERVAL", "WATCHDOG_COMMAND_TIMEOUT", "DISCORD_GUILD_ID",
 			}
 			originalEnv := make(map[string]string)
 			for _, key := range envKeys {
@@ -535,6 +857,146 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid DISCORD_TOKEN",
 		},
+		{
+			name: "無効なアラートチャンネルID",
+			config: Config{
+				DiscordToken:   "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				AlertChannelID: "alerts",
+			},
+			wantErr: true,
+			errMsg:  "invalid WATCHDOG_ALERT_CHANNEL",
+		},
+		{
+			name: "監視間隔が短すぎる",
+			config: Config{
+				DiscordToken:        "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				HealthCheckInterval: time.Second,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_CHECK_INTERVAL must be at least",
+		},
+		{
+			name: "ダッシュボードの更新間隔が短すぎる",
+			config: Config{
+				DiscordToken:      "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				DashboardInterval: time.Second,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_DASHBOARD_INTERVAL must be at least",
+		},
+		{
+			name: "コマンドのタイムアウトが負の値",
+			config: Config{
+				DiscordToken:   "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				CommandTimeout: -time.Second,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_COMMAND_TIMEOUT must not be negative",
+		},
+		{
+			name: "空になるまで待つ時間が負の値",
+			config: Config{
+				DiscordToken:     "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				EmptyWaitTimeout: -time.Minute,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_EMPTY_WAIT_TIMEOUT must not be negative",
+		},
+		{
+			name: "サービスごとの閾値が範囲外",
+			config: Config{
+				DiscordToken: "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				Services: map[string]ServiceConfig{
+					"minecraft": {Thresholds: ThresholdOverride{Memory: floatPtr(120)}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "services.minecraft.thresholds.memory must be between 0 and 100",
+		},
+		{
+			name: "設定ファイル内の無効なサービス名",
+			config: Config{
+				DiscordToken: "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				Services: map[string]ServiceConfig{
+					"mine craft": {},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid service name in config file",
+		},
+		{
+			name: "自動再起動の試行回数が0",
+			config: Config{
+				DiscordToken:      "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				AutoRestart:       true,
+				AutoRestartWindow: time.Hour,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_AUTO_RESTART_MAX_ATTEMPTS must be at least 1",
+		},
+		{
+			name: "自動再起動の期間が短すぎる",
+			config: Config{
+				DiscordToken:           "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				AutoRestart:            true,
+				AutoRestartMaxAttempts: 3,
+				AutoRestartWindow:      time.Second,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_AUTO_RESTART_WINDOW must be at least",
+		},
+		{
+			name: "確認の有効期限が短すぎる",
+			config: Config{
+				DiscordToken:   "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				ConfirmActions: true,
+				ConfirmTimeout: time.Second,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_CONFIRM_TIMEOUT must be between",
+		},
+		{
+			name: "確認の有効期限が長すぎる",
+			config: Config{
+				DiscordToken:   "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				ConfirmTimeout: time.Hour,
+			},
+			wantErr: true,
+			errMsg:  "WATCHDOG_CONFIRM_TIMEOUT must be between",
+		},
+		{
+			name: "HTTPサーバーのアドレス",
+			config: Config{
+				DiscordToken: "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				HTTPAddr:     ":9090",
+			},
+			wantErr: false,
+		},
+		{
+			name: "無効なHTTPサーバーのアドレス",
+			config: Config{
+				DiscordToken: "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				HTTPAddr:     "9090",
+			},
+			wantErr: true,
+			errMsg:  "invalid WATCHDOG_HTTP_ADDR",
+		},
+		{
+			name: "自動再起動が無効な場合は関連設定を検証しない",
+			config: Config{
+				DiscordToken: "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+			},
+			wantErr: false,
+		},
+		{
+			name: "無効なサーバーID",
+			config: Config{
+				DiscordToken: "MTIzNDU2Nzg5MDEyMzQ1Njc4OS5GdUNrLkluc1AvdXVzZWNyZXRzaGg_.test.example",
+				GuildID:      "guild",
+			},
+			wantErr: true,
+			errMsg:  "invalid DISCORD_GUILD_ID",
+		},
 		{
 			name: "空のチャンネルIDとユーザーID（エラーなし）",
 			config: Config{
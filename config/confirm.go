@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// minConfirmTimeout は確認ボタンの有効期限の最小値
+	minConfirmTimeout = 5 * time.Second
+	// maxConfirmTimeout は確認ボタンの有効期限の最大値（Discordのインタラクションの有効期限より短くする）
+	maxConfirmTimeout = 10 * time.Minute
+)
+
+// ConfirmationRequired は指定されたサービスの停止・再起動に確認が必要かを返します
+// 設定ファイルのサービスごとの指定がグローバル設定より優先されます
+func (c *Config) ConfirmationRequired(service string) bool {
+	if confirm := c.Services[service].Confirm; confirm != nil {
+		return *confirm
+	}
+	return c.ConfirmActions
+}
+
+// validateConfirm は確認の設定を検証します（0の場合はデフォルト値を使用）
+func (c *Config) validateConfirm() []error {
+	var errs []error
+	if c.ConfirmTimeout != 0 && (c.ConfirmTimeout < minConfirmTimeout || c.ConfirmTimeout > maxConfirmTimeout) {
+		errs = append(errs, fmt.Errorf("WATCHDOG_CONFIRM_TIMEOUT must be between %v and %v: %v",
+			minConfirmTimeout, maxConfirmTimeout, c.ConfirmTimeout))
+	}
+	if c.EmptyWaitTimeout < 0 {
+		errs = append(errs, fmt.Errorf("WATCHDOG_EMPTY_WAIT_TIMEOUT must not be negative: %v", c.EmptyWaitTimeout))
+	}
+	return errs
+}
@@ -0,0 +1,39 @@
+package config
+
+import "testing"
+
+func TestConfig_ConfirmationRequired(t *testing.T) {
+	cfg := &Config{
+		ConfirmActions: true,
+		Services: map[string]ServiceConfig{
+			"proxy":     {Confirm: boolPtr(false)},
+			"minecraft": {AutoRestart: boolPtr(true)},
+		},
+	}
+
+	tests := []struct {
+		service string
+		want    bool
+	}{
+		{service: "minecraft", want: true},
+		{service: "proxy", want: false},
+		{service: "terraria", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			if got := cfg.ConfirmationRequired(tt.service); got != tt.want {
+				t.Errorf("ConfirmationRequired(%q) = %v, want %v", tt.service, got, tt.want)
+			}
+		})
+	}
+
+	// グローバルで無効でもサービスごとに有効にできる
+	cfg = &Config{Services: map[string]ServiceConfig{"minecraft": {Confirm: boolPtr(true)}}}
+	if !cfg.ConfirmationRequired("minecraft") {
+		t.Error("ConfirmationRequired(minecraft) = false, want true")
+	}
+	if cfg.ConfirmationRequired("terraria") {
+		t.Error("ConfirmationRequired(terraria) = true, want false")
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig は設定ファイル（YAML）の内容を表す構造体
+type FileConfig struct {
+	// Thresholds はグローバルなアラート閾値（環境変数が優先）
+	Thresholds ThresholdOverride `yaml:"thresholds"`
+	// Permissions はロールとユーザーへの権限の割り当て（未指定の場合は全員が管理者）
+	Permissions PermissionsConfig `yaml:"permissions"`
+	// Services はcomposeサービス名をキーとしたサービスごとの設定
+	Services map[string]ServiceConfig `yaml:"services"`
+}
+
+// ServiceConfig はサービスごとの設定
+type ServiceConfig struct {
+	// Thresholds はこのサービスのアラート閾値の上書き
+	Thresholds ThresholdOverride `yaml:"thresholds"`
+	// AutoRestart はこのサービスの自動再起動の有効/無効（未指定の場合はグローバル設定）
+	AutoRestart *bool `yaml:"auto_restart"`
+	// Confirm はこのサービスの停止・再起動前の確認の有効/無効（未指定の場合はグローバル設定）
+	Confirm *bool `yaml:"confirm"`
+	// IdleShutdown はプレイヤーがいない状態が続いてから停止するまでの時間（0で無効、未指定の場合はグローバル設定）
+	IdleShutdown *time.Duration `yaml:"idle_shutdown"`
+	// Schedule はこのサービスの定期再起動・停止時間帯
+	Schedule ScheduleConfig `yaml:"schedule"`
+	// Permissions はこのサービスに限って付与する権限
+	Permissions PermissionGrants `yaml:"permissions"`
+	// RCON はこのサービスで実行を許可するRCONコマンドとゲーム内通知の書式
+	RCON RCONConfig `yaml:"rcon"`
+	// Stop はこのサービスの停止・再起動の方法
+	Stop StopConfig `yaml:"stop"`
+}
+
+// loadFile は設定ファイルを読み込みます
+func loadFile(path string) (*FileConfig, error) {
+	// #nosec G304 - 設定ファイルのパスは管理者が環境変数で指定する
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fc FileConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return &fc, nil
+}
+
+// applyFile は設定ファイルの内容を設定に反映します
+// グローバル閾値は対応する環境変数が設定されていない場合のみ反映します
+func (c *Config) applyFile(fc *FileConfig) {
+	applyIfEnvUnset("WATCHDOG_CPU_THRESHOLD", fc.Thresholds.CPU, &c.CPUAlertThreshold)
+	applyIfEnvUnset("WATCHDOG_MEM_THRESHOLD", fc.Thresholds.Memory, &c.MemoryAlertThreshold)
+	applyIfEnvUnset("WATCHDOG_DISK_THRESHOLD", fc.Thresholds.Disk, &c.DiskAlertThreshold)
+
+	c.Permissions = fc.Permissions
+	c.Services = fc.Services
+}
+
+// applyIfEnvUnset は環境変数が未設定の場合のみ値を上書きします
+func applyIfEnvUnset(envKey string, value *float64, dst *float64) {
+	if value == nil {
+		return
+	}
+	if _, ok := os.LookupEnv(envKey); ok {
+		return
+	}
+	*dst = *value
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// writeConfigFile はテスト用の設定ファイルを作成してパスを返す
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "watchdog.yml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *FileConfig
+		wantErr bool
+	}{
+		{
+			name: "グローバル閾値とサービスごとの閾値",
+			content: `
+thresholds:
+  cpu: 80
+services:
+  minecraft:
+    thresholds:
+      memory: 97
+  proxy:
+    thresholds:
+      memory: 70
+      cpu: 50
+`,
+			want: &FileConfig{
+				Thresholds: ThresholdOverride{CPU: floatPtr(80)},
+				Services: map[string]ServiceConfig{
+					"minecraft": {Thresholds: ThresholdOverride{Memory: floatPtr(97)}},
+					"proxy":     {Thresholds: ThresholdOverride{CPU: floatPtr(50), Memory: floatPtr(70)}},
+				},
+			},
+		},
+		{
+			name: "RCONの設定",
+			content: `
+services:
+  minecraft:
+    rcon:
+      commands: [list, "whitelist add"]
+      broadcast: "say [Server] %s"
+`,
+			want: &FileConfig{
+				Services: map[string]ServiceConfig{
+					"minecraft": {RCON: RCONConfig{Commands: []string{"list", "whitelist add"}, Broadcast: "say [Server] %s"}},
+				},
+			},
+		},
+		{
+			name: "プレイヤーがいない場合の自動停止",
+			content: `
+services:
+  minecraft:
+    idle_shutdown: 2h
+  proxy:
+    idle_shutdown: 0s
+`,
+			want: &FileConfig{
+				Services: map[string]ServiceConfig{
+					"minecraft": {IdleShutdown: durationPtr(2 * time.Hour)},
+					"proxy":     {IdleShutdown: durationPtr(0)},
+				},
+			},
+		},
+		{
+			name: "停止方法",
+			content: `
+services:
+  minecraft:
+    stop:
+      timeout: 2m
+      signal: SIGINT
+      rcon: [save-all]
+`,
+			want: &FileConfig{
+				Services: map[string]ServiceConfig{
+					"minecraft": {Stop: StopConfig{Timeout: 2 * time.Minute, Signal: "SIGINT", RCON: []string{"save-all"}}},
+				},
+			},
+		},
+		{
+			name:    "空のファイル",
+			content: "",
+			want:    &FileConfig{},
+		},
+		{
+			name:    "不正なYAML",
+			content: "services: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadFile(writeConfigFile(t, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_applyFile(t *testing.T) {
+	fc := &FileConfig{
+		Thresholds: ThresholdOverride{CPU: floatPtr(70), Memory: floatPtr(75)},
+		Services: map[string]ServiceConfig{
+			"minecraft": {Thresholds: ThresholdOverride{Memory: floatPtr(97)}},
+		},
+	}
+
+	// 環境変数で指定された値は設定ファイルより優先される
+	t.Setenv("WATCHDOG_MEM_THRESHOLD", "88")
+
+	cfg := &Config{CPUAlertThreshold: 85, MemoryAlertThreshold: 88, DiskAlertThreshold: 90}
+	cfg.applyFile(fc)
+
+	if cfg.CPUAlertThreshold != 70 {
+		t.Errorf("CPUAlertThreshold = %v, want 70", cfg.CPUAlertThreshold)
+	}
+	if cfg.MemoryAlertThreshold != 88 {
+		t.Errorf("MemoryAlertThreshold = %v, want 88 (env takes precedence)", cfg.MemoryAlertThreshold)
+	}
+	if cfg.DiskAlertThreshold != 90 {
+		t.Errorf("DiskAlertThreshold = %v, want 90", cfg.DiskAlertThreshold)
+	}
+	if !reflect.DeepEqual(cfg.Services, fc.Services) {
+		t.Errorf("Services = %+v, want %+v", cfg.Services, fc.Services)
+	}
+}
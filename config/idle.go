@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// minIdleShutdown はプレイヤーがいない状態が続いてから停止するまでの時間の最小値
+const minIdleShutdown = time.Minute
+
+// IdleShutdownAfter は指定されたサービスをプレイヤーがいない状態が続いてから停止するまでの時間を返します
+// 設定ファイルのサービスごとの指定がグローバル設定より優先され、0の場合は停止しません
+func (c *Config) IdleShutdownAfter(service string) time.Duration {
+	if after := c.Services[service].IdleShutdown; after != nil {
+		return *after
+	}
+	return c.IdleShutdown
+}
+
+// IdleShutdownConfigured はいずれかのサービスでプレイヤーがいない場合の自動停止が有効になり得るかを返します
+func (c *Config) IdleShutdownConfigured() bool {
+	if c.IdleShutdown > 0 {
+		return true
+	}
+	for _, service := range c.Services {
+		if service.IdleShutdown != nil && *service.IdleShutdown > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIdleShutdown はプレイヤーがいない場合の自動停止の設定を検証します
+func (c *Config) validateIdleShutdown() []error {
+	var errs []error
+	if err := validateIdleDuration("WATCHDOG_IDLE_SHUTDOWN", c.IdleShutdown); err != nil {
+		errs = append(errs, err)
+	}
+	for _, service := range sortedServiceNames(c.Services) {
+		after := c.Services[service].IdleShutdown
+		if after == nil {
+			continue
+		}
+		if err := validateIdleDuration(fmt.Sprintf("services.%s.idle_shutdown", service), *after); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateIdleDuration は停止するまでの時間が0（無効）またはminIdleShutdown以上かを検証します
+func validateIdleDuration(name string, after time.Duration) error {
+	if after != 0 && after < minIdleShutdown {
+		return fmt.Errorf("%s must be 0 or at least %v: %v", name, minIdleShutdown, after)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestConfig_IdleShutdownAfter(t *testing.T) {
+	services := map[string]ServiceConfig{
+		"minecraft": {IdleShutdown: durationPtr(30 * time.Minute)},
+		"proxy":     {IdleShutdown: durationPtr(0)},
+	}
+
+	tests := []struct {
+		name    string
+		global  time.Duration
+		service string
+		want    time.Duration
+	}{
+		{name: "サービスの指定が優先", global: 2 * time.Hour, service: "minecraft", want: 30 * time.Minute},
+		{name: "サービスで無効", global: 2 * time.Hour, service: "proxy", want: 0},
+		{name: "指定なしはグローバル設定", global: 2 * time.Hour, service: "terraria", want: 2 * time.Hour},
+		{name: "グローバル無効・指定なし", global: 0, service: "terraria", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{IdleShutdown: tt.global, Services: services}
+			if got := cfg.IdleShutdownAfter(tt.service); got != tt.want {
+				t.Errorf("IdleShutdownAfter(%q) = %v, want %v", tt.service, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_IdleShutdownConfigured(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   bool
+	}{
+		{name: "未設定", config: Config{}, want: false},
+		{name: "グローバル有効", config: Config{IdleShutdown: time.Hour}, want: true},
+		{
+			name:   "サービスのみ有効",
+			config: Config{Services: map[string]ServiceConfig{"minecraft": {IdleShutdown: durationPtr(time.Hour)}}},
+			want:   true,
+		},
+		{
+			name:   "サービスで無効のみ指定",
+			config: Config{Services: map[string]ServiceConfig{"minecraft": {IdleShutdown: durationPtr(0)}}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.IdleShutdownConfigured(); got != tt.want {
+				t.Errorf("IdleShutdownConfigured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_validateIdleShutdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		wantErrs int
+	}{
+		{name: "未設定", config: Config{}, wantErrs: 0},
+		{
+			name: "有効な設定",
+			config: Config{IdleShutdown: time.Hour, Services: map[string]ServiceConfig{
+				"minecraft": {IdleShutdown: durationPtr(0)},
+			}},
+			wantErrs: 0,
+		},
+		{name: "グローバルが短すぎる", config: Config{IdleShutdown: 30 * time.Second}, wantErrs: 1},
+		{name: "グローバルが負", config: Config{IdleShutdown: -time.Hour}, wantErrs: 1},
+		{
+			name: "サービスが短すぎる",
+			config: Config{Services: map[string]ServiceConfig{
+				"minecraft": {IdleShutdown: durationPtr(time.Second)},
+				"terraria":  {IdleShutdown: durationPtr(-time.Minute)},
+			}},
+			wantErrs: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := tt.config.validateIdleShutdown(); len(errs) != tt.wantErrs {
+				t.Errorf("validateIdleShutdown() = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
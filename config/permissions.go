@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+// PermissionsConfig はボット全体の権限の割り当て
+type PermissionsConfig struct {
+	// Default はどの割り当てにも一致しないユーザーの権限（未指定の場合はnone）
+	Default          string `yaml:"default"`
+	PermissionGrants `yaml:",inline"`
+}
+
+// PermissionGrants は権限レベルごとのロールとユーザーの割り当て
+type PermissionGrants struct {
+	Admin    PermissionGrant `yaml:"admin"`
+	Operator PermissionGrant `yaml:"operator"`
+	Viewer   PermissionGrant `yaml:"viewer"`
+}
+
+// PermissionGrant は権限を付与するDiscordのロールIDとユーザーID
+type PermissionGrant struct {
+	Roles []string `yaml:"roles"`
+	Users []string `yaml:"users"`
+}
+
+// PermissionsConfigured は権限の割り当てが設定されているかを返す
+// 未設定の場合はアクセスを許可されたユーザー全員が管理者として扱われる
+func (c *Config) PermissionsConfigured() bool {
+	if c.Permissions.Default != "" || !c.Permissions.empty() {
+		return true
+	}
+	for _, svc := range c.Services {
+		if !svc.Permissions.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionLevel はユーザーの指定されたサービスに対する権限レベルを返す
+// サービス単位の割り当てはボット全体の権限より強い場合のみ反映する
+func (c *Config) PermissionLevel(user permission.User, service string) permission.Level {
+	if !c.PermissionsConfigured() {
+		return permission.LevelAdmin
+	}
+
+	level := permission.LevelNone
+	if def, err := permission.ParseLevel(c.Permissions.Default); err == nil {
+		level = def
+	}
+	level = max(level, c.Permissions.level(user))
+	if svc, ok := c.Services[service]; ok && service != "" {
+		level = max(level, svc.Permissions.level(user))
+	}
+	return level
+}
+
+// level はユーザーに割り当てられた最も強い権限レベルを返す
+func (g PermissionGrants) level(user permission.User) permission.Level {
+	switch {
+	case g.Admin.matches(user):
+		return permission.LevelAdmin
+	case g.Operator.matches(user):
+		return permission.LevelOperator
+	case g.Viewer.matches(user):
+		return permission.LevelViewer
+	default:
+		return permission.LevelNone
+	}
+}
+
+// empty は割り当てがひとつもないかを返す
+func (g PermissionGrants) empty() bool {
+	for _, grant := range []PermissionGrant{g.Admin, g.Operator, g.Viewer} {
+		if len(grant.Roles) > 0 || len(grant.Users) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matches はユーザーまたはユーザーのロールが割り当てに含まれるかを返す
+func (g PermissionGrant) matches(user permission.User) bool {
+	for _, id := range g.Users {
+		if id == user.ID {
+			return true
+		}
+	}
+	for _, id := range g.Roles {
+		for _, roleID := range user.RoleIDs {
+			if id == roleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validatePermissions は権限の割り当てを検証する
+func (c *Config) validatePermissions() []error {
+	var errs []error
+	if c.Permissions.Default != "" {
+		if _, err := permission.ParseLevel(c.Permissions.Default); err != nil {
+			errs = append(errs, fmt.Errorf("permissions: default: %w", err))
+		}
+	}
+	errs = append(errs, validateGrants("permissions", c.Permissions.PermissionGrants)...)
+	for _, service := range sortedServiceNames(c.Services) {
+		errs = append(errs, validateGrants("services."+service+".permissions", c.Services[service].Permissions)...)
+	}
+	return errs
+}
+
+// validateGrants は割り当てられたIDの形式を検証する
+func validateGrants(prefix string, g PermissionGrants) []error {
+	var errs []error
+	for _, grant := range []struct {
+		name  string
+		grant PermissionGrant
+	}{
+		{"admin", g.Admin},
+		{"operator", g.Operator},
+		{"viewer", g.Viewer},
+	} {
+		for _, id := range grant.grant.Roles {
+			if !isValidDiscordID(id) {
+				errs = append(errs, fmt.Errorf("%s.%s: invalid role ID: %s", prefix, grant.name, id))
+			}
+		}
+		for _, id := range grant.grant.Users {
+			if !isValidDiscordID(id) {
+				errs = append(errs, fmt.Errorf("%s.%s: invalid user ID: %s", prefix, grant.name, id))
+			}
+		}
+	}
+	return errs
+}
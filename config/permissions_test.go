@@ -0,0 +1,209 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+const (
+	adminRoleID     = "100000000000000001"
+	operatorRoleID  = "100000000000000002"
+	viewerRoleID    = "100000000000000003"
+	minecraftModID  = "100000000000000004"
+	ownerUserID     = "200000000000000001"
+	regularUserID   = "200000000000000002"
+	minecraftUserID = "200000000000000003"
+)
+
+func TestLoadFile_Permissions(t *testing.T) {
+	content := `
+permissions:
+  default: viewer
+  admin:
+    users: ["` + ownerUserID + `"]
+  operator:
+    roles: ["` + operatorRoleID + `"]
+services:
+  minecraft:
+    permissions:
+      operator:
+        roles: ["` + minecraftModID + `"]
+`
+	got, err := loadFile(writeConfigFile(t, content))
+	if err != nil {
+		t.Fatalf("loadFile() error = %v", err)
+	}
+
+	want := &FileConfig{
+		Permissions: PermissionsConfig{
+			Default: "viewer",
+			PermissionGrants: PermissionGrants{
+				Admin:    PermissionGrant{Users: []string{ownerUserID}},
+				Operator: PermissionGrant{Roles: []string{operatorRoleID}},
+			},
+		},
+		Services: map[string]ServiceConfig{
+			"minecraft": {Permissions: PermissionGrants{
+				Operator: PermissionGrant{Roles: []string{minecraftModID}},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadFile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConfig_PermissionLevel(t *testing.T) {
+	cfg := &Config{
+		Permissions: PermissionsConfig{
+			PermissionGrants: PermissionGrants{
+				Admin:    PermissionGrant{Roles: []string{adminRoleID}, Users: []string{ownerUserID}},
+				Operator: PermissionGrant{Roles: []string{operatorRoleID}},
+				Viewer:   PermissionGrant{Roles: []string{viewerRoleID}},
+			},
+		},
+		Services: map[string]ServiceConfig{
+			"minecraft": {Permissions: PermissionGrants{
+				Operator: PermissionGrant{Roles: []string{minecraftModID}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		user    permission.User
+		service string
+		want    permission.Level
+	}{
+		{
+			name: "ユーザーIDで管理者",
+			user: permission.User{ID: ownerUserID},
+			want: permission.LevelAdmin,
+		},
+		{
+			name: "複数のロールのうち最も強い権限",
+			user: permission.User{ID: regularUserID, RoleIDs: []string{viewerRoleID, operatorRoleID}},
+			want: permission.LevelOperator,
+		},
+		{
+			name: "割り当てなし",
+			user: permission.User{ID: regularUserID},
+			want: permission.LevelNone,
+		},
+		{
+			name:    "サービス単位の割り当て",
+			user:    permission.User{ID: minecraftUserID, RoleIDs: []string{viewerRoleID, minecraftModID}},
+			service: "minecraft",
+			want:    permission.LevelOperator,
+		},
+		{
+			name:    "サービス単位の割り当ては他のサービスに影響しない",
+			user:    permission.User{ID: minecraftUserID, RoleIDs: []string{viewerRoleID, minecraftModID}},
+			service: "terraria",
+			want:    permission.LevelViewer,
+		},
+		{
+			name:    "サービス単位の割り当てで全体の権限は下がらない",
+			user:    permission.User{ID: ownerUserID},
+			service: "minecraft",
+			want:    permission.LevelAdmin,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.PermissionLevel(tt.user, tt.service); got != tt.want {
+				t.Errorf("PermissionLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_PermissionLevel_Defaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want permission.Level
+	}{
+		{
+			name: "権限未設定の場合は全員が管理者",
+			cfg:  &Config{},
+			want: permission.LevelAdmin,
+		},
+		{
+			name: "defaultを指定",
+			cfg:  &Config{Permissions: PermissionsConfig{Default: "viewer"}},
+			want: permission.LevelViewer,
+		},
+		{
+			name: "サービス単位の割り当てのみの場合は割り当てのないユーザーは権限なし",
+			cfg: &Config{Services: map[string]ServiceConfig{
+				"minecraft": {Permissions: PermissionGrants{Admin: PermissionGrant{Users: []string{ownerUserID}}}},
+			}},
+			want: permission.LevelNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.PermissionLevel(permission.User{ID: regularUserID}, ""); got != tt.want {
+				t.Errorf("PermissionLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_validatePermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name: "正常な設定",
+			cfg: &Config{
+				Permissions: PermissionsConfig{
+					Default:          "viewer",
+					PermissionGrants: PermissionGrants{Admin: PermissionGrant{Users: []string{ownerUserID}}},
+				},
+			},
+		},
+		{
+			name:    "不正なdefault",
+			cfg:     &Config{Permissions: PermissionsConfig{Default: "owner"}},
+			wantErr: "permissions: default",
+		},
+		{
+			name: "不正なロールID",
+			cfg: &Config{Permissions: PermissionsConfig{
+				PermissionGrants: PermissionGrants{Operator: PermissionGrant{Roles: []string{"mods"}}},
+			}},
+			wantErr: "permissions.operator: invalid role ID: mods",
+		},
+		{
+			name: "サービス単位の不正なユーザーID",
+			cfg: &Config{Services: map[string]ServiceConfig{
+				"minecraft": {Permissions: PermissionGrants{Viewer: PermissionGrant{Users: []string{"123"}}}},
+			}},
+			wantErr: "services.minecraft.permissions.viewer: invalid user ID: 123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.cfg.validatePermissions()
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validatePermissions() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("validatePermissions() = %v, want error containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
+)
+
+// RCONConfig はサービスごとのRCONの設定
+type RCONConfig struct {
+	// Commands は `rcon` コマンドで実行を許可するRCONコマンド（未指定の場合は実行できない）
+	// "list" や "whitelist add" のように指定し、先頭の単語が一致するコマンドを許可する（大文字小文字を区別しない）
+	Commands []string `yaml:"commands"`
+	// Broadcast は再起動・停止の前にゲーム内に通知するコマンドの書式（%sをメッセージに置き換える、省略時は "say %s"）
+	Broadcast string `yaml:"broadcast"`
+}
+
+// RCONAllowedCommands は指定されたサービスで実行を許可するRCONコマンドを返します
+func (c *Config) RCONAllowedCommands(service string) []string {
+	return c.Services[service].RCON.Commands
+}
+
+// RCONCommandAllowed は指定されたサービスでRCONコマンドの実行が許可されているかを返します
+// ; や改行で区切った後続のコマンドは先頭の単語の確認では判定できないため、区切り文字や制御文字を含む場合は許可しません
+func (c *Config) RCONCommandAllowed(service, command string) bool {
+	if rcon.ValidateCommand(command) != nil {
+		return false
+	}
+	words := strings.Fields(strings.ToLower(command))
+	if len(words) == 0 {
+		return false
+	}
+	for _, allowed := range c.RCONAllowedCommands(service) {
+		if hasWordPrefix(words, strings.Fields(strings.ToLower(allowed))) {
+			return true
+		}
+	}
+	return false
+}
+
+// RCONBroadcastFormat は指定されたサービスのゲーム内通知のコマンドの書式を返します（未指定の場合は空）
+func (c *Config) RCONBroadcastFormat(service string) string {
+	return c.Services[service].RCON.Broadcast
+}
+
+// hasWordPrefix はwordsの先頭がprefixの単語と一致するかを返します
+func hasWordPrefix(words, prefix []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(words) {
+		return false
+	}
+	for i := range prefix {
+		if words[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRCON はサービスのRCON設定を検証します
+func validateRCON(service string, rcon RCONConfig) []error {
+	var errs []error
+	for i, command := range rcon.Commands {
+		if strings.TrimSpace(command) == "" {
+			errs = append(errs, fmt.Errorf("services.%s.rcon.commands[%d] must not be empty", service, i))
+		}
+	}
+	if rcon.Broadcast != "" && !strings.Contains(rcon.Broadcast, "%s") {
+		errs = append(errs, fmt.Errorf("services.%s.rcon.broadcast must contain %%s: %q", service, rcon.Broadcast))
+	}
+	return errs
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_RCONCommandAllowed(t *testing.T) {
+	cfg := &Config{
+		Services: map[string]ServiceConfig{
+			"minecraft": {RCON: RCONConfig{Commands: []string{"list", "say", "Whitelist Add"}}},
+			"terraria":  {},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		command string
+		want    bool
+	}{
+		{name: "許可したコマンド", service: "minecraft", command: "list", want: true},
+		{name: "引数付き", service: "minecraft", command: "say hello world", want: true},
+		{name: "大文字小文字を区別しない", service: "minecraft", command: "LIST", want: true},
+		{name: "複数の単語", service: "minecraft", command: "whitelist add Steve", want: true},
+		{name: "複数の単語の一部だけ", service: "minecraft", command: "whitelist remove Steve"},
+		{name: "前方一致だけの単語", service: "minecraft", command: "lists"},
+		{name: "許可していないコマンド", service: "minecraft", command: "stop"},
+		{name: "空のコマンド", service: "minecraft", command: "  "},
+		{name: "セミコロンで後続のコマンドを連結", service: "minecraft", command: "list; stop"},
+		{name: "セミコロンだけで連結", service: "minecraft", command: "say hi;stop"},
+		{name: "改行で後続のコマンドを連結", service: "minecraft", command: "list\nstop"},
+		{name: "復帰文字で後続のコマンドを連結", service: "minecraft", command: "list\rstop"},
+		{name: "その他の制御文字", service: "minecraft", command: "say hi\x00"},
+		{name: "許可リストがないサービス", service: "terraria", command: "list"},
+		{name: "設定がないサービス", service: "valheim", command: "list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.RCONCommandAllowed(tt.service, tt.command); got != tt.want {
+				t.Errorf("RCONCommandAllowed(%q, %q) = %v, want %v", tt.service, tt.command, got, tt.want)
+			}
+		})
+	}
+
+	if got := cfg.RCONAllowedCommands("minecraft"); !reflect.DeepEqual(got, []string{"list", "say", "Whitelist Add"}) {
+		t.Errorf("RCONAllowedCommands(minecraft) = %v", got)
+	}
+}
+
+func TestConfig_RCONBroadcastFormat(t *testing.T) {
+	cfg := &Config{
+		Services: map[string]ServiceConfig{
+			"ark": {RCON: RCONConfig{Broadcast: "ServerChat %s"}},
+		},
+	}
+	if got := cfg.RCONBroadcastFormat("ark"); got != "ServerChat %s" {
+		t.Errorf("RCONBroadcastFormat(ark) = %q", got)
+	}
+	if got := cfg.RCONBroadcastFormat("minecraft"); got != "" {
+		t.Errorf("RCONBroadcastFormat(minecraft) = %q, want empty", got)
+	}
+}
+
+func TestValidateRCON(t *testing.T) {
+	tests := []struct {
+		name     string
+		rcon     RCONConfig
+		wantErrs int
+	}{
+		{name: "正常な設定", rcon: RCONConfig{Commands: []string{"list"}, Broadcast: "broadcast %s"}},
+		{name: "未指定", rcon: RCONConfig{}},
+		{name: "空のコマンド", rcon: RCONConfig{Commands: []string{"list", " "}}, wantErrs: 1},
+		{name: "書式に%sがない", rcon: RCONConfig{Broadcast: "say"}, wantErrs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := validateRCON("minecraft", tt.rcon); len(errs) != tt.wantErrs {
+				t.Errorf("validateRCON() errors = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxScheduleWarning は事前通知のタイミングの最大値
+const maxScheduleWarning = 24 * time.Hour
+
+// ScheduleConfig はサービスごとのスケジュール設定
+type ScheduleConfig struct {
+	// Restarts は定期再起動のスケジュール
+	Restarts []RestartSchedule `yaml:"restarts"`
+	// StopWindows はサービスを停止しておく時間帯
+	StopWindows []StopWindow `yaml:"stop_windows"`
+	// Warnings は再起動・停止の何分前に通知するか（未指定の場合はWATCHDOG_SCHEDULE_WARNINGS）
+	Warnings []time.Duration `yaml:"warnings"`
+}
+
+// RestartSchedule は定期再起動の設定
+type RestartSchedule struct {
+	// At は再起動する時刻
+	At ClockTime `yaml:"at"`
+	// Days は再起動する曜日（未指定の場合は毎日）
+	Days []Weekday `yaml:"days"`
+}
+
+// StopWindow はサービスを停止しておく時間帯の設定
+// ToがFrom以前の場合は日付をまたぐ時間帯として扱います
+type StopWindow struct {
+	// From は停止する時刻
+	From ClockTime `yaml:"from"`
+	// To は起動する時刻
+	To ClockTime `yaml:"to"`
+	// Days は停止を開始する曜日（未指定の場合は毎日）
+	Days []Weekday `yaml:"days"`
+}
+
+// IsEmpty はスケジュールが設定されていないかを返します
+func (s ScheduleConfig) IsEmpty() bool {
+	return len(s.Restarts) == 0 && len(s.StopWindows) == 0
+}
+
+// ClockTime は1日の中の時刻（HH:MM）
+type ClockTime struct {
+	Hour   int
+	Minute int
+}
+
+// ParseClockTime は "HH:MM" 形式の文字列を解析します
+func ParseClockTime(s string) (ClockTime, error) {
+	hour, minute, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return ClockTime{}, fmt.Errorf("invalid time %q (expected HH:MM)", s)
+	}
+	h, err := strconv.Atoi(hour)
+	if err != nil || h < 0 || h > 23 {
+		return ClockTime{}, fmt.Errorf("invalid hour in %q", s)
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil || len(minute) != 2 || m < 0 || m > 59 {
+		return ClockTime{}, fmt.Errorf("invalid minute in %q", s)
+	}
+	return ClockTime{Hour: h, Minute: m}, nil
+}
+
+// UnmarshalYAML は "HH:MM" 形式の文字列から時刻を読み込みます
+func (c *ClockTime) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseClockTime(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*c = parsed
+	return nil
+}
+
+// On は指定した日付の時刻を返します
+func (c ClockTime) On(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.Hour, c.Minute, 0, 0, day.Location())
+}
+
+// String は "HH:MM" 形式の文字列を返します
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", c.Hour, c.Minute)
+}
+
+// Weekday は曜日（設定ファイルでは mon, tue, ... で指定）
+type Weekday time.Weekday
+
+// weekdayNames は設定ファイルで使用する曜日名
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// UnmarshalYAML は曜日名から曜日を読み込みます
+func (w *Weekday) UnmarshalYAML(value *yaml.Node) error {
+	name := strings.ToLower(strings.TrimSpace(value.Value))
+	if len(name) > 3 {
+		name = name[:3]
+	}
+	day, ok := weekdayNames[name]
+	if !ok {
+		return fmt.Errorf("line %d: invalid weekday %q", value.Line, value.Value)
+	}
+	*w = Weekday(day)
+	return nil
+}
+
+// matchesDay は曜日の指定に一致するかを返します（未指定の場合は常に一致）
+func matchesDay(days []Weekday, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches は指定した曜日に再起動するかを返します
+func (r RestartSchedule) Matches(day time.Weekday) bool {
+	return matchesDay(r.Days, day)
+}
+
+// Matches は指定した曜日に停止を開始するかを返します
+func (w StopWindow) Matches(day time.Weekday) bool {
+	return matchesDay(w.Days, day)
+}
+
+// ScheduledServices はスケジュールが設定されているサービス名をソートして返します
+func (c *Config) ScheduledServices() []string {
+	var services []string
+	for _, name := range sortedServiceNames(c.Services) {
+		if !c.Services[name].Schedule.IsEmpty() {
+			services = append(services, name)
+		}
+	}
+	return services
+}
+
+// ServiceSchedule はサービスのスケジュールを返します
+// 事前通知のタイミングが未指定の場合はグローバル設定を使用します
+func (c *Config) ServiceSchedule(service string) ScheduleConfig {
+	schedule := c.Services[service].Schedule
+	if len(schedule.Warnings) == 0 {
+		schedule.Warnings = c.ScheduleWarnings
+	}
+	// 早い通知から順に並べる
+	warnings := append([]time.Duration(nil), schedule.Warnings...)
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i] > warnings[j] })
+	schedule.Warnings = warnings
+	return schedule
+}
+
+// validateWarnings は事前通知のタイミングを検証します
+func validateWarnings(name string, warnings []time.Duration) []error {
+	var errs []error
+	for _, w := range warnings {
+		if w <= 0 || w > maxScheduleWarning {
+			errs = append(errs, fmt.Errorf("%s must be between 0 and %v: %v", name, maxScheduleWarning, w))
+		}
+	}
+	return errs
+}
+
+// validateSchedule はサービスのスケジュール設定を検証します
+func validateSchedule(service string, schedule ScheduleConfig) []error {
+	errs := validateWarnings(fmt.Sprintf("services.%s.schedule.warnings", service), schedule.Warnings)
+	for i, w := range schedule.StopWindows {
+		if w.From == w.To {
+			errs = append(errs, fmt.Errorf("services.%s.schedule.stop_windows[%d]: from and to must differ",
+				service, i))
+		}
+	}
+	return errs
+}
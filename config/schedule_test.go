@@ -0,0 +1,190 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseClockTime(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ClockTime
+		wantErr bool
+	}{
+		{input: "03:00", want: ClockTime{Hour: 3, Minute: 0}},
+		{input: "23:59", want: ClockTime{Hour: 23, Minute: 59}},
+		{input: "7:30", want: ClockTime{Hour: 7, Minute: 30}},
+		{input: "24:00", wantErr: true},
+		{input: "12:60", wantErr: true},
+		{input: "12:5", wantErr: true},
+		{input: "1200", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseClockTime(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClockTime(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClockTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile_Schedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    ScheduleConfig
+		wantErr string
+	}{
+		{
+			name: "再起動と停止時間帯",
+			content: `
+services:
+  minecraft:
+    schedule:
+      restarts:
+        - at: "03:00"
+      stop_windows:
+        - from: "02:00"
+          to: "08:00"
+          days: [mon, tue, wed, thu, friday]
+      warnings: [5m, 30m]
+`,
+			want: ScheduleConfig{
+				Restarts: []RestartSchedule{{At: ClockTime{Hour: 3}}},
+				StopWindows: []StopWindow{{
+					From: ClockTime{Hour: 2},
+					To:   ClockTime{Hour: 8},
+					Days: []Weekday{
+						Weekday(time.Monday), Weekday(time.Tuesday), Weekday(time.Wednesday),
+						Weekday(time.Thursday), Weekday(time.Friday),
+					},
+				}},
+				Warnings: []time.Duration{5 * time.Minute, 30 * time.Minute},
+			},
+		},
+		{
+			name: "不正な時刻",
+			content: `
+services:
+  minecraft:
+    schedule:
+      restarts:
+        - at: "25:00"
+`,
+			wantErr: "invalid hour",
+		},
+		{
+			name: "不正な曜日",
+			content: `
+services:
+  minecraft:
+    schedule:
+      restarts:
+        - at: "03:00"
+          days: [someday]
+`,
+			wantErr: "invalid weekday",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc, err := loadFile(writeConfigFile(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadFile() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadFile() error = %v", err)
+			}
+			if got := fc.Services["minecraft"].Schedule; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ServiceSchedule(t *testing.T) {
+	cfg := &Config{
+		ScheduleWarnings: []time.Duration{time.Minute, 15 * time.Minute, 5 * time.Minute},
+		Services: map[string]ServiceConfig{
+			"minecraft": {Schedule: ScheduleConfig{
+				Restarts: []RestartSchedule{{At: ClockTime{Hour: 3}}},
+			}},
+			"terraria": {Schedule: ScheduleConfig{
+				Restarts: []RestartSchedule{{At: ClockTime{Hour: 4}}},
+				Warnings: []time.Duration{10 * time.Minute},
+			}},
+			"proxy": {},
+		},
+	}
+
+	if got, want := cfg.ScheduledServices(), []string{"minecraft", "terraria"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ScheduledServices() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		want    []time.Duration
+	}{
+		{
+			name:    "グローバル設定を降順で使用",
+			service: "minecraft",
+			want:    []time.Duration{15 * time.Minute, 5 * time.Minute, time.Minute},
+		},
+		{name: "サービスごとの設定", service: "terraria", want: []time.Duration{10 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.ServiceSchedule(tt.service).Warnings; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ServiceSchedule(%q).Warnings = %v, want %v", tt.service, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule ScheduleConfig
+		wantErrs int
+	}{
+		{
+			name: "正常な設定",
+			schedule: ScheduleConfig{
+				StopWindows: []StopWindow{{From: ClockTime{Hour: 22}, To: ClockTime{Hour: 6}}},
+				Warnings:    []time.Duration{time.Minute},
+			},
+		},
+		{
+			name:     "開始と終了が同じ時間帯",
+			schedule: ScheduleConfig{StopWindows: []StopWindow{{From: ClockTime{Hour: 2}, To: ClockTime{Hour: 2}}}},
+			wantErrs: 1,
+		},
+		{
+			name:     "範囲外の事前通知",
+			schedule: ScheduleConfig{Warnings: []time.Duration{0, 48 * time.Hour}},
+			wantErrs: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := validateSchedule("minecraft", tt.schedule); len(errs) != tt.wantErrs {
+				t.Errorf("validateSchedule() errors = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// StopConfig はサービスの停止・再起動の方法（未指定の項目はコンテナのラベルの値を使用）
+type StopConfig struct {
+	// Timeout は停止シグナルを送ってから強制終了するまでの時間
+	Timeout time.Duration `yaml:"timeout"`
+	// Signal は停止シグナル（例: SIGINT）
+	Signal string `yaml:"signal"`
+	// Exec は停止前にコンテナ内で実行するコマンド
+	Exec []string `yaml:"exec"`
+	// RCON は停止前にRCONで送信するコマンド（例: save-all）
+	RCON []string `yaml:"rcon"`
+}
+
+// StopSettings は指定されたサービスの設定ファイルの停止方法を返します
+func (c *Config) StopSettings(service string) docker.StopSettings {
+	stop := c.Services[service].Stop
+	return docker.StopSettings{
+		Timeout: stop.Timeout,
+		Signal:  stop.Signal,
+		Exec:    stop.Exec,
+		RCON:    stop.RCON,
+	}
+}
+
+// validateStop はサービスの停止方法の設定を検証します
+func validateStop(service string, stop StopConfig) []error {
+	var errs []error
+	if stop.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("services.%s.stop.timeout must not be negative: %v", service, stop.Timeout))
+	}
+	if stop.Signal != "" && strings.ContainsAny(stop.Signal, " \t") {
+		errs = append(errs, fmt.Errorf("services.%s.stop.signal is invalid: %q", service, stop.Signal))
+	}
+	for _, commands := range []struct {
+		name  string
+		value []string
+	}{
+		{"exec", stop.Exec},
+		{"rcon", stop.RCON},
+	} {
+		for i, command := range commands.value {
+			if strings.TrimSpace(command) == "" {
+				errs = append(errs, fmt.Errorf("services.%s.stop.%s[%d] must not be empty", service, commands.name, i))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+func TestConfig_StopSettings(t *testing.T) {
+	cfg := &Config{Services: map[string]ServiceConfig{
+		"minecraft": {Stop: StopConfig{
+			Timeout: 2 * time.Minute,
+			Signal:  "SIGINT",
+			RCON:    []string{"save-all"},
+		}},
+	}}
+
+	want := docker.StopSettings{Timeout: 2 * time.Minute, Signal: "SIGINT", RCON: []string{"save-all"}}
+	if got := cfg.StopSettings("minecraft"); !reflect.DeepEqual(got, want) {
+		t.Errorf("StopSettings(minecraft) = %+v, want %+v", got, want)
+	}
+	if got := cfg.StopSettings("terraria"); !reflect.DeepEqual(got, docker.StopSettings{}) {
+		t.Errorf("StopSettings(terraria) = %+v, want zero value", got)
+	}
+}
+
+func TestValidateStop(t *testing.T) {
+	tests := []struct {
+		name     string
+		stop     StopConfig
+		wantErrs int
+	}{
+		{name: "未設定", stop: StopConfig{}, wantErrs: 0},
+		{
+			name:     "有効な設定",
+			stop:     StopConfig{Timeout: time.Minute, Signal: "SIGINT", Exec: []string{"sync"}, RCON: []string{"stop"}},
+			wantErrs: 0,
+		},
+		{name: "負のタイムアウト", stop: StopConfig{Timeout: -time.Second}, wantErrs: 1},
+		{name: "不正なシグナル", stop: StopConfig{Signal: "SIG INT"}, wantErrs: 1},
+		{name: "空のコマンド", stop: StopConfig{Exec: []string{" "}, RCON: []string{"save-all", ""}}, wantErrs: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := validateStop("minecraft", tt.stop); len(errs) != tt.wantErrs {
+				t.Errorf("validateStop() = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
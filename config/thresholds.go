@@ -0,0 +1,94 @@
+package config
+
+import "fmt"
+
+const (
+	// DefaultCPUThreshold はCPU使用率のデフォルトアラート閾値
+	DefaultCPUThreshold = 85.0
+	// DefaultMemoryThreshold はメモリ使用率のデフォルトアラート閾値
+	DefaultMemoryThreshold = 90.0
+	// DefaultDiskThreshold はディスク使用率のデフォルトアラート閾値
+	DefaultDiskThreshold = 90.0
+)
+
+// Thresholds はアラート閾値（%）を表す構造体
+type Thresholds struct {
+	CPU    float64
+	Memory float64
+	Disk   float64
+}
+
+// ThresholdOverride は閾値の部分的な上書き設定（未指定の項目はnil）
+type ThresholdOverride struct {
+	CPU    *float64 `yaml:"cpu"`
+	Memory *float64 `yaml:"memory"`
+	Disk   *float64 `yaml:"disk"`
+}
+
+// HostThresholds はホストサーバーに適用するアラート閾値を返します
+// 未設定（0）の項目にはデフォルト値を使用します
+func (c *Config) HostThresholds() Thresholds {
+	return Thresholds{
+		CPU:    valueOrDefault(c.CPUAlertThreshold, DefaultCPUThreshold),
+		Memory: valueOrDefault(c.MemoryAlertThreshold, DefaultMemoryThreshold),
+		Disk:   valueOrDefault(c.DiskAlertThreshold, DefaultDiskThreshold),
+	}
+}
+
+// ServiceThresholds は指定されたサービスに適用するアラート閾値を返します
+// サービスごとの上書きがない項目にはグローバル閾値を使用します
+func (c *Config) ServiceThresholds(service string) Thresholds {
+	thresholds := c.HostThresholds()
+
+	override := c.Services[service].Thresholds
+	if override.CPU != nil {
+		thresholds.CPU = *override.CPU
+	}
+	if override.Memory != nil {
+		thresholds.Memory = *override.Memory
+	}
+	if override.Disk != nil {
+		thresholds.Disk = *override.Disk
+	}
+
+	return thresholds
+}
+
+// valueOrDefault は値が0の場合にデフォルト値を返します
+func valueOrDefault(value, defaultValue float64) float64 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// validateThreshold は閾値が0〜100の範囲内か検証します
+func validateThreshold(name string, value float64) error {
+	if value < 0 || value > 100 {
+		return fmt.Errorf("%s must be between 0 and 100: %v", name, value)
+	}
+	return nil
+}
+
+// validateOverride はサービスごとの閾値の上書き設定を検証します
+func validateOverride(service string, override ThresholdOverride) []error {
+	fields := []struct {
+		name  string
+		value *float64
+	}{
+		{"cpu", override.CPU},
+		{"memory", override.Memory},
+		{"disk", override.Disk},
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		if err := validateThreshold(fmt.Sprintf("services.%s.thresholds.%s", service, f.name), *f.value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,60 @@
+package config
+
+import "testing"
+
+func TestConfig_HostThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   Thresholds
+	}{
+		{
+			name:   "未設定の場合はデフォルト値",
+			config: Config{},
+			want:   Thresholds{CPU: DefaultCPUThreshold, Memory: DefaultMemoryThreshold, Disk: DefaultDiskThreshold},
+		},
+		{
+			name:   "設定値を使用",
+			config: Config{CPUAlertThreshold: 70, MemoryAlertThreshold: 80, DiskAlertThreshold: 95},
+			want:   Thresholds{CPU: 70, Memory: 80, Disk: 95},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.HostThresholds(); got != tt.want {
+				t.Errorf("HostThresholds() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ServiceThresholds(t *testing.T) {
+	cfg := &Config{
+		CPUAlertThreshold:    85,
+		MemoryAlertThreshold: 90,
+		DiskAlertThreshold:   90,
+		Services: map[string]ServiceConfig{
+			"minecraft": {Thresholds: ThresholdOverride{Memory: floatPtr(97)}},
+			"proxy":     {Thresholds: ThresholdOverride{CPU: floatPtr(50), Memory: floatPtr(70)}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		want    Thresholds
+	}{
+		{name: "メモリのみ上書き", service: "minecraft", want: Thresholds{CPU: 85, Memory: 97, Disk: 90}},
+		{name: "CPUとメモリを上書き", service: "proxy", want: Thresholds{CPU: 50, Memory: 70, Disk: 90}},
+		{name: "上書きなし", service: "terraria", want: Thresholds{CPU: 85, Memory: 90, Disk: 90}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.ServiceThresholds(tt.service); got != tt.want {
+				t.Errorf("ServiceThresholds(%q) = %+v, want %+v", tt.service, got, tt.want)
+			}
+		})
+	}
+}
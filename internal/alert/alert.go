@@ -0,0 +1,121 @@
+// Package alert はホストおよびコンテナのリソース使用率に対するアラート判定を提供します
+package alert
+
+import (
+	"strings"
+
+	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+const (
+	// ComponentHost はホストサーバーのアラートに使用するコンポーネント名
+	ComponentHost = "ホストサーバー"
+
+	// MessageHighCPU はCPU使用率のアラートメッセージ
+	MessageHighCPU = "CPU使用率が高い"
+	// MessageHighMemory はメモリ使用率のアラートメッセージ
+	MessageHighMemory = "メモリ使用率が高い"
+	// MessageHighDisk はディスク使用率のアラートメッセージ
+	MessageHighDisk = "ディスク使用率が高い"
+)
+
+// Alert はアラート情報を表す構造体
+type Alert struct {
+	Component string
+	Message   string
+	Value     float64
+}
+
+// Key はアラートを一意に識別するキーを返す
+func (a Alert) Key() string {
+	return a.Component + "/" + a.Message
+}
+
+// ThresholdProvider はホストおよびサービスごとのアラート閾値を返すインターフェース
+type ThresholdProvider interface {
+	// HostThresholds はホストサーバーの閾値を返す
+	HostThresholds() config.Thresholds
+	// ServiceThresholds は指定されたサービスの閾値を返す
+	ServiceThresholds(service string) config.Thresholds
+}
+
+// DefaultThresholds はデフォルトの閾値のみを返すThresholdProviderを返す
+func DefaultThresholds() ThresholdProvider {
+	return &config.Config{}
+}
+
+// Check はシステム情報とコンテナ統計情報から閾値を超えたアラートを返す
+func Check(sysInfo *system.SystemInfo, stats []docker.ContainerStats, provider ThresholdProvider) []Alert {
+	var alerts []Alert
+
+	// コンテナのアラートチェック
+	for i := range stats {
+		service := StatsService(&stats[i])
+		thresholds := provider.ServiceThresholds(service)
+		component := usermsg.FormatServiceName(service)
+		if stats[i].CPUPercent > thresholds.CPU {
+			alerts = append(alerts, Alert{
+				Component: component,
+				Message:   MessageHighCPU,
+				Value:     stats[i].CPUPercent,
+			})
+		}
+		if stats[i].MemoryPercent > thresholds.Memory {
+			alerts = append(alerts, Alert{
+				Component: component,
+				Message:   MessageHighMemory,
+				Value:     stats[i].MemoryPercent,
+			})
+		}
+	}
+
+	// ホストシステムのアラート
+	if sysInfo != nil {
+		thresholds := provider.HostThresholds()
+		if sysInfo.CPUUsagePercent > thresholds.CPU {
+			alerts = append(alerts, Alert{
+				Component: ComponentHost,
+				Message:   MessageHighCPU,
+				Value:     sysInfo.CPUUsagePercent,
+			})
+		}
+		if sysInfo.MemoryUsedPercent > thresholds.Memory {
+			alerts = append(alerts, Alert{
+				Component: ComponentHost,
+				Message:   MessageHighMemory,
+				Value:     sysInfo.MemoryUsedPercent,
+			})
+		}
+		if sysInfo.DiskUsedPercent > thresholds.Disk {
+			alerts = append(alerts, Alert{
+				Component: ComponentHost,
+				Message:   MessageHighDisk,
+				Value:     sysInfo.DiskUsedPercent,
+			})
+		}
+	}
+
+	return alerts
+}
+
+// StatsService は統計情報の対象サービス名を返す
+// サービス名のラベルが取得できていない場合はコンテナ名から推測する
+func StatsService(stats *docker.ContainerStats) string {
+	if stats.Service != "" {
+		return stats.Service
+	}
+	return ServiceFromContainerName(stats.Name)
+}
+
+// ServiceFromContainerName はコンテナ名からサービス名を抽出する
+func ServiceFromContainerName(containerName string) string {
+	// Docker Composeのコンテナ名は通常 "project_service_1" の形式
+	parts := strings.Split(containerName, "_")
+	if len(parts) >= 2 && parts[1] != "" {
+		return parts[1]
+	}
+	return containerName
+}
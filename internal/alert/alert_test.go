@@ -0,0 +1,126 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		sysInfo    *system.SystemInfo
+		stats      []docker.ContainerStats
+		thresholds ThresholdProvider
+		want       []Alert
+	}{
+		{
+			name: "デフォルト閾値を超えたホストとコンテナ",
+			sysInfo: &system.SystemInfo{
+				CPUUsagePercent:   90.0,
+				MemoryUsedPercent: 50.0,
+				DiskUsedPercent:   95.0,
+			},
+			stats: []docker.ContainerStats{
+				{Name: "project_minecraft_1", CPUPercent: 10.0, MemoryPercent: 92.0},
+			},
+			thresholds: DefaultThresholds(),
+			want: []Alert{
+				{Component: "Minecraft", Message: MessageHighMemory, Value: 92.0},
+				{Component: ComponentHost, Message: MessageHighCPU, Value: 90.0},
+				{Component: ComponentHost, Message: MessageHighDisk, Value: 95.0},
+			},
+		},
+		{
+			name: "カスタム閾値",
+			sysInfo: &system.SystemInfo{
+				CPUUsagePercent: 60.0,
+			},
+			thresholds: &config.Config{CPUAlertThreshold: 50.0, MemoryAlertThreshold: 100.0, DiskAlertThreshold: 100.0},
+			want: []Alert{
+				{Component: ComponentHost, Message: MessageHighCPU, Value: 60.0},
+			},
+		},
+		{
+			name: "サービスごとの閾値の上書き",
+			stats: []docker.ContainerStats{
+				{Name: "project-minecraft-1", Service: "minecraft", MemoryPercent: 92.0},
+				{Name: "project-proxy-1", Service: "proxy", MemoryPercent: 75.0},
+			},
+			thresholds: &config.Config{
+				Services: map[string]config.ServiceConfig{
+					"minecraft": {Thresholds: config.ThresholdOverride{Memory: floatPtr(95.0)}},
+					"proxy":     {Thresholds: config.ThresholdOverride{Memory: floatPtr(70.0)}},
+				},
+			},
+			want: []Alert{
+				{Component: "Proxy", Message: MessageHighMemory, Value: 75.0},
+			},
+		},
+		{
+			name:       "閾値と同じ値はアラートにならない",
+			sysInfo:    &system.SystemInfo{CPUUsagePercent: config.DefaultCPUThreshold},
+			thresholds: DefaultThresholds(),
+			want:       nil,
+		},
+		{
+			name:       "nilシステム情報",
+			sysInfo:    nil,
+			thresholds: DefaultThresholds(),
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Check(tt.sysInfo, tt.stats, tt.thresholds)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Check() returned %d alerts, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Check()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestAlert_Key(t *testing.T) {
+	a := Alert{Component: "Minecraft", Message: MessageHighCPU, Value: 90.0}
+	b := Alert{Component: "Minecraft", Message: MessageHighCPU, Value: 95.0}
+	c := Alert{Component: "Minecraft", Message: MessageHighMemory, Value: 90.0}
+
+	if a.Key() != b.Key() {
+		t.Errorf("Key() should not depend on value: %q != %q", a.Key(), b.Key())
+	}
+	if a.Key() == c.Key() {
+		t.Errorf("Key() should differ by message: %q", a.Key())
+	}
+}
+
+func TestServiceFromContainerName(t *testing.T) {
+	tests := []struct {
+		name          string
+		containerName string
+		want          string
+	}{
+		{name: "Compose形式のコンテナ名", containerName: "project_minecraft_1", want: "minecraft"},
+		{name: "区切りなし", containerName: "minecraft", want: "minecraft"},
+		{name: "サービス部分が空", containerName: "project__1", want: "project__1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ServiceFromContainerName(tt.containerName); got != tt.want {
+				t.Errorf("ServiceFromContainerName(%q) = %q, want %q", tt.containerName, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package audit はサービス操作の監査ログを提供します
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+// 操作の種類
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionRCON    = "rcon"
+)
+
+// 操作の実行元
+const (
+	SourceCommand    = "command"
+	SourceButton     = "button"
+	SourceScheduler  = "scheduler"
+	SourceSupervisor = "supervisor"
+	SourceIdle       = "idle"
+)
+
+// 操作の結果
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Actor は操作を実行したユーザー
+type Actor struct {
+	UserID    string `json:"user_id,omitempty"`
+	Username  string `json:"username"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
+// SystemActor はバックグラウンド処理による操作の実行者を返す
+func SystemActor(name string) Actor {
+	return Actor{Username: name}
+}
+
+// Entry は監査ログの1件分の記録
+type Entry struct {
+	Time time.Time `json:"time"`
+	Actor
+	Service    string `json:"service"`
+	Action     string `json:"action"`
+	Source     string `json:"source"`
+	Result     string `json:"result"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	// Detail は操作の詳細（RCONの場合は送信したコマンド）
+	Detail string `json:"detail,omitempty"`
+}
+
+// Recorder は監査ログを記録する
+type Recorder interface {
+	Record(ctx context.Context, e Entry) error
+}
+
+// Store は監査ログの記録と参照を行う
+type Store interface {
+	Recorder
+	// Recent は新しい順に最大n件の記録を返す（serviceが空の場合はすべてのサービス）
+	Recent(ctx context.Context, service string, n int) ([]Entry, error)
+}
+
+// Track は操作を実行して結果を記録する
+// recorderがnilの場合は記録せずに操作のみ実行する。記録の失敗は操作の結果に影響しない
+func Track(ctx context.Context, recorder Recorder, e Entry, op func() error) error {
+	start := time.Now()
+	err := op()
+	if recorder == nil {
+		return err
+	}
+
+	e.Time = start
+	e.DurationMS = time.Since(start).Milliseconds()
+	e.Result = ResultSuccess
+	if err != nil {
+		e.Result = ResultFailure
+		e.Error = err.Error()
+	}
+	if recErr := recorder.Record(ctx, e); recErr != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to record audit entry",
+			logging.String("service", e.Service),
+			logging.String("action", e.Action),
+			logging.ErrorField(recErr))
+	}
+	return err
+}
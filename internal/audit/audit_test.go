@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTrack(t *testing.T) {
+	tests := []struct {
+		name       string
+		opErr      error
+		wantResult string
+		wantError  string
+	}{
+		{name: "成功", wantResult: ResultSuccess},
+		{name: "失敗", opErr: errors.New("container not found"), wantResult: ResultFailure, wantError: "container not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &MockStore{}
+			entry := Entry{
+				Actor:   Actor{UserID: "user-1", Username: "alice", ChannelID: "channel-1"},
+				Service: "minecraft",
+				Action:  ActionRestart,
+				Source:  SourceCommand,
+			}
+
+			err := Track(context.Background(), store, entry, func() error { return tt.opErr })
+			if !errors.Is(err, tt.opErr) {
+				t.Errorf("Track() error = %v, want %v", err, tt.opErr)
+			}
+
+			entries := store.Entries()
+			if len(entries) != 1 {
+				t.Fatalf("recorded %d entries, want 1", len(entries))
+			}
+			got := entries[0]
+			if got.Result != tt.wantResult || got.Error != tt.wantError {
+				t.Errorf("entry result = %q, error = %q; want %q, %q", got.Result, got.Error, tt.wantResult, tt.wantError)
+			}
+			if got.Time.IsZero() || got.Username != "alice" || got.Service != "minecraft" {
+				t.Errorf("entry = %+v", got)
+			}
+		})
+	}
+}
+
+func TestTrack_RecorderFailureDoesNotAffectOperation(t *testing.T) {
+	store := &MockStore{Err: errors.New("disk full")}
+	called := false
+
+	err := Track(context.Background(), store, Entry{}, func() error {
+		called = true
+		return nil
+	})
+	if err != nil || !called {
+		t.Errorf("Track() error = %v, called = %v", err, called)
+	}
+}
+
+func TestTrack_NilRecorder(t *testing.T) {
+	opErr := errors.New("failed")
+	if err := Track(context.Background(), nil, Entry{}, func() error { return opErr }); !errors.Is(err, opErr) {
+		t.Errorf("Track() error = %v, want %v", err, opErr)
+	}
+}
@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// maxLineSize は読み込む1行の最大サイズ
+const maxLineSize = 1024 * 1024
+
+// FileLog はJSONL形式で追記する監査ログ
+type FileLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileLog は新しいFileLogを作成する
+func NewFileLog(path string) *FileLog {
+	return &FileLog{path: path}
+}
+
+// Record は記録をファイルの末尾に追記する
+func (l *FileLog) Record(_ context.Context, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// #nosec G304 - 監査ログのパスは管理者が環境変数で指定する
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// Recent は新しい順に最大n件の記録を返す（serviceが空の場合はすべてのサービス）
+// 読み込めない行は無視する。ctxがキャンセルされた場合は読み込みを中断する
+func (l *FileLog) Recent(ctx context.Context, service string, n int) ([]Entry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// #nosec G304 - 監査ログのパスは管理者が環境変数で指定する
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// 古い順に読みながら直近n件だけ保持する
+	ring := make([]Entry, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if service != "" && e.Service != service {
+			continue
+		}
+		if len(ring) == n {
+			ring = ring[1:]
+		}
+		ring = append(ring, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	entries := make([]Entry, len(ring))
+	for i := range ring {
+		entries[i] = ring[len(ring)-1-i]
+	}
+	return entries, nil
+}
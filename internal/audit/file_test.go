@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileLog_RecordAndRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewFileLog(path)
+	ctx := context.Background()
+	base := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	services := []string{"minecraft", "terraria", "minecraft", "minecraft"}
+	for i, service := range services {
+		e := Entry{
+			Time:    base.Add(time.Duration(i) * time.Minute),
+			Actor:   Actor{UserID: "user-1", Username: "alice"},
+			Service: service,
+			Action:  ActionRestart,
+			Source:  SourceCommand,
+			Result:  ResultSuccess,
+		}
+		if err := log.Record(ctx, e); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		service  string
+		n        int
+		wantMins []int
+	}{
+		{name: "すべてのサービス", n: 10, wantMins: []int{3, 2, 1, 0}},
+		{name: "件数を制限", n: 2, wantMins: []int{3, 2}},
+		{name: "サービスで絞り込み", service: "minecraft", n: 2, wantMins: []int{3, 2}},
+		{name: "一致なし", service: "rust", n: 10, wantMins: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := log.Recent(context.Background(), tt.service, tt.n)
+			if err != nil {
+				t.Fatalf("Recent() error = %v", err)
+			}
+			gotMins := []int{}
+			for _, e := range entries {
+				gotMins = append(gotMins, int(e.Time.Sub(base)/time.Minute))
+			}
+			if !reflect.DeepEqual(gotMins, tt.wantMins) {
+				t.Errorf("Recent() minutes = %v, want %v", gotMins, tt.wantMins)
+			}
+		})
+	}
+}
+
+func TestFileLog_Format(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewFileLog(path)
+
+	e := Entry{
+		Time:       time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC),
+		Actor:      Actor{UserID: "user-1", Username: "alice", ChannelID: "channel-1"},
+		Service:    "minecraft",
+		Action:     ActionStop,
+		Source:     SourceButton,
+		Result:     ResultFailure,
+		DurationMS: 1500,
+		Error:      "timeout",
+	}
+	if err := log.Record(context.Background(), e); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want := `{"time":"2025-01-20T12:00:00Z","user_id":"user-1","username":"alice","channel_id":"channel-1",` +
+		`"service":"minecraft","action":"stop","source":"button","result":"failure","duration_ms":1500,"error":"timeout"}` + "\n"
+	if string(data) != want {
+		t.Errorf("file content =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestFileLog_Recent_MissingFileAndBrokenLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewFileLog(path)
+
+	entries, err := log.Recent(context.Background(), "", 10)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Recent() on missing file = %v, %v", entries, err)
+	}
+
+	content := strings.Join([]string{
+		`{"service":"minecraft","action":"start"}`,
+		`not json`,
+		`{"service":"minecraft","action":"stop"}`,
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = log.Recent(context.Background(), "", 10)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != ActionStop || entries[1].Action != ActionStart {
+		t.Errorf("Recent() = %+v", entries)
+	}
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+// MockStore はテスト用のモック実装
+type MockStore struct {
+	mu      sync.Mutex
+	entries []Entry
+	Err     error
+}
+
+// Record は記録を保存する
+func (m *MockStore) Record(_ context.Context, e Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+// Recent は新しい順に最大n件の記録を返す
+func (m *MockStore) Recent(_ context.Context, service string, n int) ([]Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []Entry
+	for i := len(m.entries) - 1; i >= 0 && len(entries) < n; i-- {
+		if service == "" || m.entries[i].Service == service {
+			entries = append(entries, m.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+// Entries は記録されたすべての記録のコピーを古い順に返す
+func (m *MockStore) Entries() []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Entry(nil), m.entries...)
+}
@@ -4,11 +4,16 @@ package bot
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
 	"github.com/hideA88/game-server-watchdog/internal/bot/handler"
+	"github.com/hideA88/game-server-watchdog/internal/history"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
 	"github.com/hideA88/game-server-watchdog/pkg/logging"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
@@ -18,33 +23,62 @@ import (
 type Bot struct {
 	session *discordgo.Session
 	config  *config.Config
+	router  *handler.Router
+
+	// connected はDiscordのゲートウェイに接続しているか
+	connected atomic.Bool
+	// dashboardDone はダッシュボードの更新が停止すると閉じられる（更新していない場合はnil）
+	dashboardDone chan struct{}
 }
 
+// dashboardStopTimeout は停止時にダッシュボードの最後の編集を待つ時間の上限
+const dashboardStopTimeout = 10 * time.Second
+
 // New は新しいBotインスタンスを作成します
 func New(
 	ctx context.Context,
 	config *config.Config,
 	monitor system.Monitor,
 	compose docker.ComposeService,
+	locks *oplock.Locker,
+	auditLog audit.Store,
+	metricsHistory history.Reader,
 ) (*Bot, error) {
 	session, err := discordgo.New("Bot " + config.DiscordToken)
 	if err != nil {
 		return nil, fmt.Errorf("error creating Discord session: %w", err)
 	}
 
+	// ルーターを初期化して登録
+	router := handler.NewRouter(ctx, config, monitor, compose, locks, auditLog, metricsHistory,
+		&dashboardPublisher{session: session})
+	session.AddHandler(router.Handle)
+	session.AddHandler(router.HandleInteraction)
+
 	bot := &Bot{
 		session: session,
 		config:  config,
+		router:  router,
 	}
 
-	// ルーターを初期化して登録
-	router := handler.NewRouter(ctx, config, monitor, compose)
-	session.AddHandler(router.Handle)
-	session.AddHandler(router.HandleInteraction)
+	// ゲートウェイの接続状態を記録
+	session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Ready) { bot.connected.Store(true) })
+	session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Resumed) { bot.connected.Store(true) })
+	session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) { bot.connected.Store(false) })
 
 	return bot, nil
 }
 
+// Connected はDiscordのゲートウェイに接続しているかを返します
+func (b *Bot) Connected() bool {
+	return b.connected.Load()
+}
+
+// SetCommandObserver はコマンドの実行を記録するオブザーバーを設定します
+func (b *Bot) SetCommandObserver(observer handler.CommandObserver) {
+	b.router.SetCommandObserver(observer)
+}
+
 // Start starts the Discord bot session
 func (b *Bot) Start(ctx context.Context) error {
 	// セッションを開く
@@ -53,12 +87,55 @@ func (b *Bot) Start(ctx context.Context) error {
 	}
 
 	logger := logging.FromContext(ctx)
+
+	// スラッシュコマンドを登録（失敗してもメンションコマンドは使用できる）
+	if err := b.registerApplicationCommands(ctx); err != nil {
+		logger.Warn(ctx, "Failed to register slash commands", logging.ErrorField(err))
+	}
+
+	// ダッシュボードの定期的な更新を開始（ctxのキャンセルで停止する）
+	if dashboards := b.router.Dashboards(); dashboards != nil {
+		b.dashboardDone = make(chan struct{})
+		go func() {
+			defer close(b.dashboardDone)
+			dashboards.Run(ctx)
+		}()
+	}
+
 	logger.Info(ctx, "Bot is now running. Press CTRL-C to exit.")
 	return nil
 }
 
+// registerApplicationCommands はスラッシュコマンドを登録します
+// DISCORD_GUILD_IDが設定されている場合はそのサーバーに、それ以外はグローバルに登録します
+func (b *Bot) registerApplicationCommands(ctx context.Context) error {
+	if b.session.State == nil || b.session.State.User == nil {
+		return fmt.Errorf("bot user is not available")
+	}
+
+	commands := b.router.ApplicationCommands()
+	registered, err := b.session.ApplicationCommandBulkOverwrite(
+		b.session.State.User.ID, b.config.GuildID, commands, discordgo.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("error registering application commands: %w", err)
+	}
+
+	logging.FromContext(ctx).Info(ctx, "Registered slash commands",
+		logging.Int("count", len(registered)),
+		logging.String("guild_id", b.config.GuildID))
+	return nil
+}
+
 // Stop stops the Discord bot session
+// Startに渡したコンテキストをキャンセルしてから呼び出すと、ダッシュボードの最後の編集を待ってから閉じる
 func (b *Bot) Stop() {
+	if b.dashboardDone != nil {
+		select {
+		case <-b.dashboardDone:
+		case <-time.After(dashboardStopTimeout):
+		}
+	}
+
 	// Discordセッションを閉じる
 	_ = b.session.Close()
 }
@@ -0,0 +1,192 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/security"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+const (
+	// defaultAuditCount はデフォルトの表示件数
+	defaultAuditCount = 10
+	// maxAuditCount は最大表示件数
+	maxAuditCount = 50
+	// OptionCount は件数を指定するスラッシュコマンドのオプション名
+	OptionCount = "count"
+)
+
+// AuditCommand handles the audit command
+type AuditCommand struct {
+	store audit.Store
+}
+
+// NewAuditCommand creates a new AuditCommand
+// storeがnilの場合は監査ログが無効である旨を表示する
+func NewAuditCommand(store audit.Store) *AuditCommand {
+	return &AuditCommand{store: store}
+}
+
+// Name returns the command name
+func (c *AuditCommand) Name() string {
+	return "audit"
+}
+
+// Description returns the command description
+func (c *AuditCommand) Description() string {
+	return "サービスの起動・停止・再起動の履歴を表示"
+}
+
+// RequiredPermission returns the permission required to run the command
+func (c *AuditCommand) RequiredPermission(_ []string) permission.Requirement {
+	return permission.Requirement{Level: permission.LevelOperator}
+}
+
+// Options returns the slash command options
+func (c *AuditCommand) Options() []*discordgo.ApplicationCommandOption {
+	minCount := 1.0
+	service := serviceOption("履歴を表示するサービス名（省略時はすべて）")
+	service.Required = false
+	return []*discordgo.ApplicationCommandOption{
+		service,
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        OptionCount,
+			Description: fmt.Sprintf("表示する件数（最大%d件）", maxAuditCount),
+			MinValue:    &minCount,
+			MaxValue:    maxAuditCount,
+		},
+	}
+}
+
+// Execute runs the command
+func (c *AuditCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if c.store == nil {
+		return TextResponse("監査ログが無効です。`WATCHDOG_AUDIT_LOG` を設定してください。"), nil
+	}
+
+	service, count := parseAuditArgs(inv.Args)
+	entries, err := c.store.Recent(ctx, service, count)
+	if err != nil {
+		return nil, fmt.Errorf("監査ログの読み込みに失敗しました: %w", err)
+	}
+
+	var builder strings.Builder
+	if service != "" {
+		builder.WriteString(fmt.Sprintf("📜 **操作履歴: %s**（直近%d件）\n", FormatServiceName(service), count))
+	} else {
+		builder.WriteString(fmt.Sprintf("📜 **操作履歴**（直近%d件）\n", count))
+	}
+
+	if len(entries) == 0 {
+		builder.WriteString("記録はありません。")
+		return TextResponse(builder.String()), nil
+	}
+
+	for i := range entries {
+		line := formatAuditEntry(&entries[i])
+		// Discordのメッセージ上限を超える場合は古い記録を省略する
+		if builder.Len()+len(line) > maxTotalLength {
+			builder.WriteString("...")
+			break
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	return TextResponse(builder.String()), nil
+}
+
+// parseAuditArgs は引数からサービス名と件数を取り出す（順不同）
+func parseAuditArgs(args []string) (service string, count int) {
+	count = defaultAuditCount
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			count = min(max(n, 1), maxAuditCount)
+			continue
+		}
+		service = arg
+	}
+	return service, count
+}
+
+// formatAuditEntry は監査ログの1件を表示用に整形する
+func formatAuditEntry(e *audit.Entry) string {
+	icon := "✅"
+	if e.Result != audit.ResultSuccess {
+		icon = "❌"
+	}
+
+	actor := e.Username
+	if actor == "" {
+		actor = "不明なユーザー"
+	}
+
+	action := auditActionName(e.Action)
+	if e.Detail != "" {
+		action += fmt.Sprintf("「%s」", security.SanitizeForDiscord(e.Detail))
+	}
+	line := fmt.Sprintf("`%s` %s **%s** が %s を%s（%s, %.1f秒）",
+		e.Time.Local().Format("01/02 15:04:05"), icon,
+		security.SanitizeForDiscord(actor), FormatServiceName(e.Service),
+		action, auditSourceName(e.Source), float64(e.DurationMS)/1000)
+	if e.Error != "" {
+		line += ": " + security.SanitizeForDiscord(e.Error)
+	}
+	return line
+}
+
+// auditActionName は操作の表示名を返す
+func auditActionName(action string) string {
+	switch action {
+	case audit.ActionStart:
+		return "起動"
+	case audit.ActionStop:
+		return "停止"
+	case audit.ActionRestart:
+		return "再起動"
+	case audit.ActionRCON:
+		return "RCONで操作"
+	default:
+		return action
+	}
+}
+
+// auditSourceName は実行元の表示名を返す
+func auditSourceName(source string) string {
+	switch source {
+	case audit.SourceCommand:
+		return "コマンド"
+	case audit.SourceButton:
+		return "ボタン"
+	case audit.SourceScheduler:
+		return "スケジュール"
+	case audit.SourceSupervisor:
+		return "自動再起動"
+	case audit.SourceIdle:
+		return "自動停止"
+	default:
+		return source
+	}
+}
+
+// InteractionActor はインタラクションの実行者を監査ログ用に変換する
+func InteractionActor(i *discordgo.InteractionCreate) audit.Actor {
+	actor := audit.Actor{ChannelID: i.ChannelID}
+	var user *discordgo.User
+	if i.Member != nil && i.Member.User != nil {
+		user = i.Member.User
+	} else {
+		user = i.User
+	}
+	if user != nil {
+		actor.UserID = user.ID
+		actor.Username = user.Username
+	}
+	return actor
+}
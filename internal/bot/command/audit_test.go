@@ -0,0 +1,160 @@
+package command
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+)
+
+func TestAuditCommand_Execute(t *testing.T) {
+	store := &audit.MockStore{}
+	base := time.Date(2025, 1, 20, 12, 0, 0, 0, time.Local)
+	for i, e := range []audit.Entry{
+		{Actor: audit.Actor{Username: "alice"}, Service: "minecraft", Action: audit.ActionRestart,
+			Source: audit.SourceCommand, Result: audit.ResultSuccess, DurationMS: 2100},
+		{Actor: audit.SystemActor(audit.SourceScheduler), Service: "terraria", Action: audit.ActionStop,
+			Source: audit.SourceScheduler, Result: audit.ResultFailure, Error: "timeout"},
+		{Actor: audit.Actor{Username: "bob"}, Service: "minecraft", Action: audit.ActionRCON,
+			Source: audit.SourceCommand, Result: audit.ResultSuccess, DurationMS: 100, Detail: "whitelist add Steve_01"},
+	} {
+		e.Time = base.Add(time.Duration(i) * time.Minute)
+		_ = store.Record(context.Background(), e)
+	}
+
+	tests := []struct {
+		name        string
+		store       audit.Store
+		args        []string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "監査ログが無効",
+			store:    nil,
+			contains: []string{"監査ログが無効です"},
+		},
+		{
+			name:  "すべてのサービス",
+			store: store,
+			contains: []string{
+				"📜 **操作履歴**（直近10件）",
+				"`01/20 12:00:00` ✅ **alice** が Minecraft を再起動（コマンド, 2.1秒）",
+				"`01/20 12:01:00` ❌ **scheduler** が Terraria を停止（スケジュール, 0.0秒）: timeout",
+				"`01/20 12:02:00` ✅ **bob** が Minecraft をRCONで操作「whitelist add Steve\\_01」（コマンド, 0.1秒）",
+			},
+		},
+		{
+			name:        "サービスと件数を指定",
+			store:       store,
+			args:        []string{"minecraft", "5"},
+			contains:    []string{"📜 **操作履歴: Minecraft**（直近5件）", "alice"},
+			notContains: []string{"Terraria"},
+		},
+		{
+			name:     "記録なし",
+			store:    store,
+			args:     []string{"rust"},
+			contains: []string{"記録はありません"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewAuditCommand(tt.store)
+			got, err := runText(cmd, tt.args)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Execute() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Execute() = %q, should not contain %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditCommand_Execute_Truncates(t *testing.T) {
+	store := &audit.MockStore{}
+	for i := 0; i < maxAuditCount; i++ {
+		_ = store.Record(context.Background(), audit.Entry{
+			Actor:   audit.Actor{Username: strings.Repeat("a", 30)},
+			Service: "minecraft", Action: audit.ActionRestart, Result: audit.ResultSuccess,
+		})
+	}
+
+	got, err := runText(NewAuditCommand(store), []string{"50"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len([]rune(got)) > DiscordMessageLimit || !strings.HasSuffix(got, "...") {
+		t.Errorf("Execute() length = %d, want truncated output", len([]rune(got)))
+	}
+}
+
+func TestParseAuditArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantService string
+		wantCount   int
+	}{
+		{name: "引数なし", args: nil, wantService: "", wantCount: defaultAuditCount},
+		{name: "サービスのみ", args: []string{"minecraft"}, wantService: "minecraft", wantCount: defaultAuditCount},
+		{name: "件数のみ", args: []string{"20"}, wantService: "", wantCount: 20},
+		{name: "件数が先", args: []string{"5", "minecraft"}, wantService: "minecraft", wantCount: 5},
+		{name: "上限を超える件数", args: []string{"1000"}, wantService: "", wantCount: maxAuditCount},
+		{name: "0件", args: []string{"0"}, wantService: "", wantCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, count := parseAuditArgs(tt.args)
+			if service != tt.wantService || count != tt.wantCount {
+				t.Errorf("parseAuditArgs(%v) = %q, %d; want %q, %d", tt.args, service, count, tt.wantService, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestInteractionActor(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.Interaction
+		want        audit.Actor
+	}{
+		{
+			name: "サーバー内",
+			interaction: &discordgo.Interaction{
+				ChannelID: "channel-1",
+				Member:    &discordgo.Member{User: &discordgo.User{ID: "member-1", Username: "alice"}},
+			},
+			want: audit.Actor{UserID: "member-1", Username: "alice", ChannelID: "channel-1"},
+		},
+		{
+			name:        "DM",
+			interaction: &discordgo.Interaction{ChannelID: "dm-1", User: &discordgo.User{ID: "user-1", Username: "bob"}},
+			want:        audit.Actor{UserID: "user-1", Username: "bob", ChannelID: "dm-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InteractionActor(&discordgo.InteractionCreate{Interaction: tt.interaction})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InteractionActor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
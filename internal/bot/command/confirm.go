@@ -0,0 +1,394 @@
+package command
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// DefaultConfirmTimeout は確認ボタンのデフォルトの有効期限
+	DefaultConfirmTimeout = 30 * time.Second
+	// DefaultEmptyWaitTimeout はプレイヤーがいなくなってから操作する場合に待つ時間のデフォルトの上限
+	DefaultEmptyWaitTimeout = 30 * time.Minute
+
+	confirmActionPrefix = "confirm_action_"
+	cancelActionPrefix  = "cancel_action_"
+	emptyActionPrefix   = "confirm_empty_"
+
+	// maxPlayerNames は確認メッセージに表示するプレイヤー名の最大数
+	maxPlayerNames = 10
+)
+
+// confirmChoice は確認メッセージで選ばれたボタン
+type confirmChoice int
+
+const (
+	choiceCancel    confirmChoice = iota // キャンセル
+	choiceConfirm                        // すぐに実行
+	choiceWhenEmpty                      // プレイヤーがいなくなってから実行
+)
+
+// WaitFunc はゲームサーバーからプレイヤーがいなくなるまで待つ（ctxが終了した場合はエラーを返す）
+type WaitFunc func(ctx context.Context) error
+
+// ConfirmPolicy はサービスの停止・再起動に確認が必要かを判定する
+type ConfirmPolicy interface {
+	ConfirmationRequired(service string) bool
+}
+
+// pendingConfirmation は確認待ちの操作
+type pendingConfirmation struct {
+	service   string
+	action    string // 「停止」「再起動」などの操作名
+	req       permission.Requirement
+	userID    string // 確認を求めたユーザー（このユーザーのみボタンを操作できる）
+	expiresAt time.Time
+	run       func(ctx context.Context, actor audit.Actor) string
+	waitEmpty WaitFunc // nilの場合は「空になったら」のボタンを表示しない
+}
+
+// Confirmations は停止・再起動などの確認待ちの操作を管理する
+type Confirmations struct {
+	ctx          context.Context
+	policy       ConfirmPolicy
+	timeout      time.Duration
+	emptyTimeout time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*pendingConfirmation
+}
+
+// NewConfirmations は新しいConfirmationsを作成する
+// policyがnilの場合は確認を行わず、timeoutが0以下の場合はDefaultConfirmTimeoutを使用する
+// emptyTimeoutはプレイヤーがいなくなるまで待つ時間の上限（0以下の場合はDefaultEmptyWaitTimeout）
+func NewConfirmations(
+	ctx context.Context, policy ConfirmPolicy, timeout, emptyTimeout time.Duration,
+) *Confirmations {
+	if timeout <= 0 {
+		timeout = DefaultConfirmTimeout
+	}
+	if emptyTimeout <= 0 {
+		emptyTimeout = DefaultEmptyWaitTimeout
+	}
+	return &Confirmations{
+		ctx:          ctx,
+		policy:       policy,
+		timeout:      timeout,
+		emptyTimeout: emptyTimeout,
+		now:          time.Now,
+		pending:      make(map[string]*pendingConfirmation),
+	}
+}
+
+// Required は指定されたサービスの操作に確認が必要かを返す
+func (c *Confirmations) Required(service string) bool {
+	return c != nil && c.policy != nil && c.policy.ConfirmationRequired(service)
+}
+
+// Prompt は確認を求めるメッセージを返す
+// playersはゲームサーバーに問い合わせた接続中のプレイヤー（人数が分からない場合はnil）
+func (c *Confirmations) Prompt(service, action string, players *gameprobe.Status) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "⚠️ **%s** を%sしますか？ ", FormatServiceName(service), action)
+	switch {
+	case players == nil:
+		builder.WriteString("接続中のプレイヤーは切断されます。\n")
+	case players.Players == 0:
+		builder.WriteString("現在接続中のプレイヤーはいません。\n")
+	default:
+		fmt.Fprintf(&builder, "接続中のプレイヤー **%d人** が切断されます。\n", players.Players)
+		if names := formatPlayerNames(players); names != "" {
+			builder.WriteString("👥 " + names + "\n")
+		}
+		fmt.Fprintf(&builder, "「空になったら%s」を選ぶと、プレイヤーがいなくなってから%sします（最大%d分待機）。\n",
+			action, action, int(c.emptyTimeout/time.Minute))
+	}
+	fmt.Fprintf(&builder, "%d秒以内に確認してください。", int(c.timeout/time.Second))
+	return builder.String()
+}
+
+// formatPlayerNames はプレイヤー名を最大maxPlayerNames人まで列挙する
+func formatPlayerNames(players *gameprobe.Status) string {
+	names := players.PlayerNames
+	if len(names) > maxPlayerNames {
+		names = names[:maxPlayerNames]
+	}
+	list := strings.Join(names, ", ")
+	if rest := players.Players - len(names); rest > 0 && list != "" {
+		list += fmt.Sprintf(" ほか%d人", rest)
+	}
+	return list
+}
+
+// Request は確認待ちの操作を登録し、確認・キャンセルボタンを返す
+// ボタンはuserIDのユーザーのみ操作でき、他のユーザーが押した場合は本人にだけ見えるメッセージで断る
+// runは確認された時に確認したユーザーを実行者として、確認したインタラクションのロガーを引き継いだctxで実行され、結果のメッセージを返す
+// waitEmptyを指定した場合は、プレイヤーがいなくなるまで待ってから実行するボタンも返す
+func (c *Confirmations) Request(
+	service, action string,
+	req permission.Requirement,
+	userID string,
+	run func(ctx context.Context, actor audit.Actor) string,
+	waitEmpty WaitFunc,
+) ([]discordgo.MessageComponent, error) {
+	token, err := newConfirmToken()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for t, p := range c.pending {
+		if now.After(p.expiresAt) {
+			delete(c.pending, t)
+		}
+	}
+	c.pending[token] = &pendingConfirmation{
+		service:   service,
+		action:    action,
+		req:       req,
+		userID:    userID,
+		expiresAt: now.Add(c.timeout),
+		run:       run,
+		waitEmpty: waitEmpty,
+	}
+
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    fmt.Sprintf("%sする", action),
+			Style:    discordgo.DangerButton,
+			CustomID: confirmActionPrefix + token,
+		},
+	}
+	if waitEmpty != nil {
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("👥 空になったら%s", action),
+			Style:    discordgo.PrimaryButton,
+			CustomID: emptyActionPrefix + token,
+		})
+	}
+	buttons = append(buttons, discordgo.Button{
+		Label:    "キャンセル",
+		Style:    discordgo.SecondaryButton,
+		CustomID: cancelActionPrefix + token,
+	})
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}, nil
+}
+
+// CanHandle は指定されたカスタムIDを処理できるかどうかを返す
+func (c *Confirmations) CanHandle(customID string) bool {
+	return strings.HasPrefix(customID, confirmActionPrefix) || strings.HasPrefix(customID, cancelActionPrefix) ||
+		strings.HasPrefix(customID, emptyActionPrefix)
+}
+
+// RequiredInteractionPermission は確認待ちの操作と同じ権限を返す
+func (c *Confirmations) RequiredInteractionPermission(customID string) permission.Requirement {
+	token, _ := parseConfirmCustomID(customID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p, ok := c.pending[token]; ok {
+		return p.req
+	}
+	// 期限切れの場合はHandleInteractionで通知する
+	return permission.Requirement{Level: permission.LevelViewer}
+}
+
+// HandleInteraction は確認・キャンセルボタンを処理する
+func (c *Confirmations) HandleInteraction(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+) error {
+	token, choice := parseConfirmCustomID(i.MessageComponentData().CustomID)
+
+	if !c.requestedBy(token, InteractionActor(i).UserID) {
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "🚫 この確認は操作を実行したユーザーのみ使用できます。",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+
+	p, ok := c.take(token)
+	if !ok {
+		return updateMessage(s, i, "⌛ 確認の有効期限が切れました。もう一度操作してください。")
+	}
+	switch {
+	case choice == choiceCancel:
+		return updateMessage(s, i, fmt.Sprintf("↩️ %s の%sをキャンセルしました。", FormatServiceName(p.service), p.action))
+	case choice == choiceWhenEmpty && p.waitEmpty != nil:
+		return c.runWhenEmpty(ctx, s, i, p)
+	}
+
+	if err := updateMessage(s, i, fmt.Sprintf("⏳ %s を%sしています...", FormatServiceName(p.service), p.action)); err != nil {
+		return err
+	}
+
+	// 操作は応答後も続くため、インタラクションのキャンセルを引き継がない
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		content := p.run(ctx, InteractionActor(i))
+		if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{Content: content}); err != nil {
+			logging.FromContext(ctx).Error(ctx, "Failed to send followup message", logging.ErrorField(err))
+		}
+	}()
+	return nil
+}
+
+// runWhenEmpty はプレイヤーがいなくなるまで待ってから操作を実行し、結果をチャンネルに送信する
+// 待つ時間がインタラクションの有効期限（15分）を超える場合があるため、結果はフォローアップではなくチャンネルに送信する
+// 待つ時間はボットのコンテキストから数え、ログはインタラクションのロガーに記録する
+func (c *Confirmations) runWhenEmpty(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, p *pendingConfirmation,
+) error {
+	name := FormatServiceName(p.service)
+	minutes := int(c.emptyTimeout / time.Minute)
+	if err := updateMessage(s, i, fmt.Sprintf("⏳ %s のプレイヤーがいなくなるのを待っています（最大%d分）。いなくなったら%sします。",
+		name, minutes, p.action)); err != nil {
+		return err
+	}
+
+	actor := InteractionActor(i)
+	logger := logging.FromContext(ctx)
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		waitCtx, cancel := context.WithTimeout(logging.WithContext(c.ctx, logger), c.emptyTimeout)
+		defer cancel()
+
+		var content string
+		if err := p.waitEmpty(waitCtx); err != nil {
+			if c.ctx.Err() != nil {
+				return // ボットの停止中
+			}
+			content = fmt.Sprintf("⌛ %d分以内に %s のプレイヤーがいなくならなかったため、%sを中止しました。", minutes, name, p.action)
+		} else {
+			content = p.run(ctx, actor)
+		}
+		if _, err := s.ChannelMessageSend(i.ChannelID, content); err != nil {
+			logger.Error(ctx, "Failed to send message", logging.ErrorField(err))
+		}
+	}()
+	return nil
+}
+
+// ClearOnExpiry は確認メッセージを送信した後に呼び、確認されないまま有効期限が切れた場合に
+// 確認待ちの操作を取り消し、editでメッセージのボタンを取り除く
+func (c *Confirmations) ClearOnExpiry(components []discordgo.MessageComponent, edit MessageEditFunc) {
+	token := componentsToken(components)
+
+	c.mu.Lock()
+	p, ok := c.pending[token]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	time.AfterFunc(c.timeout, func() {
+		if c.ctx.Err() != nil {
+			return // ボットの停止中
+		}
+
+		c.mu.Lock()
+		_, ok := c.pending[token]
+		delete(c.pending, token)
+		c.mu.Unlock()
+		if !ok {
+			return // 確認・キャンセル済み
+		}
+
+		content := fmt.Sprintf("⌛ %s の%sの確認の有効期限が切れました。", FormatServiceName(p.service), p.action)
+		if err := edit(content); err != nil {
+			logging.FromContext(c.ctx).Warn(c.ctx, "Failed to clear expired confirmation", logging.ErrorField(err))
+		}
+	})
+}
+
+// requestedBy は確認待ちの操作をuserIDのユーザーが操作できるかどうかを返す
+// 期限切れなどで見つからない場合はHandleInteractionで通知するため操作できる扱いにする
+func (c *Confirmations) requestedBy(token, userID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pending[token]
+	return !ok || p.userID == "" || p.userID == userID
+}
+
+// take は確認待ちの操作を取り出す（期限切れの場合は見つからない扱い）
+func (c *Confirmations) take(token string) (*pendingConfirmation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pending[token]
+	if !ok {
+		return nil, false
+	}
+	delete(c.pending, token)
+	if c.now().After(p.expiresAt) {
+		return nil, false
+	}
+	return p, true
+}
+
+// parseConfirmCustomID はカスタムIDからトークンと選ばれたボタンを取り出す
+func parseConfirmCustomID(customID string) (string, confirmChoice) {
+	if token, ok := strings.CutPrefix(customID, confirmActionPrefix); ok {
+		return token, choiceConfirm
+	}
+	if token, ok := strings.CutPrefix(customID, emptyActionPrefix); ok {
+		return token, choiceWhenEmpty
+	}
+	return strings.TrimPrefix(customID, cancelActionPrefix), choiceCancel
+}
+
+// componentsToken はRequestが返したボタンからトークンを取り出す
+func componentsToken(components []discordgo.MessageComponent) string {
+	for _, component := range components {
+		row, ok := component.(discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, button := range row.Components {
+			if b, ok := button.(discordgo.Button); ok && strings.HasPrefix(b.CustomID, confirmActionPrefix) {
+				token, _ := parseConfirmCustomID(b.CustomID)
+				return token
+			}
+		}
+	}
+	return ""
+}
+
+// newConfirmToken はカスタムIDに使用するランダムなトークンを生成する
+func newConfirmToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// updateMessage はボタンを取り除いてメッセージを更新する
+func updateMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
@@ -0,0 +1,325 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+)
+
+// confirmPolicyFunc は関数をConfirmPolicyとして扱うためのアダプター
+type confirmPolicyFunc func(service string) bool
+
+func (f confirmPolicyFunc) ConfirmationRequired(service string) bool {
+	return f(service)
+}
+
+var onlyMinecraft = confirmPolicyFunc(func(service string) bool { return service == "minecraft" })
+
+// confirmButtons はRequestが返したボタンのカスタムIDを返す
+func confirmButtons(t *testing.T, components []discordgo.MessageComponent) (confirmID, cancelID string) {
+	t.Helper()
+
+	ids := buttonIDs(t, components)
+	if len(ids) != 2 {
+		t.Fatalf("unexpected components: %+v", components)
+	}
+	return ids[0], ids[1]
+}
+
+// buttonIDs はRequestが返したボタンのカスタムIDを順に返す
+func buttonIDs(t *testing.T, components []discordgo.MessageComponent) []string {
+	t.Helper()
+
+	if len(components) != 1 {
+		t.Fatalf("components = %d rows, want 1", len(components))
+	}
+	row, ok := components[0].(discordgo.ActionsRow)
+	if !ok {
+		t.Fatalf("unexpected components: %+v", components)
+	}
+	ids := make([]string, 0, len(row.Components))
+	for _, c := range row.Components {
+		ids = append(ids, c.(discordgo.Button).CustomID)
+	}
+	return ids
+}
+
+func TestConfirmations_Required(t *testing.T) {
+	var nilConfirmations *Confirmations
+	if nilConfirmations.Required("minecraft") {
+		t.Error("nil Confirmations should not require confirmation")
+	}
+	if NewConfirmations(context.Background(), nil, 0, 0).Required("minecraft") {
+		t.Error("Confirmations without policy should not require confirmation")
+	}
+
+	c := NewConfirmations(context.Background(), onlyMinecraft, 0, 0)
+	if !c.Required("minecraft") || c.Required("terraria") {
+		t.Error("Required() should follow the policy")
+	}
+}
+
+func TestConfirmations_Prompt(t *testing.T) {
+	c := NewConfirmations(context.Background(), onlyMinecraft, 45*time.Second, 20*time.Minute)
+
+	names := []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9", "p10", "p11"}
+	tests := []struct {
+		name        string
+		players     *gameprobe.Status
+		wantContain []string
+		wantMissing []string
+	}{
+		{
+			name:        "人数が分からない",
+			players:     nil,
+			wantContain: []string{"**Minecraft** を停止しますか？", "接続中のプレイヤーは切断されます。", "45秒以内"},
+			wantMissing: []string{"空になったら"},
+		},
+		{
+			name:        "プレイヤーがいない",
+			players:     &gameprobe.Status{MaxPlayers: 20},
+			wantContain: []string{"現在接続中のプレイヤーはいません。", "45秒以内"},
+			wantMissing: []string{"空になったら", "👥"},
+		},
+		{
+			name:    "プレイヤーがいる",
+			players: &gameprobe.Status{Players: 2, PlayerNames: []string{"Steve", "Alex"}},
+			wantContain: []string{
+				"接続中のプレイヤー **2人** が切断されます。", "👥 Steve, Alex\n", "「空になったら停止」", "最大20分", "45秒以内",
+			},
+			wantMissing: []string{"ほか"},
+		},
+		{
+			name:        "プレイヤー名の一部だけ分かる",
+			players:     &gameprobe.Status{Players: 5, PlayerNames: []string{"Steve"}},
+			wantContain: []string{"**5人**", "👥 Steve ほか4人"},
+		},
+		{
+			name:        "プレイヤー名が多い",
+			players:     &gameprobe.Status{Players: 11, PlayerNames: names},
+			wantContain: []string{"**11人**", "p10 ほか1人"},
+			wantMissing: []string{"p11"},
+		},
+		{
+			name:        "プレイヤー名が分からない",
+			players:     &gameprobe.Status{Players: 3},
+			wantContain: []string{"**3人**", "「空になったら停止」"},
+			wantMissing: []string{"👥"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Prompt("minecraft", "停止", tt.players)
+			for _, want := range tt.wantContain {
+				if !strings.Contains(got, want) {
+					t.Errorf("Prompt() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(got, missing) {
+					t.Errorf("Prompt() = %q, should not contain %q", got, missing)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmations_RequestAndTake(t *testing.T) {
+	c := NewConfirmations(context.Background(), onlyMinecraft, 30*time.Second, 0)
+	now := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	req := permission.Requirement{Level: permission.LevelOperator, Service: "minecraft"}
+	ran := false
+	components, err := c.Request("minecraft", "停止", req, "user-1", func(context.Context, audit.Actor) string {
+		ran = true
+		return "stopped"
+	}, nil)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+
+	confirmID, cancelID := confirmButtons(t, components)
+	for _, id := range []string{confirmID, cancelID} {
+		if !c.CanHandle(id) {
+			t.Errorf("CanHandle(%q) = false", id)
+		}
+		if got := c.RequiredInteractionPermission(id); got != req {
+			t.Errorf("RequiredInteractionPermission(%q) = %+v, want %+v", id, got, req)
+		}
+	}
+	if c.CanHandle("stop_service_minecraft") {
+		t.Error("CanHandle() should not handle other custom IDs")
+	}
+
+	token, choice := parseConfirmCustomID(confirmID)
+	if choice != choiceConfirm {
+		t.Errorf("parseConfirmCustomID(%q) choice = %v, want %v", confirmID, choice, choiceConfirm)
+	}
+	if cancelToken, choice := parseConfirmCustomID(cancelID); choice != choiceCancel || cancelToken != token {
+		t.Errorf("parseConfirmCustomID(%q) = %q, %v", cancelID, cancelToken, choice)
+	}
+
+	p, ok := c.take(token)
+	if !ok {
+		t.Fatal("take() did not find pending confirmation")
+	}
+	if p.run(context.Background(), audit.Actor{}) != "stopped" || !ran {
+		t.Error("run was not the registered function")
+	}
+
+	// 一度取り出した確認は再利用できない
+	if _, ok := c.take(token); ok {
+		t.Error("take() returned an already used confirmation")
+	}
+	if got := c.RequiredInteractionPermission(confirmID); got.Level != permission.LevelViewer {
+		t.Errorf("RequiredInteractionPermission() for unknown token = %+v", got)
+	}
+}
+
+func TestConfirmations_Expired(t *testing.T) {
+	c := NewConfirmations(context.Background(), onlyMinecraft, 30*time.Second, 0)
+	now := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	components, err := c.Request("minecraft", "再起動", permission.Requirement{}, "user-1", func(context.Context, audit.Actor) string { return "" }, nil)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	confirmID, _ := confirmButtons(t, components)
+	token, _ := parseConfirmCustomID(confirmID)
+
+	now = now.Add(31 * time.Second)
+	if _, ok := c.take(token); ok {
+		t.Error("take() returned an expired confirmation")
+	}
+
+	// 期限切れの確認は次の登録時に削除される
+	_, _ = c.Request("minecraft", "再起動", permission.Requirement{}, "user-1", func(context.Context, audit.Actor) string { return "" }, nil)
+	now = now.Add(time.Minute)
+	_, _ = c.Request("minecraft", "再起動", permission.Requirement{}, "user-1", func(context.Context, audit.Actor) string { return "" }, nil)
+	if got := len(c.pending); got != 1 {
+		t.Errorf("pending = %d, want 1", got)
+	}
+}
+
+func TestConfirmations_requestedBy(t *testing.T) {
+	c := NewConfirmations(context.Background(), onlyMinecraft, 30*time.Second, 0)
+
+	components, err := c.Request("minecraft", "停止", permission.Requirement{}, "user-1", func(context.Context, audit.Actor) string { return "" }, nil)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	token := componentsToken(components)
+
+	tests := []struct {
+		name   string
+		token  string
+		userID string
+		want   bool
+	}{
+		{name: "確認を求めたユーザー", token: token, userID: "user-1", want: true},
+		{name: "他のユーザー", token: token, userID: "user-2", want: false},
+		{name: "見つからない確認", token: "unknown", userID: "user-2", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.requestedBy(tt.token, tt.userID); got != tt.want {
+				t.Errorf("requestedBy(%q, %q) = %v, want %v", tt.token, tt.userID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmations_ClearOnExpiry(t *testing.T) {
+	c := NewConfirmations(context.Background(), onlyMinecraft, 10*time.Millisecond, 0)
+	run := func(context.Context, audit.Actor) string { return "" }
+
+	// 確認されないまま期限が切れた場合はボタンを取り除く
+	components, err := c.Request("minecraft", "停止", permission.Requirement{}, "user-1", run, nil)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	edited := make(chan string, 1)
+	c.ClearOnExpiry(components, func(content string) error {
+		edited <- content
+		return nil
+	})
+	select {
+	case content := <-edited:
+		if !strings.Contains(content, "有効期限が切れました") {
+			t.Errorf("expired content = %q", content)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expired confirmation was not cleared")
+	}
+	if _, ok := c.take(componentsToken(components)); ok {
+		t.Error("expired confirmation is still pending")
+	}
+
+	// 期限内に確認された場合はメッセージを変更しない
+	components, _ = c.Request("minecraft", "停止", permission.Requirement{}, "user-1", run, nil)
+	c.ClearOnExpiry(components, func(content string) error {
+		edited <- content
+		return nil
+	})
+	if _, ok := c.take(componentsToken(components)); !ok {
+		t.Fatal("take() did not find pending confirmation")
+	}
+	select {
+	case content := <-edited:
+		t.Errorf("confirmed message was edited: %q", content)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestConfirmations_RequestWithWaitEmpty(t *testing.T) {
+	c := NewConfirmations(context.Background(), onlyMinecraft, 30*time.Second, 0)
+
+	waited := false
+	wait := func(context.Context) error {
+		waited = true
+		return nil
+	}
+	components, err := c.Request("minecraft", "再起動", permission.Requirement{}, "user-1", func(context.Context, audit.Actor) string { return "" }, wait)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+
+	// 確認、空になったら、キャンセルの順に並ぶ
+	ids := buttonIDs(t, components)
+	if len(ids) != 3 {
+		t.Fatalf("buttons = %v, want 3", ids)
+	}
+	row := components[0].(discordgo.ActionsRow)
+	if label := row.Components[1].(discordgo.Button).Label; label != "👥 空になったら再起動" {
+		t.Errorf("wait button label = %q", label)
+	}
+	if !c.CanHandle(ids[1]) {
+		t.Errorf("CanHandle(%q) = false", ids[1])
+	}
+
+	token, choice := parseConfirmCustomID(ids[1])
+	if choice != choiceWhenEmpty {
+		t.Errorf("parseConfirmCustomID(%q) choice = %v, want %v", ids[1], choice, choiceWhenEmpty)
+	}
+	if confirmToken, _ := parseConfirmCustomID(ids[0]); confirmToken != token {
+		t.Errorf("tokens differ: %q, %q", confirmToken, token)
+	}
+
+	p, ok := c.take(token)
+	if !ok {
+		t.Fatal("take() did not find pending confirmation")
+	}
+	if p.waitEmpty == nil || p.waitEmpty(context.Background()) != nil || !waited {
+		t.Error("waitEmpty was not the registered function")
+	}
+}
@@ -2,44 +2,54 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/alert"
 	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
 )
 
 const (
 	// defaultComposePath はデフォルトのdocker-compose.ymlのパス
 	defaultComposePath = "docker-compose.yml"
-	// containerStateRunning は実行中のコンテナの状態
-	containerStateRunning = "running"
-	// containerMemoryZero はメモリ使用量が0の場合の表示
-	containerMemoryZero = "0B / 0B"
 	// defaultLogLines はデフォルトのログ行数
 	defaultLogLines = 10
-	// cpuHighThreshold はCPU使用率の高負荷閾値
-	cpuHighThreshold = 85.0
-	// memoryHighThreshold はメモリ使用率の高負荷閾値
-	memoryHighThreshold = 90.0
 	// maxLogLineLength は1行の最大文字数
 	maxLogLineLength = 80
+	// containerUsage はcontainerコマンドの使用方法
+	containerUsage = "使用方法: `@bot container <サービス名>`"
 )
 
 // ContainerCommand handles the container command
 type ContainerCommand struct {
 	compose     docker.ComposeService
 	composePath string
+	thresholds  alert.ThresholdProvider
 }
 
 // NewContainerCommand creates a new ContainerCommand
-func NewContainerCommand(compose docker.ComposeService, composePath string) *ContainerCommand {
+func NewContainerCommand(
+	compose docker.ComposeService,
+	composePath string,
+	thresholds alert.ThresholdProvider,
+) *ContainerCommand {
 	if composePath == "" {
 		composePath = defaultComposePath
 	}
+	if thresholds == nil {
+		thresholds = alert.DefaultThresholds()
+	}
 	return &ContainerCommand{
 		compose:     compose,
 		composePath: composePath,
+		thresholds:  thresholds,
 	}
 }
 
@@ -53,14 +63,26 @@ func (c *ContainerCommand) Description() string {
 	return "個別コンテナの詳細情報を表示"
 }
 
-// Execute runs the command
-func (c *ContainerCommand) Execute(args []string) (string, error) {
+// RequiredPermission returns the permission required to run the command
+func (c *ContainerCommand) RequiredPermission(args []string) permission.Requirement {
+	return serviceRequirement(permission.LevelViewer, args)
+}
+
+// Options returns the slash command options
+func (c *ContainerCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		serviceOption("詳細を表示するサービス名"),
+	}
+}
+
+// executeText は実行結果をテキストで返す
+func (c *ContainerCommand) executeText(ctx context.Context, args []string) (string, error) {
 	if len(args) == 0 {
-		return "使用方法: `@bot container <サービス名>`", nil
+		return containerUsage, nil
 	}
 
 	serviceName := args[0]
-	targetContainer, err := c.findContainer(serviceName)
+	targetContainer, err := c.findContainer(ctx, serviceName)
 	if err != nil {
 		return "", err
 	}
@@ -75,12 +97,12 @@ func (c *ContainerCommand) Execute(args []string) (string, error) {
 	c.addBasicInfo(&builder, targetContainer)
 
 	// 実行中の場合はリソース使用状況を表示
-	if strings.EqualFold(targetContainer.State, containerStateRunning) {
-		c.addResourceInfo(&builder, targetContainer)
+	if strings.EqualFold(targetContainer.State, docker.StateRunning) {
+		c.addResourceInfo(ctx, &builder, targetContainer)
 	}
 
 	// 最近のログを追加
-	c.addRecentLogs(&builder, serviceName)
+	c.addRecentLogs(ctx, &builder, serviceName)
 
 	// 使用可能なコマンドを追加
 	c.addAvailableCommands(&builder, serviceName, targetContainer.State)
@@ -89,8 +111,8 @@ func (c *ContainerCommand) Execute(args []string) (string, error) {
 }
 
 // findContainer は指定されたサービス名のコンテナを検索する
-func (c *ContainerCommand) findContainer(serviceName string) (*docker.ContainerInfo, error) {
-	containers, err := c.compose.ListContainers(c.composePath)
+func (c *ContainerCommand) findContainer(ctx context.Context, serviceName string) (*docker.ContainerInfo, error) {
+	containers, err := c.compose.ListContainers(ctx, c.composePath)
 	if err != nil {
 		if docker.IsPermissionDenied(err) {
 			return nil, fmt.Errorf("docker権限エラー: %s", usermsg.DockerPermissionMessage())
@@ -132,8 +154,9 @@ func (c *ContainerCommand) addBasicInfo(builder *strings.Builder, container *doc
 }
 
 // addResourceInfo はリソース使用状況を追加する
-func (c *ContainerCommand) addResourceInfo(builder *strings.Builder, container *docker.ContainerInfo) {
-	stats, err := c.compose.GetContainerStats(container.Name)
+func (c *ContainerCommand) addResourceInfo(
+	ctx context.Context, builder *strings.Builder, container *docker.ContainerInfo) {
+	stats, err := c.containerStats(ctx, container)
 	if err != nil {
 		return
 	}
@@ -141,60 +164,183 @@ func (c *ContainerCommand) addResourceInfo(builder *strings.Builder, container *
 	builder.WriteString("\n**リソース使用状況**\n")
 	fmt.Fprintf(builder, "- CPU使用率: %.1f%%\n", stats.CPUPercent)
 	fmt.Fprintf(builder, "- メモリ使用率: %.1f%%\n", stats.MemoryPercent)
-	fmt.Fprintf(builder, "- メモリ使用量: %s\n", stats.MemoryUsage)
+	fmt.Fprintf(builder, "- メモリ使用量: %s\n", usermsg.FormatMemoryUsage(stats.MemoryUsageBytes, stats.MemoryLimitBytes))
 
-	if stats.NetworkIO != "" && stats.NetworkIO != containerMemoryZero {
-		fmt.Fprintf(builder, "- ネットワークI/O: %s\n", stats.NetworkIO)
+	if stats.NetworkRxBytes > 0 || stats.NetworkTxBytes > 0 {
+		fmt.Fprintf(builder, "- ネットワークI/O: %s\n", usermsg.FormatIO(stats.NetworkRxBytes, stats.NetworkTxBytes))
 	}
-	if stats.BlockIO != "" && stats.BlockIO != containerMemoryZero {
-		fmt.Fprintf(builder, "- ブロックI/O: %s\n", stats.BlockIO)
+	if stats.BlockReadBytes > 0 || stats.BlockWriteBytes > 0 {
+		fmt.Fprintf(builder, "- ブロックI/O: %s\n", usermsg.FormatIO(stats.BlockReadBytes, stats.BlockWriteBytes))
+	}
+	if stats.PIDs > 0 {
+		fmt.Fprintf(builder, "- プロセス数: %d\n", stats.PIDs)
+	}
+	if stats.RestartCount > 0 {
+		fmt.Fprintf(builder, "- 再起動回数: %d\n", stats.RestartCount)
 	}
 
 	// 高負荷警告
-	if stats.CPUPercent > cpuHighThreshold || stats.MemoryPercent > memoryHighThreshold {
+	thresholds := c.thresholds.ServiceThresholds(container.Service)
+	if stats.CPUPercent > thresholds.CPU || stats.MemoryPercent > thresholds.Memory {
 		builder.WriteString("\n⚠️ **警告**\n")
-		if stats.CPUPercent > cpuHighThreshold {
+		if stats.CPUPercent > thresholds.CPU {
 			fmt.Fprintf(builder, "- CPU使用率が高い状態です (%.1f%%)\n", stats.CPUPercent)
 		}
-		if stats.MemoryPercent > memoryHighThreshold {
+		if stats.MemoryPercent > thresholds.Memory {
 			fmt.Fprintf(builder, "- メモリ使用率が高い状態です (%.1f%%)\n", stats.MemoryPercent)
 		}
 	}
 }
 
 // addRecentLogs は最近のログを追加する
-func (c *ContainerCommand) addRecentLogs(builder *strings.Builder, serviceName string) {
+func (c *ContainerCommand) addRecentLogs(ctx context.Context, builder *strings.Builder, serviceName string) {
 	builder.WriteString("\n**最近のログ** (最後の10行)\n")
 	builder.WriteString("```\n")
 
-	logs, err := c.compose.GetContainerLogs(c.composePath, serviceName, defaultLogLines)
+	logs, err := c.compose.GetContainerLogs(ctx, c.composePath, serviceName, defaultLogLines)
 	if err != nil {
+		logFetchFailure(ctx, serviceName, err)
 		builder.WriteString("ログの取得に失敗しました\n")
 	} else {
-		// ログが長すぎる場合は切り詰める
-		logLines := strings.Split(strings.TrimSpace(logs), "\n")
-		for i, line := range logLines {
-			if i >= defaultLogLines {
-				break
-			}
-			// 各行を最大80文字に制限
-			if len(line) > maxLogLineLength {
-				line = line[:maxLogLineLength-3] + "..."
-			}
-			builder.WriteString(line + "\n")
-		}
+		builder.WriteString(recentLogLines(logs))
 	}
 
 	builder.WriteString("```\n")
 }
 
+// recentLogLines はログを最大10行・1行80文字に切り詰めて返す
+func recentLogLines(logs string) string {
+	var builder strings.Builder
+	logLines := strings.Split(strings.TrimSpace(logs), "\n")
+	for i, line := range logLines {
+		if i >= defaultLogLines {
+			break
+		}
+		// 各行を最大80文字に制限
+		if len(line) > maxLogLineLength {
+			line = line[:maxLogLineLength-3] + "..."
+		}
+		builder.WriteString(line + "\n")
+	}
+	return builder.String()
+}
+
 // addAvailableCommands は使用可能なコマンドを追加する
 func (c *ContainerCommand) addAvailableCommands(builder *strings.Builder, serviceName, state string) {
 	builder.WriteString("\n**使用可能なコマンド**\n")
-	if strings.EqualFold(state, containerStateRunning) {
-		builder.WriteString("- `@bot restart " + serviceName + "` - コンテナを再起動\n")
-		builder.WriteString("- `@bot logs " + serviceName + " [行数]` - より多くのログを表示\n")
+	builder.WriteString(availableCommands(serviceName, state))
+}
+
+// availableCommands はコンテナの状態に応じて使用可能なコマンドを1行ずつ返す
+func availableCommands(serviceName, state string) string {
+	if strings.EqualFold(state, docker.StateRunning) {
+		return "- `@bot restart " + serviceName + "` - コンテナを再起動\n" +
+			"- `@bot logs " + serviceName + " [行数]` - より多くのログを表示\n"
+	}
+	return "- `@bot monitor` から起動ボタンを使用してコンテナを起動\n"
+}
+
+// Execute はコンテナの詳細情報を埋め込みで返す
+// 埋め込みの色はコンテナの状態とヘルスチェック、リソース使用率から判定する
+func (c *ContainerCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if len(inv.Args) == 0 {
+		return TextResponse(containerUsage), nil
+	}
+
+	serviceName := inv.Args[0]
+	target, err := c.findContainer(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return TextResponse(fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName)), nil
+	}
+
+	health := containerHealth(target)
+	embed := newEmbed(fmt.Sprintf("📦 %s の詳細情報", FormatServiceName(serviceName)), health)
+
+	// 基本情報
+	addEmbedField(embed, "状態", GetStatusIcon(target.State)+" "+target.State, true)
+	if target.RunningFor != "" {
+		addEmbedField(embed, "稼働時間", target.RunningFor, true)
+	}
+	if target.HealthStatus != "" && target.HealthStatus != "none" {
+		addEmbedField(embed, "ヘルス", GetHealthIcon(target.HealthStatus)+" "+target.HealthStatus, true)
+	}
+	addEmbedField(embed, "コンテナ名", target.Name, false)
+	if len(target.Ports) > 0 {
+		addEmbedField(embed, "ポート", strings.Join(target.Ports, ", "), false)
+	}
+
+	// 実行中の場合はリソース使用状況を表示
+	if strings.EqualFold(target.State, docker.StateRunning) {
+		if c.addResourceFields(ctx, embed, target) {
+			health = max(health, HealthWarning)
+		}
+	}
+
+	// 最近のログ
+	if logs, err := c.compose.GetContainerLogs(ctx, c.composePath, serviceName, defaultLogLines); err != nil {
+		logFetchFailure(ctx, serviceName, err)
+		addEmbedField(embed, "最近のログ", "ログの取得に失敗しました", false)
 	} else {
-		builder.WriteString("- `@bot monitor` から起動ボタンを使用してコンテナを起動\n")
+		addEmbedField(embed, "最近のログ", codeBlock(recentLogLines(logs), embedFieldValueLimit), false)
+	}
+
+	addEmbedField(embed, "使用可能なコマンド", availableCommands(serviceName, target.State), false)
+
+	embed.Color = health.Color()
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: "コンテナID: " + target.ID[:min(len(target.ID), 12)]}
+	return &Response{Embeds: []*discordgo.MessageEmbed{embed}}, nil
+}
+
+// addResourceFields はリソース使用状況のフィールドを追加し、閾値を超えている場合はtrueを返す
+// 統計情報を取得できなかった場合は何も追加しない
+func (c *ContainerCommand) addResourceFields(
+	ctx context.Context, embed *discordgo.MessageEmbed, container *docker.ContainerInfo) bool {
+	stats, err := c.containerStats(ctx, container)
+	if err != nil {
+		return false
+	}
+
+	addEmbedField(embed, "CPU使用率", fmt.Sprintf("%.1f%%", stats.CPUPercent), true)
+	addEmbedField(embed, "メモリ使用量", fmt.Sprintf("%s (%.1f%%)",
+		usermsg.FormatMemoryUsage(stats.MemoryUsageBytes, stats.MemoryLimitBytes), stats.MemoryPercent), true)
+	if stats.NetworkRxBytes > 0 || stats.NetworkTxBytes > 0 {
+		addEmbedField(embed, "ネットワークI/O", usermsg.FormatIO(stats.NetworkRxBytes, stats.NetworkTxBytes), true)
+	}
+	if stats.BlockReadBytes > 0 || stats.BlockWriteBytes > 0 {
+		addEmbedField(embed, "ブロックI/O", usermsg.FormatIO(stats.BlockReadBytes, stats.BlockWriteBytes), true)
+	}
+	if stats.PIDs > 0 {
+		addEmbedField(embed, "プロセス数", strconv.FormatUint(stats.PIDs, 10), true)
+	}
+	if stats.RestartCount > 0 {
+		addEmbedField(embed, "再起動回数", strconv.Itoa(stats.RestartCount), true)
+	}
+
+	// 高負荷警告
+	thresholds := c.thresholds.ServiceThresholds(container.Service)
+	var warnings []string
+	if stats.CPUPercent > thresholds.CPU {
+		warnings = append(warnings, fmt.Sprintf("- CPU使用率が高い状態です (%.1f%%)", stats.CPUPercent))
+	}
+	if stats.MemoryPercent > thresholds.Memory {
+		warnings = append(warnings, fmt.Sprintf("- メモリ使用率が高い状態です (%.1f%%)", stats.MemoryPercent))
+	}
+	if len(warnings) > 0 {
+		addEmbedField(embed, "⚠️ 警告", strings.Join(warnings, "\n"), false)
+	}
+	return len(warnings) > 0
+}
+
+// containerStats はコンテナの統計情報を取得する（失敗した場合はリクエストのロガーに記録する）
+func (c *ContainerCommand) containerStats(
+	ctx context.Context, container *docker.ContainerInfo) (*docker.ContainerStats, error) {
+	stats, err := c.compose.GetContainerStats(ctx, container.Name)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to get container stats",
+			logging.String("service", container.Service), logging.ErrorField(err))
 	}
+	return stats, err
 }
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -9,14 +10,14 @@ import (
 )
 
 func TestContainerCommand_Name(t *testing.T) {
-	cmd := NewContainerCommand(&docker.MockComposeService{}, "")
+	cmd := NewContainerCommand(&docker.MockComposeService{}, "", nil)
 	if got := cmd.Name(); got != "container" {
 		t.Errorf("ContainerCommand.Name() = %v, want %v", got, "container")
 	}
 }
 
 func TestContainerCommand_Description(t *testing.T) {
-	cmd := NewContainerCommand(&docker.MockComposeService{}, "")
+	cmd := NewContainerCommand(&docker.MockComposeService{}, "", nil)
 	if got := cmd.Description(); got != "個別コンテナの詳細情報を表示" {
 		t.Errorf("ContainerCommand.Description() = %v, want %v", got, "個別コンテナの詳細情報を表示")
 	}
@@ -43,14 +44,14 @@ func TestNewContainerCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCompose := &docker.MockComposeService{}
-			cmd := NewContainerCommand(mockCompose, tt.composePath)
+			cmd := NewContainerCommand(mockCompose, tt.composePath, nil)
 
 			if cmd.composePath != tt.expected {
-				t.Errorf("NewContainerCommand() composePath = %v, want %v", cmd.composePath, tt.expected)
+				t.Errorf("NewContainerCommand(, nil) composePath = %v, want %v", cmd.composePath, tt.expected)
 			}
 
 			if cmd.compose != mockCompose {
-				t.Error("NewContainerCommand() compose service not set correctly")
+				t.Error("NewContainerCommand(, nil) compose service not set correctly")
 			}
 		})
 	}
@@ -90,11 +91,16 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    45.2,
-				MemoryPercent: 67.8,
-				MemoryUsage:   "2.1GiB / 4.0GiB",
-				NetworkIO:     "1.2MB / 800KB",
-				BlockIO:       "500KB / 1.5MB",
+				CPUPercent:       45.2,
+				MemoryPercent:    67.8,
+				MemoryUsageBytes: 2 * 1024 * 1024 * 1024,
+				MemoryLimitBytes: 4 * 1024 * 1024 * 1024,
+				NetworkRxBytes:   1258291,
+				NetworkTxBytes:   819200,
+				BlockReadBytes:   512000,
+				BlockWriteBytes:  1572864,
+				PIDs:             24,
+				RestartCount:     3,
 			},
 			containerLogs: "2023-01-01 12:00:00 INFO Starting application\n" +
 				"2023-01-01 12:00:01 INFO Server listening on port 8080",
@@ -110,9 +116,11 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				"**リソース使用状況**",
 				"- CPU使用率: 45.2%",
 				"- メモリ使用率: 67.8%",
-				"- メモリ使用量: 2.1GiB / 4.0GiB",
-				"- ネットワークI/O: 1.2MB / 800KB",
-				"- ブロックI/O: 500KB / 1.5MB",
+				"- メモリ使用量: 2.00GiB / 4.00GiB",
+				"- ネットワークI/O: 1.2MB / 800.0KB",
+				"- ブロックI/O: 500.0KB / 1.5MB",
+				"- プロセス数: 24",
+				"- 再起動回数: 3",
 				"**最近のログ**",
 				"INFO Starting application",
 				"**使用可能なコマンド**",
@@ -154,9 +162,9 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    92.5,
-				MemoryPercent: 95.3,
-				MemoryUsage:   "7.8GiB / 8.0GiB",
+				CPUPercent:       92.5,
+				MemoryPercent:    95.3,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			containerLogs: "Worker processing tasks",
 			expectedContains: []string{
@@ -178,9 +186,9 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    5.0,
-				MemoryPercent: 30.0,
-				MemoryUsage:   "500MB / 1.5GB",
+				CPUPercent:       5.0,
+				MemoryPercent:    30.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			containerLogs: "Redis server started",
 			expectedNotContains: []string{
@@ -200,9 +208,9 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    10.0,
-				MemoryPercent: 25.0,
-				MemoryUsage:   "300MB / 1GB",
+				CPUPercent:       10.0,
+				MemoryPercent:    25.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			containerLogs: "Queue worker started",
 			expectedNotContains: []string{
@@ -256,9 +264,9 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    20.0,
-				MemoryPercent: 40.0,
-				MemoryUsage:   "1GB / 2GB",
+				CPUPercent:       20.0,
+				MemoryPercent:    40.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			logsError: errors.New("logs not available"),
 			expectedContains: []string{
@@ -277,9 +285,9 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    15.0,
-				MemoryPercent: 35.0,
-				MemoryUsage:   "800MB / 2GB",
+				CPUPercent:       15.0,
+				MemoryPercent:    35.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			containerLogs: strings.Repeat(
 				"Very long log line that exceeds the maximum allowed length and should be truncated",
@@ -301,9 +309,9 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			},
 			containerStats: &docker.ContainerStats{
-				CPUPercent:    30.0,
-				MemoryPercent: 50.0,
-				MemoryUsage:   "1.5GB / 3GB",
+				CPUPercent:       30.0,
+				MemoryPercent:    50.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			containerLogs: "Web server v2 started",
 			expectedContains: []string{
@@ -326,8 +334,8 @@ func TestContainerCommand_Execute(t *testing.T) { // テーブル駆動テスト
 				},
 			}
 
-			cmd := NewContainerCommand(mockCompose, "test-compose.yml")
-			result, err := cmd.Execute(tt.args)
+			cmd := NewContainerCommand(mockCompose, "test-compose.yml", nil)
+			result, err := cmd.executeText(context.Background(), tt.args)
 
 			if tt.expectError {
 				if err == nil {
@@ -398,8 +406,8 @@ func TestContainerCommand_findContainer(t *testing.T) { // テーブル駆動テ
 				},
 			}
 
-			cmd := NewContainerCommand(mockCompose, "test-compose.yml")
-			result, err := cmd.findContainer(tt.serviceName)
+			cmd := NewContainerCommand(mockCompose, "test-compose.yml", nil)
+			result, err := cmd.findContainer(context.Background(), tt.serviceName)
 
 			if tt.expectError {
 				if err == nil {
@@ -471,7 +479,7 @@ func TestContainerCommand_addBasicInfo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := NewContainerCommand(&docker.MockComposeService{}, "")
+			cmd := NewContainerCommand(&docker.MockComposeService{}, "", nil)
 			var builder strings.Builder
 
 			cmd.addBasicInfo(&builder, tt.container)
@@ -499,28 +507,35 @@ func TestContainerCommand_addResourceInfo(t *testing.T) { // テーブル駆動
 			name:      "正常なリソース情報",
 			container: &docker.ContainerInfo{Name: "app_web_1"},
 			stats: &docker.ContainerStats{
-				CPUPercent:    45.2,
-				MemoryPercent: 67.8,
-				MemoryUsage:   "2.1GiB / 4.0GiB",
-				NetworkIO:     "1.2MB / 800KB",
-				BlockIO:       "500KB / 1.5MB",
+				CPUPercent:       45.2,
+				MemoryPercent:    67.8,
+				MemoryUsageBytes: 2 * 1024 * 1024 * 1024,
+				MemoryLimitBytes: 4 * 1024 * 1024 * 1024,
+				NetworkRxBytes:   1258291,
+				NetworkTxBytes:   819200,
+				BlockReadBytes:   512000,
+				BlockWriteBytes:  1572864,
+				PIDs:             24,
+				RestartCount:     3,
 			},
 			expectedContains: []string{
 				"**リソース使用状況**",
 				"- CPU使用率: 45.2%",
 				"- メモリ使用率: 67.8%",
-				"- メモリ使用量: 2.1GiB / 4.0GiB",
-				"- ネットワークI/O: 1.2MB / 800KB",
-				"- ブロックI/O: 500KB / 1.5MB",
+				"- メモリ使用量: 2.00GiB / 4.00GiB",
+				"- ネットワークI/O: 1.2MB / 800.0KB",
+				"- ブロックI/O: 500.0KB / 1.5MB",
+				"- プロセス数: 24",
+				"- 再起動回数: 3",
 			},
 		},
 		{
 			name:      "高負荷警告",
 			container: &docker.ContainerInfo{Name: "app_worker_1"},
 			stats: &docker.ContainerStats{
-				CPUPercent:    90.0,
-				MemoryPercent: 95.0,
-				MemoryUsage:   "7.5GiB / 8.0GiB",
+				CPUPercent:       90.0,
+				MemoryPercent:    95.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			},
 			expectedContains: []string{
 				"⚠️ **警告**",
@@ -546,10 +561,10 @@ func TestContainerCommand_addResourceInfo(t *testing.T) { // テーブル駆動
 				},
 			}
 
-			cmd := NewContainerCommand(mockCompose, "")
+			cmd := NewContainerCommand(mockCompose, "", nil)
 			var builder strings.Builder
 
-			cmd.addResourceInfo(&builder, tt.container)
+			cmd.addResourceInfo(context.Background(), &builder, tt.container)
 			result := builder.String()
 
 			for _, expected := range tt.expectedContains {
@@ -604,10 +619,10 @@ func TestContainerCommand_addRecentLogs(t *testing.T) {
 				},
 			}
 
-			cmd := NewContainerCommand(mockCompose, "")
+			cmd := NewContainerCommand(mockCompose, "", nil)
 			var builder strings.Builder
 
-			cmd.addRecentLogs(&builder, tt.serviceName)
+			cmd.addRecentLogs(context.Background(), &builder, tt.serviceName)
 			result := builder.String()
 
 			for _, expected := range tt.expectedContains {
@@ -652,7 +667,7 @@ func TestContainerCommand_addAvailableCommands(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := NewContainerCommand(&docker.MockComposeService{}, "")
+			cmd := NewContainerCommand(&docker.MockComposeService{}, "", nil)
 			var builder strings.Builder
 
 			cmd.addAvailableCommands(&builder, tt.serviceName, tt.state)
@@ -683,9 +698,9 @@ func BenchmarkContainerCommand_Execute(b *testing.B) {
 		},
 		GetContainerStatsFunc: func(string) (*docker.ContainerStats, error) {
 			return &docker.ContainerStats{
-				CPUPercent:    50.0,
-				MemoryPercent: 60.0,
-				MemoryUsage:   "2GB / 4GB",
+				CPUPercent:       50.0,
+				MemoryPercent:    60.0,
+				MemoryUsageBytes: 1024 * 1024 * 1024,
 			}, nil
 		},
 		GetContainerLogsFunc: func(string, string, int) (string, error) {
@@ -693,14 +708,129 @@ func BenchmarkContainerCommand_Execute(b *testing.B) {
 		},
 	}
 
-	cmd := NewContainerCommand(mockCompose, "test-compose.yml")
+	cmd := NewContainerCommand(mockCompose, "test-compose.yml", nil)
 	args := []string{"web"}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := cmd.Execute(args)
+		_, err := cmd.executeText(context.Background(), args)
 		if err != nil {
 			b.Fatalf("Execute() failed: %v", err)
 		}
 	}
 }
+
+func TestContainerCommand_ExecuteEmbed(t *testing.T) { // テーブル駆動テストのため長い関数を許可
+	running := docker.ContainerInfo{
+		Service: "web", Name: "app_web_1", ID: "abc123def456789", State: "running", RunningFor: "2h30m",
+		HealthStatus: "healthy",
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		container    docker.ContainerInfo
+		stats        *docker.ContainerStats
+		listError    error
+		wantErr      bool
+		wantContent  string
+		wantColor    int
+		wantFields   []string
+		wantNoFields []string
+	}{
+		{
+			name:        "引数なし",
+			wantContent: "使用方法: `@bot container <サービス名>`",
+		},
+		{
+			name:        "存在しないサービス",
+			args:        []string{"db"},
+			container:   running,
+			wantContent: "❌ サービス 'db' が見つかりません",
+		},
+		{
+			name:      "コンテナ情報の取得エラー",
+			args:      []string{"web"},
+			listError: errors.New("docker error"),
+			wantErr:   true,
+		},
+		{
+			name:         "稼働中の場合は緑",
+			args:         []string{"web"},
+			container:    running,
+			stats:        &docker.ContainerStats{CPUPercent: 45.2, MemoryPercent: 67.8},
+			wantColor:    ColorHealthy,
+			wantFields:   []string{"状態", "稼働時間", "ヘルス", "CPU使用率", "メモリ使用量", "最近のログ", "使用可能なコマンド"},
+			wantNoFields: []string{"⚠️ 警告"},
+		},
+		{
+			name:       "高負荷の場合は黄色で警告",
+			args:       []string{"web"},
+			container:  running,
+			stats:      &docker.ContainerStats{CPUPercent: 95.0, MemoryPercent: 50.0},
+			wantColor:  ColorWarning,
+			wantFields: []string{"⚠️ 警告"},
+		},
+		{
+			name: "停止中の場合は赤でリソース使用状況なし",
+			args: []string{"web"},
+			container: docker.ContainerInfo{
+				Service: "web", Name: "app_web_1", ID: "abc123def456789", State: "exited",
+			},
+			wantColor:    ColorCritical,
+			wantFields:   []string{"状態", "使用可能なコマンド"},
+			wantNoFields: []string{"CPU使用率", "稼働時間"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCompose := &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return []docker.ContainerInfo{tt.container}, tt.listError
+				},
+				GetContainerStatsFunc: func(string) (*docker.ContainerStats, error) {
+					if tt.stats == nil {
+						return nil, errors.New("no stats")
+					}
+					return tt.stats, nil
+				},
+				GetContainerLogsFunc: func(string, string, int) (string, error) {
+					return "INFO started", nil
+				},
+			}
+
+			got, err := NewContainerCommand(mockCompose, "", nil).Execute(context.Background(), &Invocation{Args: tt.args})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteEmbed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantContent != "" {
+				if !strings.Contains(got.Content, tt.wantContent) {
+					t.Errorf("ExecuteEmbed() content = %q, want %q", got.Content, tt.wantContent)
+				}
+				return
+			}
+
+			embed := got.Embeds[0]
+			if embed.Color != tt.wantColor {
+				t.Errorf("Color = %#x, want %#x", embed.Color, tt.wantColor)
+			}
+			if embed.Footer == nil || embed.Footer.Text != "コンテナID: abc123def456" {
+				t.Errorf("Footer = %+v, want the container ID", embed.Footer)
+			}
+			for _, name := range tt.wantFields {
+				if findEmbedField(embed, name) == nil {
+					t.Errorf("field %q not found in %+v", name, embed.Fields)
+				}
+			}
+			for _, name := range tt.wantNoFields {
+				if findEmbedField(embed, name) != nil {
+					t.Errorf("field %q should not be present", name)
+				}
+			}
+		})
+	}
+}
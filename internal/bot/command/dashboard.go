@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/dashboard"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+const (
+	// OptionAction は操作を指定するスラッシュコマンドのオプション名
+	OptionAction = "action"
+
+	// dashboardActionStart はダッシュボードを表示する操作
+	dashboardActionStart = "start"
+	// dashboardActionStop はダッシュボードの更新を停止する操作
+	dashboardActionStop = "stop"
+
+	// dashboardTimeout はダッシュボードの送信・編集のタイムアウト時間
+	dashboardTimeout = 30 * time.Second
+)
+
+// DashboardCommand handles the dashboard command
+type DashboardCommand struct {
+	dashboards *dashboard.Manager
+	ctx        context.Context
+}
+
+// NewDashboardCommand creates a new DashboardCommand
+// dashboardsがnilの場合はダッシュボードが無効である旨を表示する
+func NewDashboardCommand(ctx context.Context, dashboards *dashboard.Manager) *DashboardCommand {
+	return &DashboardCommand{dashboards: dashboards, ctx: ctx}
+}
+
+// Name returns the command name
+func (c *DashboardCommand) Name() string {
+	return "dashboard"
+}
+
+// Description returns the command description
+func (c *DashboardCommand) Description() string {
+	return "このチャンネルに自動更新される監視ダッシュボードを表示・停止"
+}
+
+// RequiredPermission returns the permission required to run the command
+func (c *DashboardCommand) RequiredPermission(_ []string) permission.Requirement {
+	return permission.Requirement{Level: permission.LevelOperator}
+}
+
+// Options returns the slash command options
+func (c *DashboardCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        OptionAction,
+			Description: "操作（デフォルト: start）",
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "表示", Value: dashboardActionStart},
+				{Name: "停止", Value: dashboardActionStop},
+			},
+		},
+	}
+}
+
+// Execute runs the command in the invoking channel
+func (c *DashboardCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if c.dashboards == nil {
+		return TextResponse("ダッシュボードが無効です。"), nil
+	}
+	if inv.ChannelID == "" {
+		return TextResponse("❌ ダッシュボードを表示するチャンネルが分かりません。"), nil
+	}
+
+	action := dashboardActionStart
+	if len(inv.Args) > 0 {
+		action = strings.ToLower(inv.Args[0])
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dashboardTimeout)
+	defer cancel()
+
+	switch action {
+	case dashboardActionStart:
+		if err := c.dashboards.Start(ctx, inv.ChannelID); err != nil {
+			return nil, fmt.Errorf("ダッシュボードの表示に失敗しました: %w", err)
+		}
+		return TextResponse(fmt.Sprintf("📌 ダッシュボードを表示しました。%sごとに更新します（停止: `@bot dashboard stop`）",
+			dashboard.FormatInterval(c.dashboards.Interval()))), nil
+	case dashboardActionStop:
+		if !c.dashboards.Stop(ctx, inv.ChannelID) {
+			return TextResponse("このチャンネルにはダッシュボードがありません。"), nil
+		}
+		return TextResponse("⏹️ ダッシュボードの更新を停止しました。"), nil
+	default:
+		return TextResponse("使用方法: `@bot dashboard [start|stop]`"), nil
+	}
+}
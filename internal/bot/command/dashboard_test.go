@@ -0,0 +1,113 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/dashboard"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+func TestDashboardCommand_Invoke(t *testing.T) {
+	channel := Invocation{UserID: "user-1", ChannelID: "channel-1"}
+
+	tests := []struct {
+		name         string
+		disabled     bool
+		started      bool
+		inv          Invocation
+		args         []string
+		want         string
+		wantSent     int
+		wantChannels int
+	}{
+		{
+			name:         "引数なしでダッシュボードを表示",
+			inv:          channel,
+			want:         "📌 ダッシュボードを表示しました。30秒ごとに更新します",
+			wantSent:     1,
+			wantChannels: 1,
+		},
+		{
+			name:         "startでダッシュボードを表示",
+			inv:          channel,
+			args:         []string{"START"},
+			want:         "📌 ダッシュボードを表示しました。",
+			wantSent:     1,
+			wantChannels: 1,
+		},
+		{
+			name:     "stopで更新を停止",
+			started:  true,
+			inv:      channel,
+			args:     []string{"stop"},
+			want:     "⏹️ ダッシュボードの更新を停止しました。",
+			wantSent: 1,
+		},
+		{
+			name: "ダッシュボードがないチャンネルでstop",
+			inv:  channel,
+			args: []string{"stop"},
+			want: "このチャンネルにはダッシュボードがありません。",
+		},
+		{
+			name: "不明な操作",
+			inv:  channel,
+			args: []string{"pin"},
+			want: "使用方法: `@bot dashboard [start|stop]`",
+		},
+		{
+			name: "チャンネルが分からない",
+			want: "❌ ダッシュボードを表示するチャンネルが分かりません。",
+		},
+		{
+			name:     "ダッシュボードが無効",
+			disabled: true,
+			inv:      channel,
+			want:     "ダッシュボードが無効です。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &dashboard.MockPublisher{}
+			var manager *dashboard.Manager
+			if !tt.disabled {
+				manager = dashboard.New(&dashboard.MockRenderer{Content: "report"}, publisher, "", 30*time.Second)
+			}
+			if tt.started {
+				if err := manager.Start(context.Background(), channel.ChannelID); err != nil {
+					t.Fatalf("Start() error = %v", err)
+				}
+			}
+
+			inv := tt.inv
+			inv.Args = tt.args
+			got, err := NewDashboardCommand(context.Background(), manager).Execute(context.Background(), &inv)
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if !strings.Contains(got.Content, tt.want) {
+				t.Errorf("Invoke() = %q, want to contain %q", got.Content, tt.want)
+			}
+			if tt.disabled {
+				return
+			}
+			if n := len(publisher.Sent()); n != tt.wantSent {
+				t.Errorf("sent %d dashboards, want %d", n, tt.wantSent)
+			}
+			if n := len(manager.Channels()); n != tt.wantChannels {
+				t.Errorf("Channels() = %d, want %d", n, tt.wantChannels)
+			}
+		})
+	}
+}
+
+func TestDashboardCommand_RequiredPermission(t *testing.T) {
+	got := NewDashboardCommand(context.Background(), nil).RequiredPermission(nil)
+	if got.Level != permission.LevelOperator || got.Service != "" {
+		t.Errorf("RequiredPermission() = %+v, want operator", got)
+	}
+}
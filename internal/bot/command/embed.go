@@ -0,0 +1,105 @@
+package command
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+const (
+	// ColorHealthy は正常な状態の埋め込みの色（緑）
+	ColorHealthy = 0x2ECC71
+	// ColorWarning は注意が必要な状態の埋め込みの色（黄）
+	ColorWarning = 0xF1C40F
+	// ColorCritical は異常な状態の埋め込みの色（赤）
+	ColorCritical = 0xE74C3C
+
+	// embedFieldValueLimit は埋め込みのフィールドの値の最大文字数
+	embedFieldValueLimit = 1024
+)
+
+// Health は埋め込みの色分けに使用する状態
+type Health int
+
+const (
+	// HealthOK は正常な状態
+	HealthOK Health = iota
+	// HealthWarning は注意が必要な状態（アラートや再起動中のコンテナなど）
+	HealthWarning
+	// HealthCritical は異常な状態（停止中・unhealthyのコンテナや情報の取得失敗など）
+	HealthCritical
+)
+
+// Color は状態に対応する埋め込みの色を返す
+func (h Health) Color() int {
+	switch h {
+	case HealthWarning:
+		return ColorWarning
+	case HealthCritical:
+		return ColorCritical
+	default:
+		return ColorHealthy
+	}
+}
+
+// containerHealth はコンテナの状態とヘルスチェックの結果から状態を判定する
+func containerHealth(container *docker.ContainerInfo) Health {
+	if strings.EqualFold(container.HealthStatus, docker.HealthUnhealthy) {
+		return HealthCritical
+	}
+	switch strings.ToLower(container.State) {
+	case docker.StateRunning:
+		if strings.EqualFold(container.HealthStatus, "starting") {
+			return HealthWarning
+		}
+		return HealthOK
+	case "restarting", "paused":
+		return HealthWarning
+	default:
+		return HealthCritical
+	}
+}
+
+// newEmbed は状態に応じた色と現在時刻のタイムスタンプを持つ埋め込みを作成する
+func newEmbed(title string, health Health) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:     title,
+		Color:     health.Color(),
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// addEmbedField は埋め込みにフィールドを追加する（値は上限の文字数で切り詰める）
+func addEmbedField(embed *discordgo.MessageEmbed, name, value string, inline bool) {
+	if value == "" {
+		value = "-"
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   name,
+		Value:  truncateRunes(value, embedFieldValueLimit),
+		Inline: inline,
+	})
+}
+
+// codeBlock はテキストをコードブロックで囲み、上限の文字数に収まるように先頭を切り詰める
+// ログのように新しい行ほど重要なテキスト向け
+func codeBlock(text string, limit int) string {
+	const fences = len("```\n") + len("\n```")
+	body := []rune(strings.TrimRight(text, "\n"))
+	if room := limit - fences; len(body) > room {
+		body = append([]rune("…"), body[len(body)-room+1:]...)
+	}
+	return "```\n" + string(body) + "\n```"
+}
+
+// truncateRunes は文字列を指定した文字数に切り詰める（切り詰めた場合は末尾を…にする）
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
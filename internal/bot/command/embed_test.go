@@ -0,0 +1,140 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// findEmbedField は埋め込みから指定した名前のフィールドを返す（ない場合はnil）
+func findEmbedField(embed *discordgo.MessageEmbed, name string) *discordgo.MessageEmbedField {
+	for _, field := range embed.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func TestHealth_Color(t *testing.T) {
+	tests := []struct {
+		name   string
+		health Health
+		want   int
+	}{
+		{name: "正常は緑", health: HealthOK, want: ColorHealthy},
+		{name: "注意は黄", health: HealthWarning, want: ColorWarning},
+		{name: "異常は赤", health: HealthCritical, want: ColorCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.health.Color(); got != tt.want {
+				t.Errorf("Color() = %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerHealth(t *testing.T) {
+	tests := []struct {
+		name      string
+		container docker.ContainerInfo
+		want      Health
+	}{
+		{name: "稼働中", container: docker.ContainerInfo{State: "running"}, want: HealthOK},
+		{name: "稼働中でhealthy", container: docker.ContainerInfo{State: "Running", HealthStatus: "healthy"}, want: HealthOK},
+		{name: "ヘルスチェック開始中", container: docker.ContainerInfo{State: "running", HealthStatus: "starting"},
+			want: HealthWarning},
+		{name: "再起動中", container: docker.ContainerInfo{State: "restarting"}, want: HealthWarning},
+		{name: "一時停止中", container: docker.ContainerInfo{State: "paused"}, want: HealthWarning},
+		{name: "unhealthy", container: docker.ContainerInfo{State: "running", HealthStatus: "unhealthy"},
+			want: HealthCritical},
+		{name: "停止中", container: docker.ContainerInfo{State: "exited"}, want: HealthCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerHealth(&tt.container); got != tt.want {
+				t.Errorf("containerHealth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddEmbedField(t *testing.T) {
+	embed := newEmbed("title", HealthOK)
+	addEmbedField(embed, "空", "", true)
+	addEmbedField(embed, "長い", strings.Repeat("あ", 2000), false)
+
+	if embed.Timestamp == "" {
+		t.Error("newEmbed() should set the timestamp")
+	}
+	if got := findEmbedField(embed, "空"); got == nil || got.Value != "-" || !got.Inline {
+		t.Errorf("empty field = %+v, want \"-\" inline", got)
+	}
+	if got := findEmbedField(embed, "長い"); got == nil || len([]rune(got.Value)) != embedFieldValueLimit {
+		t.Errorf("long field should be truncated to %d runes", embedFieldValueLimit)
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		want     string
+		wantTail string
+	}{
+		{
+			name:  "上限に収まる",
+			text:  "line 1\nline 2\n",
+			limit: 100,
+			want:  "```\nline 1\nline 2\n```",
+		},
+		{
+			name:     "上限を超える場合は先頭を切り詰める",
+			text:     strings.Repeat("古いログ\n", 50) + "最新のログ",
+			limit:    50,
+			wantTail: "最新のログ\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := codeBlock(tt.text, tt.limit)
+			if n := len([]rune(got)); n > tt.limit {
+				t.Errorf("codeBlock() length = %d, want at most %d", n, tt.limit)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("codeBlock() = %q, want %q", got, tt.want)
+			}
+			if tt.wantTail != "" && (!strings.HasPrefix(got, "```\n…") || !strings.HasSuffix(got, tt.wantTail)) {
+				t.Errorf("codeBlock() = %q, want truncated head and tail %q", got, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		limit int
+		want  string
+	}{
+		{name: "上限以内", s: "サーバー", limit: 4, want: "サーバー"},
+		{name: "上限を超える", s: "サーバー監視", limit: 4, want: "サーバ…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateRunes(tt.s, tt.limit); got != tt.want {
+				t.Errorf("truncateRunes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/history"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/chart"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+// graphFileName は添付するグラフ画像のファイル名
+const graphFileName = "graph.png"
+
+// GraphCommand handles the graph command
+type GraphCommand struct {
+	reader history.Reader
+}
+
+// NewGraphCommand creates a new GraphCommand
+// readerがnilの場合は履歴が無効である旨を表示する
+func NewGraphCommand(reader history.Reader) *GraphCommand {
+	return &GraphCommand{reader: reader}
+}
+
+// Name returns the command name
+func (c *GraphCommand) Name() string {
+	return "graph"
+}
+
+// Description returns the command description
+func (c *GraphCommand) Description() string {
+	return "ホストまたはサービスのCPU・メモリ使用率の推移をグラフで表示"
+}
+
+// RequiredPermission returns the permission required to run the command
+func (c *GraphCommand) RequiredPermission(args []string) permission.Requirement {
+	return NewHistoryCommand(nil).RequiredPermission(args)
+}
+
+// Options returns the slash command options
+func (c *GraphCommand) Options() []*discordgo.ApplicationCommandOption {
+	return NewHistoryCommand(nil).Options()
+}
+
+// Execute runs the command
+// 集計結果の概要を本文に、グラフの画像を添付ファイルに含めて返す
+func (c *GraphCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if len(inv.Args) == 0 {
+		return TextResponse(fmt.Sprintf(
+			"使用方法: `@bot graph <サービス名|%s> [1h|24h|7d]`\n例: `@bot graph minecraft 24h`", history.HostKey)), nil
+	}
+
+	query, summary, message := c.summarize(inv.Args)
+	if message != "" {
+		return TextResponse(message), nil
+	}
+	resp := TextResponse(fmt.Sprintf("📈 **%s** のCPU・メモリ使用率（直近%s、%s単位）\n"+
+		"- CPU: 平均 %.1f%% / 最大 %.1f%%\n- メモリ: 平均 %.1f%% / 最大 %.1f%%",
+		historyTargetName(query.key), query.label, formatResolution(summary.Resolution),
+		summary.CPU.Avg(), summary.CPU.Max, summary.Memory.Avg(), summary.Memory.Max))
+
+	var buf bytes.Buffer
+	if err := renderHistoryChart(&buf, &query, &summary); err != nil {
+		// グラフを描画できない場合は概要だけ返す
+		logging.FromContext(ctx).Warn(ctx, "Failed to render history chart", logging.ErrorField(err))
+		return resp, nil
+	}
+	resp.Files = []*discordgo.File{{Name: graphFileName, ContentType: "image/png", Reader: &buf}}
+	return resp, nil
+}
+
+// summarize は引数で指定された対象と期間の履歴を集計する
+// 集計できない場合は利用者に表示するメッセージを返す
+func (c *GraphCommand) summarize(args []string) (query historyQuery, summary history.Summary, message string) {
+	if c.reader == nil {
+		return query, summary, "リソースの履歴が無効です。"
+	}
+	query, message = parseHistoryQuery(args)
+	if message != "" {
+		return query, summary, message
+	}
+	summary = c.reader.Summarize(query.key, query.window)
+	if summary.CPU.Count == 0 {
+		return query, summary, noHistoryMessage(&query)
+	}
+	return query, summary, ""
+}
+
+// renderHistoryChart は集計結果のCPU・メモリ使用率の平均値を折れ線グラフとして描画する
+func renderHistoryChart(buf *bytes.Buffer, query *historyQuery, s *history.Summary) error {
+	cpu := make([]chart.Point, len(s.Points))
+	memory := make([]chart.Point, len(s.Points))
+	for i := range s.Points {
+		p := &s.Points[i]
+		cpu[i] = chart.Point{Time: p.Start, Value: p.CPU.Avg()}
+		memory[i] = chart.Point{Time: p.Start, Value: p.Memory.Avg()}
+	}
+
+	c := &chart.LineChart{
+		Title: fmt.Sprintf("%s %s", query.key, query.period),
+		Series: []chart.Series{
+			{Name: "CPU", Color: chart.ColorBlue, Points: cpu},
+			{Name: "MEM", Color: chart.ColorGreen, Points: memory},
+		},
+		MaxValue: 100,
+		Unit:     "%",
+		// 記録が途切れた期間（ウォッチドッグの停止中など）は線をつながない
+		MaxGap: 2 * s.Resolution,
+	}
+	if err := c.Render(buf); err != nil {
+		return fmt.Errorf("グラフの描画に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/history"
+)
+
+// newGraphTestStore はminecraftの直近30分の履歴を持つStoreを作成する
+func newGraphTestStore() *history.Store {
+	now := time.Now()
+	store := history.NewStore()
+	for i := 30; i > 0; i-- {
+		store.Record("minecraft", now.Add(-time.Duration(i)*time.Minute), float64(i), 50)
+	}
+	return store
+}
+
+func TestGraphCommand_Execute(t *testing.T) {
+	store := newGraphTestStore()
+
+	tests := []struct {
+		name     string
+		reader   history.Reader
+		args     []string
+		contains []string
+	}{
+		{
+			name:     "引数なし",
+			reader:   store,
+			contains: []string{"使用方法: `@bot graph <サービス名|host> [1h|24h|7d]`"},
+		},
+		{
+			name:     "履歴が無効",
+			reader:   nil,
+			args:     []string{"minecraft"},
+			contains: []string{"リソースの履歴が無効です"},
+		},
+		{
+			name:   "グラフの説明",
+			reader: store,
+			args:   []string{"minecraft", "1h"},
+			contains: []string{
+				"📈 **Minecraft** のCPU・メモリ使用率（直近1時間、1分単位）",
+				"- CPU: 平均 15.5% / 最大 30.0%",
+				"- メモリ: 平均 50.0% / 最大 50.0%",
+			},
+		},
+		{
+			name:     "記録のないサービス",
+			reader:   store,
+			args:     []string{"rust"},
+			contains: []string{"📈 Rust の直近24時間の記録はありません。"},
+		},
+		{
+			name:     "不正な期間",
+			reader:   store,
+			args:     []string{"minecraft", "1y"},
+			contains: []string{"❌ 期間 '1y' は指定できません"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runText(NewGraphCommand(tt.reader), tt.args)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Execute() = %q, want to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphCommand_Execute_Files(t *testing.T) {
+	store := newGraphTestStore()
+
+	tests := []struct {
+		name      string
+		reader    history.Reader
+		args      []string
+		wantImage bool
+	}{
+		{name: "グラフを添付", reader: store, args: []string{"minecraft", "24h"}, wantImage: true},
+		{name: "引数なし", reader: store},
+		{name: "履歴が無効", reader: nil, args: []string{"minecraft"}},
+		{name: "記録がない", reader: store, args: []string{"host"}},
+		{name: "不正な期間", reader: store, args: []string{"minecraft", "1y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGraphCommand(tt.reader).Execute(context.Background(), &Invocation{Args: tt.args})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			files := resp.Files
+			if !tt.wantImage {
+				if len(files) != 0 {
+					t.Errorf("Execute() = %d files, want none", len(files))
+				}
+				return
+			}
+
+			if len(files) != 1 || files[0].Name != graphFileName || files[0].ContentType != "image/png" {
+				t.Fatalf("Execute() files = %+v, want a PNG attachment", files)
+			}
+			if _, err := png.Decode(files[0].Reader); err != nil {
+				t.Errorf("attachment is not a valid PNG: %v", err)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package command
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 )
 
 // HelpCommand はhelpコマンドの実装
@@ -28,20 +31,26 @@ func (c *HelpCommand) Description() string {
 	return helpDescription
 }
 
+// RequiredPermission はコマンドの実行に必要な権限を返す
+func (c *HelpCommand) RequiredPermission(_ []string) permission.Requirement {
+	return permission.Requirement{Level: permission.LevelViewer}
+}
+
 // SetCommands は利用可能なコマンドのリストを設定
 func (c *HelpCommand) SetCommands(commands []Command) {
 	c.commands = commands
 }
 
 // Execute はコマンドを実行する
-func (c *HelpCommand) Execute(_ []string) (string, error) {
+func (c *HelpCommand) Execute(_ context.Context, _ *Invocation) (*Response, error) {
 	helpMessage := "**利用可能なコマンド:**\n"
 
 	for _, cmd := range c.commands {
 		helpMessage += fmt.Sprintf("`@ボット %s` - %s\n", cmd.Name(), cmd.Description())
 	}
 
-	helpMessage += "\n**使い方:**\nボットをメンションしてコマンドを送信してください。"
+	helpMessage += "\n**使い方:**\nボットをメンションしてコマンドを送信してください。\n" +
+		"`/monitor` や `/logs service:minecraft lines:100` のようにスラッシュコマンドでも実行できます。"
 
-	return helpMessage, nil
+	return TextResponse(helpMessage), nil
 }
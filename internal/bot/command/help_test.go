@@ -128,7 +128,7 @@ func TestHelpCommand_Execute(t *testing.T) {
 			cmd := NewHelpCommand()
 			cmd.SetCommands(tt.commands)
 
-			got, err := cmd.Execute(tt.args)
+			got, err := runText(cmd, tt.args)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
@@ -142,6 +142,7 @@ func TestHelpCommand_Execute(t *testing.T) {
 					"`@ボット ping` - ボットの応答を確認",
 					"`@ボット help` - コマンド一覧を表示",
 					"**使い方:**\nボットをメンションしてコマンドを送信してください。",
+					"スラッシュコマンドでも実行できます。",
 				}
 				for _, part := range expectedParts {
 					if !strings.Contains(got, part) {
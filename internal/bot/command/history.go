@@ -0,0 +1,177 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/history"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// OptionPeriod は集計する期間を指定するスラッシュコマンドのオプション名
+	OptionPeriod = "period"
+	// defaultHistoryPeriod はデフォルトの集計期間
+	defaultHistoryPeriod = "24h"
+)
+
+// historyPeriods は指定できる集計期間（表示順）
+var historyPeriods = []struct {
+	name   string
+	label  string
+	window time.Duration
+}{
+	{name: "1h", label: "1時間", window: time.Hour},
+	{name: "24h", label: "24時間", window: 24 * time.Hour},
+	{name: "7d", label: "7日間", window: 7 * 24 * time.Hour},
+}
+
+// HistoryCommand handles the history command
+type HistoryCommand struct {
+	reader history.Reader
+}
+
+// NewHistoryCommand creates a new HistoryCommand
+// readerがnilの場合は履歴が無効である旨を表示する
+func NewHistoryCommand(reader history.Reader) *HistoryCommand {
+	return &HistoryCommand{reader: reader}
+}
+
+// Name returns the command name
+func (c *HistoryCommand) Name() string {
+	return "history"
+}
+
+// Description returns the command description
+func (c *HistoryCommand) Description() string {
+	return "ホストまたはサービスのCPU・メモリ使用率の推移を表示"
+}
+
+// RequiredPermission returns the permission required to run the command
+func (c *HistoryCommand) RequiredPermission(args []string) permission.Requirement {
+	if len(args) > 0 && args[0] == history.HostKey {
+		return permission.Requirement{Level: permission.LevelViewer}
+	}
+	return serviceRequirement(permission.LevelViewer, args)
+}
+
+// Options returns the slash command options
+func (c *HistoryCommand) Options() []*discordgo.ApplicationCommandOption {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(historyPeriods))
+	for _, p := range historyPeriods {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: p.label, Value: p.name})
+	}
+	return []*discordgo.ApplicationCommandOption{
+		serviceOption(fmt.Sprintf("サービス名（ホストの場合は %s）", history.HostKey)),
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        OptionPeriod,
+			Description: fmt.Sprintf("集計する期間（デフォルト: %s）", defaultHistoryPeriod),
+			Choices:     choices,
+		},
+	}
+}
+
+// Execute runs the command
+func (c *HistoryCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if len(inv.Args) == 0 {
+		return TextResponse(fmt.Sprintf(
+			"使用方法: `@bot history <サービス名|%s> [1h|24h|7d]`\n例: `@bot history minecraft 1h`", history.HostKey)), nil
+	}
+	if c.reader == nil {
+		return TextResponse("リソースの履歴が無効です。"), nil
+	}
+
+	query, message := parseHistoryQuery(inv.Args)
+	if message != "" {
+		return TextResponse(message), nil
+	}
+
+	summary := c.reader.Summarize(query.key, query.window)
+	logging.FromContext(ctx).Debug(ctx, "Summarized resource history",
+		logging.String("key", query.key),
+		logging.String("period", query.period),
+		logging.Int("samples", summary.CPU.Count))
+	if summary.CPU.Count == 0 {
+		message := noHistoryMessage(&query)
+		if keys := c.reader.Keys(); len(keys) > 0 {
+			message += fmt.Sprintf("\n記録があるのは: %s", strings.Join(keys, ", "))
+		}
+		return TextResponse(message), nil
+	}
+
+	return TextResponse(buildHistoryOutput(&summary, query.label)), nil
+}
+
+// historyQuery は history / graph コマンドで指定された対象と期間
+type historyQuery struct {
+	key    string
+	period string // 1h / 24h / 7d
+	label  string // 表示用の期間
+	window time.Duration
+}
+
+// parseHistoryQuery は引数から対象と期間を取り出す（期間の省略時はdefaultHistoryPeriod）
+// 期間が不正な場合は利用者に表示するメッセージを返す
+func parseHistoryQuery(args []string) (query historyQuery, message string) {
+	query.key = args[0]
+	query.period = defaultHistoryPeriod
+	if len(args) > 1 {
+		query.period = strings.ToLower(args[1])
+	}
+	for _, p := range historyPeriods {
+		if p.name == query.period {
+			query.label = p.label
+			query.window = p.window
+			return query, ""
+		}
+	}
+	return query, fmt.Sprintf("❌ 期間 '%s' は指定できません（1h / 24h / 7d）", query.period)
+}
+
+// noHistoryMessage は期間内に記録がない場合のメッセージを返す
+func noHistoryMessage(query *historyQuery) string {
+	return fmt.Sprintf("📈 %s の直近%sの記録はありません。", historyTargetName(query.key), query.label)
+}
+
+// historyTargetName は履歴のキーを表示名に変換する
+func historyTargetName(key string) string {
+	if key == history.HostKey {
+		return "ホスト"
+	}
+	return FormatServiceName(key)
+}
+
+// buildHistoryOutput は集計結果を表示用に整形する
+func buildHistoryOutput(s *history.Summary, label string) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("📈 **リソース履歴: %s**（直近%s、%s単位）\n",
+		historyTargetName(s.Key), label, formatResolution(s.Resolution)))
+	builder.WriteString(fmt.Sprintf("- CPU: 最小 %.1f%% / 平均 %.1f%% / 最大 %.1f%%\n",
+		s.CPU.Min, s.CPU.Avg(), s.CPU.Max))
+	builder.WriteString(fmt.Sprintf("- メモリ: 最小 %.1f%% / 平均 %.1f%% / 最大 %.1f%%\n",
+		s.Memory.Min, s.Memory.Avg(), s.Memory.Max))
+
+	if len(s.Peaks) > 0 {
+		builder.WriteString("\n**CPU使用率のピーク**\n")
+		for i := range s.Peaks {
+			p := &s.Peaks[i]
+			builder.WriteString(fmt.Sprintf("`%s` CPU %.1f%%（メモリ %.1f%%）\n",
+				p.CPU.MaxAt.Local().Format("01/02 15:04"), p.CPU.Max, p.Memory.Max))
+		}
+	}
+	return builder.String()
+}
+
+// formatResolution は集計の単位を表示用に整形する
+func formatResolution(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%d時間", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d分", int(d.Minutes()))
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/history"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+func TestHistoryCommand_Execute(t *testing.T) {
+	now := time.Now()
+	store := history.NewStore()
+	store.Record(history.HostKey, now.Add(-30*time.Minute), 20, 50)
+	store.Record(history.HostKey, now.Add(-10*time.Minute), 60, 70)
+	store.Record("minecraft", now.Add(-3*time.Hour), 95, 40)
+	store.Record("minecraft", now.Add(-5*time.Minute), 15, 30)
+	peak := now.Add(-3 * time.Hour).Local().Format("01/02 15:04")
+
+	tests := []struct {
+		name        string
+		reader      history.Reader
+		args        []string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "引数なし",
+			reader:   store,
+			contains: []string{"使用方法: `@bot history <サービス名|host> [1h|24h|7d]`"},
+		},
+		{
+			name:     "履歴が無効",
+			reader:   nil,
+			args:     []string{"minecraft"},
+			contains: []string{"リソースの履歴が無効です"},
+		},
+		{
+			name:   "ホストの1時間",
+			reader: store,
+			args:   []string{"host", "1h"},
+			contains: []string{
+				"📈 **リソース履歴: ホスト**（直近1時間、1分単位）",
+				"- CPU: 最小 20.0% / 平均 40.0% / 最大 60.0%",
+				"- メモリ: 最小 50.0% / 平均 60.0% / 最大 70.0%",
+			},
+		},
+		{
+			name:   "期間の省略は24時間",
+			reader: store,
+			args:   []string{"minecraft"},
+			contains: []string{
+				"📈 **リソース履歴: Minecraft**（直近24時間、1分単位）",
+				"- CPU: 最小 15.0% / 平均 55.0% / 最大 95.0%",
+				"**CPU使用率のピーク**\n`" + peak + "` CPU 95.0%（メモリ 40.0%）",
+			},
+		},
+		{
+			name:     "7日間は15分単位",
+			reader:   store,
+			args:     []string{"minecraft", "7D"},
+			contains: []string{"（直近7日間、15分単位）"},
+		},
+		{
+			name:        "1時間は期間外の記録を含まない",
+			reader:      store,
+			args:        []string{"minecraft", "1h"},
+			contains:    []string{"最大 15.0%"},
+			notContains: []string{"95.0%"},
+		},
+		{
+			name:     "記録のないサービス",
+			reader:   store,
+			args:     []string{"rust"},
+			contains: []string{"📈 Rust の直近24時間の記録はありません。", "記録があるのは: host, minecraft"},
+		},
+		{
+			name:     "不正な期間",
+			reader:   store,
+			args:     []string{"minecraft", "30d"},
+			contains: []string{"❌ 期間 '30d' は指定できません"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewHistoryCommand(tt.reader)
+			got, err := runText(cmd, tt.args)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Execute() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Execute() = %q, should not contain %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryCommand_RequiredPermission(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want permission.Requirement
+	}{
+		{name: "ホスト", args: []string{"host"}, want: permission.Requirement{Level: permission.LevelViewer}},
+		{
+			name: "サービス",
+			args: []string{"minecraft", "1h"},
+			want: permission.Requirement{Level: permission.LevelViewer, Service: "minecraft"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewHistoryCommand(nil).RequiredPermission(tt.args); got != tt.want {
+				t.Errorf("RequiredPermission() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryCommand_Options(t *testing.T) {
+	options := NewHistoryCommand(nil).Options()
+	if len(options) != 2 || options[0].Name != OptionService || options[1].Name != OptionPeriod {
+		t.Fatalf("Options() = %+v, want service and period", options)
+	}
+
+	var values []string
+	for _, choice := range options[1].Choices {
+		values = append(values, choice.Value.(string))
+	}
+	if strings.Join(values, ",") != "1h,24h,7d" {
+		t.Errorf("period choices = %v, want 1h, 24h, 7d", values)
+	}
+}
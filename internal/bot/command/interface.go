@@ -1,6 +1,12 @@
 package command
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
 
 // Command はコマンドのインターフェース
 type Command interface {
@@ -8,21 +14,30 @@ type Command interface {
 	Name() string
 	// Description はコマンドの説明を返す
 	Description() string
-	// Execute はコマンドを実行する
-	Execute(args []string) (string, error)
+	// Execute は呼び出し情報を指定してコマンドを実行する（ctxがキャンセルされた場合は中断する）
+	// ルーターはリクエストごとのタイムアウトと、リクエストの情報を付与したロガーをコンテキストに設定する
+	// 操作ボタンや添付ファイルは本文と同じデータから作成して実行結果に含める
+	Execute(ctx context.Context, inv *Invocation) (*Response, error)
+	// RequiredPermission は指定された引数で実行するために必要な権限を返す
+	RequiredPermission(args []string) permission.Requirement
 }
 
-// InteractiveCommand はインタラクティブなコンポーネントを返すコマンドのインターフェース
-type InteractiveCommand interface {
+// SlashCommand はスラッシュコマンドのオプションを定義するコマンドのインターフェース
+// Optionsの順序はExecuteに渡す引数の順序に対応する
+type SlashCommand interface {
 	Command
-	// GetComponents はコマンド結果に対するDiscordメッセージコンポーネントを返す
-	GetComponents(args []string) ([]discordgo.MessageComponent, error)
+	// Options はスラッシュコマンドのオプション定義を返す
+	Options() []*discordgo.ApplicationCommandOption
 }
 
 // InteractionHandler はDiscordのインタラクションを処理するインターフェース
 type InteractionHandler interface {
 	// HandleInteraction はインタラクションを処理する
-	HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) error
+	// ルーターは操作ごとのタイムアウトと、操作の情報を付与したロガーをコンテキストに設定する
+	// 応答後も続く処理はcontext.WithoutCancelでロガーを引き継いで実行する
+	HandleInteraction(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error
 	// CanHandle は指定されたカスタムIDを処理できるかどうかを返す
 	CanHandle(customID string) bool
+	// RequiredInteractionPermission は指定されたカスタムIDの操作に必要な権限を返す
+	RequiredInteractionPermission(customID string) permission.Requirement
 }
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+// Invocation はコマンドの呼び出し情報
+type Invocation struct {
+	// RequestID はリクエストを識別するID（ログの相関に使用する）
+	RequestID string
+	// UserID は実行者のユーザーID
+	UserID string
+	// Username は実行者のユーザー名
+	Username string
+	// RoleIDs は実行者のロールID（DMなどメンバー情報がない場合は空）
+	RoleIDs []string
+	// ChannelID は実行されたチャンネルのID
+	ChannelID string
+	// GuildID は実行されたサーバーのID（DMの場合は空）
+	GuildID string
+	// Args はコマンドの引数
+	Args []string
+}
+
+// Actor は実行者を監査ログ用に返す
+func (inv *Invocation) Actor() audit.Actor {
+	return audit.Actor{UserID: inv.UserID, Username: inv.Username, ChannelID: inv.ChannelID}
+}
+
+// User は実行者を権限判定用に返す
+func (inv *Invocation) User() permission.User {
+	return permission.User{ID: inv.UserID, RoleIDs: inv.RoleIDs}
+}
@@ -0,0 +1,27 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
+func TestInvocation(t *testing.T) {
+	inv := &Invocation{
+		RequestID: "req-1",
+		UserID:    "user-1",
+		Username:  "alice",
+		RoleIDs:   []string{"role-1"},
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+	}
+
+	if got, want := inv.Actor(), (audit.Actor{UserID: "user-1", Username: "alice", ChannelID: "channel-1"}); got != want {
+		t.Errorf("Actor() = %+v, want %+v", got, want)
+	}
+	if got, want := inv.User(), (permission.User{ID: "user-1", RoleIDs: []string{"role-1"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("User() = %+v, want %+v", got, want)
+	}
+}
@@ -1,11 +1,16 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
 )
 
 const (
@@ -17,6 +22,8 @@ const (
 	maxLogLineLen = 200
 	// maxTotalLength はDiscordメッセージの最大文字数（ヘッダー・フッター用の余裕を持たせる）
 	maxTotalLength = 1800
+	// logsUsage はlogsコマンドの使用方法
+	logsUsage = "使用方法: `@bot logs <サービス名> [行数]`\n例: `@bot logs minecraft 50`"
 )
 
 // LogsCommand handles the logs command
@@ -46,27 +53,48 @@ func (c *LogsCommand) Description() string {
 	return "指定されたコンテナのログを表示"
 }
 
-// Execute runs the command
-func (c *LogsCommand) Execute(args []string) (string, error) {
+// RequiredPermission returns the permission required to run the command
+func (c *LogsCommand) RequiredPermission(args []string) permission.Requirement {
+	return serviceRequirement(permission.LevelViewer, args)
+}
+
+// Options returns the slash command options
+func (c *LogsCommand) Options() []*discordgo.ApplicationCommandOption {
+	minLines := 1.0
+	return []*discordgo.ApplicationCommandOption{
+		serviceOption("ログを表示するサービス名"),
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        OptionLines,
+			Description: fmt.Sprintf("表示する行数（デフォルト: %d、最大: %d）", defaultLogCount, maxLogCount),
+			MinValue:    &minLines,
+			MaxValue:    maxLogCount,
+		},
+	}
+}
+
+// executeText は実行結果をテキストで返す
+func (c *LogsCommand) executeText(ctx context.Context, args []string) (string, error) {
 	if len(args) == 0 {
-		return "使用方法: `@bot logs <サービス名> [行数]`\n例: `@bot logs minecraft 50`", nil
+		return logsUsage, nil
 	}
 
 	serviceName := args[0]
 	lines := c.parseLineCount(args)
 
 	// コンテナの存在確認
-	exists, err := c.containerExists(serviceName)
+	target, err := c.findContainer(ctx, serviceName)
 	if err != nil {
 		return "", err
 	}
-	if !exists {
+	if target == nil {
 		return fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName), nil
 	}
 
 	// ログを取得
-	logs, err := c.compose.GetContainerLogs(c.composePath, serviceName, lines)
+	logs, err := c.compose.GetContainerLogs(ctx, c.composePath, serviceName, lines)
 	if err != nil {
+		logFetchFailure(ctx, serviceName, err)
 		return fmt.Sprintf("❌ %s のログ取得に失敗しました: %v", FormatServiceName(serviceName), err), nil
 	}
 
@@ -95,25 +123,37 @@ func (c *LogsCommand) parseLineCount(args []string) int {
 	return lines
 }
 
-// containerExists は指定されたサービスのコンテナが存在するか確認する
-func (c *LogsCommand) containerExists(serviceName string) (bool, error) {
-	containers, err := c.compose.ListContainers(c.composePath)
+// findContainer は指定されたサービスのコンテナを検索する（存在しない場合はnil）
+func (c *LogsCommand) findContainer(ctx context.Context, serviceName string) (*docker.ContainerInfo, error) {
+	containers, err := c.compose.ListContainers(ctx, c.composePath)
 	if err != nil {
-		return false, fmt.Errorf("コンテナ情報の取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("コンテナ情報の取得に失敗しました: %w", err)
 	}
 
 	for i := range containers {
 		if containers[i].Service == serviceName {
-			return true, nil
+			return &containers[i], nil
 		}
 	}
-	return false, nil
+	return nil, nil
 }
 
 // buildLogOutput はログ出力を構築する
 func (c *LogsCommand) buildLogOutput(serviceName string, lines int, logs string) string {
 	var builder strings.Builder
 	builder.WriteString(fmt.Sprintf("📜 **%s のログ** (最新の%d行)\n", FormatServiceName(serviceName), lines))
+	builder.WriteString(c.buildLogBlock(lines, logs))
+
+	// ヒント
+	builder.WriteString("\n💡 **ヒント**: より多くのログを見るには、行数を指定してください\n")
+	builder.WriteString(fmt.Sprintf("例: `@bot logs %s 100`", serviceName))
+
+	return builder.String()
+}
+
+// buildLogBlock はログをコードブロックにして返す
+func (c *LogsCommand) buildLogBlock(lines int, logs string) string {
+	var builder strings.Builder
 	builder.WriteString("```\n")
 
 	// ログが空の場合
@@ -124,12 +164,47 @@ func (c *LogsCommand) buildLogOutput(serviceName string, lines int, logs string)
 	}
 
 	builder.WriteString("```\n")
+	return builder.String()
+}
 
-	// ヒント
-	builder.WriteString("\n💡 **ヒント**: より多くのログを見るには、行数を指定してください\n")
-	builder.WriteString(fmt.Sprintf("例: `@bot logs %s 100`", serviceName))
+// Execute はログを埋め込みで返す
+// 埋め込みの色はコンテナの状態とヘルスチェックの結果から判定する
+func (c *LogsCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if len(inv.Args) == 0 {
+		return TextResponse(logsUsage), nil
+	}
 
-	return builder.String()
+	serviceName := inv.Args[0]
+	lines := c.parseLineCount(inv.Args)
+
+	target, err := c.findContainer(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return TextResponse(fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName)), nil
+	}
+
+	logs, err := c.compose.GetContainerLogs(ctx, c.composePath, serviceName, lines)
+	if err != nil {
+		logFetchFailure(ctx, serviceName, err)
+		return TextResponse(fmt.Sprintf("❌ %s のログ取得に失敗しました: %v", FormatServiceName(serviceName), err)), nil
+	}
+
+	embed := newEmbed(fmt.Sprintf("📜 %s のログ (最新の%d行)", FormatServiceName(serviceName), lines),
+		containerHealth(target))
+	embed.Description = c.buildLogBlock(lines, logs)
+	addEmbedField(embed, "状態", GetStatusIcon(target.State)+" "+target.State, true)
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("💡 より多くのログを見るには行数を指定してください（例: @bot logs %s 100）", serviceName),
+	}
+	return &Response{Embeds: []*discordgo.MessageEmbed{embed}}, nil
+}
+
+// logFetchFailure はログの取得に失敗したことをリクエストのロガーに記録する
+func logFetchFailure(ctx context.Context, serviceName string, err error) {
+	logging.FromContext(ctx).Warn(ctx, "Failed to get container logs",
+		logging.String("service", serviceName), logging.ErrorField(err))
 }
 
 // addFormattedLogs はフォーマットされたログを追加する
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -126,7 +127,7 @@ func TestLogsCommand_parseLineCount(t *testing.T) { // テーブル駆動テス
 	}
 }
 
-func TestLogsCommand_containerExists(t *testing.T) {
+func TestLogsCommand_findContainer(t *testing.T) {
 	tests := []struct {
 		name        string
 		serviceName string
@@ -175,18 +176,18 @@ func TestLogsCommand_containerExists(t *testing.T) {
 			}
 
 			cmd := NewLogsCommand(mockCompose, "test-compose.yml")
-			result, err := cmd.containerExists(tt.serviceName)
+			result, err := cmd.findContainer(context.Background(), tt.serviceName)
 
 			if tt.expectError {
 				if err == nil {
-					t.Errorf("containerExists() expected error, got nil")
+					t.Errorf("findContainer() expected error, got nil")
 				}
 			} else {
 				if err != nil {
-					t.Errorf("containerExists() unexpected error = %v", err)
+					t.Errorf("findContainer() unexpected error = %v", err)
 				}
-				if result != tt.expected {
-					t.Errorf("containerExists() = %v, want %v", result, tt.expected)
+				if (result != nil) != tt.expected {
+					t.Errorf("findContainer() = %v, want found %v", result, tt.expected)
 				}
 			}
 		})
@@ -356,7 +357,7 @@ func TestLogsCommand_Execute(t *testing.T) {
 			}
 
 			cmd := NewLogsCommand(mockCompose, "test-compose.yml")
-			result, err := cmd.Execute(tt.args)
+			result, err := cmd.executeText(context.Background(), tt.args)
 
 			if tt.expectError {
 				if err == nil {
@@ -518,7 +519,7 @@ func TestLogsCommand_MessageLengthLimit(t *testing.T) {
 		},
 	}, "")
 
-	result, err := cmd.Execute([]string{"test", "1000"})
+	result, err := cmd.executeText(context.Background(), []string{"test", "1000"})
 	if err != nil {
 		t.Fatalf("Execute() failed: %v", err)
 	}
@@ -551,7 +552,7 @@ func TestLogsCommand_Performance(t *testing.T) {
 	}, "")
 
 	// 実行時間を測定（大量ログでも合理的な時間で処理されるか）
-	_, err := cmd.Execute([]string{"perf", "200"})
+	_, err := cmd.executeText(context.Background(), []string{"perf", "200"})
 	if err != nil {
 		t.Fatalf("Performance test failed: %v", err)
 	}
@@ -575,7 +576,7 @@ func BenchmarkLogsCommand_Execute(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := cmd.Execute(args)
+		_, err := cmd.executeText(context.Background(), args)
 		if err != nil {
 			b.Fatalf("Execute() failed: %v", err)
 		}
@@ -602,3 +603,79 @@ func BenchmarkLogsCommand_addFormattedLogs(b *testing.B) {
 		cmd.addFormattedLogs(&builder, logs, 100)
 	}
 }
+
+func TestLogsCommand_ExecuteEmbed(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		state       string
+		logsErr     error
+		wantContent string
+		wantColor   int
+	}{
+		{
+			name:        "引数なし",
+			wantContent: "使用方法: `@bot logs <サービス名> [行数]`",
+		},
+		{
+			name:        "存在しないサービス",
+			args:        []string{"db"},
+			state:       "running",
+			wantContent: "❌ サービス 'db' が見つかりません",
+		},
+		{
+			name:        "ログの取得エラー",
+			args:        []string{"web"},
+			state:       "running",
+			logsErr:     errors.New("logs error"),
+			wantContent: "❌ Web のログ取得に失敗しました",
+		},
+		{
+			name:      "稼働中の場合は緑",
+			args:      []string{"web", "20"},
+			state:     "running",
+			wantColor: ColorHealthy,
+		},
+		{
+			name:      "停止中の場合は赤",
+			args:      []string{"web"},
+			state:     "exited",
+			wantColor: ColorCritical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCompose := &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return []docker.ContainerInfo{{Service: "web", Name: "app_web_1", State: tt.state}}, nil
+				},
+				GetContainerLogsFunc: func(string, string, int) (string, error) {
+					return "Log line 1\nLog line 2", tt.logsErr
+				},
+			}
+
+			got, err := NewLogsCommand(mockCompose, "").Execute(context.Background(), &Invocation{Args: tt.args})
+			if err != nil {
+				t.Fatalf("ExecuteEmbed() error = %v", err)
+			}
+			if tt.wantContent != "" {
+				if !strings.Contains(got.Content, tt.wantContent) || len(got.Embeds) != 0 {
+					t.Errorf("ExecuteEmbed() = %+v, want content %q", got, tt.wantContent)
+				}
+				return
+			}
+
+			embed := got.Embeds[0]
+			if embed.Color != tt.wantColor {
+				t.Errorf("Color = %#x, want %#x", embed.Color, tt.wantColor)
+			}
+			if !strings.HasPrefix(embed.Title, "📜 Web のログ") {
+				t.Errorf("Title = %q", embed.Title)
+			}
+			if !strings.Contains(embed.Description, "```\nLog line 1\nLog line 2\n```") {
+				t.Errorf("Description = %q, want the logs in a code block", embed.Description)
+			}
+		})
+	}
+}
@@ -3,42 +3,48 @@ package command
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/hideA88/game-server-watchdog/internal/alert"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
 	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/dashboard"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
 	"github.com/hideA88/game-server-watchdog/pkg/logging"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
 const (
-	// CPUAlertThreshold はCPU使用率のアラート閾値
-	CPUAlertThreshold = 85.0
-	// MemoryAlertThreshold はメモリ使用率のアラート闾値
-	MemoryAlertThreshold = 90.0
-	// DiskAlertThreshold はディスク使用率のアラート闾値
-	DiskAlertThreshold = 90.0
-
 	// DiscordMessageLimit はDiscordメッセージの最大文字数
 	DiscordMessageLimit = 2000
 
-	// ServiceOperationTimeout は操作のタイムアウト時間
-	ServiceOperationTimeout = 60 * time.Second
-
-	// containerStateStopped は停止中のコンテナの状態
-	containerStateStopped = "stopped"
-	// containerStateExited は終了したコンテナの状態
-	containerStateExited = "exited"
 	// gameServiceMinecraft はMinecraftサービス名
 	gameServiceMinecraft = "minecraft"
 	// statusIconUnknown は不明な状態のアイコン
 	statusIconUnknown = "❓"
+
+	// monitorPageSize は1ページに表示するコンテナ数
+	// 起動・停止ボタンの行とページ切り替えの行がDiscordの最大行数に収まる必要がある
+	monitorPageSize = 8
+
+	// customIDMonitorPrev は前のページを表示するボタンのカスタムIDの接頭辞（現在のページ番号が続く）
+	customIDMonitorPrev = "monitor_prev_"
+	// customIDMonitorNext は次のページを表示するボタンのカスタムIDの接頭辞（現在のページ番号が続く）
+	customIDMonitorNext = "monitor_next_"
+	// customIDMonitorRefresh は現在のページを更新するボタンのカスタムIDの接頭辞（現在のページ番号が続く）
+	customIDMonitorRefresh = "monitor_refresh_"
+
+	// monitorUsage はmonitorコマンドの使用方法
+	monitorUsage = "使用方法: `@bot monitor [ページ]`\n例: `@bot monitor 2`"
 )
 
 // MonitorCommand handles the monitor command
@@ -46,25 +52,49 @@ type MonitorCommand struct {
 	compose           docker.ComposeService
 	monitor           system.Monitor
 	composePath       string
-	serviceOperations *sync.Map // サービス名をキーとした操作ロック
+	serviceOperations *oplock.Locker // サービス名をキーとした操作ロック
+	thresholds        alert.ThresholdProvider
+	confirmations     *Confirmations // 停止前の確認（nilの場合は確認しない）
+	recorder          audit.Recorder // 監査ログ（nilの場合は記録しない）
+	prober            gameprobe.Prober
+	dashboards        *dashboard.Manager // ページを切り替えたダッシュボードの記録（nilの場合は記録しない）
 	ctx               context.Context
 }
 
 // NewMonitorCommand creates a new MonitorCommand
+// proberはゲームサーバーへのクエリに使用する（nilの場合はデフォルトのタイムアウトで作成）
 func NewMonitorCommand(
 	ctx context.Context,
 	compose docker.ComposeService,
 	monitor system.Monitor,
 	composePath string,
+	thresholds alert.ThresholdProvider,
+	locks *oplock.Locker,
+	confirmations *Confirmations,
+	recorder audit.Recorder,
+	prober gameprobe.Prober,
 ) *MonitorCommand {
 	if composePath == "" {
 		composePath = defaultComposePath
 	}
+	if thresholds == nil {
+		thresholds = alert.DefaultThresholds()
+	}
+	if locks == nil {
+		locks = oplock.New()
+	}
+	if prober == nil {
+		prober = gameprobe.NewProber(0)
+	}
 	return &MonitorCommand{
 		compose:           compose,
 		monitor:           monitor,
 		composePath:       composePath,
-		serviceOperations: &sync.Map{},
+		serviceOperations: locks,
+		thresholds:        thresholds,
+		confirmations:     confirmations,
+		recorder:          recorder,
+		prober:            prober,
 		ctx:               ctx,
 	}
 }
@@ -79,31 +109,86 @@ func (c *MonitorCommand) Description() string {
 	return "システムとゲームサーバーの監視情報を表示（操作ボタン付き）"
 }
 
-// Execute runs the command
-func (c *MonitorCommand) Execute(_ []string) (string, error) {
+// RequiredPermission returns the permission required to run the command
+func (c *MonitorCommand) RequiredPermission(_ []string) permission.Requirement {
+	return permission.Requirement{Level: permission.LevelViewer}
+}
+
+// Options returns the slash command options
+func (c *MonitorCommand) Options() []*discordgo.ApplicationCommandOption {
+	minPage := 1.0
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        OptionPage,
+			Description: fmt.Sprintf("表示するページ（1ページに%dコンテナ、デフォルト: 1）", monitorPageSize),
+			MinValue:    &minPage,
+		},
+	}
+}
+
+// Execute は監視レポートを埋め込みで返す
+// 埋め込みの色はページに関わらずすべてのコンテナとアラートから判定し、表示しているページの操作ボタンを付ける
+func (c *MonitorCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	page, ok := parseMonitorPage(inv.Args)
+	if !ok {
+		return TextResponse(monitorUsage), nil
+	}
+
+	data, err := c.collectMonitorData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("監視データの収集に失敗しました: %w", err)
+	}
+
+	paged := data.paginate(page, monitorPageSize)
+	embed := c.buildMonitorEmbed(paged, c.monitorHealth(data))
+	return &Response{Embeds: []*discordgo.MessageEmbed{embed}, Components: monitorComponents(paged)}, nil
+}
+
+// executeText は監視レポートをテキストで返す（ダッシュボードなど埋め込みを使用しないメッセージ向け）
+func (c *MonitorCommand) executeText(ctx context.Context, args []string) (*Response, error) {
+	page, ok := parseMonitorPage(args)
+	if !ok {
+		return TextResponse(monitorUsage), nil
+	}
+
 	// データ収集
-	data, err := c.collectMonitorData()
+	data, err := c.collectMonitorData(ctx)
 	if err != nil {
-		return "", fmt.Errorf("監視データの収集に失敗しました: %w", err)
+		return nil, fmt.Errorf("監視データの収集に失敗しました: %w", err)
 	}
 
 	// レポート生成
-	report := c.buildMonitorReport(data)
+	paged := data.paginate(page, monitorPageSize)
+	report := c.buildMonitorReport(paged)
 
-	// メッセージ長チェック
-	if len(report) > DiscordMessageLimit {
-		return c.buildSummaryMessage(data), nil
+	// メッセージ長チェック（Discordの上限はバイト数ではなく文字数）
+	if len([]rune(report)) > DiscordMessageLimit {
+		report = c.buildSummaryMessage(data)
 	}
 
-	return report, nil
+	return &Response{Content: report, Components: monitorComponents(paged)}, nil
+}
+
+// parseMonitorPage は引数から表示するページ（0始まり）を取り出す
+// 引数がない場合は最初のページ、1以上の数値でない場合はfalseを返す
+func parseMonitorPage(args []string) (int, bool) {
+	if len(args) == 0 {
+		return 0, true
+	}
+	page, err := strconv.Atoi(args[0])
+	if err != nil || page < 1 {
+		return 0, false
+	}
+	return page - 1, true
 }
 
 // collectMonitorData は監視データを収集する
-func (c *MonitorCommand) collectMonitorData() (*MonitorData, error) {
+func (c *MonitorCommand) collectMonitorData(ctx context.Context) (*MonitorData, error) {
 	data := &MonitorData{}
 
 	// 1分のタイムアウトを設定
-	ctx, cancel := context.WithTimeout(c.ctx, 1*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
 	// コンテキスト付きのerrgroupを使用
@@ -116,7 +201,7 @@ func (c *MonitorCommand) collectMonitorData() (*MonitorData, error) {
 			data.SystemError = ctx.Err()
 			return nil
 		default:
-			data.SystemInfo, data.SystemError = c.monitor.GetSystemInfo()
+			data.SystemInfo, data.SystemError = c.monitor.GetSystemInfo(ctx)
 			return nil
 		}
 	})
@@ -129,7 +214,7 @@ func (c *MonitorCommand) collectMonitorData() (*MonitorData, error) {
 			return nil
 		default:
 			// まずコンテナ情報を取得
-			data.Containers, data.ContainerError = c.compose.ListContainers(c.composePath)
+			data.Containers, data.ContainerError = c.compose.ListContainers(ctx, c.composePath)
 
 			// コンテナ情報が取得できた場合のみ統計情報を取得
 			if data.ContainerError == nil {
@@ -138,10 +223,9 @@ func (c *MonitorCommand) collectMonitorData() (*MonitorData, error) {
 				case <-ctx.Done():
 					return nil
 				default:
-					data.Stats, data.StatsError = c.compose.GetAllContainersStats(c.composePath)
+					data.Stats, data.StatsError = c.compose.GetAllContainersStats(ctx, c.composePath)
 					if data.StatsError != nil {
-						logger := logging.FromContext(c.ctx)
-						logger.Warn(c.ctx, "Failed to get container stats",
+						logging.FromContext(ctx).Warn(ctx, "Failed to get container stats",
 							logging.ErrorField(data.StatsError))
 					}
 				}
@@ -157,7 +241,10 @@ func (c *MonitorCommand) collectMonitorData() (*MonitorData, error) {
 			data.GameError = ctx.Err()
 			return nil
 		default:
-			data.GameContainers, data.GameError = c.compose.ListGameContainers(c.composePath)
+			data.GameContainers, data.GameError = c.compose.ListGameContainers(ctx, c.composePath)
+			if data.GameError == nil {
+				data.GameStatuses = c.probeGameServers(ctx, data.GameContainers)
+			}
 			return nil
 		}
 	})
@@ -168,6 +255,23 @@ func (c *MonitorCommand) collectMonitorData() (*MonitorData, error) {
 	return data, nil
 }
 
+// probeGameServers は稼働中のゲームサーバーのうちクエリの送信先を設定しているものにクエリを送信する
+func (c *MonitorCommand) probeGameServers(
+	ctx context.Context, gameContainers []docker.ContainerInfo,
+) map[string]gameprobe.Result {
+	var running []docker.ContainerInfo
+	for i := range gameContainers {
+		if strings.EqualFold(gameContainers[i].State, docker.StateRunning) {
+			running = append(running, gameContainers[i])
+		}
+	}
+	targets := gameprobe.Targets(running)
+	if len(targets) == 0 {
+		return nil
+	}
+	return gameprobe.ProbeAll(ctx, c.prober, targets)
+}
+
 // buildMonitorReport は監視レポートを生成する
 func (c *MonitorCommand) buildMonitorReport(data *MonitorData) string {
 	var builder strings.Builder
@@ -193,6 +297,7 @@ func (c *MonitorCommand) buildMonitorReport(data *MonitorData) string {
 		}
 		containerTable := c.buildContainerTable(data.Containers, statsMap)
 		builder.WriteString(containerTable)
+		builder.WriteString(c.buildStatsErrorSection(data.StatsError))
 	}
 
 	// アラート
@@ -204,11 +309,18 @@ func (c *MonitorCommand) buildMonitorReport(data *MonitorData) string {
 	if data.GameError != nil {
 		builder.WriteString("\n⚠️ **ゲームサーバー情報の取得に失敗しました**\n")
 		builder.WriteString(fmt.Sprintf("エラー: %v\n", data.GameError))
+	} else if len(data.GameContainers) == 0 && data.Pages > 1 {
+		builder.WriteString("\n🎮 **ゲームサーバー状態**\n- このページにゲームサーバーはありません\n")
 	} else {
-		gameServerInfo := c.buildGameServerInfo(data.GameContainers)
+		gameServerInfo := c.buildGameServerInfo(data.GameContainers, data.GameStatuses)
 		builder.WriteString(gameServerInfo)
 	}
 
+	// ページ
+	if data.Pages > 1 {
+		builder.WriteString(fmt.Sprintf("\n📄 ページ %d/%d（◀ / ▶ で切り替え）\n", data.Page+1, data.Pages))
+	}
+
 	return builder.String()
 }
 
@@ -235,9 +347,9 @@ func GetGameIcon(service string) string {
 func GetStatusIcon(state string) string {
 	lowerState := strings.ToLower(state)
 	switch lowerState {
-	case containerStateRunning:
+	case docker.StateRunning:
 		return "🟢"
-	case containerStateStopped, containerStateExited:
+	case docker.StateStopped, docker.StateExited:
 		return "🔴"
 	case "restarting":
 		return "🟡"
@@ -250,32 +362,16 @@ func GetStatusIcon(state string) string {
 
 // FormatServiceName formats the service name for display
 func FormatServiceName(service string) string {
-	if service == "" {
-		return ""
-	}
-
-	// Replace hyphens and underscores with spaces
-	formatted := strings.ReplaceAll(service, "-", " ")
-	formatted = strings.ReplaceAll(formatted, "_", " ")
-
-	// Split into words and capitalize each
-	words := strings.Fields(formatted)
-	for i, word := range words {
-		if word != "" {
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
-		}
-	}
-
-	return strings.Join(words, " ")
+	return usermsg.FormatServiceName(service)
 }
 
 // GetHealthIcon returns an icon based on the health status
 func GetHealthIcon(health string) string {
 	lowerHealth := strings.ToLower(health)
 	switch lowerHealth {
-	case "healthy":
+	case docker.HealthHealthy:
 		return "✅"
-	case "unhealthy":
+	case docker.HealthUnhealthy:
 		return "❌"
 	case "starting":
 		return "🔄"
@@ -284,92 +380,122 @@ func GetHealthIcon(health string) string {
 	}
 }
 
-// getServiceFromContainerName extracts service name from container name
-func getServiceFromContainerName(containerName string) string {
-	// Docker Composeのコンテナ名は通常 "project_service_1" の形式
-	parts := strings.Split(containerName, "_")
-	if len(parts) >= 2 && parts[1] != "" {
-		return parts[1]
+// monitorComponents は表示しているページのゲームサーバーの起動・停止ボタンと、ページの切り替え・更新ボタンを返す
+// ゲームコンテナの取得に失敗した場合は起動・停止ボタンを作成しない
+func monitorComponents(data *MonitorData) []discordgo.MessageComponent {
+	var components []discordgo.MessageComponent
+	if data.GameError == nil {
+		components = serviceButtonRows(data.GameContainers)
 	}
-	return containerName
+	return append(components, pageButtonRow(data.Page, data.Pages))
 }
 
-// GetComponents returns Discord message components for the monitor command
-func (c *MonitorCommand) GetComponents(_ []string) ([]discordgo.MessageComponent, error) {
-	containers, err := c.compose.ListGameContainers(c.composePath)
-	if err != nil {
-		return nil, fmt.Errorf("コンテナ情報の取得に失敗しました: %w", err)
-	}
-
-	var components []discordgo.MessageComponent
+// serviceButtonRows は停止中のコンテナに起動ボタン、稼働中のコンテナに停止ボタンを作成し、行に分割する
+// ページ切り替えの行を残すため、最大 MaxButtonRows-1 行まで
+func serviceButtonRows(containers []docker.ContainerInfo) []discordgo.MessageComponent {
 	var buttons []discordgo.MessageComponent
-
-	// ボタン数のカウント（最大値を超えないように）
-	buttonCount := 0
 	for i := range containers {
-		// 最大ボタン数に達したら終了
-		if buttonCount >= docker.MaxTotalButtons {
-			break
-		}
 		// 停止中のコンテナに対しては起動ボタンを追加
-		if strings.EqualFold(containers[i].State, containerStateStopped) ||
-			strings.EqualFold(containers[i].State, containerStateExited) {
-			button := discordgo.Button{
+		if strings.EqualFold(containers[i].State, docker.StateStopped) ||
+			strings.EqualFold(containers[i].State, docker.StateExited) {
+			buttons = append(buttons, discordgo.Button{
 				Label:    fmt.Sprintf("🚀 %s を起動", FormatServiceName(containers[i].Service)),
 				Style:    discordgo.SuccessButton,
 				CustomID: fmt.Sprintf("start_service_%s", containers[i].Service),
-			}
-			buttons = append(buttons, button)
-			buttonCount++
-		} else if strings.EqualFold(containers[i].State, containerStateRunning) {
+			})
+		} else if strings.EqualFold(containers[i].State, docker.StateRunning) {
 			// 稼働中のコンテナに対しては停止ボタンを追加
-			button := discordgo.Button{
+			buttons = append(buttons, discordgo.Button{
 				Label:    fmt.Sprintf("🛑 %s を停止", FormatServiceName(containers[i].Service)),
 				Style:    discordgo.DangerButton,
 				CustomID: fmt.Sprintf("stop_service_%s", containers[i].Service),
-			}
-			buttons = append(buttons, button)
-			buttonCount++
+			})
 		}
 	}
 
-	// ボタンがある場合のみコンポーネントを返す
-	if len(buttons) > 0 {
-		// MaxButtonsPerRow個ずつのボタンをアクションローに分割
-		for i := 0; i < len(buttons); i += docker.MaxButtonsPerRow {
-			end := i + docker.MaxButtonsPerRow
-			if end > len(buttons) {
-				end = len(buttons)
-			}
-
-			row := discordgo.ActionsRow{
-				Components: buttons[i:end],
-			}
-			components = append(components, row)
+	// MaxButtonsPerRow個ずつのボタンをアクションローに分割
+	var rows []discordgo.MessageComponent
+	for i := 0; i < len(buttons) && len(rows) < docker.MaxButtonRows-1; i += docker.MaxButtonsPerRow {
+		end := min(i+docker.MaxButtonsPerRow, len(buttons))
+		rows = append(rows, discordgo.ActionsRow{Components: buttons[i:end]})
+	}
+	return rows
+}
 
-			// 最大MaxButtonRows行まで
-			if len(components) >= docker.MaxButtonRows {
-				break
-			}
-		}
+// pageButtonRow はページの切り替えボタン（ページが1つの場合は更新ボタンのみ）の行を作成する
+func pageButtonRow(page, pages int) discordgo.ActionsRow {
+	refresh := discordgo.Button{
+		Label:    "🔄 更新",
+		Style:    discordgo.SecondaryButton,
+		CustomID: fmt.Sprintf("%s%d", customIDMonitorRefresh, page),
+	}
+	if pages <= 1 {
+		return discordgo.ActionsRow{Components: []discordgo.MessageComponent{refresh}}
 	}
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "◀",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("%s%d", customIDMonitorPrev, page),
+			Disabled: page == 0,
+		},
+		discordgo.Button{
+			Label:    "▶",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("%s%d", customIDMonitorNext, page),
+			Disabled: page >= pages-1,
+		},
+		refresh,
+	}}
+}
+
+// SetDashboards はダッシュボードのメッセージでページを切り替えた場合に、表示中のページを記録する先を設定する
+func (c *MonitorCommand) SetDashboards(dashboards *dashboard.Manager) {
+	c.dashboards = dashboards
+}
 
-	return components, nil
+// Render はダッシュボードに表示する指定したページ（0始まり）の監視レポートと操作ボタンを返す
+func (c *MonitorCommand) Render(ctx context.Context, page int) (string, []discordgo.MessageComponent, error) {
+	resp, err := c.executeText(ctx, []string{strconv.Itoa(page + 1)})
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.Content, resp.Components, nil
 }
 
 // CanHandle は指定されたカスタムIDを処理できるかどうかを返す
 func (c *MonitorCommand) CanHandle(customID string) bool {
+	if _, ok := parsePageCustomID(customID); ok {
+		return true
+	}
 	return strings.HasPrefix(customID, "start_service_") || strings.HasPrefix(customID, "stop_service_")
 }
 
+// RequiredInteractionPermission はサービスの起動/停止に必要な権限を返す
+// ページの切り替えと更新は閲覧と同じ権限で実行できる
+func (c *MonitorCommand) RequiredInteractionPermission(customID string) permission.Requirement {
+	if _, ok := parsePageCustomID(customID); ok {
+		return permission.Requirement{Level: permission.LevelViewer}
+	}
+	service := strings.TrimPrefix(strings.TrimPrefix(customID, "start_service_"), "stop_service_")
+	return permission.Requirement{Level: permission.LevelOperator, Service: service}
+}
+
 // HandleInteraction はサービスの起動/停止インタラクションを処理する
-func (c *MonitorCommand) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+func (c *MonitorCommand) HandleInteraction(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+) error {
 	if i.Type != discordgo.InteractionMessageComponent {
 		return fmt.Errorf("unexpected interaction type: %v", i.Type)
 	}
 
 	data := i.MessageComponentData()
 
+	// ページの切り替えと更新
+	if page, ok := parsePageCustomID(data.CustomID); ok {
+		return c.showPage(ctx, s, i, page)
+	}
+
 	// サービス名と操作を判定
 	var serviceName string
 	var isStart bool
@@ -385,8 +511,13 @@ func (c *MonitorCommand) HandleInteraction(s *discordgo.Session, i *discordgo.In
 		return fmt.Errorf("unknown custom ID: %s", data.CustomID)
 	}
 
+	// 停止は確認が必要なサービスと、接続中のプレイヤーがいるサービスだけ確認してから実行する
+	if !isStart && c.confirmations != nil {
+		return c.confirmOrStop(ctx, s, i, serviceName, c.RequiredInteractionPermission(data.CustomID))
+	}
+
 	// 操作ロックをチェック
-	if _, loaded := c.serviceOperations.LoadOrStore(serviceName, true); loaded {
+	if !c.serviceOperations.TryLock(serviceName) {
 		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -401,55 +532,230 @@ func (c *MonitorCommand) HandleInteraction(s *discordgo.Session, i *discordgo.In
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 	if err != nil {
-		c.serviceOperations.Delete(serviceName)
+		c.serviceOperations.Unlock(serviceName)
+		return fmt.Errorf("failed to send defer response: %w", err)
+	}
+
+	// サービス操作処理を実行（操作は応答後も続くため、インタラクションのキャンセルを引き継がない）
+	go c.handleServiceOperation(context.WithoutCancel(ctx), s, i, serviceName, isStart)
+
+	return nil
+}
+
+// parsePageCustomID はページの切り替え・更新ボタンのカスタムIDから表示するページ（0始まり）を取り出す
+func parsePageCustomID(customID string) (int, bool) {
+	for _, button := range []struct {
+		prefix string
+		offset int
+	}{
+		{customIDMonitorPrev, -1},
+		{customIDMonitorNext, 1},
+		{customIDMonitorRefresh, 0},
+	} {
+		if !strings.HasPrefix(customID, button.prefix) {
+			continue
+		}
+		page, err := strconv.Atoi(strings.TrimPrefix(customID, button.prefix))
+		if err != nil || page < 0 {
+			return 0, false
+		}
+		return max(page+button.offset, 0), true
+	}
+	return 0, false
+}
+
+// showPage はボタンが押されたメッセージを指定したページの最新のレポートに置き換える
+func (c *MonitorCommand) showPage(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, page int,
+) error {
+	// 監視データの収集に時間がかかる場合があるため、先に応答を保留する（3秒以内）
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send defer response: %w", err)
+	}
+
+	edit := c.pageEdit(ctx, []string{strconv.Itoa(page + 1)}, i.Message != nil && len(i.Message.Embeds) > 0)
+	if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+		return fmt.Errorf("failed to update monitor page: %w", err)
+	}
+	// ダッシュボードの場合は、次の更新でも同じページを表示する
+	if c.dashboards != nil && i.Message != nil {
+		c.dashboards.SetPage(ctx, i.ChannelID, i.Message.ID, page)
+	}
+	return nil
+}
+
+// pageEdit は指定したページのレポートと操作ボタンでメッセージを置き換える編集内容を返す
+// 埋め込みのメッセージは埋め込みで、テキストのメッセージ（ダッシュボードなど）はテキストで置き換える
+func (c *MonitorCommand) pageEdit(ctx context.Context, args []string, embed bool) *discordgo.WebhookEdit {
+	var resp *Response
+	var err error
+	if embed {
+		resp, err = c.Execute(ctx, &Invocation{Args: args})
+	} else {
+		resp, err = c.executeText(ctx, args)
+	}
+	if err != nil {
+		resp = TextResponse(err.Error())
+	}
+
+	components := append([]discordgo.MessageComponent{}, resp.Components...)
+	edit := &discordgo.WebhookEdit{Content: &resp.Content, Components: &components}
+	if embed {
+		embeds := append([]*discordgo.MessageEmbed{}, resp.Embeds...)
+		edit.Embeds = &embeds
+	}
+	return edit
+}
+
+// checkServicePlayers はサービスのゲームサーバーに接続中のプレイヤーを問い合わせる
+// コンテナ情報を取得できない場合は人数が分からないものとして扱う
+func (c *MonitorCommand) checkServicePlayers(ctx context.Context, serviceName string) playerCheck {
+	containers, err := c.compose.ListContainers(ctx, c.composePath)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to list containers for player check", logging.ErrorField(err))
+		return playerCheck{}
+	}
+	for i := range containers {
+		if containers[i].Service == serviceName {
+			return checkPlayers(ctx, c.prober, &containers[i])
+		}
+	}
+	return playerCheck{}
+}
+
+// confirmOrStop は接続中のプレイヤーを問い合わせ、確認が必要な場合は確認ボタンを、不要な場合はそのまま停止する
+// プレイヤーの問い合わせに時間がかかる場合があるため、先に実行者のみに見える応答を保留する（3秒以内）
+func (c *MonitorCommand) confirmOrStop(
+	ctx context.Context,
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	serviceName string,
+	req permission.Requirement,
+) error {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
 		return fmt.Errorf("failed to send defer response: %w", err)
 	}
 
-	// サービス操作処理を実行
-	go c.handleServiceOperation(s, i, serviceName, isStart)
+	players := c.checkServicePlayers(ctx, serviceName)
+	if c.confirmations.Required(serviceName) || players.online() {
+		return c.requestStopConfirmation(s, i, serviceName, req, players)
+	}
+
+	if !c.serviceOperations.TryLock(serviceName) {
+		return editInteractionContent(s, i,
+			fmt.Sprintf("⚠️ %s は現在操作中です。しばらくお待ちください。", FormatServiceName(serviceName)))
+	}
+	// 保留した応答を編集してから、結果は全員に見えるフォローアップメッセージで送信する
+	if err := editInteractionContent(s, i, fmt.Sprintf("⏳ %s を停止しています…", FormatServiceName(serviceName))); err != nil {
+		c.serviceOperations.Unlock(serviceName)
+		return err
+	}
+	go c.handleServiceOperation(context.WithoutCancel(ctx), s, i, serviceName, false)
+	return nil
+}
+
+// requestStopConfirmation は保留した応答を停止の確認ボタンに置き換える
+// 接続中のプレイヤーがいる場合はプレイヤーを表示し、いなくなってから停止するボタンも表示する
+func (c *MonitorCommand) requestStopConfirmation(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	serviceName string,
+	req permission.Requirement,
+	players playerCheck,
+) error {
+	stop := func(ctx context.Context, actor audit.Actor) string {
+		return c.stopService(ctx, actor, serviceName)
+	}
+	userID := InteractionActor(i).UserID
+	components, err := c.confirmations.Request(serviceName, "停止", req, userID, stop, players.waitEmpty(c.prober))
+	if err != nil {
+		_ = editInteractionContent(s, i, "❌ "+err.Error())
+		return err
+	}
+
+	content := c.confirmations.Prompt(serviceName, "停止", players.status)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send stop confirmation: %w", err)
+	}
+	c.confirmations.ClearOnExpiry(components, func(content string) error {
+		return clearInteractionResponse(s, i, content)
+	})
+	return nil
+}
 
+// editInteractionContent は保留した応答の本文を置き換える
+func editInteractionContent(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content}); err != nil {
+		return fmt.Errorf("failed to edit interaction response: %w", err)
+	}
 	return nil
 }
 
+// clearInteractionResponse は保留した応答の本文を置き換え、ボタンを取り除く
+func clearInteractionResponse(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &[]discordgo.MessageComponent{},
+	})
+	return err
+}
+
+// stopService は確認後にサービスを停止し、結果のメッセージを返す
+func (c *MonitorCommand) stopService(ctx context.Context, actor audit.Actor, serviceName string) string {
+	if !c.serviceOperations.TryLock(serviceName) {
+		return fmt.Sprintf("⚠️ %s は現在操作中です。しばらくお待ちください。", FormatServiceName(serviceName))
+	}
+	defer c.serviceOperations.Unlock(serviceName)
+
+	result := c.executeServiceOperation(ctx, actor, serviceName, false)
+	c.logOperationResult(ctx, serviceName, false, result.Err)
+	return c.createResponseMessage(serviceName, result)
+}
+
 // handleServiceOperation はサービスの起動/停止処理を行う
+// ctxはインタラクションのロガーを引き継ぎ、キャンセルされないコンテキスト
 func (c *MonitorCommand) handleServiceOperation(
+	ctx context.Context,
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	serviceName string,
 	isStart bool,
 ) {
-	// 初期化と検証
-	ctx, cancel, logger := c.setupServiceOperation()
-	defer cancel()
-	defer c.serviceOperations.Delete(serviceName)
+	// 操作ロックはDockerの操作が終わるまで保持する
+	logger := logging.FromContext(ctx)
+	defer c.serviceOperations.Unlock(serviceName)
 
 	// パニックリカバリーを設定
 	defer c.handlePanicRecovery(ctx, s, i, serviceName, isStart, logger)
 
 	// 親コンテキストのキャンセルをチェック
-	if c.isParentContextCanceled(serviceName, logger) {
+	if c.isParentContextCanceled(ctx, serviceName, logger) {
 		return
 	}
 
 	// サービス操作を実行
-	result := c.executeServiceOperation(ctx, serviceName, isStart)
+	result := c.executeServiceOperation(ctx, InteractionActor(i), serviceName, isStart)
 
 	// 結果を処理してメッセージを送信
-	c.handleOperationResult(s, i, serviceName, isStart, result, logger)
+	c.handleOperationResult(ctx, s, i, serviceName, isStart, result, logger)
 }
 
-// setupServiceOperation は初期化とコンテキストの設定を行う
-func (c *MonitorCommand) setupServiceOperation() (context.Context, context.CancelFunc, logging.Logger) {
-	logger := logging.FromContext(c.ctx)
-	ctx, cancel := context.WithTimeout(c.ctx, ServiceOperationTimeout)
-	return ctx, cancel, logger
-}
-
-// isParentContextCanceled は親コンテキストがキャンセルされているかチェックする
-func (c *MonitorCommand) isParentContextCanceled(serviceName string, logger logging.Logger) bool {
+// isParentContextCanceled はボットの停止などで親コンテキストがキャンセルされているかチェックする
+func (c *MonitorCommand) isParentContextCanceled(ctx context.Context, serviceName string, logger logging.Logger) bool {
 	select {
 	case <-c.ctx.Done():
-		logger.Warn(c.ctx, "Parent context canceled, aborting operation",
+		logger.Warn(ctx, "Parent context canceled, aborting operation",
 			logging.String("service", serviceName))
 		return true
 	default:
@@ -488,45 +794,37 @@ type ServiceOperationResult struct {
 }
 
 // executeServiceOperation はサービス操作を実行し、結果を返す
+// Dockerの操作は停止前のコマンドと停止を待つ時間を含めてpkg/dockerが制限するため、完了するまで待つ
 func (c *MonitorCommand) executeServiceOperation(
 	ctx context.Context,
+	actor audit.Actor,
 	serviceName string,
 	isStart bool,
 ) ServiceOperationResult {
 	var result ServiceOperationResult
-
-	// タイムアウトチャンネルとの競合を処理
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		if isStart {
-			result.Err = c.compose.StartService(c.composePath, serviceName)
-			formattedName := FormatServiceName(serviceName)
-			result.SuccessMessage = fmt.Sprintf("✅ %s を起動しました！", formattedName)
-			result.ErrorPrefix = "起動"
-		} else {
-			result.Err = c.compose.StopService(c.composePath, serviceName)
-			formattedName := FormatServiceName(serviceName)
-			result.SuccessMessage = fmt.Sprintf("🛑 %s を停止しました。", formattedName)
-			result.ErrorPrefix = "停止"
-		}
-	}()
-
-	// タイムアウトまたは完了を待つ
-	select {
-	case <-done:
-		// 正常完了
-	case <-ctx.Done():
-		// タイムアウト
-		result.Err = fmt.Errorf("操作がタイムアウトしました (%v)", ServiceOperationTimeout)
-		result.ErrorPrefix = "操作"
+	formattedName := FormatServiceName(serviceName)
+	entry := audit.Entry{Actor: actor, Service: serviceName, Source: audit.SourceButton}
+	if isStart {
+		entry.Action = audit.ActionStart
+		result.Err = audit.Track(ctx, c.recorder, entry, func() error {
+			return c.compose.StartService(ctx, c.composePath, serviceName)
+		})
+		result.SuccessMessage = fmt.Sprintf("✅ %s を起動しました！", formattedName)
+		result.ErrorPrefix = "起動"
+	} else {
+		entry.Action = audit.ActionStop
+		result.Err = audit.Track(ctx, c.recorder, entry, func() error {
+			return c.compose.StopService(ctx, c.composePath, serviceName)
+		})
+		result.SuccessMessage = fmt.Sprintf("🛑 %s を停止しました。", formattedName)
+		result.ErrorPrefix = "停止"
 	}
-
 	return result
 }
 
 // handleOperationResult は操作結果を処理してDiscordにメッセージを送信する
 func (c *MonitorCommand) handleOperationResult(
+	ctx context.Context,
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	serviceName string,
@@ -538,10 +836,10 @@ func (c *MonitorCommand) handleOperationResult(
 	content := c.createResponseMessage(serviceName, result)
 
 	// ログを出力
-	c.logOperationResult(serviceName, isStart, result.Err, logger)
+	c.logOperationResult(ctx, serviceName, isStart, result.Err)
 
 	// フォローアップメッセージを送信
-	c.sendFollowupMessage(s, i, content, logger)
+	c.sendFollowupMessage(ctx, s, i, content, logger)
 }
 
 // createResponseMessage は操作結果に基づいてレスポンスメッセージを作成する
@@ -555,14 +853,15 @@ func (c *MonitorCommand) createResponseMessage(serviceName string, result Servic
 }
 
 // logOperationResult は操作結果をログに記録する
-func (c *MonitorCommand) logOperationResult(serviceName string, isStart bool, err error, logger logging.Logger) {
+func (c *MonitorCommand) logOperationResult(ctx context.Context, serviceName string, isStart bool, err error) {
+	logger := logging.FromContext(ctx)
 	if err != nil {
-		logger.Error(c.ctx, "Service operation failed",
+		logger.Error(ctx, "Service operation failed",
 			logging.String("service", serviceName),
 			logging.Bool("start", isStart),
 			logging.ErrorField(err))
 	} else {
-		logger.Info(c.ctx, "Service operation succeeded",
+		logger.Info(ctx, "Service operation succeeded",
 			logging.String("service", serviceName),
 			logging.Bool("start", isStart))
 	}
@@ -570,6 +869,7 @@ func (c *MonitorCommand) logOperationResult(serviceName string, isStart bool, er
 
 // sendFollowupMessage はDiscordにフォローアップメッセージを送信する
 func (c *MonitorCommand) sendFollowupMessage(
+	ctx context.Context,
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	content string,
@@ -579,6 +879,6 @@ func (c *MonitorCommand) sendFollowupMessage(
 		Content: content,
 	})
 	if err != nil {
-		logger.Error(c.ctx, "Failed to send followup message", logging.ErrorField(err))
+		logger.Error(ctx, "Failed to send followup message", logging.ErrorField(err))
 	}
 }
@@ -1,10 +1,16 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/alert"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
@@ -14,8 +20,12 @@ func (c *MonitorCommand) buildSystemInfo(sysInfo *system.SystemInfo) string {
 		return "⚠️ システム情報の取得に失敗しました\n"
 	}
 
+	return "📊 **ホストサーバー**\n" + formatSystemInfo(sysInfo)
+}
+
+// formatSystemInfo はCPU・メモリ・ディスクの使用率をプログレスバー付きで1行ずつ返す
+func formatSystemInfo(sysInfo *system.SystemInfo) string {
 	var builder strings.Builder
-	builder.WriteString("📊 **ホストサーバー**\n")
 
 	// CPU使用率
 	cpuBar := NewProgressBar(sysInfo.CPUUsagePercent, 10)
@@ -81,13 +91,7 @@ func (c *MonitorCommand) formatContainerRow(
 	var cpu, memory string
 	if stat, ok := statsMap[container.Name]; ok && stat != nil {
 		cpu = fmt.Sprintf("%6.1f%%", stat.CPUPercent)
-		// メモリ使用量から数値を抽出
-		memParts := strings.Split(stat.MemoryUsage, " / ")
-		if len(memParts) > 0 {
-			memory = fmt.Sprintf("%-8s", memParts[0])
-		} else {
-			memory = fmt.Sprintf("%-8s", "-")
-		}
+		memory = fmt.Sprintf("%-8s", usermsg.FormatGiB(stat.MemoryUsageBytes))
 	} else {
 		cpu = fmt.Sprintf("%-8s", "-")
 		memory = fmt.Sprintf("%-8s", "-")
@@ -107,54 +111,21 @@ func (c *MonitorCommand) formatContainerRow(
 		serviceName, state, cpu, memory, runningFor)
 }
 
-// checkAlerts はアラートをチェックして返す
-func (c *MonitorCommand) checkAlerts(sysInfo *system.SystemInfo, stats []docker.ContainerStats) []Alert {
-	var alerts []Alert
-
-	// コンテナのアラートチェック
-	for i := range stats {
-		if stats[i].CPUPercent > CPUAlertThreshold {
-			alerts = append(alerts, Alert{
-				Component: FormatServiceName(getServiceFromContainerName(stats[i].Name)),
-				Message:   "CPU使用率が高い",
-				Value:     stats[i].CPUPercent,
-			})
-		}
-		if stats[i].MemoryPercent > MemoryAlertThreshold {
-			alerts = append(alerts, Alert{
-				Component: FormatServiceName(getServiceFromContainerName(stats[i].Name)),
-				Message:   "メモリ使用率が高い",
-				Value:     stats[i].MemoryPercent,
-			})
-		}
+// buildStatsErrorSection は統計情報を取得できなかった場合の注意書きを生成する
+func (c *MonitorCommand) buildStatsErrorSection(err error) string {
+	if err == nil {
+		return ""
 	}
-
-	// ホストシステムのアラート
-	if sysInfo != nil {
-		if sysInfo.CPUUsagePercent > CPUAlertThreshold {
-			alerts = append(alerts, Alert{
-				Component: "ホストサーバー",
-				Message:   "CPU使用率が高い",
-				Value:     sysInfo.CPUUsagePercent,
-			})
-		}
-		if sysInfo.MemoryUsedPercent > MemoryAlertThreshold {
-			alerts = append(alerts, Alert{
-				Component: "ホストサーバー",
-				Message:   "メモリ使用率が高い",
-				Value:     sysInfo.MemoryUsedPercent,
-			})
-		}
-		if sysInfo.DiskUsedPercent > DiskAlertThreshold {
-			alerts = append(alerts, Alert{
-				Component: "ホストサーバー",
-				Message:   "ディスク使用率が高い",
-				Value:     sysInfo.DiskUsedPercent,
-			})
-		}
+	var statsErr *docker.ContainerStatsError
+	if errors.As(err, &statsErr) {
+		return fmt.Sprintf("⚠️ 統計情報を取得できなかったコンテナ: %s\n", strings.Join(statsErr.Containers(), ", "))
 	}
+	return "⚠️ コンテナの統計情報の取得に失敗しました\n"
+}
 
-	return alerts
+// checkAlerts はアラートをチェックして返す
+func (c *MonitorCommand) checkAlerts(sysInfo *system.SystemInfo, stats []docker.ContainerStats) []Alert {
+	return alert.Check(sysInfo, stats, c.thresholds)
 }
 
 // buildAlertSection はアラートセクションを生成する
@@ -165,40 +136,60 @@ func (c *MonitorCommand) buildAlertSection(alerts []Alert) string {
 	if len(alerts) == 0 {
 		builder.WriteString("- 現在アラートはありません\n")
 	} else {
-		for _, alert := range alerts {
-			builder.WriteString(fmt.Sprintf("- %s: %s (%.1f%%)\n",
-				alert.Component, alert.Message, alert.Value))
-		}
+		builder.WriteString(formatAlerts(alerts) + "\n")
 	}
 
 	return builder.String()
 }
 
 // buildGameServerInfo はゲームサーバー情報を生成する
-func (c *MonitorCommand) buildGameServerInfo(gameContainers []docker.ContainerInfo) string {
+func (c *MonitorCommand) buildGameServerInfo(
+	gameContainers []docker.ContainerInfo, statuses map[string]gameprobe.Result,
+) string {
+	if len(gameContainers) == 0 {
+		return "\n🎮 **ゲームサーバー状態**\n- 現在稼働中のゲームサーバーはありません\n"
+	}
+	return "\n🎮 **ゲームサーバー状態**\n" + formatGameServers(gameContainers, statuses)
+}
+
+// formatGameServers はゲームサーバーの状態を1行ずつ返す
+// クエリを送信したゲームサーバーは、応答の有無とプレイヤー数などを次の行に続ける
+func formatGameServers(gameContainers []docker.ContainerInfo, statuses map[string]gameprobe.Result) string {
 	var builder strings.Builder
-	builder.WriteString("\n🎮 **ゲームサーバー状態**\n")
+	for i := range gameContainers {
+		// Status icon and name
+		statusIcon := GetStatusIcon(gameContainers[i].State)
+		gameIcon := GetGameIcon(gameContainers[i].Service)
+		builder.WriteString(fmt.Sprintf("• %s %s **%s**: %s",
+			statusIcon, gameIcon, FormatServiceName(gameContainers[i].Service), gameContainers[i].State))
+
+		if strings.EqualFold(gameContainers[i].State, docker.StateRunning) && gameContainers[i].RunningFor != "" {
+			builder.WriteString(fmt.Sprintf(" (%s)", gameContainers[i].RunningFor))
+		}
+		builder.WriteString("\n")
 
-	if len(gameContainers) == 0 {
-		builder.WriteString("- 現在稼働中のゲームサーバーはありません\n")
-	} else {
-		for i := range gameContainers {
-			// Status icon and name
-			statusIcon := GetStatusIcon(gameContainers[i].State)
-			gameIcon := GetGameIcon(gameContainers[i].Service)
-			builder.WriteString(fmt.Sprintf("• %s %s **%s**: %s",
-				statusIcon, gameIcon, FormatServiceName(gameContainers[i].Service), gameContainers[i].State))
-
-			if strings.EqualFold(gameContainers[i].State, containerStateRunning) && gameContainers[i].RunningFor != "" {
-				builder.WriteString(fmt.Sprintf(" (%s)", gameContainers[i].RunningFor))
-			}
-			builder.WriteString("\n")
+		if result, ok := statuses[gameContainers[i].Name]; ok {
+			builder.WriteString("  └ " + formatGameStatus(result) + "\n")
 		}
 	}
-
 	return builder.String()
 }
 
+// formatGameStatus はゲームサーバーへのクエリの結果（応答の有無・プレイヤー数・バージョン・マップ）を返す
+func formatGameStatus(result gameprobe.Result) string {
+	if !result.Online() {
+		return "🔴 オフライン（クエリに応答しません）"
+	}
+	parts := []string{fmt.Sprintf("🟢 オンライン %d/%d人", result.Status.Players, result.Status.MaxPlayers)}
+	if result.Status.Version != "" {
+		parts = append(parts, "バージョン: "+result.Status.Version)
+	}
+	if result.Status.Map != "" {
+		parts = append(parts, "マップ: "+result.Status.Map)
+	}
+	return strings.Join(parts, " | ")
+}
+
 // buildSummaryMessage は要約版メッセージを生成する
 func (c *MonitorCommand) buildSummaryMessage(data *MonitorData) string {
 	var builder strings.Builder
@@ -214,7 +205,7 @@ func (c *MonitorCommand) buildSummaryMessage(data *MonitorData) string {
 	if data.Containers != nil {
 		runningCount := 0
 		for i := range data.Containers {
-			if strings.EqualFold(data.Containers[i].State, containerStateRunning) {
+			if strings.EqualFold(data.Containers[i].State, docker.StateRunning) {
 				runningCount++
 			}
 		}
@@ -232,3 +223,110 @@ func (c *MonitorCommand) buildSummaryMessage(data *MonitorData) string {
 	builder.WriteString("\n*詳細情報が多すぎるため要約版を表示しています*")
 	return builder.String()
 }
+
+// buildMonitorEmbed は監視レポートの埋め込みを生成する
+// healthはページに関わらず監視データ全体から判定した状態
+func (c *MonitorCommand) buildMonitorEmbed(data *MonitorData, health Health) *discordgo.MessageEmbed {
+	embed := newEmbed("🖥️ システム監視ダッシュボード", health)
+
+	// システム情報
+	if data.SystemInfo == nil {
+		addEmbedField(embed, "📊 ホストサーバー", "⚠️ システム情報の取得に失敗しました", false)
+	} else {
+		addEmbedField(embed, "📊 ホストサーバー", formatSystemInfo(data.SystemInfo), false)
+	}
+
+	// コンテナ一覧
+	addEmbedField(embed, "📦 コンテナ状況", c.buildContainerList(data), false)
+
+	// アラート
+	alerts := c.checkAlerts(data.SystemInfo, data.Stats)
+	if len(alerts) == 0 {
+		addEmbedField(embed, "⚠️ アラート", "現在アラートはありません", false)
+	} else {
+		addEmbedField(embed, "⚠️ アラート", formatAlerts(alerts), false)
+	}
+
+	// ゲームサーバー情報
+	switch {
+	case data.GameError != nil:
+		addEmbedField(embed, "🎮 ゲームサーバー状態",
+			fmt.Sprintf("⚠️ ゲームサーバー情報の取得に失敗しました\nエラー: %v", data.GameError), false)
+	case len(data.GameContainers) == 0 && data.Pages > 1:
+		addEmbedField(embed, "🎮 ゲームサーバー状態", "このページにゲームサーバーはありません", false)
+	case len(data.GameContainers) == 0:
+		addEmbedField(embed, "🎮 ゲームサーバー状態", "現在稼働中のゲームサーバーはありません", false)
+	default:
+		addEmbedField(embed, "🎮 ゲームサーバー状態", formatGameServers(data.GameContainers, data.GameStatuses), false)
+	}
+
+	// ページ
+	if data.Pages > 1 {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("📄 ページ %d/%d（◀ / ▶ で切り替え）", data.Page+1, data.Pages),
+		}
+	}
+
+	return embed
+}
+
+// buildContainerList はコンテナの状態とリソース使用状況を1行ずつ返す（埋め込みのフィールド用）
+func (c *MonitorCommand) buildContainerList(data *MonitorData) string {
+	if data.ContainerError != nil {
+		if docker.IsPermissionDenied(data.ContainerError) {
+			return "⚠️ コンテナ情報の取得に失敗しました\n" + usermsg.DockerPermissionMessage()
+		}
+		return fmt.Sprintf("⚠️ コンテナ情報の取得に失敗しました\nエラー: %v", data.ContainerError)
+	}
+	if len(data.Containers) == 0 {
+		return "稼働中のコンテナはありません"
+	}
+
+	statsMap := make(map[string]*docker.ContainerStats)
+	for i := range data.Stats {
+		statsMap[data.Stats[i].Name] = &data.Stats[i]
+	}
+
+	var builder strings.Builder
+	for i := range data.Containers {
+		container := &data.Containers[i]
+		fmt.Fprintf(&builder, "%s **%s**", GetStatusIcon(container.State), FormatServiceName(container.Service))
+		if stat, ok := statsMap[container.Name]; ok && stat != nil {
+			fmt.Fprintf(&builder, " CPU %.1f%% | MEM %s", stat.CPUPercent, usermsg.FormatGiB(stat.MemoryUsageBytes))
+		}
+		if container.RunningFor != "" {
+			fmt.Fprintf(&builder, " | %s", container.RunningFor)
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(c.buildStatsErrorSection(data.StatsError))
+	return builder.String()
+}
+
+// monitorHealth は監視データから埋め込みの色分けに使用する状態を判定する
+// 情報を取得できない場合やunhealthyのコンテナがある場合は異常、
+// アラートや統計情報の取得失敗、稼働していないコンテナ、クエリに応答しないゲームサーバーがある場合は注意とする
+func (c *MonitorCommand) monitorHealth(data *MonitorData) Health {
+	if data.SystemError != nil || data.ContainerError != nil || data.GameError != nil {
+		return HealthCritical
+	}
+
+	health := HealthOK
+	if data.StatsError != nil || len(c.checkAlerts(data.SystemInfo, data.Stats)) > 0 {
+		health = HealthWarning
+	}
+	for i := range data.Containers {
+		switch {
+		case strings.EqualFold(data.Containers[i].HealthStatus, docker.HealthUnhealthy):
+			return HealthCritical
+		case containerHealth(&data.Containers[i]) != HealthOK:
+			health = HealthWarning
+		}
+	}
+	for _, result := range data.GameStatuses {
+		if !result.Online() {
+			health = HealthWarning
+		}
+	}
+	return health
+}
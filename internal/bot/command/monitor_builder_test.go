@@ -1,10 +1,13 @@
 package command
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/hideA88/game-server-watchdog/internal/alert"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
@@ -47,7 +50,7 @@ func TestMonitorCommand_buildSystemInfo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := &MonitorCommand{}
+			cmd := &MonitorCommand{thresholds: alert.DefaultThresholds()}
 			got := cmd.buildSystemInfo(tt.sysInfo)
 
 			for _, want := range tt.wantContains {
@@ -120,7 +123,7 @@ func TestMonitorCommand_checkAlerts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := &MonitorCommand{}
+			cmd := &MonitorCommand{thresholds: alert.DefaultThresholds()}
 			alerts := cmd.checkAlerts(tt.sysInfo, tt.stats)
 
 			if len(alerts) != tt.wantAlerts {
@@ -148,7 +151,9 @@ func TestMonitorCommand_buildGameServerInfo(t *testing.T) {
 	tests := []struct {
 		name           string
 		gameContainers []docker.ContainerInfo
+		statuses       map[string]gameprobe.Result
 		wantContains   []string
+		wantMissing    []string
 	}{
 		{
 			name: "稼働中のゲームサーバー",
@@ -168,6 +173,34 @@ func TestMonitorCommand_buildGameServerInfo(t *testing.T) {
 				"🟢 ⛏️ **Minecraft**: running (2 hours)",
 				"🔴 🌳 **Terraria**: stopped",
 			},
+			wantMissing: []string{"└"},
+		},
+		{
+			name: "クエリに応答したゲームサーバー",
+			gameContainers: []docker.ContainerInfo{
+				{Name: "mc-1", Service: "minecraft", State: "running"},
+				{Name: "valheim-1", Service: "valheim", State: "running"},
+			},
+			statuses: map[string]gameprobe.Result{
+				"mc-1":      {Status: &gameprobe.Status{Players: 3, MaxPlayers: 20, Version: "1.20.4"}},
+				"valheim-1": {Status: &gameprobe.Status{Players: 0, MaxPlayers: 10, Version: "0.217.46", Map: "Dedicated"}},
+			},
+			wantContains: []string{
+				"⛏️ **Minecraft**: running\n  └ 🟢 オンライン 3/20人 | バージョン: 1.20.4\n",
+				"⚔️ **Valheim**: running\n  └ 🟢 オンライン 0/10人 | バージョン: 0.217.46 | マップ: Dedicated\n",
+			},
+		},
+		{
+			name: "クエリに応答しないゲームサーバー",
+			gameContainers: []docker.ContainerInfo{
+				{Name: "mc-1", Service: "minecraft", State: "running"},
+			},
+			statuses: map[string]gameprobe.Result{
+				"mc-1": {Err: errors.New("i/o timeout")},
+			},
+			wantContains: []string{
+				"⛏️ **Minecraft**: running\n  └ 🔴 オフライン（クエリに応答しません）\n",
+			},
 		},
 		{
 			name:           "ゲームサーバーなし",
@@ -180,14 +213,19 @@ func TestMonitorCommand_buildGameServerInfo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := &MonitorCommand{}
-			got := cmd.buildGameServerInfo(tt.gameContainers)
+			cmd := &MonitorCommand{thresholds: alert.DefaultThresholds()}
+			got := cmd.buildGameServerInfo(tt.gameContainers, tt.statuses)
 
 			for _, want := range tt.wantContains {
 				if !strings.Contains(got, want) {
 					t.Errorf("buildGameServerInfo() does not contain %q\nGot: %s", want, got)
 				}
 			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(got, missing) {
+					t.Errorf("buildGameServerInfo() should not contain %q\nGot: %s", missing, got)
+				}
+			}
 		})
 	}
 }
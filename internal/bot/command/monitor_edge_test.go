@@ -6,12 +6,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hideA88/game-server-watchdog/internal/alert"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
 func TestMonitorCommand_ExecuteWithLongMessage(t *testing.T) {
-	// 2000文字を超えるレスポンスを生成するテスト
 	mockMonitor := &system.MockMonitor{
 		SystemInfo: &system.SystemInfo{
 			CPUUsagePercent:   65.5,
@@ -23,58 +23,93 @@ func TestMonitorCommand_ExecuteWithLongMessage(t *testing.T) {
 		},
 	}
 
-	// 多数のコンテナを生成
-	var containers []docker.ContainerInfo
-	var stats []docker.ContainerStats
-	for i := 0; i < 50; i++ {
-		containers = append(containers, docker.ContainerInfo{
-			ID:         fmt.Sprintf("container%d", i),
-			Name:       fmt.Sprintf("very-long-container-name-for-testing-%d", i),
-			Service:    fmt.Sprintf("service%d", i),
-			State:      "running",
-			RunningFor: "2 hours",
-			Ports:      []string{"8080->8080/tcp", "9090->9090/tcp"},
-		})
-		stats = append(stats, docker.ContainerStats{
-			Name:          fmt.Sprintf("very-long-container-name-for-testing-%d", i),
-			CPUPercent:    float64(i % 100),
-			MemoryPercent: float64((i * 2) % 100),
-			MemoryUsage:   fmt.Sprintf("%d.%dGiB", i/10, i%10),
-		})
-	}
-
-	mockCompose := &docker.MockComposeService{
-		ListContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
-			return containers, nil
+	tests := []struct {
+		name         string
+		cpuPercent   func(i int) float64
+		args         []string
+		wantContains []string
+	}{
+		{
+			name:       "多数のコンテナはページに分けて表示",
+			cpuPercent: func(i int) float64 { return float64(i % 80) },
+			wantContains: []string{
+				"│service0 ",
+				"│service7 ",
+				"📄 ページ 1/7（◀ / ▶ で切り替え）",
+			},
 		},
-		ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
-			return containers[:10], nil // ゲームコンテナは10個
+		{
+			name:       "指定したページを表示",
+			cpuPercent: func(i int) float64 { return float64(i % 80) },
+			args:       []string{"7"},
+			wantContains: []string{
+				"│service48 ",
+				"│service49 ",
+				"- このページにゲームサーバーはありません",
+				"📄 ページ 7/7",
+			},
 		},
-		GetAllContainersStatsFunc: func(_ string) ([]docker.ContainerStats, error) {
-			return stats, nil
+		{
+			name:       "1ページでも長すぎる場合は要約版",
+			cpuPercent: func(int) float64 { return 99 },
+			wantContains: []string{
+				"(要約版)",
+				"詳細情報が多すぎるため要約版を表示しています",
+			},
 		},
 	}
 
-	cmd := NewMonitorCommand(context.Background(), mockCompose, mockMonitor, "")
-	result, err := cmd.Execute([]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 多数のコンテナを生成
+			var containers []docker.ContainerInfo
+			var stats []docker.ContainerStats
+			for i := 0; i < 50; i++ {
+				containers = append(containers, docker.ContainerInfo{
+					ID:         fmt.Sprintf("container%d", i),
+					Name:       fmt.Sprintf("very-long-container-name-for-testing-%d", i),
+					Service:    fmt.Sprintf("service%d", i),
+					State:      "running",
+					RunningFor: "2 hours",
+					Ports:      []string{"8080->8080/tcp", "9090->9090/tcp"},
+				})
+				stats = append(stats, docker.ContainerStats{
+					Name:             fmt.Sprintf("very-long-container-name-for-testing-%d", i),
+					CPUPercent:       tt.cpuPercent(i),
+					MemoryPercent:    float64(i % 80),
+					MemoryUsageBytes: uint64(i) * 1024 * 1024 * 1024 / 10,
+				})
+			}
 
-	if err != nil {
-		t.Errorf("Execute() error = %v", err)
-		return
-	}
+			mockCompose := &docker.MockComposeService{
+				ListContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+					return containers, nil
+				},
+				ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+					return containers[:10], nil // ゲームコンテナは10個
+				},
+				GetAllContainersStatsFunc: func(_ string) ([]docker.ContainerStats, error) {
+					return stats, nil
+				},
+			}
 
-	// 要約版が返されることを確認
-	if !strings.Contains(result, "(要約版)") {
-		t.Error("Expected summary version, but got full version")
-	}
+			cmd := NewMonitorCommand(context.Background(), mockCompose, mockMonitor, "", nil, nil, nil, nil, nil)
+			result, err := monitorText(cmd, tt.args)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
 
-	if !strings.Contains(result, "詳細情報が多すぎるため要約版を表示しています") {
-		t.Error("Expected summary message explanation")
-	}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(result, want) {
+					t.Errorf("Execute() result does not contain %q\nGot: %s", want, result)
+				}
+			}
 
-	// メッセージ長が2000文字以下であることを確認
-	if len(result) > DiscordMessageLimit {
-		t.Errorf("Message length %d exceeds Discord limit %d", len(result), DiscordMessageLimit)
+			// メッセージ長が2000文字以下であることを確認
+			if n := len([]rune(result)); n > DiscordMessageLimit {
+				t.Errorf("Message length %d exceeds Discord limit %d", n, DiscordMessageLimit)
+			}
+		})
 	}
 }
 
@@ -125,8 +160,8 @@ func TestMonitorCommand_collectMonitorDataWithErrors(t *testing.T) {
 				},
 			}
 
-			cmd := NewMonitorCommand(context.Background(), mockCompose, mockMonitor, "")
-			data, err := cmd.collectMonitorData()
+			cmd := NewMonitorCommand(context.Background(), mockCompose, mockMonitor, "", nil, nil, nil, nil, nil)
+			data, err := cmd.collectMonitorData(context.Background())
 
 			if err != nil {
 				t.Errorf("collectMonitorData() returned unexpected error: %v", err)
@@ -152,7 +187,7 @@ func TestMonitorCommand_collectMonitorDataWithErrors(t *testing.T) {
 }
 
 func TestMonitorCommand_formatContainerRowEdgeCases(t *testing.T) {
-	cmd := &MonitorCommand{}
+	cmd := &MonitorCommand{thresholds: alert.DefaultThresholds()}
 
 	tests := []struct {
 		name      string
@@ -179,7 +214,7 @@ func TestMonitorCommand_formatContainerRowEdgeCases(t *testing.T) {
 			want:  []string{"-", "-"}, // CPU、メモリ共に "-"
 		},
 		{
-			name: "メモリ使用量形式異常",
+			name: "メモリ使用量が0",
 			container: docker.ContainerInfo{
 				Name:    "test",
 				Service: "test",
@@ -187,11 +222,10 @@ func TestMonitorCommand_formatContainerRowEdgeCases(t *testing.T) {
 			},
 			stats: map[string]*docker.ContainerStats{
 				"test": {
-					CPUPercent:  50.0,
-					MemoryUsage: "invalid format",
+					CPUPercent: 50.0,
 				},
 			},
-			want: []string{"50.0%", "invalid "},
+			want: []string{"50.0%", "0.00GiB"},
 		},
 	}
 
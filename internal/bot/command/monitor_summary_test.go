@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hideA88/game-server-watchdog/internal/alert"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
@@ -88,7 +89,7 @@ func TestMonitorCommand_buildSummaryMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := &MonitorCommand{}
+			cmd := &MonitorCommand{thresholds: alert.DefaultThresholds()}
 			got := cmd.buildSummaryMessage(tt.data)
 
 			for _, want := range tt.wantContains {
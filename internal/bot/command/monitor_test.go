@@ -2,13 +2,19 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
 
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
@@ -25,7 +31,7 @@ func TestMonitorCommand_Name(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "")
+			cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
 			if got := cmd.Name(); got != tt.want {
 				t.Errorf("Name() = %v, want %v", got, tt.want)
 			}
@@ -46,7 +52,7 @@ func TestMonitorCommand_Description(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "")
+			cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
 			if got := cmd.Description(); got != tt.want {
 				t.Errorf("Description() = %v, want %v", got, tt.want)
 			}
@@ -64,6 +70,7 @@ func TestMonitorCommand_Execute(t *testing.T) {
 		gameContainers []docker.ContainerInfo
 		gameErr        error
 		stats          []docker.ContainerStats
+		statsErr       error
 		wantContains   []string
 		wantErr        bool
 		wantNotContain []string
@@ -99,10 +106,10 @@ func TestMonitorCommand_Execute(t *testing.T) {
 			},
 			stats: []docker.ContainerStats{
 				{
-					Name:          "minecraft-server",
-					CPUPercent:    45.2,
-					MemoryPercent: 68.7,
-					MemoryUsage:   "2.3GiB",
+					Name:             "minecraft-server",
+					CPUPercent:       45.2,
+					MemoryPercent:    68.7,
+					MemoryUsageBytes: 2469606195,
 				},
 			},
 			wantContains: []string{
@@ -118,7 +125,7 @@ func TestMonitorCommand_Execute(t *testing.T) {
 				"コンテナ状況",
 				"minecraft",
 				"45.2%",
-				"2.3GiB",
+				"2.30GiB",
 				"2 hours",
 				"🟢 ⛏️ **Minecraft**: running (2 hours)",
 			},
@@ -150,6 +157,35 @@ func TestMonitorCommand_Execute(t *testing.T) {
 				"エラー: container error",
 			},
 		},
+		{
+			name:       "一部のコンテナの統計情報取得エラー",
+			systemInfo: &system.SystemInfo{},
+			containers: []docker.ContainerInfo{
+				{Name: "minecraft-server", Service: "minecraft", State: "running"},
+				{Name: "rust-server", Service: "rust", State: "running"},
+			},
+			stats: []docker.ContainerStats{
+				{Name: "minecraft-server", CPUPercent: 45.2},
+			},
+			statsErr: &docker.ContainerStatsError{Failures: []*docker.DockerError{
+				docker.NewDockerContainerError("stats", "rust-server", fmt.Errorf("timeout")),
+			}},
+			wantContains: []string{
+				"45.2%",
+				"⚠️ 統計情報を取得できなかったコンテナ: rust-server",
+			},
+		},
+		{
+			name:       "統計情報取得エラー",
+			systemInfo: &system.SystemInfo{},
+			containers: []docker.ContainerInfo{
+				{Name: "minecraft-server", Service: "minecraft", State: "running"},
+			},
+			statsErr: fmt.Errorf("docker unavailable"),
+			wantContains: []string{
+				"⚠️ コンテナの統計情報の取得に失敗しました",
+			},
+		},
 		{
 			name: "高負荷アラート表示",
 			systemInfo: &system.SystemInfo{
@@ -187,7 +223,7 @@ func TestMonitorCommand_Execute(t *testing.T) {
 					return tt.gameContainers, tt.gameErr
 				},
 				GetAllContainersStatsFunc: func(_ string) ([]docker.ContainerStats, error) {
-					return tt.stats, nil
+					return tt.stats, tt.statsErr
 				},
 				GetContainerStatsFunc: func(containerName string) (*docker.ContainerStats, error) {
 					for i := range tt.stats {
@@ -199,8 +235,8 @@ func TestMonitorCommand_Execute(t *testing.T) {
 				},
 			}
 
-			cmd := NewMonitorCommand(context.Background(), mockCompose, mockMonitor, "")
-			result, err := cmd.Execute([]string{})
+			cmd := NewMonitorCommand(context.Background(), mockCompose, mockMonitor, "", nil, nil, nil, nil, nil)
+			result, err := monitorText(cmd, []string{})
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
@@ -216,13 +252,12 @@ func TestMonitorCommand_Execute(t *testing.T) {
 	}
 }
 
-func TestMonitorCommand_GetComponents(t *testing.T) {
+func TestMonitorCommand_Execute_Components(t *testing.T) {
 	tests := []struct {
 		name        string
 		containers  []docker.ContainerInfo
 		err         error
 		wantButtons int
-		wantErr     bool
 	}{
 		{
 			name: "停止中のコンテナに起動ボタン",
@@ -259,10 +294,9 @@ func TestMonitorCommand_GetComponents(t *testing.T) {
 			wantButtons: 2,
 		},
 		{
-			name:        "コンテナ情報取得エラー",
+			name:        "コンテナ情報取得エラーの場合は更新ボタンのみ",
 			err:         fmt.Errorf("error"),
 			wantButtons: 0,
-			wantErr:     true,
 		},
 	}
 
@@ -274,33 +308,291 @@ func TestMonitorCommand_GetComponents(t *testing.T) {
 				},
 			}
 
-			cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "")
-			components, err := cmd.GetComponents([]string{})
+			cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+			resp, err := cmd.Execute(context.Background(), &Invocation{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			components := resp.Components
 
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetComponents() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			// 起動・停止ボタン数をカウント
+			if got := len(serviceButtonIDs(components)); got != tt.wantButtons {
+				t.Errorf("Execute() button count = %v, want %v", got, tt.wantButtons)
 			}
 
-			if tt.wantErr {
-				return
+			// 最後の行は更新ボタン
+			last, ok := components[len(components)-1].(discordgo.ActionsRow)
+			if !ok || len(last.Components) != 1 || last.Components[0].(discordgo.Button).CustomID != "monitor_refresh_0" {
+				t.Errorf("Execute() last row = %+v, want refresh button", components[len(components)-1])
+			}
+		})
+	}
+}
+
+func TestMonitorCommand_Execute_ComponentsPagination(t *testing.T) {
+	// 20個のゲームコンテナ（3ページ）
+	var containers []docker.ContainerInfo
+	for i := 0; i < 20; i++ {
+		containers = append(containers, docker.ContainerInfo{
+			Name: fmt.Sprintf("game-server%02d-1", i), Service: fmt.Sprintf("server%02d", i), State: "running",
+		})
+	}
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc:     func(_ string) ([]docker.ContainerInfo, error) { return containers, nil },
+		ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) { return containers, nil },
+	}
+	cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantButtons []string
+		wantNav     []string
+		wantPrev    bool
+		wantNext    bool
+	}{
+		{
+			name:        "最初のページ",
+			wantButtons: []string{"stop_service_server00", "stop_service_server07"},
+			wantNav:     []string{"monitor_prev_0", "monitor_next_0", "monitor_refresh_0"},
+			wantNext:    true,
+		},
+		{
+			name:        "2ページ目",
+			args:        []string{"2"},
+			wantButtons: []string{"stop_service_server08", "stop_service_server15"},
+			wantNav:     []string{"monitor_prev_1", "monitor_next_1", "monitor_refresh_1"},
+			wantPrev:    true,
+			wantNext:    true,
+		},
+		{
+			name:        "ページ数を超える場合は最後のページ",
+			args:        []string{"9"},
+			wantButtons: []string{"stop_service_server16", "stop_service_server19"},
+			wantNav:     []string{"monitor_prev_2", "monitor_next_2", "monitor_refresh_2"},
+			wantPrev:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := cmd.Execute(context.Background(), &Invocation{Args: tt.args})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			components := resp.Components
+			if len(components) > docker.MaxButtonRows {
+				t.Fatalf("Execute() = %d rows, want at most %d", len(components), docker.MaxButtonRows)
 			}
 
-			// ボタン数をカウント
-			buttonCount := 0
-			for _, comp := range components {
-				if row, ok := comp.(discordgo.ActionsRow); ok {
-					buttonCount += len(row.Components)
+			ids := serviceButtonIDs(components)
+			if ids[0] != tt.wantButtons[0] || ids[len(ids)-1] != tt.wantButtons[1] {
+				t.Errorf("service buttons = %v, want %s .. %s", ids, tt.wantButtons[0], tt.wantButtons[1])
+			}
+
+			nav := components[len(components)-1].(discordgo.ActionsRow).Components
+			if len(nav) != len(tt.wantNav) {
+				t.Fatalf("navigation = %+v, want %v", nav, tt.wantNav)
+			}
+			for i, want := range tt.wantNav {
+				if got := nav[i].(discordgo.Button).CustomID; got != want {
+					t.Errorf("navigation[%d] = %s, want %s", i, got, want)
 				}
 			}
+			if nav[0].(discordgo.Button).Disabled == tt.wantPrev || nav[1].(discordgo.Button).Disabled == tt.wantNext {
+				t.Errorf("navigation enabled = %v/%v, want %v/%v",
+					!nav[0].(discordgo.Button).Disabled, !nav[1].(discordgo.Button).Disabled, tt.wantPrev, tt.wantNext)
+			}
+		})
+	}
+
+	// 不正なページ番号の場合はボタンなし
+	if resp, _ := cmd.Execute(context.Background(), &Invocation{Args: []string{"0"}}); resp.Components != nil {
+		t.Errorf("Execute(0) components = %+v, want nil", resp.Components)
+	}
+}
+
+// serviceButtonIDs は起動・停止ボタンのカスタムIDを返す
+func serviceButtonIDs(components []discordgo.MessageComponent) []string {
+	var ids []string
+	for _, comp := range components {
+		row, ok := comp.(discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, c := range row.Components {
+			if b, ok := c.(discordgo.Button); ok &&
+				(strings.HasPrefix(b.CustomID, "start_service_") || strings.HasPrefix(b.CustomID, "stop_service_")) {
+				ids = append(ids, b.CustomID)
+			}
+		}
+	}
+	return ids
+}
+
+func TestParsePageCustomID(t *testing.T) {
+	tests := []struct {
+		name     string
+		customID string
+		wantPage int
+		wantOK   bool
+	}{
+		{name: "前のページ", customID: "monitor_prev_2", wantPage: 1, wantOK: true},
+		{name: "最初のページより前には戻らない", customID: "monitor_prev_0", wantPage: 0, wantOK: true},
+		{name: "次のページ", customID: "monitor_next_2", wantPage: 3, wantOK: true},
+		{name: "更新", customID: "monitor_refresh_2", wantPage: 2, wantOK: true},
+		{name: "ページ番号が数値でない", customID: "monitor_next_abc"},
+		{name: "ページ番号が負", customID: "monitor_refresh_-1"},
+		{name: "起動ボタン", customID: "start_service_minecraft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, ok := parsePageCustomID(tt.customID)
+			if page != tt.wantPage || ok != tt.wantOK {
+				t.Errorf("parsePageCustomID(%q) = %d, %v, want %d, %v", tt.customID, page, ok, tt.wantPage, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMonitorData_paginate(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		{Name: "game-minecraft-1"}, {Name: "game-db-1"}, {Name: "game-rust-1"}, {Name: "game-cache-1"}, {Name: "game-ark-1"},
+	}
+	games := []docker.ContainerInfo{{Name: "game-minecraft-1"}, {Name: "game-rust-1"}, {Name: "game-ark-1"}}
+
+	tests := []struct {
+		name           string
+		data           MonitorData
+		page           int
+		wantPage       int
+		wantPages      int
+		wantContainers int
+		wantGames      []string
+	}{
+		{
+			name:           "1ページに収まる場合はそのまま",
+			data:           MonitorData{Containers: containers, GameContainers: games},
+			wantPages:      1,
+			wantContainers: 5,
+			wantGames:      []string{"game-minecraft-1", "game-rust-1", "game-ark-1"},
+		},
+		{
+			name:           "ページに含まれるゲームコンテナだけを残す",
+			data:           MonitorData{Containers: containers, GameContainers: games},
+			page:           1,
+			wantPage:       1,
+			wantPages:      3,
+			wantContainers: 2,
+			wantGames:      []string{"game-rust-1"},
+		},
+		{
+			name:           "ページ数を超える場合は最後のページ",
+			data:           MonitorData{Containers: containers, GameContainers: games},
+			page:           5,
+			wantPage:       2,
+			wantPages:      3,
+			wantContainers: 1,
+			wantGames:      []string{"game-ark-1"},
+		},
+		{
+			name:      "コンテナ一覧の取得に失敗した場合はゲームコンテナで分割",
+			data:      MonitorData{ContainerError: fmt.Errorf("error"), GameContainers: games},
+			page:      1,
+			wantPage:  1,
+			wantPages: 2,
+			wantGames: []string{"game-ark-1"},
+		},
+		{
+			name:      "コンテナがない場合も1ページ",
+			wantPages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := 2
+			if tt.wantPages == 1 {
+				size = monitorPageSize
+			}
+			got := tt.data.paginate(tt.page, size)
+			if got.Page != tt.wantPage || got.Pages != tt.wantPages || len(got.Containers) != tt.wantContainers {
+				t.Errorf("paginate() = page %d/%d with %d containers, want %d/%d with %d",
+					got.Page, got.Pages, len(got.Containers), tt.wantPage, tt.wantPages, tt.wantContainers)
+			}
+			var names []string
+			for i := range got.GameContainers {
+				names = append(names, got.GameContainers[i].Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantGames, ",") {
+				t.Errorf("game containers = %v, want %v", names, tt.wantGames)
+			}
+		})
+	}
+}
+
+func TestMonitorCommand_Render(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantButtons bool
+	}{
+		{name: "レポートと操作ボタン", wantButtons: true},
+		{name: "ゲームコンテナの取得に失敗した場合は起動・停止ボタンなし", err: fmt.Errorf("error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCompose := &docker.MockComposeService{
+				ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+					return []docker.ContainerInfo{{Service: "minecraft", State: "running"}}, tt.err
+				},
+			}
 
-			if buttonCount != tt.wantButtons {
-				t.Errorf("GetComponents() button count = %v, want %v", buttonCount, tt.wantButtons)
+			cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+			content, components, err := cmd.Render(context.Background(), 0)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(content, "システム監視ダッシュボード") {
+				t.Errorf("Render() content = %q, want the monitor report", content)
+			}
+			if got := len(serviceButtonIDs(components)) > 0; got != tt.wantButtons {
+				t.Errorf("Render() components = %+v, want buttons %v", components, tt.wantButtons)
 			}
 		})
 	}
 }
 
+func TestMonitorCommand_Render_Page(t *testing.T) {
+	containers := make([]docker.ContainerInfo, monitorPageSize+1)
+	for i := range containers {
+		containers[i] = docker.ContainerInfo{Name: fmt.Sprintf("game-%d", i), Service: fmt.Sprintf("game%d", i), State: "running"}
+	}
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+			return containers, nil
+		},
+		ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+			return containers, nil
+		},
+	}
+	cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+
+	// 切り替えたページを表示し続ける
+	content, components, err := cmd.Render(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(content, "ページ 2/2") {
+		t.Errorf("Render(1) content = %q, want the second page", content)
+	}
+	if ids := serviceButtonIDs(components); len(ids) != 1 || ids[0] != "stop_service_game8" {
+		t.Errorf("Render(1) service buttons = %v, want only the second page", ids)
+	}
+}
+
 func TestMonitorCommand_CanHandle(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -317,6 +609,16 @@ func TestMonitorCommand_CanHandle(t *testing.T) {
 			customID: "stop_service_terraria",
 			want:     true,
 		},
+		{
+			name:     "ページ切り替えボタンのカスタムID",
+			customID: "monitor_next_0",
+			want:     true,
+		},
+		{
+			name:     "更新ボタンのカスタムID",
+			customID: "monitor_refresh_1",
+			want:     true,
+		},
 		{
 			name:     "無関係のカスタムID",
 			customID: "something_else",
@@ -331,7 +633,7 @@ func TestMonitorCommand_CanHandle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "")
+			cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
 			if got := cmd.CanHandle(tt.customID); got != tt.want {
 				t.Errorf("CanHandle() = %v, want %v", got, tt.want)
 			}
@@ -526,3 +828,315 @@ func TestGetHealthIcon(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitorCommand_stopService(t *testing.T) {
+	stopped := false
+	mockCompose := &docker.MockComposeService{
+		StopServiceFunc: func(_, _ string) error {
+			stopped = true
+			return nil
+		},
+	}
+	locks := oplock.New()
+	cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "", nil, locks, nil, nil, nil)
+
+	// 確認中に他の操作が始まった場合は停止しない
+	locks.TryLock("minecraft")
+	if got := cmd.stopService(context.Background(), audit.Actor{}, "minecraft"); !strings.Contains(got, "現在操作中です") || stopped {
+		t.Errorf("stopService() while locked = %q, stopped = %v", got, stopped)
+	}
+	locks.Unlock("minecraft")
+
+	if got := cmd.stopService(context.Background(), audit.Actor{}, "minecraft"); got != "🛑 Minecraft を停止しました。" || !stopped {
+		t.Errorf("stopService() = %q, stopped = %v", got, stopped)
+	}
+	if !locks.TryLock("minecraft") {
+		t.Error("stopService() did not release the lock")
+	}
+}
+
+func TestMonitorCommand_stopService_HoldsLockUntilStopped(t *testing.T) {
+	locks := oplock.New()
+	lockedDuringStop := false
+	mockCompose := &docker.MockComposeService{
+		StopServiceFunc: func(_, _ string) error {
+			// 停止前のコマンドと停止の待機で時間がかかっても、終わるまでは他の操作を受け付けない
+			lockedDuringStop = !locks.TryLock("minecraft")
+			return nil
+		},
+	}
+	cmd := NewMonitorCommand(context.Background(), mockCompose, &system.MockMonitor{}, "", nil, locks, nil, nil, nil)
+
+	if got := cmd.stopService(context.Background(), audit.Actor{}, "minecraft"); got != "🛑 Minecraft を停止しました。" {
+		t.Errorf("stopService() = %q", got)
+	}
+	if !lockedDuringStop {
+		t.Error("lock was released before StopService returned")
+	}
+}
+
+func TestMonitorCommand_Execute_InvalidPage(t *testing.T) {
+	cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+
+	for _, arg := range []string{"0", "-1", "abc"} {
+		t.Run(arg, func(t *testing.T) {
+			got, err := monitorText(cmd, []string{arg})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !strings.Contains(got, "使用方法: `@bot monitor [ページ]`") {
+				t.Errorf("Execute(%q) = %q, want usage", arg, got)
+			}
+		})
+	}
+}
+
+// queryableMinecraft はクエリの送信先を設定した稼働中のMinecraftサーバー
+var queryableMinecraft = docker.ContainerInfo{
+	Service: "minecraft",
+	Name:    "mc-1",
+	State:   "running",
+	Labels:  map[string]string{docker.LabelGameType: "minecraft", gameprobe.LabelQueryPort: "25565"},
+}
+
+func TestMonitorCommand_probeGameServers(t *testing.T) {
+	stopped := queryableMinecraft
+	stopped.Name = "mc-2"
+	stopped.State = "exited"
+	withoutPort := docker.ContainerInfo{
+		Service: "terraria", Name: "terraria-1", State: "running",
+		Labels: map[string]string{docker.LabelGameType: "terraria"},
+	}
+
+	var probed []gameprobe.Target
+	var mu sync.Mutex
+	prober := &gameprobe.MockProber{
+		ProbeFunc: func(_ context.Context, target gameprobe.Target) (*gameprobe.Status, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			probed = append(probed, target)
+			return &gameprobe.Status{Players: 1}, nil
+		},
+	}
+	cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "",
+		nil, nil, nil, nil, prober)
+
+	got := cmd.probeGameServers(context.Background(), []docker.ContainerInfo{queryableMinecraft, stopped, withoutPort})
+	want := []gameprobe.Target{
+		{Container: "mc-1", Service: "minecraft", Protocol: gameprobe.ProtocolMinecraft, Host: "minecraft", Port: 25565},
+	}
+	if !reflect.DeepEqual(probed, want) {
+		t.Errorf("probed targets = %+v, want %+v", probed, want)
+	}
+	if len(got) != 1 || !got["mc-1"].Online() {
+		t.Errorf("probeGameServers() = %+v, want online result for mc-1", got)
+	}
+
+	if got := cmd.probeGameServers(context.Background(), []docker.ContainerInfo{withoutPort}); got != nil {
+		t.Errorf("probeGameServers() = %+v, want nil without targets", got)
+	}
+}
+
+func TestMonitorCommand_checkServicePlayers(t *testing.T) {
+	prober := &gameprobe.MockProber{
+		ProbeFunc: func(context.Context, gameprobe.Target) (*gameprobe.Status, error) {
+			return &gameprobe.Status{Players: 2}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		containers []docker.ContainerInfo
+		listErr    error
+		service    string
+		wantOnline bool
+	}{
+		{name: "プレイヤーがいる", containers: []docker.ContainerInfo{queryableMinecraft}, service: "minecraft", wantOnline: true},
+		{name: "サービスが見つからない", containers: []docker.ContainerInfo{queryableMinecraft}, service: "valheim"},
+		{name: "コンテナ情報を取得できない", listErr: errors.New("docker error"), service: "minecraft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compose := &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) { return tt.containers, tt.listErr },
+			}
+			cmd := NewMonitorCommand(context.Background(), compose, &system.MockMonitor{}, "", nil, nil, nil, nil, prober)
+			got := cmd.checkServicePlayers(context.Background(), tt.service)
+			if got.online() != tt.wantOnline {
+				t.Errorf("checkServicePlayers(%q).online() = %v, want %v", tt.service, got.online(), tt.wantOnline)
+			}
+		})
+	}
+}
+
+func TestMonitorCommand_ExecuteEmbed(t *testing.T) { // テーブル駆動テストのため長い関数を許可
+	manyContainers := make([]docker.ContainerInfo, 10)
+	for i := range manyContainers {
+		manyContainers[i] = docker.ContainerInfo{
+			Service: fmt.Sprintf("service-%02d", i), Name: fmt.Sprintf("container-%02d", i), State: "running",
+		}
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		containers   []docker.ContainerInfo
+		listErr      error
+		stats        []docker.ContainerStats
+		probeErr     error
+		wantContent  string
+		wantColor    int
+		wantContains map[string]string
+		wantFooter   string
+	}{
+		{
+			name: "すべて稼働中の場合は緑",
+			containers: []docker.ContainerInfo{
+				{Service: "minecraft", Name: "mc-1", State: "running", RunningFor: "2 hours"},
+			},
+			stats:     []docker.ContainerStats{{Name: "mc-1", CPUPercent: 12.5}},
+			wantColor: ColorHealthy,
+			wantContains: map[string]string{
+				"📦 コンテナ状況":    "🟢 **Minecraft** CPU 12.5%",
+				"⚠️ アラート":     "現在アラートはありません",
+				"🎮 ゲームサーバー状態": "**Minecraft**: running (2 hours)",
+			},
+		},
+		{
+			name: "停止中のコンテナがある場合は黄色",
+			containers: []docker.ContainerInfo{
+				{Service: "minecraft", Name: "mc-1", State: "exited"},
+			},
+			wantColor:    ColorWarning,
+			wantContains: map[string]string{"📦 コンテナ状況": "🔴 **Minecraft**"},
+		},
+		{
+			name: "アラートがある場合は黄色",
+			containers: []docker.ContainerInfo{
+				{Service: "minecraft", Name: "mc-1", State: "running"},
+			},
+			stats:        []docker.ContainerStats{{Name: "mc-1", CPUPercent: 95}},
+			wantColor:    ColorWarning,
+			wantContains: map[string]string{"⚠️ アラート": "CPU使用率が高い"},
+		},
+		{
+			name: "unhealthyのコンテナがある場合は赤",
+			containers: []docker.ContainerInfo{
+				{Service: "minecraft", Name: "mc-1", State: "running", HealthStatus: "unhealthy"},
+			},
+			wantColor: ColorCritical,
+		},
+		{
+			name:       "クエリに応答したゲームサーバーはプレイヤー数を表示",
+			containers: []docker.ContainerInfo{queryableMinecraft},
+			wantColor:  ColorHealthy,
+			wantContains: map[string]string{
+				"🎮 ゲームサーバー状態": "**Minecraft**: running\n  └ 🟢 オンライン 2/20人 | バージョン: 1.20.4",
+			},
+		},
+		{
+			name:         "クエリに応答しないゲームサーバーがある場合は黄色",
+			containers:   []docker.ContainerInfo{queryableMinecraft},
+			probeErr:     fmt.Errorf("i/o timeout"),
+			wantColor:    ColorWarning,
+			wantContains: map[string]string{"🎮 ゲームサーバー状態": "🔴 オフライン"},
+		},
+		{
+			name:         "コンテナ情報の取得に失敗した場合は赤",
+			listErr:      fmt.Errorf("docker unavailable"),
+			wantColor:    ColorCritical,
+			wantContains: map[string]string{"📦 コンテナ状況": "エラー: docker unavailable"},
+		},
+		{
+			name:         "2ページ目をフッターに表示",
+			args:         []string{"2"},
+			containers:   manyContainers,
+			wantColor:    ColorHealthy,
+			wantContains: map[string]string{"📦 コンテナ状況": "Service 09"},
+			wantFooter:   "📄 ページ 2/2",
+		},
+		{
+			name:        "不正なページ",
+			args:        []string{"0"},
+			wantContent: "使用方法: `@bot monitor [ページ]`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCompose := &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return tt.containers, tt.listErr
+				},
+				ListGameContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return tt.containers, tt.listErr
+				},
+				GetAllContainersStatsFunc: func(string) ([]docker.ContainerStats, error) {
+					return tt.stats, nil
+				},
+			}
+			monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{CPUUsagePercent: 10}}
+			prober := &gameprobe.MockProber{
+				ProbeFunc: func(context.Context, gameprobe.Target) (*gameprobe.Status, error) {
+					if tt.probeErr != nil {
+						return nil, tt.probeErr
+					}
+					return &gameprobe.Status{Players: 2, MaxPlayers: 20, Version: "1.20.4"}, nil
+				},
+			}
+			cmd := NewMonitorCommand(context.Background(), mockCompose, monitor, "", nil, nil, nil, nil, prober)
+
+			got, err := cmd.Execute(context.Background(), &Invocation{Args: tt.args})
+			if err != nil {
+				t.Fatalf("ExecuteEmbed() error = %v", err)
+			}
+			if tt.wantContent != "" {
+				if !strings.Contains(got.Content, tt.wantContent) || len(got.Embeds) != 0 {
+					t.Errorf("ExecuteEmbed() = %+v, want content %q", got, tt.wantContent)
+				}
+				return
+			}
+			if len(got.Embeds) != 1 {
+				t.Fatalf("ExecuteEmbed() embeds = %d, want 1", len(got.Embeds))
+			}
+			embed := got.Embeds[0]
+			if embed.Color != tt.wantColor {
+				t.Errorf("Color = %#x, want %#x", embed.Color, tt.wantColor)
+			}
+			for name, want := range tt.wantContains {
+				if field := findEmbedField(embed, name); field == nil || !strings.Contains(field.Value, want) {
+					t.Errorf("field %q = %+v, want to contain %q", name, field, want)
+				}
+			}
+			if tt.wantFooter != "" && (embed.Footer == nil || !strings.Contains(embed.Footer.Text, tt.wantFooter)) {
+				t.Errorf("Footer = %+v, want %q", embed.Footer, tt.wantFooter)
+			}
+		})
+	}
+}
+
+func TestMonitorCommand_pageEdit(t *testing.T) {
+	cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+
+	// 埋め込みのメッセージは埋め込みで置き換える
+	edit := cmd.pageEdit(context.Background(), []string{"1"}, true)
+	if edit.Embeds == nil || len(*edit.Embeds) != 1 || *edit.Content != "" {
+		t.Errorf("pageEdit(embed) = %+v, want an embed without content", edit)
+	}
+
+	// テキストのメッセージ（ダッシュボードなど）はテキストで置き換える
+	edit = cmd.pageEdit(context.Background(), []string{"1"}, false)
+	if edit.Embeds != nil || !strings.Contains(*edit.Content, "システム監視ダッシュボード") {
+		t.Errorf("pageEdit(text) = %+v, want the text report", edit)
+	}
+}
+
+// monitorText は監視レポートをテキストで返す
+func monitorText(cmd *MonitorCommand, args []string) (string, error) {
+	resp, err := cmd.executeText(context.Background(), args)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
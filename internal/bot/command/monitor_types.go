@@ -1,7 +1,9 @@
 package command
 
 import (
+	"github.com/hideA88/game-server-watchdog/internal/alert"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
@@ -15,15 +17,60 @@ type MonitorData struct {
 	StatsError     error
 	GameContainers []docker.ContainerInfo
 	GameError      error
+	GameStatuses   map[string]gameprobe.Result // コンテナ名をキーとしたゲームサーバーへのクエリの結果
+
+	Page  int // 表示しているページ（0始まり）
+	Pages int // ページ数（1以下の場合はページを表示しない）
 }
 
-// Alert はアラート情報を表す構造体
-type Alert struct {
-	Component string
-	Message   string
-	Value     float64
+// paginate は指定したページのコンテナだけを含むコピーを返す
+// コンテナ一覧を基準にページを分割し、ゲームコンテナはそのページに含まれるものだけを残す
+// コンテナ一覧を取得できなかった場合はゲームコンテナを基準に分割する
+// pageがページ数を超える場合は最後のページを返す
+func (d *MonitorData) paginate(page, size int) *MonitorData {
+	p := *d
+	if d.ContainerError != nil {
+		p.Pages = pageCount(len(d.GameContainers), size)
+		p.Page = min(page, p.Pages-1)
+		p.GameContainers = pageOf(d.GameContainers, p.Page, size)
+		return &p
+	}
+
+	p.Pages = pageCount(len(d.Containers), size)
+	p.Page = min(page, p.Pages-1)
+	p.Containers = pageOf(d.Containers, p.Page, size)
+	if p.Pages <= 1 {
+		return &p
+	}
+
+	onPage := make(map[string]bool, len(p.Containers))
+	for i := range p.Containers {
+		onPage[p.Containers[i].Name] = true
+	}
+	p.GameContainers = nil
+	for i := range d.GameContainers {
+		if onPage[d.GameContainers[i].Name] {
+			p.GameContainers = append(p.GameContainers, d.GameContainers[i])
+		}
+	}
+	return &p
+}
+
+// pageCount はn件をsize件ずつに分割したときのページ数を返す（0件の場合も1ページ）
+func pageCount(n, size int) int {
+	return max((n+size-1)/size, 1)
 }
 
+// pageOf はitemsをsize件ずつに分割したときのpageページ目を返す
+func pageOf[T any](items []T, page, size int) []T {
+	start := min(page*size, len(items))
+	end := min(start+size, len(items))
+	return items[start:end]
+}
+
+// Alert はアラート情報を表す構造体
+type Alert = alert.Alert
+
 // ProgressBar はプログレスバーを表す構造体
 type ProgressBar struct {
 	Value  float64
@@ -0,0 +1,23 @@
+package command
+
+import "github.com/bwmarrin/discordgo"
+
+const (
+	// OptionService はサービス名を指定するスラッシュコマンドのオプション名
+	OptionService = "service"
+	// OptionLines はログの行数を指定するスラッシュコマンドのオプション名
+	OptionLines = "lines"
+	// OptionPage は表示するページを指定するスラッシュコマンドのオプション名
+	OptionPage = "page"
+)
+
+// serviceOption はサービス名（オートコンプリート付き）のオプション定義を返す
+func serviceOption(description string) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:         discordgo.ApplicationCommandOptionString,
+		Name:         OptionService,
+		Description:  description,
+		Required:     true,
+		Autocomplete: true,
+	}
+}
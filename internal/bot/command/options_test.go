@@ -0,0 +1,61 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+func TestSlashCommand_Options(t *testing.T) {
+	compose := &docker.MockComposeService{}
+
+	tests := []struct {
+		name      string
+		cmd       SlashCommand
+		wantNames []string
+	}{
+		{name: "restart", cmd: NewRestartCommand(compose, "", nil, nil, nil, nil, nil), wantNames: []string{OptionService}},
+		{name: "container", cmd: NewContainerCommand(compose, "", nil), wantNames: []string{OptionService}},
+		{name: "logs", cmd: NewLogsCommand(compose, ""), wantNames: []string{OptionService, OptionLines}},
+		{name: "rcon", cmd: NewRCONCommand(nil, nil, nil), wantNames: []string{OptionService, OptionCommand}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.cmd.Options()
+			if len(options) != len(tt.wantNames) {
+				t.Fatalf("Options() returned %d options, want %d", len(options), len(tt.wantNames))
+			}
+			for i, opt := range options {
+				if opt.Name != tt.wantNames[i] {
+					t.Errorf("Options()[%d].Name = %q, want %q", i, opt.Name, tt.wantNames[i])
+				}
+				if opt.Description == "" {
+					t.Errorf("Options()[%d].Description is empty", i)
+				}
+			}
+
+			// サービス名は必須でオートコンプリートを有効にする
+			service := options[0]
+			if service.Type != discordgo.ApplicationCommandOptionString || !service.Required || !service.Autocomplete {
+				t.Errorf("service option = %+v, want required string option with autocomplete", service)
+			}
+		})
+	}
+}
+
+func TestLogsCommand_Options_Lines(t *testing.T) {
+	lines := NewLogsCommand(&docker.MockComposeService{}, "").Options()[1]
+
+	if lines.Type != discordgo.ApplicationCommandOptionInteger {
+		t.Errorf("lines option type = %v, want integer", lines.Type)
+	}
+	if lines.Required {
+		t.Error("lines option should be optional")
+	}
+	if lines.MinValue == nil || *lines.MinValue != 1 || lines.MaxValue != maxLogCount {
+		t.Errorf("lines option range = %v..%v, want 1..%d", lines.MinValue, lines.MaxValue, maxLogCount)
+	}
+}
@@ -0,0 +1,12 @@
+package command
+
+import "github.com/hideA88/game-server-watchdog/internal/permission"
+
+// serviceRequirement は最初の引数をサービス名として必要な権限を返す
+func serviceRequirement(level permission.Level, args []string) permission.Requirement {
+	req := permission.Requirement{Level: level}
+	if len(args) > 0 {
+		req.Service = args[0]
+	}
+	return req
+}
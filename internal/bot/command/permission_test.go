@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+func TestRequiredPermission(t *testing.T) {
+	compose := &docker.MockComposeService{}
+	monitor := &system.MockMonitor{}
+
+	tests := []struct {
+		name string
+		cmd  Command
+		args []string
+		want permission.Requirement
+	}{
+		{
+			name: "ping",
+			cmd:  NewPingCommand(),
+			want: permission.Requirement{Level: permission.LevelViewer},
+		},
+		{
+			name: "monitor",
+			cmd:  NewMonitorCommand(context.Background(), compose, monitor, "", nil, nil, nil, nil, nil),
+			want: permission.Requirement{Level: permission.LevelViewer},
+		},
+		{
+			name: "logsはサービスの閲覧権限",
+			cmd:  NewLogsCommand(compose, ""),
+			args: []string{"minecraft", "100"},
+			want: permission.Requirement{Level: permission.LevelViewer, Service: "minecraft"},
+		},
+		{
+			name: "restartはサービスの操作権限",
+			cmd:  NewRestartCommand(compose, "", nil, nil, nil, nil, nil),
+			args: []string{"minecraft"},
+			want: permission.Requirement{Level: permission.LevelOperator, Service: "minecraft"},
+		},
+		{
+			name: "rconはサービスの操作権限",
+			cmd:  NewRCONCommand(nil, nil, nil),
+			args: []string{"minecraft", "list"},
+			want: permission.Requirement{Level: permission.LevelOperator, Service: "minecraft"},
+		},
+		{
+			name: "引数なしのrestart",
+			cmd:  NewRestartCommand(compose, "", nil, nil, nil, nil, nil),
+			want: permission.Requirement{Level: permission.LevelOperator},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmd.RequiredPermission(tt.args); got != tt.want {
+				t.Errorf("RequiredPermission(%v) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitorCommand_RequiredInteractionPermission(t *testing.T) {
+	cmd := NewMonitorCommand(context.Background(), &docker.MockComposeService{}, &system.MockMonitor{}, "", nil, nil, nil, nil, nil)
+
+	for _, customID := range []string{"start_service_minecraft", "stop_service_minecraft"} {
+		t.Run(customID, func(t *testing.T) {
+			want := permission.Requirement{Level: permission.LevelOperator, Service: "minecraft"}
+			if got := cmd.RequiredInteractionPermission(customID); got != want {
+				t.Errorf("RequiredInteractionPermission(%q) = %+v, want %+v", customID, got, want)
+			}
+		})
+	}
+
+	// ページの切り替えと更新は閲覧権限で実行できる
+	for _, customID := range []string{"monitor_prev_1", "monitor_next_0", "monitor_refresh_0"} {
+		t.Run(customID, func(t *testing.T) {
+			want := permission.Requirement{Level: permission.LevelViewer}
+			if got := cmd.RequiredInteractionPermission(customID); got != want {
+				t.Errorf("RequiredInteractionPermission(%q) = %+v, want %+v", customID, got, want)
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package command
 
+import (
+	"context"
+
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+)
+
 // PingCommand はpingコマンドの実装
 type PingCommand struct{}
 
@@ -18,7 +24,12 @@ func (c *PingCommand) Description() string {
 	return "ボットの応答を確認"
 }
 
+// RequiredPermission はコマンドの実行に必要な権限を返す
+func (c *PingCommand) RequiredPermission(_ []string) permission.Requirement {
+	return permission.Requirement{Level: permission.LevelViewer}
+}
+
 // Execute はコマンドを実行する
-func (c *PingCommand) Execute(_ []string) (string, error) {
-	return "pong!!", nil
+func (c *PingCommand) Execute(_ context.Context, _ *Invocation) (*Response, error) {
+	return TextResponse("pong!!"), nil
 }
@@ -76,7 +76,7 @@ func TestPingCommand_Execute(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			cmd := NewPingCommand()
-			got, err := cmd.Execute(tt.args)
+			got, err := runText(cmd, tt.args)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+)
+
+// playerCheckTimeout は停止・再起動の前に接続中のプレイヤーを問い合わせる時間の上限
+// ボタンの操作にはDiscordのインタラクションの応答期限（3秒）以内に応答する必要があるため短くする
+const playerCheckTimeout = 1500 * time.Millisecond
+
+// playerCheck は停止・再起動の前にゲームサーバーに問い合わせた接続中のプレイヤー
+type playerCheck struct {
+	target gameprobe.Target
+	status *gameprobe.Status // クエリの送信先を設定していない場合や応答しない場合はnil
+}
+
+// checkPlayers は稼働中のゲームサーバーに接続中のプレイヤーを問い合わせる
+func checkPlayers(ctx context.Context, prober gameprobe.Prober, container *docker.ContainerInfo) playerCheck {
+	if !strings.EqualFold(container.State, docker.StateRunning) {
+		return playerCheck{}
+	}
+	target, ok := gameprobe.TargetFor(container)
+	if !ok {
+		return playerCheck{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, playerCheckTimeout)
+	defer cancel()
+	status, err := prober.Probe(ctx, target)
+	if err != nil {
+		return playerCheck{target: target}
+	}
+	return playerCheck{target: target, status: status}
+}
+
+// online は接続中のプレイヤーがいるかを返す
+func (p playerCheck) online() bool {
+	return p.status != nil && p.status.Players > 0
+}
+
+// waitEmpty はプレイヤーがいる場合に、いなくなるまで待つ関数を返す（いない場合や人数が分からない場合はnil）
+func (p playerCheck) waitEmpty(prober gameprobe.Prober) WaitFunc {
+	if !p.online() {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		return gameprobe.WaitUntilEmpty(ctx, prober, p.target, 0)
+	}
+}
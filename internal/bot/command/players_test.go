@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+)
+
+func TestCheckPlayers(t *testing.T) {
+	labels := map[string]string{"game.type": "minecraft", gameprobe.LabelQueryPort: "25565"}
+
+	tests := []struct {
+		name       string
+		container  docker.ContainerInfo
+		probe      func(context.Context, gameprobe.Target) (*gameprobe.Status, error)
+		wantProbe  bool
+		wantOnline bool
+		wantStatus bool
+	}{
+		{
+			name:      "プレイヤーがいる",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft", State: "running", Labels: labels},
+			probe: func(context.Context, gameprobe.Target) (*gameprobe.Status, error) {
+				return &gameprobe.Status{Players: 1}, nil
+			},
+			wantProbe:  true,
+			wantOnline: true,
+			wantStatus: true,
+		},
+		{
+			name:       "プレイヤーがいない",
+			container:  docker.ContainerInfo{Name: "mc-1", Service: "minecraft", State: "running", Labels: labels},
+			probe:      func(context.Context, gameprobe.Target) (*gameprobe.Status, error) { return &gameprobe.Status{}, nil },
+			wantProbe:  true,
+			wantStatus: true,
+		},
+		{
+			name:      "応答しない",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft", State: "running", Labels: labels},
+			probe: func(context.Context, gameprobe.Target) (*gameprobe.Status, error) {
+				return nil, errors.New("timeout")
+			},
+			wantProbe: true,
+		},
+		{
+			name:      "停止中のコンテナには問い合わせない",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft", State: "exited", Labels: labels},
+		},
+		{
+			name:      "クエリの送信先がない",
+			container: docker.ContainerInfo{Name: "terraria-1", Service: "terraria", State: "running"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probed := false
+			prober := &gameprobe.MockProber{
+				ProbeFunc: func(ctx context.Context, target gameprobe.Target) (*gameprobe.Status, error) {
+					probed = true
+					if _, ok := ctx.Deadline(); !ok {
+						t.Error("Probe() called without deadline")
+					}
+					return tt.probe(ctx, target)
+				},
+			}
+
+			got := checkPlayers(context.Background(), prober, &tt.container)
+			if probed != tt.wantProbe {
+				t.Errorf("probed = %v, want %v", probed, tt.wantProbe)
+			}
+			if got.online() != tt.wantOnline {
+				t.Errorf("online() = %v, want %v", got.online(), tt.wantOnline)
+			}
+			if (got.status != nil) != tt.wantStatus {
+				t.Errorf("status = %+v, wantStatus %v", got.status, tt.wantStatus)
+			}
+			if (got.waitEmpty(prober) != nil) != tt.wantOnline {
+				t.Errorf("waitEmpty() = nil: %v, want wait func only when online", got.waitEmpty(prober) == nil)
+			}
+		})
+	}
+}
+
+func TestPlayerCheck_WaitEmpty(t *testing.T) {
+	target := gameprobe.Target{Container: "mc-1", Protocol: gameprobe.ProtocolMinecraft, Host: "minecraft", Port: 25565}
+	var probed []gameprobe.Target
+	prober := &gameprobe.MockProber{
+		ProbeFunc: func(_ context.Context, target gameprobe.Target) (*gameprobe.Status, error) {
+			probed = append(probed, target)
+			return &gameprobe.Status{}, nil
+		},
+	}
+
+	check := playerCheck{target: target, status: &gameprobe.Status{Players: 3}}
+	wait := check.waitEmpty(prober)
+	if wait == nil {
+		t.Fatal("waitEmpty() = nil")
+	}
+	if err := wait(context.Background()); err != nil {
+		t.Errorf("wait() error = %v", err)
+	}
+	if len(probed) != 1 || probed[0] != target {
+		t.Errorf("probed = %+v, want %+v", probed, target)
+	}
+}
@@ -0,0 +1,171 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/security"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
+)
+
+const (
+	// OptionCommand は送信するRCONコマンドを指定するスラッシュコマンドのオプション名
+	OptionCommand = "command"
+	// maxRCONOutputLength はRCONの応答を表示する最大文字数
+	maxRCONOutputLength = 1800
+	// rconUsage はrconコマンドの使用方法
+	rconUsage = "使用方法: `@bot rcon <サービス名> <コマンド>`\n例: `@bot rcon minecraft list`"
+	// rconUnsafeMessage は区切り文字や制御文字を含むコマンドの場合のメッセージ
+	rconUnsafeMessage = "🚫 RCONコマンドに `;` や改行などの制御文字は使用できません。1回に1つのコマンドを送信してください。"
+)
+
+// RCONPolicy はサービスごとに実行を許可するRCONコマンドを提供する
+type RCONPolicy interface {
+	RCONCommandAllowed(service, command string) bool
+	RCONAllowedCommands(service string) []string
+}
+
+// RCONCommand handles the rcon command
+type RCONCommand struct {
+	runner   *rcon.Runner
+	policy   RCONPolicy
+	recorder audit.Recorder
+}
+
+// NewRCONCommand creates a new RCONCommand
+// policyがnilの場合はすべてのRCONコマンドの実行を拒否する。recorderがnilの場合は監査ログに記録しない
+func NewRCONCommand(runner *rcon.Runner, policy RCONPolicy, recorder audit.Recorder) *RCONCommand {
+	return &RCONCommand{runner: runner, policy: policy, recorder: recorder}
+}
+
+// Name returns the command name
+func (c *RCONCommand) Name() string {
+	return "rcon"
+}
+
+// Description returns the command description
+func (c *RCONCommand) Description() string {
+	return "ゲームサーバーにRCONコマンドを送信"
+}
+
+// RequiredPermission returns the permission required to run the command
+func (c *RCONCommand) RequiredPermission(args []string) permission.Requirement {
+	return serviceRequirement(permission.LevelOperator, args)
+}
+
+// Options returns the slash command options
+func (c *RCONCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		serviceOption("コマンドを送信するサービス名"),
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        OptionCommand,
+			Description: "送信するRCONコマンド（設定ファイルで許可したコマンドのみ）",
+			Required:    true,
+			MaxLength:   rcon.MaxCommandLength,
+		},
+	}
+}
+
+// Execute runs the command with the request context
+// 許可リストにないコマンドは送信せず、応答はDiscord用にサニタイズして返す
+func (c *RCONCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if len(inv.Args) < 2 {
+		return TextResponse(rconUsage), nil
+	}
+
+	serviceName := inv.Args[0]
+	rconCommand := strings.TrimSpace(strings.Join(inv.Args[1:], " "))
+	if rconCommand == "" {
+		return TextResponse(rconUsage), nil
+	}
+	if rcon.ValidateCommand(rconCommand) != nil {
+		return TextResponse(rconUnsafeMessage), nil
+	}
+	if c.policy == nil || !c.policy.RCONCommandAllowed(serviceName, rconCommand) {
+		return TextResponse(c.deniedMessage(serviceName, rconCommand)), nil
+	}
+
+	logging.FromContext(ctx).Info(ctx, "Executing RCON command",
+		logging.String("service", serviceName),
+		logging.String("command", rconCommand),
+		logging.String("user", inv.Username))
+
+	var output string
+	entry := audit.Entry{
+		Actor:   inv.Actor(),
+		Service: serviceName,
+		Action:  audit.ActionRCON,
+		Source:  audit.SourceCommand,
+		Detail:  rconCommand,
+	}
+	err := audit.Track(context.WithoutCancel(ctx), c.recorder, entry, func() error {
+		var err error
+		output, err = c.runner.Run(ctx, serviceName, rconCommand)
+		return err
+	})
+	if err != nil {
+		return TextResponse(rconErrorMessage(serviceName, err)), nil
+	}
+	return TextResponse(formatRCONOutput(serviceName, rconCommand, output)), nil
+}
+
+// deniedMessage は許可されていないコマンドの場合のメッセージを返す
+func (c *RCONCommand) deniedMessage(serviceName, rconCommand string) string {
+	name := FormatServiceName(serviceName)
+	message := fmt.Sprintf("🚫 %s では `%s` の実行は許可されていません。",
+		name, security.SanitizeForDiscord(strings.Fields(rconCommand)[0]))
+
+	var allowed []string
+	if c.policy != nil {
+		allowed = c.policy.RCONAllowedCommands(serviceName)
+	}
+	if len(allowed) == 0 {
+		return message + fmt.Sprintf("\n設定ファイルの `services.%s.rcon.commands` で実行を許可するコマンドを指定してください。",
+			serviceName)
+	}
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		quoted[i] = "`" + a + "`"
+	}
+	return message + "\n許可されているコマンド: " + strings.Join(quoted, ", ")
+}
+
+// rconErrorMessage はRCONコマンドの送信に失敗した場合のメッセージを返す
+func rconErrorMessage(serviceName string, err error) string {
+	name := FormatServiceName(serviceName)
+	switch {
+	case errors.Is(err, rcon.ErrServiceNotFound):
+		return fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName)
+	case errors.Is(err, rcon.ErrNotRunning):
+		return fmt.Sprintf("⚠️ %s は停止中です", name)
+	case errors.Is(err, rcon.ErrNotConfigured):
+		return fmt.Sprintf("❌ %s のRCONの接続先が見つかりません。コンテナに `%s` ラベルか環境変数 `%s` と、`%s` を設定してください。",
+			name, rcon.LabelPort, rcon.EnvPort, rcon.EnvPassword)
+	case errors.Is(err, rcon.ErrAuthFailed):
+		return fmt.Sprintf("❌ %s のRCONの認証に失敗しました。パスワードを確認してください。", name)
+	default:
+		return fmt.Sprintf("❌ %s へのRCONコマンドの送信に失敗しました: %s", name, security.SanitizeForDiscord(err.Error()))
+	}
+}
+
+// formatRCONOutput はRCONの応答をDiscord用にサニタイズして表示する
+func formatRCONOutput(serviceName, rconCommand, output string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🖥️ **%s** ← %s\n", FormatServiceName(serviceName), security.SanitizeForDiscord(rconCommand))
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		builder.WriteString("(応答なし)")
+		return builder.String()
+	}
+	builder.WriteString(truncateRunes(security.SanitizeForDiscord(output), maxRCONOutputLength))
+	return builder.String()
+}
@@ -0,0 +1,249 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
+)
+
+// rconPolicyStub は許可リストをそのまま返すRCONPolicy
+type rconPolicyStub map[string][]string
+
+func (p rconPolicyStub) RCONCommandAllowed(service, command string) bool {
+	for _, allowed := range p[service] {
+		if strings.Fields(command)[0] == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (p rconPolicyStub) RCONAllowedCommands(service string) []string {
+	return p[service]
+}
+
+// rconCompose はRCONを設定したminecraftと、停止中のvalheim、RCONを設定していないterrariaを返す
+func rconCompose() *docker.MockComposeService {
+	return &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{Name: "mc-1", Service: "minecraft", State: "running"},
+				{Name: "valheim-1", Service: "valheim", State: "exited"},
+				{Name: "terraria-1", Service: "terraria", State: "running"},
+			}, nil
+		},
+		GetContainerEnvFunc: func(name string) (map[string]string, error) {
+			if name == "mc-1" {
+				return map[string]string{rcon.EnvPort: "25575", rcon.EnvPassword: "secret"}, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestRCONCommand_Basics(t *testing.T) {
+	cmd := NewRCONCommand(nil, nil, nil)
+	if got := cmd.Name(); got != "rcon" {
+		t.Errorf("Name() = %q, want %q", got, "rcon")
+	}
+	if cmd.Description() == "" {
+		t.Error("Description() is empty")
+	}
+}
+
+func TestRCONCommand_Execute(t *testing.T) {
+	policy := rconPolicyStub{
+		"minecraft": {"list", "say"},
+		"valheim":   {"list"},
+		"terraria":  {"list"},
+	}
+
+	tests := []struct {
+		name        string
+		policy      RCONPolicy
+		args        []string
+		output      string
+		execErr     error
+		wantCommand string
+		wantContain []string
+		wantMissing []string
+	}{
+		{
+			name:        "引数なし",
+			policy:      policy,
+			args:        []string{"minecraft"},
+			wantContain: []string{"使用方法"},
+		},
+		{
+			name:        "空のコマンド",
+			policy:      policy,
+			args:        []string{"minecraft", " "},
+			wantContain: []string{"使用方法"},
+		},
+		{
+			name:        "正常系",
+			policy:      policy,
+			args:        []string{"minecraft", "list"},
+			output:      "There are 1 of a max of 20 players online: Steve_01\n",
+			wantCommand: "list",
+			wantContain: []string{"🖥️ **Minecraft** ← list", "There are 1 of a max of 20 players online: Steve\\_01"},
+		},
+		{
+			name:        "引数を結合して送信する",
+			policy:      policy,
+			args:        []string{"minecraft", "say", "hello", "world"},
+			wantCommand: "say hello world",
+			wantContain: []string{"say hello world", "(応答なし)"},
+		},
+		{
+			name:        "応答のセンシティブな情報を伏せる",
+			policy:      policy,
+			args:        []string{"minecraft", "list"},
+			output:      "token: abcdefghijklmnop",
+			wantCommand: "list",
+			wantContain: []string{"[REDACTED]"},
+			wantMissing: []string{"abcdefghijklmnop"},
+		},
+		{
+			name:        "長い応答は切り詰める",
+			policy:      policy,
+			args:        []string{"minecraft", "list"},
+			output:      strings.Repeat("a", 3000),
+			wantCommand: "list",
+			wantContain: []string{"…"},
+			wantMissing: []string{strings.Repeat("a", maxRCONOutputLength)},
+		},
+		{
+			name:        "許可されていないコマンド",
+			policy:      policy,
+			args:        []string{"minecraft", "stop"},
+			wantContain: []string{"🚫", "`stop` の実行は許可されていません", "許可されているコマンド: `list`, `say`"},
+		},
+		{
+			name:        "セミコロンで区切った後続のコマンド",
+			policy:      policy,
+			args:        []string{"minecraft", "list;", "stop"},
+			wantContain: []string{"🚫", "制御文字は使用できません"},
+		},
+		{
+			name:        "改行で区切った後続のコマンド",
+			policy:      policy,
+			args:        []string{"minecraft", "list\nstop"},
+			wantContain: []string{"🚫", "制御文字は使用できません"},
+		},
+		{
+			name:        "復帰文字で区切った後続のコマンド",
+			policy:      policy,
+			args:        []string{"minecraft", "say hi\rstop"},
+			wantContain: []string{"🚫", "制御文字は使用できません"},
+		},
+		{
+			name:        "許可リストがないサービス",
+			policy:      policy,
+			args:        []string{"rust", "list"},
+			wantContain: []string{"🚫", "services.rust.rcon.commands"},
+		},
+		{
+			name:        "ポリシーがない",
+			policy:      nil,
+			args:        []string{"minecraft", "list"},
+			wantContain: []string{"🚫"},
+		},
+		{
+			name:        "停止中のサービス",
+			policy:      policy,
+			args:        []string{"valheim", "list"},
+			wantContain: []string{"停止中"},
+		},
+		{
+			name:        "RCONを設定していないサービス",
+			policy:      policy,
+			args:        []string{"terraria", "list"},
+			wantContain: []string{"RCONの接続先が見つかりません", "RCON_PASSWORD"},
+		},
+		{
+			name:        "認証に失敗",
+			policy:      policy,
+			args:        []string{"minecraft", "list"},
+			execErr:     rcon.ErrAuthFailed,
+			wantCommand: "list",
+			wantContain: []string{"認証に失敗しました"},
+		},
+		{
+			name:        "送信に失敗",
+			policy:      policy,
+			args:        []string{"minecraft", "list"},
+			execErr:     errors.New("dial tcp 203.0.113.5:25575: connection refused"),
+			wantCommand: "list",
+			wantContain: []string{"送信に失敗しました", "[IP\\_REDACTED]"},
+			wantMissing: []string{"203.0.113.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var command string
+			executor := &rcon.MockExecutor{
+				ExecuteFunc: func(_ context.Context, _ rcon.Target, c string) (string, error) {
+					command = c
+					return tt.output, tt.execErr
+				},
+			}
+			cmd := NewRCONCommand(rcon.NewRunner(rconCompose(), "docker-compose.yml", executor), tt.policy, nil)
+
+			got, err := runText(cmd, tt.args)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if command != tt.wantCommand {
+				t.Errorf("sent command = %q, want %q", command, tt.wantCommand)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(got, want) {
+					t.Errorf("Execute() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(got, missing) {
+					t.Errorf("Execute() = %q, should not contain %q", got, missing)
+				}
+			}
+		})
+	}
+}
+
+func TestRCONCommand_Invoke_RecordsAudit(t *testing.T) {
+	policy := rconPolicyStub{"minecraft": {"list"}}
+	executor := &rcon.MockExecutor{
+		ExecuteFunc: func(context.Context, rcon.Target, string) (string, error) {
+			return "", rcon.ErrAuthFailed
+		},
+	}
+	store := &audit.MockStore{}
+	cmd := NewRCONCommand(rcon.NewRunner(rconCompose(), "docker-compose.yml", executor), policy, store)
+
+	inv := &Invocation{UserID: "user-1", Username: "alice", ChannelID: "channel-1", Args: []string{"minecraft", "list"}}
+	if _, err := cmd.Execute(context.Background(), inv); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	// 拒否したコマンドは実行していないため記録しない
+	denied := &Invocation{UserID: "user-1", Username: "alice", Args: []string{"minecraft", "list;stop"}}
+	if _, err := cmd.Execute(context.Background(), denied); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	entries := store.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Actor != inv.Actor() || e.Service != "minecraft" || e.Action != audit.ActionRCON ||
+		e.Source != audit.SourceCommand || e.Detail != "list" || e.Result != audit.ResultFailure {
+		t.Errorf("recorded entry = %+v", e)
+	}
+}
@@ -0,0 +1,58 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Response はコマンドの実行結果（本文・埋め込み・コンポーネント・添付ファイル）
+type Response struct {
+	Content    string
+	Embeds     []*discordgo.MessageEmbed
+	Components []discordgo.MessageComponent
+	Files      []*discordgo.File
+	// Ephemeral は実行者のみに表示するか（スラッシュコマンドの場合のみ有効）
+	Ephemeral bool
+	// Sent は送信後に呼ばれ、送信したメッセージを更新する関数を受け取る（nilの場合は呼ばない）
+	Sent func(edit MessageEditFunc)
+}
+
+// MessageEditFunc は送信済みのメッセージの本文を置き換え、ボタンを取り除く
+type MessageEditFunc func(content string) error
+
+// TextResponse は本文だけの実行結果を返す
+func TextResponse(content string) *Response {
+	return &Response{Content: content}
+}
+
+// Text は埋め込みをテキストに展開した実行結果を返す（埋め込みを表示できない場合やテスト向け）
+func (r *Response) Text() string {
+	var builder strings.Builder
+	builder.WriteString(r.Content)
+	for _, embed := range r.Embeds {
+		if builder.Len() > 0 {
+			builder.WriteString("\n\n")
+		}
+		writeEmbedText(&builder, embed)
+	}
+	return builder.String()
+}
+
+// writeEmbedText は埋め込みのタイトル・説明・フィールド・フッターをテキストとして書き込む
+func writeEmbedText(builder *strings.Builder, embed *discordgo.MessageEmbed) {
+	lines := make([]string, 0, len(embed.Fields)+3)
+	if embed.Title != "" {
+		lines = append(lines, "**"+embed.Title+"**")
+	}
+	if embed.Description != "" {
+		lines = append(lines, embed.Description)
+	}
+	for _, field := range embed.Fields {
+		lines = append(lines, "**"+field.Name+"**\n"+field.Value)
+	}
+	if embed.Footer != nil && embed.Footer.Text != "" {
+		lines = append(lines, embed.Footer.Text)
+	}
+	builder.WriteString(strings.Join(lines, "\n"))
+}
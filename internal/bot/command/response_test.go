@@ -0,0 +1,57 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestResponse_Text(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *Response
+		want string
+	}{
+		{
+			name: "本文のみ",
+			resp: TextResponse("pong!!"),
+			want: "pong!!",
+		},
+		{
+			name: "埋め込みをテキストに展開",
+			resp: &Response{Embeds: []*discordgo.MessageEmbed{{
+				Title:       "📊 サーバーステータス",
+				Description: "説明",
+				Fields:      []*discordgo.MessageEmbedField{{Name: "CPU使用率", Value: "25.5%"}},
+				Footer:      &discordgo.MessageEmbedFooter{Text: "フッター"},
+			}}},
+			want: "**📊 サーバーステータス**\n説明\n**CPU使用率**\n25.5%\nフッター",
+		},
+		{
+			name: "本文と複数の埋め込み",
+			resp: &Response{
+				Content: "本文",
+				Embeds:  []*discordgo.MessageEmbed{{Title: "1"}, {Title: "2"}},
+			},
+			want: "本文\n\n**1**\n\n**2**",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.Text(); got != tt.want {
+				t.Errorf("Text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// runText はコマンドを実行して実行結果をテキストで返す
+func runText(cmd Command, args []string) (string, error) {
+	resp, err := cmd.Execute(context.Background(), &Invocation{Args: args})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text(), nil
+}
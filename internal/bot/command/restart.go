@@ -1,28 +1,62 @@
 package command
 
 import (
+	"context"
 	"fmt"
-	"sync"
 
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
 )
 
+// restartBroadcast は再起動の直前にゲーム内に通知するメッセージ
+const restartBroadcast = "[Watchdog] まもなくサーバーを再起動します"
+
 // RestartCommand handles the restart command
 type RestartCommand struct {
 	compose           docker.ComposeService
 	composePath       string
-	serviceOperations *sync.Map // サービス名をキーとした操作ロック
+	serviceOperations *oplock.Locker // サービス名をキーとした操作ロック
+	confirmations     *Confirmations // 再起動前の確認（nilの場合は確認しない）
+	recorder          audit.Recorder // 監査ログ（nilの場合は記録しない）
+	prober            gameprobe.Prober
+	broadcaster       *rcon.Broadcaster // 再起動前のゲーム内通知（nilの場合は通知しない）
 }
 
 // NewRestartCommand creates a new RestartCommand
-func NewRestartCommand(compose docker.ComposeService, composePath string) *RestartCommand {
+// proberは再起動前に接続中のプレイヤーを問い合わせるために使用する（nilの場合はデフォルトのタイムアウトで作成）
+func NewRestartCommand(
+	compose docker.ComposeService,
+	composePath string,
+	locks *oplock.Locker,
+	confirmations *Confirmations,
+	recorder audit.Recorder,
+	prober gameprobe.Prober,
+	broadcaster *rcon.Broadcaster,
+) *RestartCommand {
 	if composePath == "" {
 		composePath = "docker-compose.yml"
 	}
+	if locks == nil {
+		locks = oplock.New()
+	}
+	if prober == nil {
+		prober = gameprobe.NewProber(0)
+	}
 	return &RestartCommand{
 		compose:           compose,
 		composePath:       composePath,
-		serviceOperations: &sync.Map{},
+		serviceOperations: locks,
+		confirmations:     confirmations,
+		recorder:          recorder,
+		prober:            prober,
+		broadcaster:       broadcaster,
 	}
 }
 
@@ -36,43 +70,118 @@ func (c *RestartCommand) Description() string {
 	return "指定されたコンテナを再起動"
 }
 
-// Execute runs the command
-func (c *RestartCommand) Execute(args []string) (string, error) {
-	if len(args) == 0 {
-		return "使用方法: `@bot restart <サービス名>`", nil
+// RequiredPermission returns the permission required to run the command
+func (c *RestartCommand) RequiredPermission(args []string) permission.Requirement {
+	return serviceRequirement(permission.LevelOperator, args)
+}
+
+// Options returns the slash command options
+func (c *RestartCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		serviceOption("再起動するサービス名"),
 	}
+}
 
-	serviceName := args[0]
+// Execute runs the command and records the invoking user in the audit log
+// 確認が必要なサービスと、接続中のプレイヤーがいるサービスは確認ボタンを返して再起動しない
+func (c *RestartCommand) Execute(ctx context.Context, inv *Invocation) (*Response, error) {
+	if len(inv.Args) == 0 {
+		return TextResponse("使用方法: `@bot restart <サービス名>`"), nil
+	}
 
+	serviceName := inv.Args[0]
+	container, err := c.findService(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if container == nil {
+		return TextResponse(fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName)), nil
+	}
+
+	players := checkPlayers(ctx, c.prober, container)
+	if c.confirmations != nil && (c.confirmations.Required(serviceName) || players.online()) {
+		components, err := c.confirmations.Request(serviceName, "再起動", c.RequiredPermission(inv.Args), inv.UserID,
+			func(ctx context.Context, actor audit.Actor) string {
+				content, err := c.restart(ctx, actor, audit.SourceButton, serviceName)
+				if err != nil {
+					return "❌ " + err.Error()
+				}
+				return content
+			}, players.waitEmpty(c.prober))
+		if err != nil {
+			return nil, err
+		}
+		// メンションで実行した場合は全員に表示されるが、ボタンは実行したユーザーのみ操作できる
+		return &Response{
+			Content:    c.confirmations.Prompt(serviceName, "再起動", players.status),
+			Components: components,
+			Ephemeral:  true,
+			Sent: func(edit MessageEditFunc) {
+				c.confirmations.ClearOnExpiry(components, edit)
+			},
+		}, nil
+	}
+
+	content, err := c.restart(ctx, inv.Actor(), audit.SourceCommand, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return TextResponse(content), nil
+}
+
+// restart restarts the service while holding the operation lock
+// ctxがキャンセルされている場合は再起動しない
+func (c *RestartCommand) restart(
+	ctx context.Context, actor audit.Actor, source, serviceName string,
+) (string, error) {
 	// 操作ロックをチェック
-	if _, loaded := c.serviceOperations.LoadOrStore(serviceName, true); loaded {
+	if !c.serviceOperations.TryLock(serviceName) {
 		return fmt.Sprintf("⚠️ %s は現在操作中です。しばらくお待ちください。", FormatServiceName(serviceName)), nil
 	}
-	defer c.serviceOperations.Delete(serviceName)
+	defer c.serviceOperations.Unlock(serviceName)
 
 	// コンテナの存在確認
-	containers, err := c.compose.ListContainers(c.composePath)
+	container, err := c.findService(ctx, serviceName)
 	if err != nil {
-		return "", fmt.Errorf("コンテナ情報の取得に失敗しました: %w", err)
+		return "", err
+	}
+	if container == nil {
+		return fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName), nil
 	}
 
-	found := false
-	for i := range containers {
-		if containers[i].Service == serviceName {
-			found = true
-			break
-		}
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%s の再起動を中止しました: %w", FormatServiceName(serviceName), ctx.Err())
 	}
 
-	if !found {
-		return fmt.Sprintf("❌ サービス '%s' が見つかりません", serviceName), nil
+	// ゲーム内のプレイヤーに通知してから再起動する（通知の失敗は再起動に影響しない）
+	logger := logging.FromContext(ctx)
+	if err := c.broadcaster.Broadcast(ctx, serviceName, restartBroadcast); err != nil {
+		logger.Warn(ctx, "Failed to broadcast restart", logging.String("service", serviceName), logging.ErrorField(err))
 	}
 
-	// 再起動を実行
-	err = c.compose.RestartContainer(c.composePath, serviceName)
+	// 再起動を実行（途中でタイムアウトしても監査ログは記録する）
+	logger.Info(ctx, "Restarting service", logging.String("service", serviceName))
+	entry := audit.Entry{Actor: actor, Service: serviceName, Action: audit.ActionRestart, Source: source}
+	err = audit.Track(context.WithoutCancel(ctx), c.recorder, entry, func() error {
+		return c.compose.RestartContainer(ctx, c.composePath, serviceName)
+	})
 	if err != nil {
 		return fmt.Sprintf("❌ %s の再起動に失敗しました: %v", FormatServiceName(serviceName), err), nil
 	}
 
 	return fmt.Sprintf("🔄 %s を再起動しました！", FormatServiceName(serviceName)), nil
 }
+
+// findService returns the container of the service (nil if the service has no container)
+func (c *RestartCommand) findService(ctx context.Context, serviceName string) (*docker.ContainerInfo, error) {
+	containers, err := c.compose.ListContainers(ctx, c.composePath)
+	if err != nil {
+		return nil, fmt.Errorf("コンテナ情報の取得に失敗しました: %w", err)
+	}
+	for i := range containers {
+		if containers[i].Service == serviceName {
+			return &containers[i], nil
+		}
+	}
+	return nil, nil
+}
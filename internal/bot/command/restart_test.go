@@ -1,24 +1,29 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
 )
 
 func TestRestartCommand_Name(t *testing.T) {
-	cmd := NewRestartCommand(&docker.MockComposeService{}, "")
+	cmd := NewRestartCommand(&docker.MockComposeService{}, "", nil, nil, nil, nil, nil)
 	if got := cmd.Name(); got != "restart" {
 		t.Errorf("RestartCommand.Name() = %v, want %v", got, "restart")
 	}
 }
 
 func TestRestartCommand_Description(t *testing.T) {
-	cmd := NewRestartCommand(&docker.MockComposeService{}, "")
+	cmd := NewRestartCommand(&docker.MockComposeService{}, "", nil, nil, nil, nil, nil)
 	if got := cmd.Description(); got != "指定されたコンテナを再起動" {
 		t.Errorf("RestartCommand.Description() = %v, want %v", got, "指定されたコンテナを再起動")
 	}
@@ -45,7 +50,7 @@ func TestNewRestartCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCompose := &docker.MockComposeService{}
-			cmd := NewRestartCommand(mockCompose, tt.composePath)
+			cmd := NewRestartCommand(mockCompose, tt.composePath, nil, nil, nil, nil, nil)
 
 			if cmd.composePath != tt.expected {
 				t.Errorf("NewRestartCommand() composePath = %v, want %v", cmd.composePath, tt.expected)
@@ -56,7 +61,7 @@ func TestNewRestartCommand(t *testing.T) {
 			}
 
 			if cmd.serviceOperations == nil {
-				t.Error("NewRestartCommand() serviceOperations map not initialized")
+				t.Error("NewRestartCommand() serviceOperations lock not initialized")
 			}
 		})
 	}
@@ -164,8 +169,8 @@ func TestRestartCommand_Execute(t *testing.T) {
 				},
 			}
 
-			cmd := NewRestartCommand(mockCompose, "test-compose.yml")
-			result, err := cmd.Execute(tt.args)
+			cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, nil)
+			result, err := runText(cmd, tt.args)
 
 			if tt.expectError {
 				if err == nil {
@@ -200,7 +205,7 @@ func TestRestartCommand_Execute_ConcurrentOperations(t *testing.T) {
 		},
 	}
 
-	cmd := NewRestartCommand(mockCompose, "test-compose.yml")
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, nil)
 
 	// 同時実行テスト
 	var wg sync.WaitGroup
@@ -211,7 +216,7 @@ func TestRestartCommand_Execute_ConcurrentOperations(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			result, err := cmd.Execute([]string{"web"})
+			result, err := runText(cmd, []string{"web"})
 			if err != nil {
 				results <- "ERROR: " + err.Error()
 			} else {
@@ -268,7 +273,7 @@ func TestRestartCommand_Execute_MultipleServices(t *testing.T) {
 		},
 	}
 
-	cmd := NewRestartCommand(mockCompose, "test-compose.yml")
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, nil)
 
 	// 異なるサービスに対する並行操作
 	var wg sync.WaitGroup
@@ -279,7 +284,7 @@ func TestRestartCommand_Execute_MultipleServices(t *testing.T) {
 		wg.Add(1)
 		go func(svc string) {
 			defer wg.Done()
-			result, err := cmd.Execute([]string{svc})
+			result, err := runText(cmd, []string{svc})
 			if err != nil {
 				results <- "ERROR: " + err.Error()
 			} else {
@@ -340,8 +345,8 @@ func TestRestartCommand_Execute_InvalidServiceName(t *testing.T) {
 				},
 			}
 
-			cmd := NewRestartCommand(mockCompose, "test-compose.yml")
-			result, err := cmd.Execute([]string{tt.serviceName})
+			cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, nil)
+			result, err := runText(cmd, []string{tt.serviceName})
 
 			if tt.expectError {
 				if err == nil {
@@ -372,14 +377,273 @@ func BenchmarkRestartCommand_Execute(b *testing.B) {
 		},
 	}
 
-	cmd := NewRestartCommand(mockCompose, "test-compose.yml")
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, nil)
 	args := []string{"web"}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := cmd.Execute(args)
+		_, err := runText(cmd, args)
 		if err != nil {
 			b.Fatalf("Execute() failed: %v", err)
 		}
 	}
 }
+
+func TestRestartCommand_Execute_SharedLock(t *testing.T) {
+	restarted := false
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{Service: "minecraft", State: "running"}}, nil
+		},
+		RestartContainerFunc: func(_, _ string) error {
+			restarted = true
+			return nil
+		},
+	}
+
+	// 他のコンポーネント（自動再起動など）がロックを保持している
+	locks := oplock.New()
+	locks.TryLock("minecraft")
+
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", locks, nil, nil, nil, nil)
+	result, err := runText(cmd, []string{"minecraft"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(result, "現在操作中です") {
+		t.Errorf("Execute() = %q, want operation-in-progress message", result)
+	}
+	if restarted {
+		t.Error("RestartContainer was called while the lock was held by another component")
+	}
+
+	locks.Unlock("minecraft")
+	result, err = runText(cmd, []string{"minecraft"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(result, "再起動しました") {
+		t.Errorf("Execute() = %q, want success after unlock", result)
+	}
+}
+
+func TestRestartCommand_Confirmation(t *testing.T) {
+	restarted := false
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{Service: "minecraft", State: "running"},
+				{Service: "terraria", State: "running"},
+			}, nil
+		},
+		RestartContainerFunc: func(_, _ string) error {
+			restarted = true
+			return nil
+		},
+	}
+	confirmations := NewConfirmations(context.Background(), onlyMinecraft, 0, 0)
+	store := &audit.MockStore{}
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, confirmations, store, nil, nil)
+
+	// 確認が必要なサービスはすぐに再起動しない
+	resp, err := cmd.Execute(context.Background(), &Invocation{UserID: "user-1", Args: []string{"minecraft"}})
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if restarted || !strings.Contains(resp.Content, "再起動しますか？") {
+		t.Fatalf("Invoke() = %q, restarted = %v; want confirmation prompt", resp.Content, restarted)
+	}
+	if !resp.Ephemeral {
+		t.Error("confirmation prompt should be ephemeral")
+	}
+
+	confirmID, _ := confirmButtons(t, resp.Components)
+	token, _ := parseConfirmCustomID(confirmID)
+	p, ok := confirmations.take(token)
+	if !ok {
+		t.Fatal("confirmation was not registered")
+	}
+	if p.userID != "user-1" {
+		t.Errorf("confirmation userID = %q, want the invoking user", p.userID)
+	}
+	if resp.Sent == nil {
+		t.Error("confirmation prompt should clear its buttons on expiry")
+	}
+	if got := p.run(context.Background(), audit.Actor{}); !strings.Contains(got, "再起動しました") || !restarted {
+		t.Errorf("confirmed run = %q, restarted = %v", got, restarted)
+	}
+	// ボタンで確定した再起動はボタン操作として記録する
+	if entries := store.Entries(); len(entries) != 1 || entries[0].Source != audit.SourceButton {
+		t.Errorf("confirmed run audit entries = %+v, want one button entry", entries)
+	}
+
+	// 確認が不要なサービスはすぐに再起動する
+	restarted = false
+	resp, _ = cmd.Execute(context.Background(), &Invocation{Args: []string{"terraria"}})
+	if !restarted || !strings.Contains(resp.Content, "再起動しました") {
+		t.Errorf("Invoke(terraria) = %q, restarted = %v", resp.Content, restarted)
+	}
+	if resp.Components != nil || resp.Ephemeral {
+		t.Errorf("Invoke(terraria) components = %v, ephemeral = %v; want a public message", resp.Components, resp.Ephemeral)
+	}
+
+	// 存在しないサービスは確認を求めない
+	always := confirmPolicyFunc(func(string) bool { return true })
+	cmd = NewRestartCommand(mockCompose, "test-compose.yml", nil, NewConfirmations(context.Background(), always, 0, 0), nil, nil, nil)
+	resp, _ = cmd.Execute(context.Background(), &Invocation{Args: []string{"minecraft-old"}})
+	if !strings.Contains(resp.Content, "見つかりません") {
+		t.Errorf("Invoke(minecraft-old) = %q", resp.Content)
+	}
+	if resp.Components != nil {
+		t.Errorf("Invoke(minecraft-old) components = %v, want nil", resp.Components)
+	}
+}
+
+func TestRestartCommand_PlayersOnline(t *testing.T) {
+	labels := map[string]string{"game.type": "minecraft", gameprobe.LabelQueryPort: "25565"}
+	restarted := false
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{Name: "mc-1", Service: "minecraft", State: "running", Labels: labels},
+				{Name: "mc-2", Service: "minecraft-creative", State: "running", Labels: labels},
+			}, nil
+		},
+		RestartContainerFunc: func(_, _ string) error {
+			restarted = true
+			return nil
+		},
+	}
+	prober := &gameprobe.MockProber{
+		ProbeFunc: func(_ context.Context, target gameprobe.Target) (*gameprobe.Status, error) {
+			if target.Service == "minecraft" {
+				return &gameprobe.Status{Players: 2, PlayerNames: []string{"Steve", "Alex"}}, nil
+			}
+			return &gameprobe.Status{}, nil
+		},
+	}
+	confirmations := NewConfirmations(context.Background(), nil, 0, 0)
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, confirmations, nil, prober, nil)
+
+	// 確認が不要なサービスでもプレイヤーがいる場合は確認を求める
+	resp, err := cmd.Execute(context.Background(), &Invocation{Args: []string{"minecraft"}})
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if restarted {
+		t.Fatal("Invoke() restarted while players are online")
+	}
+	for _, want := range []string{"再起動しますか？", "**2人**", "Steve, Alex"} {
+		if !strings.Contains(resp.Content, want) {
+			t.Errorf("Invoke() = %q, want to contain %q", resp.Content, want)
+		}
+	}
+	if ids := buttonIDs(t, resp.Components); len(ids) != 3 || !strings.HasPrefix(ids[1], emptyActionPrefix) {
+		t.Errorf("Invoke() buttons = %v, want wait-until-empty button", ids)
+	}
+
+	// プレイヤーがいない場合はすぐに再起動する
+	resp, _ = cmd.Execute(context.Background(), &Invocation{Args: []string{"minecraft-creative"}})
+	if !restarted || !strings.Contains(resp.Content, "再起動しました") {
+		t.Errorf("Invoke(minecraft-creative) = %q, restarted = %v", resp.Content, restarted)
+	}
+}
+
+func TestRestartCommand_Invoke_RecordsAudit(t *testing.T) {
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{Service: "minecraft", State: "running"}}, nil
+		},
+		RestartContainerFunc: func(_, _ string) error {
+			return errors.New("restart failed")
+		},
+	}
+	store := &audit.MockStore{}
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, store, nil, nil)
+
+	inv := &Invocation{UserID: "user-1", Username: "alice", ChannelID: "channel-1", Args: []string{"minecraft"}}
+	if _, err := cmd.Execute(context.Background(), inv); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	entries := store.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Actor != inv.Actor() || e.Service != "minecraft" || e.Action != audit.ActionRestart ||
+		e.Source != audit.SourceCommand || e.Result != audit.ResultFailure || e.Error != "restart failed" {
+		t.Errorf("recorded entry = %+v", e)
+	}
+}
+
+func TestRestartCommand_Invoke_Canceled(t *testing.T) {
+	restarted := false
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{Service: "minecraft", State: "running"}}, nil
+		},
+		RestartContainerFunc: func(_, _ string) error {
+			restarted = true
+			return nil
+		},
+	}
+	cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cmd.Execute(ctx, &Invocation{Args: []string{"minecraft"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Invoke() error = %v, want context.Canceled", err)
+	}
+	if restarted {
+		t.Error("Invoke() should not restart the service after the request is canceled")
+	}
+}
+
+func TestRestartCommand_Broadcast(t *testing.T) {
+	var events []string
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{Name: "mc-1", Service: "minecraft", State: "running"}}, nil
+		},
+		GetContainerEnvFunc: func(string) (map[string]string, error) {
+			return map[string]string{rcon.EnvPort: "25575", rcon.EnvPassword: "secret"}, nil
+		},
+		RestartContainerFunc: func(_, _ string) error {
+			events = append(events, "restart")
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		execErr error
+	}{
+		{name: "通知してから再起動する"},
+		{name: "通知に失敗しても再起動する", execErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events = nil
+			executor := &rcon.MockExecutor{
+				ExecuteFunc: func(_ context.Context, _ rcon.Target, command string) (string, error) {
+					events = append(events, command)
+					return "", tt.execErr
+				},
+			}
+			broadcaster := rcon.NewBroadcaster(rcon.NewRunner(mockCompose, "test-compose.yml", executor), nil)
+			cmd := NewRestartCommand(mockCompose, "test-compose.yml", nil, nil, nil, nil, broadcaster)
+
+			result, err := runText(cmd, []string{"minecraft"})
+			if err != nil || !strings.Contains(result, "再起動しました") {
+				t.Fatalf("Execute() = %q, %v", result, err)
+			}
+			want := []string{"say " + restartBroadcast, "restart"}
+			if strings.Join(events, ",") != strings.Join(want, ",") {
+				t.Errorf("events = %q, want %q", events, want)
+			}
+		})
+	}
+}
@@ -1,20 +1,32 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/alert"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
 // StatusCommand はstatusコマンドの実装
 type StatusCommand struct {
-	monitor system.Monitor
+	monitor    system.Monitor
+	thresholds alert.ThresholdProvider
 }
 
 // NewStatusCommand は新しいStatusCommandを作成
-func NewStatusCommand(monitor system.Monitor) *StatusCommand {
+// thresholdsは埋め込みの色分けに使用する閾値（nilの場合はデフォルトの閾値）
+func NewStatusCommand(monitor system.Monitor, thresholds alert.ThresholdProvider) *StatusCommand {
+	if thresholds == nil {
+		thresholds = alert.DefaultThresholds()
+	}
 	return &StatusCommand{
-		monitor: monitor,
+		monitor:    monitor,
+		thresholds: thresholds,
 	}
 }
 
@@ -28,9 +40,14 @@ func (c *StatusCommand) Description() string {
 	return "サーバーのステータスを表示"
 }
 
-// Execute はコマンドを実行する
-func (c *StatusCommand) Execute(_ []string) (string, error) {
-	info, err := c.monitor.GetSystemInfo()
+// RequiredPermission はコマンドの実行に必要な権限を返す
+func (c *StatusCommand) RequiredPermission(_ []string) permission.Requirement {
+	return permission.Requirement{Level: permission.LevelViewer}
+}
+
+// executeText は実行結果をテキストで返す
+func (c *StatusCommand) executeText(ctx context.Context, _ []string) (string, error) {
+	info, err := c.monitor.GetSystemInfo(ctx)
 	if err != nil {
 		return "", fmt.Errorf("システム情報の取得に失敗しました: %w", err)
 	}
@@ -47,3 +64,40 @@ func (c *StatusCommand) Execute(_ []string) (string, error) {
 
 	return message, nil
 }
+
+// Execute はサーバーのステータスを埋め込みで返す
+// 閾値を超えたリソースがある場合は黄色で表示する
+func (c *StatusCommand) Execute(ctx context.Context, _ *Invocation) (*Response, error) {
+	info, err := c.monitor.GetSystemInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("システム情報の取得に失敗しました: %w", err)
+	}
+
+	alerts := alert.Check(info, nil, c.thresholds)
+	health := HealthOK
+	if len(alerts) > 0 {
+		health = HealthWarning
+	}
+
+	embed := newEmbed("📊 サーバーステータス", health)
+	addEmbedField(embed, "CPU使用率", fmt.Sprintf("%s\n%.1f%%",
+		NewProgressBar(info.CPUUsagePercent, 10), info.CPUUsagePercent), true)
+	addEmbedField(embed, "メモリ使用量", fmt.Sprintf("%s\n%.1fGB / %.1fGB (%.1f%%)",
+		NewProgressBar(info.MemoryUsedPercent, 10), info.MemoryUsedGB, info.MemoryTotalGB, info.MemoryUsedPercent), true)
+	addEmbedField(embed, "ディスク空き容量", fmt.Sprintf("%s\n%.1fGB / %.1fGB (%.1f%%)",
+		NewProgressBar(info.DiskUsedPercent, 10), info.DiskFreeGB, info.DiskTotalGB, info.DiskUsedPercent), true)
+	if len(alerts) > 0 {
+		addEmbedField(embed, "⚠️ アラート", formatAlerts(alerts), false)
+	}
+
+	return &Response{Embeds: []*discordgo.MessageEmbed{embed}}, nil
+}
+
+// formatAlerts はアラートを1件1行の箇条書きにする
+func formatAlerts(alerts []Alert) string {
+	lines := make([]string, len(alerts))
+	for i, a := range alerts {
+		lines[i] = fmt.Sprintf("- %s: %s (%.1f%%)", a.Component, a.Message, a.Value)
+	}
+	return strings.Join(lines, "\n")
+}
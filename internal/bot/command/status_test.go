@@ -1,7 +1,9 @@
 package command
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -24,7 +26,7 @@ func TestStatusCommand_Name(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			mockMonitor := &system.MockMonitor{}
-			cmd := NewStatusCommand(mockMonitor)
+			cmd := NewStatusCommand(mockMonitor, nil)
 			if got := cmd.Name(); got != tt.want {
 				t.Errorf("Name() = %v, want %v", got, tt.want)
 			}
@@ -48,7 +50,7 @@ func TestStatusCommand_Description(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			mockMonitor := &system.MockMonitor{}
-			cmd := NewStatusCommand(mockMonitor)
+			cmd := NewStatusCommand(mockMonitor, nil)
 			if got := cmd.Description(); got != tt.want {
 				t.Errorf("Description() = %v, want %v", got, tt.want)
 			}
@@ -102,9 +104,9 @@ func TestStatusCommand_Execute(t *testing.T) {
 				SystemInfo: tt.systemInfo,
 				Err:        tt.mockErr,
 			}
-			cmd := NewStatusCommand(mockMonitor)
+			cmd := NewStatusCommand(mockMonitor, nil)
 
-			got, err := cmd.Execute(tt.args)
+			got, err := cmd.executeText(context.Background(), tt.args)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
@@ -121,3 +123,73 @@ func TestStatusCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusCommand_ExecuteEmbed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		systemInfo *system.SystemInfo
+		mockErr    error
+		wantErr    bool
+		wantColor  int
+		wantAlert  bool
+	}{
+		{
+			name: "閾値以内の場合は緑",
+			systemInfo: &system.SystemInfo{
+				CPUUsagePercent:   25.5,
+				MemoryUsedGB:      8.0,
+				MemoryTotalGB:     16.0,
+				MemoryUsedPercent: 50.0,
+				DiskFreeGB:        256.0,
+				DiskTotalGB:       512.0,
+				DiskUsedPercent:   50.0,
+			},
+			wantColor: ColorHealthy,
+		},
+		{
+			name: "閾値を超えた場合は黄色でアラートを表示",
+			systemInfo: &system.SystemInfo{
+				CPUUsagePercent:   95.0,
+				MemoryUsedPercent: 50.0,
+				DiskUsedPercent:   50.0,
+			},
+			wantColor: ColorWarning,
+			wantAlert: true,
+		},
+		{
+			name:    "システム情報取得エラー",
+			mockErr: errors.New("monitor error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewStatusCommand(&system.MockMonitor{SystemInfo: tt.systemInfo, Err: tt.mockErr}, nil)
+
+			got, err := cmd.Execute(context.Background(), &Invocation{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteEmbed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got.Embeds) != 1 {
+				t.Fatalf("ExecuteEmbed() embeds = %d, want 1", len(got.Embeds))
+			}
+			embed := got.Embeds[0]
+			if embed.Color != tt.wantColor {
+				t.Errorf("Color = %#x, want %#x", embed.Color, tt.wantColor)
+			}
+			if field := findEmbedField(embed, "CPU使用率"); field == nil ||
+				!strings.Contains(field.Value, fmt.Sprintf("%.1f%%", tt.systemInfo.CPUUsagePercent)) {
+				t.Errorf("CPU field = %+v", field)
+			}
+			if got := findEmbedField(embed, "⚠️ アラート") != nil; got != tt.wantAlert {
+				t.Errorf("alert field = %v, want %v", got, tt.wantAlert)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/dashboard"
+)
+
+// dashboardPublisher はDiscordのチャンネルにダッシュボードを送信・編集する
+type dashboardPublisher struct {
+	session *discordgo.Session
+}
+
+// Send はダッシュボードを送信し、送信したメッセージのIDを返す
+func (p *dashboardPublisher) Send(
+	ctx context.Context, channelID, content string, components []discordgo.MessageComponent,
+) (string, error) {
+	msg, err := p.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:    content,
+		Components: components,
+	}, discordgo.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to send dashboard to channel %s: %w", channelID, err)
+	}
+	return msg.ID, nil
+}
+
+// Edit はダッシュボードを編集する（componentsが空の場合はボタンを削除する）
+func (p *dashboardPublisher) Edit(
+	ctx context.Context, channelID, messageID, content string, components []discordgo.MessageComponent,
+) error {
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+	edit := discordgo.NewMessageEdit(channelID, messageID).SetContent(content)
+	edit.Components = &components
+	if _, err := p.session.ChannelMessageEditComplex(edit, discordgo.WithContext(ctx)); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("%w: %s/%s", dashboard.ErrMessageNotFound, channelID, messageID)
+		}
+		return fmt.Errorf("failed to edit dashboard %s in channel %s: %w", messageID, channelID, err)
+	}
+	return nil
+}
+
+// isNotFound はメッセージまたはチャンネルが削除されている場合のエラーかを返す
+func isNotFound(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) {
+		return false
+	}
+	if restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return restErr.Message != nil && (restErr.Message.Code == discordgo.ErrCodeUnknownMessage ||
+		restErr.Message.Code == discordgo.ErrCodeUnknownChannel)
+}
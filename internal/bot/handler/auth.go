@@ -2,7 +2,13 @@
 package handler
 
 import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
 	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 )
 
 // IsAuthorized はユーザーがボットにアクセスする権限があるかチェック
@@ -38,3 +44,31 @@ func IsAuthorized(cfg *config.Config, channelID, userID string) bool {
 
 	return true
 }
+
+// messageUser はメッセージの送信者を権限判定用のユーザーに変換
+// DMなどメンバー情報がない場合はロールなしとして扱う
+func messageUser(m *discordgo.MessageCreate) permission.User {
+	user := permission.User{ID: m.Author.ID}
+	if m.Member != nil {
+		user.RoleIDs = m.Member.Roles
+	}
+	return user
+}
+
+// interactionUser はインタラクションの実行者を権限判定用のユーザーに変換
+func interactionUser(i *discordgo.InteractionCreate) permission.User {
+	user := permission.User{ID: interactionUserID(i)}
+	if i.Member != nil {
+		user.RoleIDs = i.Member.Roles
+	}
+	return user
+}
+
+// permissionDeniedMessage は権限が不足している場合のメッセージを返す
+func permissionDeniedMessage(req permission.Requirement) string {
+	if req.Service != "" {
+		return fmt.Sprintf("⛔ **%s** のこの操作には `%s` 権限が必要です。",
+			usermsg.FormatServiceName(req.Service), req.Level)
+	}
+	return fmt.Sprintf("⛔ この操作には `%s` 権限が必要です。", req.Level)
+}
@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/bwmarrin/discordgo"
+
 	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
 )
 
 func TestIsAuthorized(t *testing.T) {
@@ -106,3 +112,106 @@ func TestIsAuthorized(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageUser(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *discordgo.MessageCreate
+		want permission.User
+	}{
+		{
+			name: "サーバー内のメッセージ",
+			msg: &discordgo.MessageCreate{Message: &discordgo.Message{
+				Author: &discordgo.User{ID: "user-1"},
+				Member: &discordgo.Member{Roles: []string{"role-1", "role-2"}},
+			}},
+			want: permission.User{ID: "user-1", RoleIDs: []string{"role-1", "role-2"}},
+		},
+		{
+			name: "DM",
+			msg: &discordgo.MessageCreate{Message: &discordgo.Message{
+				Author: &discordgo.User{ID: "user-1"},
+			}},
+			want: permission.User{ID: "user-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageUser(tt.msg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messageUser() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInteractionUser(t *testing.T) {
+	i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Member: &discordgo.Member{User: &discordgo.User{ID: "member-1"}, Roles: []string{"role-1"}},
+	}}
+
+	want := permission.User{ID: "member-1", RoleIDs: []string{"role-1"}}
+	if got := interactionUser(i); !reflect.DeepEqual(got, want) {
+		t.Errorf("interactionUser() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPermissionDeniedMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		req  permission.Requirement
+		want string
+	}{
+		{
+			name: "サービス指定あり",
+			req:  permission.Requirement{Level: permission.LevelOperator, Service: "minecraft"},
+			want: "⛔ **Minecraft** のこの操作には `operator` 権限が必要です。",
+		},
+		{
+			name: "サービス指定なし",
+			req:  permission.Requirement{Level: permission.LevelAdmin},
+			want: "⛔ この操作には `admin` 権限が必要です。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := permissionDeniedMessage(tt.req); got != tt.want {
+				t.Errorf("permissionDeniedMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionAllowed_WithConfig(t *testing.T) {
+	const modRoleID = "100000000000000004"
+	cfg := &config.Config{
+		Permissions: config.PermissionsConfig{Default: "viewer"},
+		Services: map[string]config.ServiceConfig{
+			"minecraft": {Permissions: config.PermissionGrants{
+				Operator: config.PermissionGrant{Roles: []string{modRoleID}},
+			}},
+		},
+	}
+	restart := command.NewRestartCommand(&docker.MockComposeService{}, "", nil, nil, nil, nil, nil)
+	mod := permission.User{ID: "user-1", RoleIDs: []string{modRoleID}}
+
+	tests := []struct {
+		name string
+		user permission.User
+		args []string
+		want bool
+	}{
+		{name: "MODは担当サービスを再起動できる", user: mod, args: []string{"minecraft"}, want: true},
+		{name: "MODは他のサービスを再起動できない", user: mod, args: []string{"terraria"}, want: false},
+		{name: "一般ユーザーは再起動できない", user: permission.User{ID: "user-2"}, args: []string{"minecraft"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := permission.Allowed(cfg, tt.user, restart.RequiredPermission(tt.args)); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
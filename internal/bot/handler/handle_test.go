@@ -75,7 +75,7 @@ func TestHandleMethod(t *testing.T) {
 				},
 			}
 			mockCompose := &docker.MockComposeService{}
-			router := NewRouter(ctx, tt.config, mockMonitor, mockCompose)
+			router := NewRouter(ctx, tt.config, mockMonitor, mockCompose, nil, nil, nil, nil)
 
 			// セッションのモック化が困難なため、メソッドが存在することを確認
 			if router == nil {
@@ -145,7 +145,7 @@ func TestHandleInteractionMethod(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockMonitor := &system.MockMonitor{}
 			mockCompose := &docker.MockComposeService{}
-			router := NewRouter(ctx, tt.config, mockMonitor, mockCompose)
+			router := NewRouter(ctx, tt.config, mockMonitor, mockCompose, nil, nil, nil, nil)
 
 			// インタラクションのバリデーション
 			if router == nil {
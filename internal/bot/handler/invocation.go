@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+// defaultCommandTimeout は設定がない場合のコマンドのタイムアウト時間
+const defaultCommandTimeout = 2 * time.Minute
+
+// messageInvocation はメッセージからコマンドの呼び出し情報を作成
+func messageInvocation(m *discordgo.MessageCreate, args []string) *command.Invocation {
+	user := messageUser(m)
+	return &command.Invocation{
+		RequestID: newRequestID(),
+		UserID:    user.ID,
+		Username:  m.Author.Username,
+		RoleIDs:   user.RoleIDs,
+		ChannelID: m.ChannelID,
+		GuildID:   m.GuildID,
+		Args:      args,
+	}
+}
+
+// interactionInvocation はインタラクションからコマンドの呼び出し情報を作成
+func interactionInvocation(i *discordgo.InteractionCreate, args []string) *command.Invocation {
+	user := interactionUser(i)
+	actor := command.InteractionActor(i)
+	return &command.Invocation{
+		RequestID: newRequestID(),
+		UserID:    user.ID,
+		Username:  actor.Username,
+		RoleIDs:   user.RoleIDs,
+		ChannelID: i.ChannelID,
+		GuildID:   i.GuildID,
+		Args:      args,
+	}
+}
+
+// newRequestID はリクエストを識別するランダムなIDを生成
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // crypto/rand.Readはエラーを返さない
+	return hex.EncodeToString(b)
+}
+
+// commandTimeout はコマンドとボタン操作のタイムアウト時間を返す
+func (r *Router) commandTimeout() time.Duration {
+	if r.config.CommandTimeout <= 0 {
+		return defaultCommandTimeout
+	}
+	return r.config.CommandTimeout
+}
+
+// requestContext はリクエストごとのタイムアウトと、リクエストの情報を付与したロガーを設定したコンテキストを返す
+func (r *Router) requestContext(cmd command.Command, inv *command.Invocation) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.commandTimeout())
+
+	logger := logging.FromContext(r.ctx).With(
+		logging.String("request_id", inv.RequestID),
+		logging.String("command", cmd.Name()),
+		logging.String("user_id", inv.UserID),
+		logging.String("channel_id", inv.ChannelID),
+		logging.String("guild_id", inv.GuildID),
+	)
+	return logging.WithContext(ctx, logger), cancel
+}
+
+// interactionContext はボタン操作ごとのタイムアウトと、操作の情報を付与したロガーを設定したコンテキストを返す
+func (r *Router) interactionContext(
+	i *discordgo.InteractionCreate, customID string,
+) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.commandTimeout())
+
+	logger := logging.FromContext(r.ctx).With(
+		logging.String("request_id", newRequestID()),
+		logging.String("custom_id", customID),
+		logging.String("user_id", interactionUserID(i)),
+		logging.String("channel_id", i.ChannelID),
+		logging.String("guild_id", i.GuildID),
+	)
+	return logging.WithContext(ctx, logger), cancel
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+func TestMessageInvocation(t *testing.T) {
+	m := &discordgo.MessageCreate{Message: &discordgo.Message{
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Author:    &discordgo.User{ID: "user-1", Username: "alice"},
+		Member:    &discordgo.Member{Roles: []string{"role-1"}},
+	}}
+
+	got := messageInvocation(m, []string{"minecraft"})
+	want := &command.Invocation{
+		RequestID: got.RequestID,
+		UserID:    "user-1",
+		Username:  "alice",
+		RoleIDs:   []string{"role-1"},
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Args:      []string{"minecraft"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("messageInvocation() = %+v, want %+v", got, want)
+	}
+	if got.RequestID == "" {
+		t.Error("messageInvocation() should set the request ID")
+	}
+	if actor := got.Actor(); actor != (audit.Actor{UserID: "user-1", Username: "alice", ChannelID: "channel-1"}) {
+		t.Errorf("Actor() = %+v", actor)
+	}
+}
+
+func TestInteractionInvocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.Interaction
+		want        command.Invocation
+	}{
+		{
+			name: "サーバー内",
+			interaction: &discordgo.Interaction{
+				ChannelID: "channel-1",
+				GuildID:   "guild-1",
+				Member: &discordgo.Member{
+					User:  &discordgo.User{ID: "member-1", Username: "alice"},
+					Roles: []string{"role-1"},
+				},
+			},
+			want: command.Invocation{
+				UserID: "member-1", Username: "alice", RoleIDs: []string{"role-1"},
+				ChannelID: "channel-1", GuildID: "guild-1",
+			},
+		},
+		{
+			name: "DM",
+			interaction: &discordgo.Interaction{
+				ChannelID: "dm-1",
+				User:      &discordgo.User{ID: "user-1", Username: "bob"},
+			},
+			want: command.Invocation{UserID: "user-1", Username: "bob", ChannelID: "dm-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interactionInvocation(&discordgo.InteractionCreate{Interaction: tt.interaction}, nil)
+			tt.want.RequestID = got.RequestID
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("interactionInvocation() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	a, b := newRequestID(), newRequestID()
+	if len(a) != 16 || a == b {
+		t.Errorf("newRequestID() = %q, %q, want distinct 16 character IDs", a, b)
+	}
+}
+
+// fieldsLogger はWithで追加されたフィールドを記録するテスト用のロガー
+type fieldsLogger struct {
+	fields []logging.Field
+}
+
+func (l *fieldsLogger) Debug(context.Context, string, ...logging.Field) {}
+func (l *fieldsLogger) Info(context.Context, string, ...logging.Field)  {}
+func (l *fieldsLogger) Warn(context.Context, string, ...logging.Field)  {}
+func (l *fieldsLogger) Error(context.Context, string, ...logging.Field) {}
+func (l *fieldsLogger) Named(string) logging.Logger                     { return l }
+func (l *fieldsLogger) With(fields ...logging.Field) logging.Logger {
+	return &fieldsLogger{fields: append(append([]logging.Field{}, l.fields...), fields...)}
+}
+
+func TestRouter_requestContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		wantTimeout time.Duration
+	}{
+		{name: "設定したタイムアウト", timeout: 10 * time.Second, wantTimeout: 10 * time.Second},
+		{name: "未設定の場合はデフォルト", wantTimeout: defaultCommandTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logging.WithContext(context.Background(), &fieldsLogger{})
+			cfg := &config.Config{CommandTimeout: tt.timeout}
+			router := NewRouter(ctx, cfg, &system.MockMonitor{}, &docker.MockComposeService{}, nil, nil, nil, nil)
+			inv := &command.Invocation{RequestID: "req-1", UserID: "user-1", ChannelID: "channel-1", GuildID: "guild-1"}
+
+			reqCtx, cancel := router.requestContext(command.NewPingCommand(), inv)
+			defer cancel()
+
+			deadline, ok := reqCtx.Deadline()
+			if remaining := time.Until(deadline); !ok || remaining > tt.wantTimeout || remaining < tt.wantTimeout-time.Second {
+				t.Errorf("deadline in %v, want %v", remaining, tt.wantTimeout)
+			}
+
+			logger, ok := logging.FromContext(reqCtx).(*fieldsLogger)
+			if !ok {
+				t.Fatalf("FromContext() = %T, want the request logger", logging.FromContext(reqCtx))
+			}
+			want := map[string]interface{}{
+				"request_id": "req-1", "command": "ping", "user_id": "user-1",
+				"channel_id": "channel-1", "guild_id": "guild-1",
+			}
+			got := make(map[string]interface{}, len(logger.fields))
+			for _, f := range logger.fields {
+				got[f.Key] = f.Value
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("logger fields = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRouter_interactionContext(t *testing.T) {
+	ctx := logging.WithContext(context.Background(), &fieldsLogger{})
+	cfg := &config.Config{CommandTimeout: 10 * time.Second}
+	router := NewRouter(ctx, cfg, &system.MockMonitor{}, &docker.MockComposeService{}, nil, nil, nil, nil)
+	i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+	}}
+
+	reqCtx, cancel := router.interactionContext(i, "monitor_refresh_0")
+	defer cancel()
+
+	deadline, ok := reqCtx.Deadline()
+	if remaining := time.Until(deadline); !ok || remaining > 10*time.Second || remaining < 9*time.Second {
+		t.Errorf("deadline in %v, want 10s", remaining)
+	}
+
+	logger, ok := logging.FromContext(reqCtx).(*fieldsLogger)
+	if !ok {
+		t.Fatalf("FromContext() = %T, want the interaction logger", logging.FromContext(reqCtx))
+	}
+	got := make(map[string]interface{}, len(logger.fields))
+	for _, f := range logger.fields {
+		got[f.Key] = f.Value
+	}
+	if id, _ := got["request_id"].(string); id == "" {
+		t.Errorf("request_id = %v, want a generated ID", got["request_id"])
+	}
+	delete(got, "request_id")
+	want := map[string]interface{}{
+		"custom_id": "monitor_refresh_0", "user_id": "user-1", "channel_id": "channel-1", "guild_id": "guild-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("logger fields = %v, want %v", got, want)
+	}
+}
@@ -4,13 +4,21 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
 	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/internal/dashboard"
+	"github.com/hideA88/game-server-watchdog/internal/history"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
 	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
 
@@ -23,38 +31,64 @@ type CommandHandler struct {
 type sendMessageFunc func(
 	s *discordgo.Session,
 	m *discordgo.MessageCreate,
-	content string,
-	components []discordgo.MessageComponent,
+	resp *command.Response,
 ) (*discordgo.Message, error)
 
 func sendMessage(
 	s *discordgo.Session,
 	m *discordgo.MessageCreate,
-	content string,
-	components []discordgo.MessageComponent,
+	resp *command.Response,
 ) (*discordgo.Message, error) {
-	if len(components) > 0 {
+	if len(resp.Embeds) > 0 || len(resp.Components) > 0 || len(resp.Files) > 0 {
 		return s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
-			Content:    content,
-			Components: components,
+			Content:    resp.Content,
+			Embeds:     resp.Embeds,
+			Components: resp.Components,
+			Files:      resp.Files,
 		})
 	}
-	return s.ChannelMessageSend(m.ChannelID, content)
+	return s.ChannelMessageSend(m.ChannelID, resp.Content)
+}
+
+// CommandObserver はコマンドの実行回数や実行時間を記録する
+type CommandObserver interface {
+	ObserveCommand(command string, duration time.Duration, err error)
 }
 
 // Router はメッセージをルーティングして適切なコマンドに振り分ける
 type Router struct {
 	ctx                 context.Context
 	config              *config.Config
+	compose             docker.ComposeService
 	commands            map[string]*CommandHandler
 	interactionHandlers []command.InteractionHandler
+	observer            CommandObserver
+	dashboards          *dashboard.Manager
 }
 
 // NewRouter は新しいルーターを作成し、コマンドを登録
-func NewRouter(ctx context.Context, cfg *config.Config, monitor system.Monitor, compose docker.ComposeService) *Router {
+// locksはサービス操作ロックで、バックグラウンド処理と共有する場合に指定する（nilの場合は新規作成）
+// auditLogは操作を記録する監査ログ（nilの場合は記録しない）
+// metricsHistoryはリソース使用率の履歴（nilの場合はhistoryコマンドで無効である旨を表示する）
+// dashboardPublisherはダッシュボードを送信・編集する（nilの場合はdashboardコマンドで無効である旨を表示する）
+func NewRouter(
+	ctx context.Context,
+	cfg *config.Config,
+	monitor system.Monitor,
+	compose docker.ComposeService,
+	locks *oplock.Locker,
+	auditLog audit.Store,
+	metricsHistory history.Reader,
+	dashboardPublisher dashboard.Publisher,
+) *Router {
+	if locks == nil {
+		locks = oplock.New()
+	}
+
 	r := &Router{
 		ctx:                 ctx,
 		config:              cfg,
+		compose:             compose,
 		commands:            make(map[string]*CommandHandler),
 		interactionHandlers: []command.InteractionHandler{},
 	}
@@ -62,11 +96,25 @@ func NewRouter(ctx context.Context, cfg *config.Config, monitor system.Monitor,
 	// コマンドを初期化して登録
 	pingCmd := command.NewPingCommand()
 	helpCmd := command.NewHelpCommand()
-	statusCmd := command.NewStatusCommand(monitor)
-	monitorCmd := command.NewMonitorCommand(ctx, compose, monitor, cfg.DockerComposePath)
-	containerCmd := command.NewContainerCommand(compose, cfg.DockerComposePath)
-	restartCmd := command.NewRestartCommand(compose, cfg.DockerComposePath)
+	statusCmd := command.NewStatusCommand(monitor, cfg)
+	confirmations := command.NewConfirmations(ctx, cfg, cfg.ConfirmTimeout, cfg.EmptyWaitTimeout)
+	prober := gameprobe.NewProber(0)
+	rconRunner := rcon.NewRunner(compose, cfg.DockerComposePath, nil)
+	monitorCmd := command.NewMonitorCommand(
+		ctx, compose, monitor, cfg.DockerComposePath, cfg, locks, confirmations, auditLog, prober)
+	containerCmd := command.NewContainerCommand(compose, cfg.DockerComposePath, cfg)
+	restartCmd := command.NewRestartCommand(compose, cfg.DockerComposePath, locks, confirmations, auditLog, prober,
+		rcon.NewBroadcaster(rconRunner, cfg))
+	rconCmd := command.NewRCONCommand(rconRunner, cfg, auditLog)
 	logsCmd := command.NewLogsCommand(compose, cfg.DockerComposePath)
+	auditCmd := command.NewAuditCommand(auditLog)
+	historyCmd := command.NewHistoryCommand(metricsHistory)
+	graphCmd := command.NewGraphCommand(metricsHistory)
+	if dashboardPublisher != nil {
+		r.dashboards = dashboard.New(monitorCmd, dashboardPublisher, cfg.DashboardPath, cfg.DashboardInterval)
+		monitorCmd.SetDashboards(r.dashboards)
+	}
+	dashboardCmd := command.NewDashboardCommand(ctx, r.dashboards)
 
 	r.RegisterCommand(pingCmd, sendMessage)
 	r.RegisterCommand(helpCmd, sendMessage)
@@ -75,17 +123,31 @@ func NewRouter(ctx context.Context, cfg *config.Config, monitor system.Monitor,
 	r.RegisterCommand(containerCmd, sendMessage)
 	r.RegisterCommand(restartCmd, sendMessage)
 	r.RegisterCommand(logsCmd, sendMessage)
+	r.RegisterCommand(rconCmd, sendMessage)
+	r.RegisterCommand(auditCmd, sendMessage)
+	r.RegisterCommand(historyCmd, sendMessage)
+	r.RegisterCommand(graphCmd, sendMessage)
+	r.RegisterCommand(dashboardCmd, sendMessage)
 
 	// インタラクションハンドラーを登録
 	r.RegisterInteractionHandler(monitorCmd)
+	r.RegisterInteractionHandler(confirmations)
 
 	// helpコマンドに利用可能なコマンドを設定
-	commands := []command.Command{pingCmd, helpCmd, statusCmd, monitorCmd, containerCmd, restartCmd, logsCmd}
+	commands := []command.Command{
+		pingCmd, helpCmd, statusCmd, monitorCmd, containerCmd, restartCmd, logsCmd, rconCmd, auditCmd, historyCmd,
+		graphCmd, dashboardCmd,
+	}
 	helpCmd.SetCommands(commands)
 
 	return r
 }
 
+// Dashboards はダッシュボードの管理を返す（ダッシュボードが無効の場合はnil）
+func (r *Router) Dashboards() *dashboard.Manager {
+	return r.dashboards
+}
+
 // RegisterCommand はコマンドを登録
 func (r *Router) RegisterCommand(cmd command.Command, sendMsgFunc sendMessageFunc) {
 	r.commands[cmd.Name()] = &CommandHandler{
@@ -99,6 +161,11 @@ func (r *Router) RegisterInteractionHandler(handler command.InteractionHandler)
 	r.interactionHandlers = append(r.interactionHandlers, handler)
 }
 
+// SetCommandObserver はコマンドの実行を記録するオブザーバーを設定
+func (r *Router) SetCommandObserver(observer CommandObserver) {
+	r.observer = observer
+}
+
 // ParseCommand はメッセージからメンションを削除してコマンドと引数を抽出
 func ParseCommand(content string, mentions []string) (command string, args []string) {
 	// メンション部分を削除
@@ -133,12 +200,40 @@ func CheckMention(mentions []string, targetUserID string) bool {
 }
 
 // ExecuteCommand はコマンドを実行して結果を返す
+// 埋め込みで返すコマンドの結果はテキストに展開する
 func (r *Router) ExecuteCommand(commandName string, args []string) (string, error) {
+	resp, err := r.executeCommand(commandName, &command.Invocation{RequestID: newRequestID(), Args: args})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text(), nil
+}
+
+// executeCommand は呼び出し情報を指定してコマンドを実行して結果を返す
+func (r *Router) executeCommand(commandName string, inv *command.Invocation) (*command.Response, error) {
 	handler, exists := r.commands[commandName]
 	if !exists {
-		return "", fmt.Errorf("不明なコマンドです。`@ボット help`でコマンド一覧を確認してください。")
+		return nil, fmt.Errorf("不明なコマンドです。`@ボット help`でコマンド一覧を確認してください。")
 	}
-	return handler.Cmd.Execute(args)
+	return r.runCommand(handler.Cmd, inv)
+}
+
+// runCommand はリクエストごとのタイムアウトとロガーを設定したコンテキストでコマンドを実行
+// 実行結果には本文・埋め込みと同じデータから作成した操作ボタンと添付ファイルが含まれる
+// オブザーバーが設定されている場合は実行結果と実行時間を記録する
+func (r *Router) runCommand(cmd command.Command, inv *command.Invocation) (*command.Response, error) {
+	start := time.Now()
+	ctx, cancel := r.requestContext(cmd, inv)
+	defer cancel()
+
+	resp, err := cmd.Execute(ctx, inv)
+	if err != nil {
+		logging.FromContext(ctx).Error(ctx, "コマンド実行エラー", logging.ErrorField(err))
+	}
+	if r.observer != nil {
+		r.observer.ObserveCommand(cmd.Name(), time.Since(start), err)
+	}
+	return resp, err
 }
 
 // Handle はDiscordのメッセージイベントを処理
@@ -175,29 +270,45 @@ func (r *Router) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
 		return
 	}
 
+	// 権限チェック
+	if handler, exists := r.commands[command]; exists {
+		req := handler.Cmd.RequiredPermission(args)
+		if !permission.Allowed(r.config, messageUser(m), req) {
+			logger.Warn(r.ctx, "Permission denied",
+				logging.String("user_id", m.Author.ID),
+				logging.String("command", command),
+				logging.String("required", req.Level.String()),
+				logging.String("service", req.Service))
+			_, _ = s.ChannelMessageSend(m.ChannelID, permissionDeniedMessage(req))
+			return
+		}
+	}
+
 	// コマンドを実行
-	result, err := r.ExecuteCommand(command, args)
+	resp, err := r.executeCommand(command, messageInvocation(m, args))
 	if err != nil {
-		logger.Error(r.ctx, "コマンド実行エラー", logging.ErrorField(err))
 		_, _ = s.ChannelMessageSend(m.ChannelID, err.Error())
 		return
 	}
 
 	// 結果を送信
 	if handler, exists := r.commands[command]; exists {
-		// インタラクティブコマンドの場合はコンポーネントも送信
-		var components []discordgo.MessageComponent
-		if interactiveCmd, ok := handler.Cmd.(interface {
-			GetComponents(args []string) ([]discordgo.MessageComponent, error)
-		}); ok {
-			if comps, err := interactiveCmd.GetComponents(args); err == nil {
-				components = comps
-			}
-		}
-
-		if _, err := handler.SendMsgFunc(s, m, result, components); err != nil {
+		msg, err := handler.SendMsgFunc(s, m, resp)
+		if err != nil {
 			logger.Error(r.ctx, "メッセージの送信に失敗しました", logging.ErrorField(err))
 			_, _ = s.ChannelMessageSend(m.ChannelID, "メッセージの送信中にエラーが発生しました。")
+			return
+		}
+		if resp.Sent != nil && msg != nil {
+			resp.Sent(func(content string) error {
+				_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+					ID:         msg.ID,
+					Channel:    msg.ChannelID,
+					Content:    &content,
+					Components: &[]discordgo.MessageComponent{},
+				})
+				return err
+			})
 		}
 	}
 }
@@ -206,26 +317,34 @@ func (r *Router) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
 func (r *Router) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	logger := logging.FromContext(r.ctx)
 
-	// メッセージコンポーネントのインタラクションのみ処理
-	if i.Type != discordgo.InteractionMessageComponent {
+	// 対応するインタラクションのみ処理
+	switch i.Type {
+	case discordgo.InteractionMessageComponent,
+		discordgo.InteractionApplicationCommand,
+		discordgo.InteractionApplicationCommandAutocomplete:
+	default:
 		return
 	}
 
 	// アクセス権限チェック
-	if !IsAuthorized(r.config, i.ChannelID, i.Member.User.ID) {
+	userID := interactionUserID(i)
+	if !IsAuthorized(r.config, i.ChannelID, userID) {
 		logger.Warn(r.ctx, "Unauthorized interaction",
-			logging.String("user_id", i.Member.User.ID),
+			logging.String("user_id", userID),
 			logging.String("channel_id", i.ChannelID))
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "このアクションを実行する権限がありません。",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		if err != nil {
-			logger.Error(r.ctx, "Failed to respond to unauthorized interaction", logging.ErrorField(err))
+		if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+			return
 		}
+		r.respondEphemeral(s, i, "このアクションを実行する権限がありません。")
+		return
+	}
+
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		r.handleSlashCommand(s, i)
+		return
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		r.handleAutocomplete(s, i)
 		return
 	}
 
@@ -234,16 +353,21 @@ func (r *Router) HandleInteraction(s *discordgo.Session, i *discordgo.Interactio
 	// 登録されたハンドラーから適切なものを探す
 	for _, handler := range r.interactionHandlers {
 		if handler.CanHandle(data.CustomID) {
-			if err := handler.HandleInteraction(s, i); err != nil {
-				logger.Error(r.ctx, "Failed to handle interaction", logging.ErrorField(err))
+			req := handler.RequiredInteractionPermission(data.CustomID)
+			if !permission.Allowed(r.config, interactionUser(i), req) {
+				logger.Warn(r.ctx, "Permission denied for interaction",
+					logging.String("user_id", userID),
+					logging.String("custom_id", data.CustomID),
+					logging.String("required", req.Level.String()))
+				r.respondEphemeral(s, i, permissionDeniedMessage(req))
+				return
+			}
+			ctx, cancel := r.interactionContext(i, data.CustomID)
+			defer cancel()
+			if err := handler.HandleInteraction(ctx, s, i); err != nil {
+				logging.FromContext(ctx).Error(ctx, "Failed to handle interaction", logging.ErrorField(err))
 				// エラー応答を試みる
-				_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Content: "処理中にエラーが発生しました。",
-						Flags:   discordgo.MessageFlagsEphemeral,
-					},
-				})
+				r.respondEphemeral(s, i, "処理中にエラーが発生しました。")
 			}
 			return
 		}
@@ -253,3 +377,15 @@ func (r *Router) HandleInteraction(s *discordgo.Session, i *discordgo.Interactio
 	logger.Warn(r.ctx, "Unknown interaction custom ID",
 		logging.String("custom_id", data.CustomID))
 }
+
+// interactionUserID はインタラクションを実行したユーザーのIDを返す
+// サーバー内ではMember、DMではUserに設定される
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
@@ -2,11 +2,16 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
 	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/internal/dashboard"
 	"github.com/hideA88/game-server-watchdog/pkg/docker"
 	"github.com/hideA88/game-server-watchdog/pkg/system"
 )
@@ -26,9 +31,9 @@ func TestNewRouter(t *testing.T) {
 				AllowedChannelIDs: []string{},
 				AllowedUserIDs:    []string{},
 			},
-			wantCommands:            []string{"ping", "help", "status", "monitor", "container", "restart", "logs"},
-			wantCommandCount:        7,
-			wantInteractionHandlers: 1,
+			wantCommands:            []string{"ping", "help", "status", "monitor", "container", "restart", "logs", "rcon", "audit", "history", "graph", "dashboard"},
+			wantCommandCount:        12,
+			wantInteractionHandlers: 2,
 		},
 	}
 
@@ -37,7 +42,7 @@ func TestNewRouter(t *testing.T) {
 			mockMonitor := &system.MockMonitor{}
 			mockCompose := &docker.MockComposeService{}
 			ctx := context.Background()
-			router := NewRouter(ctx, tt.config, mockMonitor, mockCompose)
+			router := NewRouter(ctx, tt.config, mockMonitor, mockCompose, nil, nil, nil, nil)
 
 			// ルーターが正しく初期化されているか確認
 			if router == nil {
@@ -68,6 +73,34 @@ func TestNewRouter(t *testing.T) {
 	}
 }
 
+func TestNewRouter_Dashboards(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		publisher dashboard.Publisher
+		wantNil   bool
+	}{
+		{name: "送信先がある場合はダッシュボードを有効にする", publisher: &dashboard.MockPublisher{}},
+		{name: "送信先がない場合は無効", wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{DashboardInterval: 30 * time.Second}
+			router := NewRouter(context.Background(), cfg, &system.MockMonitor{}, &docker.MockComposeService{},
+				nil, nil, nil, tt.publisher)
+
+			got := router.Dashboards()
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("Dashboards() = %v, want nil %v", got, tt.wantNil)
+			}
+			if got != nil && got.Interval() != cfg.DashboardInterval {
+				t.Errorf("Interval() = %v, want %v", got.Interval(), cfg.DashboardInterval)
+			}
+		})
+	}
+}
+
 func TestRouter_RegisterCommand(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -288,7 +321,7 @@ func TestRouter_ExecuteCommand(t *testing.T) {
 			}
 			mockCompose := &docker.MockComposeService{}
 			ctx := context.Background()
-			router := NewRouter(ctx, &config.Config{}, mockMonitor, mockCompose)
+			router := NewRouter(ctx, &config.Config{}, mockMonitor, mockCompose, nil, nil, nil, nil)
 
 			gotResult, err := router.ExecuteCommand(tt.commandName, tt.args)
 
@@ -307,3 +340,100 @@ func TestRouter_ExecuteCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_executeCommand_RecordsActor(t *testing.T) {
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{Service: "minecraft", State: "running"}}, nil
+		},
+	}
+	store := &audit.MockStore{}
+	router := NewRouter(context.Background(), &config.Config{}, &system.MockMonitor{}, mockCompose, nil, store, nil, nil)
+
+	inv := &command.Invocation{UserID: "user-1", Username: "alice", ChannelID: "channel-1", Args: []string{"minecraft"}}
+	if _, err := router.executeCommand("restart", inv); err != nil {
+		t.Fatalf("executeCommand() error = %v", err)
+	}
+
+	entries := store.Entries()
+	if len(entries) != 1 || entries[0].Actor != inv.Actor() || entries[0].Action != audit.ActionRestart {
+		t.Errorf("audit entries = %+v", entries)
+	}
+}
+
+// observedCommand はオブザーバーに記録されたコマンドの実行
+type observedCommand struct {
+	name string
+	err  error
+}
+
+// recordingObserver はテスト用のCommandObserver
+type recordingObserver struct {
+	observed []observedCommand
+}
+
+func (o *recordingObserver) ObserveCommand(name string, _ time.Duration, err error) {
+	o.observed = append(o.observed, observedCommand{name: name, err: err})
+}
+
+func TestRouter_SetCommandObserver(t *testing.T) {
+	mockCompose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return nil, errors.New("docker unavailable")
+		},
+	}
+	router := NewRouter(context.Background(), &config.Config{}, &system.MockMonitor{}, mockCompose, nil, nil, nil, nil)
+	observer := &recordingObserver{}
+	router.SetCommandObserver(observer)
+
+	if _, err := router.ExecuteCommand("ping", nil); err != nil {
+		t.Fatalf("ExecuteCommand(ping) error = %v", err)
+	}
+	if _, err := router.ExecuteCommand("restart", []string{"minecraft"}); err == nil {
+		t.Fatal("ExecuteCommand(restart) expected error")
+	}
+	// 不明なコマンドは記録しない
+	_, _ = router.ExecuteCommand("unknown", nil)
+
+	if len(observer.observed) != 2 {
+		t.Fatalf("observed = %+v, want 2 commands", observer.observed)
+	}
+	if got := observer.observed[0]; got.name != "ping" || got.err != nil {
+		t.Errorf("observed[0] = %+v, want ping without error", got)
+	}
+	if got := observer.observed[1]; got.name != "restart" || got.err == nil {
+		t.Errorf("observed[1] = %+v, want restart with error", got)
+	}
+}
+
+func TestRouter_runCommand_Embed(t *testing.T) {
+	monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{CPUUsagePercent: 25.5}}
+	router := NewRouter(context.Background(), &config.Config{}, monitor, &docker.MockComposeService{}, nil, nil, nil, nil)
+
+	// 埋め込みで返すコマンドは埋め込みで実行する
+	resp, err := router.executeCommand("status", &command.Invocation{})
+	if err != nil {
+		t.Fatalf("executeCommand(status) error = %v", err)
+	}
+	if len(resp.Embeds) != 1 || resp.Content != "" {
+		t.Errorf("executeCommand(status) = %+v, want a single embed", resp)
+	}
+
+	// それ以外のコマンドは本文で返す
+	resp, err = router.executeCommand("ping", &command.Invocation{})
+	if err != nil {
+		t.Fatalf("executeCommand(ping) error = %v", err)
+	}
+	if resp.Content != "pong!!" || len(resp.Embeds) != 0 {
+		t.Errorf("executeCommand(ping) = %+v, want text", resp)
+	}
+
+	// ExecuteCommandは埋め込みをテキストに展開する
+	text, err := router.ExecuteCommand("status", nil)
+	if err != nil {
+		t.Fatalf("ExecuteCommand(status) error = %v", err)
+	}
+	if !strings.Contains(text, "サーバーステータス") || !strings.Contains(text, "25.5%") {
+		t.Errorf("ExecuteCommand(status) = %q, want the status as text", text)
+	}
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/internal/permission"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// maxAutocompleteChoices はオートコンプリートで返せる候補の最大数
+	maxAutocompleteChoices = 25
+	// maxSlashDescriptionLen はスラッシュコマンドの説明の最大文字数
+	maxSlashDescriptionLen = 100
+)
+
+// ApplicationCommands は登録済みのコマンドからスラッシュコマンドの定義を作成
+func (r *Router) ApplicationCommands() []*discordgo.ApplicationCommand {
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	appCommands := make([]*discordgo.ApplicationCommand, 0, len(names))
+	for _, name := range names {
+		cmd := r.commands[name].Cmd
+		appCmd := &discordgo.ApplicationCommand{
+			Name:        cmd.Name(),
+			Description: truncateRunes(cmd.Description(), maxSlashDescriptionLen),
+		}
+		if slashCmd, ok := cmd.(command.SlashCommand); ok {
+			appCmd.Options = slashCmd.Options()
+		}
+		appCommands = append(appCommands, appCmd)
+	}
+	return appCommands
+}
+
+// slashCommandArgs はスラッシュコマンドのオプションをExecuteに渡す引数に変換
+// オプションの定義順に並べ、指定されていない省略可能なオプションは詰め、必須のオプションが欠けている場合はそれ以降を渡さない
+func slashCommandArgs(cmd command.Command, options []*discordgo.ApplicationCommandInteractionDataOption) []string {
+	slashCmd, ok := cmd.(command.SlashCommand)
+	if !ok {
+		return nil
+	}
+
+	provided := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		provided[opt.Name] = opt
+	}
+
+	var args []string
+	for _, def := range slashCmd.Options() {
+		opt, ok := provided[def.Name]
+		if !ok {
+			if def.Required {
+				break
+			}
+			continue
+		}
+		args = append(args, optionValueString(opt))
+	}
+	return args
+}
+
+// optionValueString はオプションの値を文字列に変換
+func optionValueString(opt *discordgo.ApplicationCommandInteractionDataOption) string {
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionInteger:
+		return strconv.FormatInt(opt.IntValue(), 10)
+	case discordgo.ApplicationCommandOptionBoolean:
+		return strconv.FormatBool(opt.BoolValue())
+	case discordgo.ApplicationCommandOptionString:
+		return opt.StringValue()
+	default:
+		if s, ok := opt.Value.(string); ok {
+			return s
+		}
+		return ""
+	}
+}
+
+// serviceChoices はコンテナ一覧から入力中の文字列に一致するサービス名の候補を作成
+func serviceChoices(containers []docker.ContainerInfo, prefix string) []*discordgo.ApplicationCommandOptionChoice {
+	prefix = strings.ToLower(prefix)
+	seen := make(map[string]bool, len(containers))
+	var services []string
+	for i := range containers {
+		service := containers[i].Service
+		if service == "" || seen[service] || !strings.HasPrefix(strings.ToLower(service), prefix) {
+			continue
+		}
+		seen[service] = true
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	if len(services) > maxAutocompleteChoices {
+		services = services[:maxAutocompleteChoices]
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(services))
+	for _, service := range services {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: service, Value: service})
+	}
+	return choices
+}
+
+// focusedOption はオートコンプリート対象のオプションを返す
+func focusedOption(
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range options {
+		if opt.Focused {
+			return opt
+		}
+	}
+	return nil
+}
+
+// handleSlashCommand はスラッシュコマンドを実行して結果を返信
+func (r *Router) handleSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger := logging.FromContext(r.ctx)
+	data := i.ApplicationCommandData()
+
+	handler, exists := r.commands[data.Name]
+	if !exists {
+		logger.Warn(r.ctx, "Unknown slash command", logging.String("command", data.Name))
+		r.respondEphemeral(s, i, "不明なコマンドです。`/help`でコマンド一覧を確認してください。")
+		return
+	}
+
+	args := slashCommandArgs(handler.Cmd, data.Options)
+	if req := handler.Cmd.RequiredPermission(args); !permission.Allowed(r.config, interactionUser(i), req) {
+		logger.Warn(r.ctx, "Permission denied for slash command",
+			logging.String("user_id", interactionUserID(i)),
+			logging.String("command", data.Name),
+			logging.String("required", req.Level.String()),
+			logging.String("service", req.Service))
+		r.respondEphemeral(s, i, permissionDeniedMessage(req))
+		return
+	}
+
+	// 実行に時間がかかるコマンドがあるため、先に応答を保留する（3秒以内）
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		logger.Error(r.ctx, "Failed to send defer response", logging.ErrorField(err))
+		return
+	}
+
+	resp, err := r.runCommand(handler.Cmd, interactionInvocation(i, args))
+	if err != nil {
+		resp = command.TextResponse(err.Error())
+	}
+	if resp.Ephemeral {
+		r.followupEphemeral(s, i, resp)
+		return
+	}
+
+	edit := &discordgo.WebhookEdit{Content: &resp.Content}
+	if len(resp.Embeds) > 0 {
+		edit.Embeds = &resp.Embeds
+	}
+	if len(resp.Components) > 0 {
+		edit.Components = &resp.Components
+	}
+	edit.Files = resp.Files
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+		logger.Error(r.ctx, "メッセージの送信に失敗しました", logging.ErrorField(err))
+		return
+	}
+	if resp.Sent != nil {
+		resp.Sent(func(content string) error {
+			_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content:    &content,
+				Components: &[]discordgo.MessageComponent{},
+			})
+			return err
+		})
+	}
+}
+
+// followupEphemeral は保留した応答を削除し、実行結果を実行者のみに見えるメッセージで送信
+// 保留中の応答へのフォローアップは保留した応答の表示範囲を引き継ぐため、先に削除する
+func (r *Router) followupEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, resp *command.Response) {
+	logger := logging.FromContext(r.ctx)
+	if err := s.InteractionResponseDelete(i.Interaction); err != nil {
+		logger.Warn(r.ctx, "Failed to delete deferred response", logging.ErrorField(err))
+	}
+
+	msg, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content:    resp.Content,
+		Embeds:     resp.Embeds,
+		Components: resp.Components,
+		Files:      resp.Files,
+		Flags:      discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		logger.Error(r.ctx, "メッセージの送信に失敗しました", logging.ErrorField(err))
+		return
+	}
+	if resp.Sent != nil {
+		resp.Sent(func(content string) error {
+			_, err := s.FollowupMessageEdit(i.Interaction, msg.ID, &discordgo.WebhookEdit{
+				Content:    &content,
+				Components: &[]discordgo.MessageComponent{},
+			})
+			return err
+		})
+	}
+}
+
+// handleAutocomplete はスラッシュコマンドのオプションの候補を返信
+func (r *Router) handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger := logging.FromContext(r.ctx)
+	data := i.ApplicationCommandData()
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	if opt := focusedOption(data.Options); opt != nil && opt.Name == command.OptionService {
+		containers, err := r.compose.ListContainers(r.ctx, r.config.DockerComposePath)
+		if err != nil {
+			logger.Warn(r.ctx, "Failed to list containers for autocomplete", logging.ErrorField(err))
+		} else {
+			choices = serviceChoices(containers, opt.StringValue())
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+	if err != nil {
+		logger.Error(r.ctx, "Failed to respond to autocomplete", logging.ErrorField(err))
+	}
+}
+
+// respondEphemeral は実行者のみに見えるメッセージで応答
+func (r *Router) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logging.FromContext(r.ctx).Error(r.ctx, "Failed to send ephemeral response", logging.ErrorField(err))
+	}
+}
+
+// truncateRunes は文字数が上限を超える場合に切り詰める
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/command"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+func TestRouter_ApplicationCommands(t *testing.T) {
+	router := NewRouter(context.Background(), &config.Config{}, &system.MockMonitor{}, &docker.MockComposeService{}, nil, nil, nil, nil)
+
+	appCommands := router.ApplicationCommands()
+
+	var names []string
+	byName := make(map[string]*discordgo.ApplicationCommand)
+	for _, cmd := range appCommands {
+		names = append(names, cmd.Name)
+		byName[cmd.Name] = cmd
+		if cmd.Description == "" || len([]rune(cmd.Description)) > maxSlashDescriptionLen {
+			t.Errorf("command %q has invalid description %q", cmd.Name, cmd.Description)
+		}
+	}
+
+	wantNames := []string{"audit", "container", "dashboard", "graph", "help", "history", "logs", "monitor", "ping", "rcon", "restart", "status"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("ApplicationCommands() names = %v, want %v", names, wantNames)
+	}
+
+	if got := len(byName["logs"].Options); got != 2 {
+		t.Errorf("logs options = %d, want 2", got)
+	}
+	if got := len(byName["ping"].Options); got != 0 {
+		t.Errorf("ping options = %d, want 0", got)
+	}
+}
+
+func TestSlashCommandArgs(t *testing.T) {
+	logsCmd := command.NewLogsCommand(&docker.MockComposeService{}, "")
+	auditCmd := command.NewAuditCommand(nil)
+
+	stringOpt := func(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{
+			Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value,
+		}
+	}
+	// DiscordのJSONでは整数もfloat64としてデコードされる
+	intOpt := func(name string, value float64) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{
+			Name: name, Type: discordgo.ApplicationCommandOptionInteger, Value: value,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cmd     command.Command
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		want    []string
+	}{
+		{
+			name:    "定義順に並べ替える",
+			cmd:     logsCmd,
+			options: []*discordgo.ApplicationCommandInteractionDataOption{intOpt("lines", 100), stringOpt("service", "minecraft")},
+			want:    []string{"minecraft", "100"},
+		},
+		{
+			name:    "省略可能なオプションなし",
+			cmd:     logsCmd,
+			options: []*discordgo.ApplicationCommandInteractionDataOption{stringOpt("service", "minecraft")},
+			want:    []string{"minecraft"},
+		},
+		{
+			name:    "途中のオプションが欠けている場合は以降を渡さない",
+			cmd:     logsCmd,
+			options: []*discordgo.ApplicationCommandInteractionDataOption{intOpt("lines", 100)},
+			want:    nil,
+		},
+		{
+			name:    "省略可能なオプションが欠けている場合は詰めて渡す",
+			cmd:     auditCmd,
+			options: []*discordgo.ApplicationCommandInteractionDataOption{intOpt("count", 30)},
+			want:    []string{"30"},
+		},
+		{
+			name:    "オプションを持たないコマンド",
+			cmd:     command.NewPingCommand(),
+			options: []*discordgo.ApplicationCommandInteractionDataOption{stringOpt("service", "minecraft")},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slashCommandArgs(tt.cmd, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("slashCommandArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlashCommandArgs_AuditCountWithoutService(t *testing.T) {
+	auditCmd := command.NewAuditCommand(&audit.MockStore{})
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: command.OptionCount, Type: discordgo.ApplicationCommandOptionInteger, Value: float64(30)},
+	}
+
+	// /audit count:30 はサービスを指定しなくても件数を反映する
+	resp, err := auditCmd.Execute(context.Background(), &command.Invocation{Args: slashCommandArgs(auditCmd, options)})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(resp.Content, "📜 **操作履歴**（直近30件）") {
+		t.Errorf("Execute() = %q, want the requested count for all services", resp.Content)
+	}
+}
+
+func TestServiceChoices(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		{Service: "minecraft"},
+		{Service: "terraria"},
+		{Service: "minecraft-creative"},
+		{Service: "minecraft"},
+		{Service: ""},
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{name: "入力なし", prefix: "", want: []string{"minecraft", "minecraft-creative", "terraria"}},
+		{name: "前方一致", prefix: "Mine", want: []string{"minecraft", "minecraft-creative"}},
+		{name: "一致なし", prefix: "rust", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := serviceChoices(containers, tt.prefix)
+			got := make([]string, 0, len(choices))
+			for _, c := range choices {
+				got = append(got, c.Name)
+				if c.Value != c.Name {
+					t.Errorf("choice value = %v, want %v", c.Value, c.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serviceChoices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceChoices_Limit(t *testing.T) {
+	var containers []docker.ContainerInfo
+	for i := 0; i < maxAutocompleteChoices+10; i++ {
+		containers = append(containers, docker.ContainerInfo{Service: fmt.Sprintf("server%02d", i)})
+	}
+
+	if got := len(serviceChoices(containers, "")); got != maxAutocompleteChoices {
+		t.Errorf("serviceChoices() returned %d choices, want %d", got, maxAutocompleteChoices)
+	}
+}
+
+func TestInteractionUserID(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.Interaction
+		want        string
+	}{
+		{
+			name:        "サーバー内",
+			interaction: &discordgo.Interaction{Member: &discordgo.Member{User: &discordgo.User{ID: "member-1"}}},
+			want:        "member-1",
+		},
+		{
+			name:        "DM",
+			interaction: &discordgo.Interaction{User: &discordgo.User{ID: "user-1"}},
+			want:        "user-1",
+		},
+		{
+			name:        "ユーザー情報なし",
+			interaction: &discordgo.Interaction{},
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interactionUserID(&discordgo.InteractionCreate{Interaction: tt.interaction}); got != tt.want {
+				t.Errorf("interactionUserID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		input string
+		limit int
+		want  string
+	}{
+		{input: "短い説明", limit: 10, want: "短い説明"},
+		{input: "システムとゲームサーバー", limit: 4, want: "システム"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := truncateRunes(tt.input, tt.limit); got != tt.want {
+				t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.input, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
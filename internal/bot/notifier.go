@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+)
+
+// channelNotifier は指定されたDiscordチャンネルへ通知を送信する
+type channelNotifier struct {
+	session   *discordgo.Session
+	channelID string
+}
+
+// Notify はチャンネルにメッセージを送信する
+func (n *channelNotifier) Notify(ctx context.Context, content string) error {
+	if _, err := n.session.ChannelMessageSend(n.channelID, content, discordgo.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to send notification to channel %s: %w", n.channelID, err)
+	}
+	return nil
+}
+
+// NotifyWithActions はチャンネルにボタン付きのメッセージを送信する
+func (n *channelNotifier) NotifyWithActions(ctx context.Context, content string, actions []notify.Action) error {
+	buttons := make([]discordgo.MessageComponent, 0, len(actions))
+	for _, action := range actions {
+		buttons = append(buttons, discordgo.Button{
+			Label:    action.Label,
+			Style:    discordgo.SuccessButton,
+			CustomID: action.CustomID,
+		})
+	}
+	message := &discordgo.MessageSend{
+		Content:    content,
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}},
+	}
+	if _, err := n.session.ChannelMessageSendComplex(n.channelID, message, discordgo.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to send notification to channel %s: %w", n.channelID, err)
+	}
+	return nil
+}
+
+// Notifier は指定されたチャンネルへ通知を送信するNotifierを返します
+func (b *Bot) Notifier(channelID string) notify.Notifier {
+	return &channelNotifier{
+		session:   b.session,
+		channelID: channelID,
+	}
+}
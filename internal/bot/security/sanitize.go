@@ -78,6 +78,9 @@ func SanitizeForDiscord(message string) string {
 	sanitized = strings.ReplaceAll(sanitized, "_", "\\_")
 	sanitized = strings.ReplaceAll(sanitized, "~", "\\~")
 
+	// @everyone・@here・ユーザーやロールへのメンションで通知が飛ばないよう、@の後にゼロ幅スペースを挟む
+	sanitized = strings.ReplaceAll(sanitized, "@", "@\u200b")
+
 	return sanitized
 }
 
@@ -119,6 +119,11 @@ func TestSanitizeForDiscord(t *testing.T) {
 			input:    "Discord token: `abc123def456ghi789jkl012mno345pqr678`",
 			expected: "Discord token: \\`[REDACTED]\\`",
 		},
+		{
+			name:     "メンションの無効化",
+			input:    "@everyone @here <@123> <@&456>",
+			expected: "@\u200beveryone @\u200bhere <@\u200b123> <@\u200b&456>",
+		},
 		{
 			name:     "特殊文字なし",
 			input:    "Normal message",
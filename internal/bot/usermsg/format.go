@@ -0,0 +1,57 @@
+package usermsg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatServiceName はサービス名を表示用にフォーマットする。
+// ハイフンとアンダースコアを空白に置き換え、各単語の先頭を大文字にする。
+func FormatServiceName(service string) string {
+	if service == "" {
+		return ""
+	}
+
+	// Replace hyphens and underscores with spaces
+	formatted := strings.ReplaceAll(service, "-", " ")
+	formatted = strings.ReplaceAll(formatted, "_", " ")
+
+	// Split into words and capitalize each
+	words := strings.Fields(formatted)
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// FormatBytes はバイト数を表示用にフォーマットする（例: "1.5MB"）
+func FormatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatGiB はバイト数をGiB単位でフォーマットする（例: "1.50GiB"）
+func FormatGiB(bytes uint64) string {
+	return fmt.Sprintf("%.2fGiB", float64(bytes)/1024/1024/1024)
+}
+
+// FormatMemoryUsage はメモリ使用量を「使用量 / 上限」の形式でフォーマットする
+func FormatMemoryUsage(used, limit uint64) string {
+	return FormatGiB(used) + " / " + FormatGiB(limit)
+}
+
+// FormatIO は受信・送信などの2つのバイト数を「a / b」の形式でフォーマットする
+func FormatIO(a, b uint64) string {
+	return FormatBytes(a) + " / " + FormatBytes(b)
+}
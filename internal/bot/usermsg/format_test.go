@@ -0,0 +1,136 @@
+package usermsg
+
+import "testing"
+
+func TestFormatServiceName(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		want    string
+	}{
+		{name: "空文字", service: "", want: ""},
+		{name: "単語", service: "minecraft", want: "Minecraft"},
+		{name: "ハイフン区切り", service: "ark-island", want: "Ark Island"},
+		{name: "アンダースコア区切り", service: "game_SERVER", want: "Game Server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatServiceName(tt.service); got != tt.want {
+				t.Errorf("FormatServiceName(%q) = %q, want %q", tt.service, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    uint64
+		expected string
+	}{
+		{
+			name:     "バイト単位",
+			bytes:    512,
+			expected: "512B",
+		},
+		{
+			name:     "キロバイト単位",
+			bytes:    1536, // 1.5KB
+			expected: "1.5KB",
+		},
+		{
+			name:     "メガバイト単位",
+			bytes:    2097152, // 2MB
+			expected: "2.0MB",
+		},
+		{
+			name:     "ギガバイト単位",
+			bytes:    3221225472, // 3GB
+			expected: "3.0GB",
+		},
+		{
+			name:     "テラバイト単位",
+			bytes:    1099511627776, // 1TB
+			expected: "1.0TB",
+		},
+		{
+			name:     "0バイト",
+			bytes:    0,
+			expected: "0B",
+		},
+		{
+			name:     "1バイト",
+			bytes:    1,
+			expected: "1B",
+		},
+		{
+			name:     "1023バイト（1KB未満）",
+			bytes:    1023,
+			expected: "1023B",
+		},
+		{
+			name:     "1024バイト（ちょうど1KB）",
+			bytes:    1024,
+			expected: "1.0KB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatBytes(tt.bytes)
+			if result != tt.expected {
+				t.Errorf("FormatBytes() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatMemoryUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		used  uint64
+		limit uint64
+		want  string
+	}{
+		{name: "正常なメモリ使用量", used: 536870912, limit: 1073741824, want: "0.50GiB / 1.00GiB"},
+		{name: "大きなメモリ使用量", used: 8589934592, limit: 17179869184, want: "8.00GiB / 16.00GiB"},
+		{name: "0バイト", used: 0, limit: 0, want: "0.00GiB / 0.00GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatMemoryUsage(tt.used, tt.limit); got != tt.want {
+				t.Errorf("FormatMemoryUsage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatIO(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b uint64
+		want string
+	}{
+		{name: "正常なI/O", a: 1560576, b: 3121152, want: "1.5MB / 3.0MB"},
+		{name: "I/Oなし", a: 0, b: 0, want: "0B / 0B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatIO(tt.a, tt.b); got != tt.want {
+				t.Errorf("FormatIO() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkFormatBytes(b *testing.B) {
+	bytes := uint64(1073741824) // 1GB
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FormatBytes(bytes)
+	}
+}
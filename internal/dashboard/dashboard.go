@@ -0,0 +1,347 @@
+// Package dashboard はチャンネルごとに1件の監視ダッシュボードメッセージを定期的に更新する機能を提供します
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// DefaultInterval はデフォルトの更新間隔
+	DefaultInterval = time.Minute
+
+	// messageLimit はDiscordメッセージの最大文字数
+	messageLimit = 2000
+	// finalEditTimeout は停止時に最後の編集を行う時間の上限
+	finalEditTimeout = 5 * time.Second
+
+	// stoppedNote は自動更新を停止したメッセージに追記する文言
+	stoppedNote = "⏹️ このダッシュボードの自動更新は停止しました。"
+	// pausedNote はウォッチドッグの停止中にメッセージに追記する文言
+	pausedNote = "⏸️ ウォッチドッグの停止中は更新されません（再起動すると自動更新を再開します）。"
+)
+
+// ErrMessageNotFound はダッシュボードのメッセージ（またはチャンネル）が削除されている場合のエラー
+var ErrMessageNotFound = errors.New("dashboard message not found")
+
+// Renderer はダッシュボードの本文と操作ボタンを生成する
+type Renderer interface {
+	// Render は指定したページ（0始まり）の本文と操作ボタンを生成する
+	Render(ctx context.Context, page int) (string, []discordgo.MessageComponent, error)
+}
+
+// Publisher はダッシュボードのメッセージを送信・編集する
+type Publisher interface {
+	// Send はメッセージを送信し、送信したメッセージのIDを返す
+	Send(ctx context.Context, channelID, content string, components []discordgo.MessageComponent) (string, error)
+	// Edit はメッセージを編集する（componentsが空の場合はボタンを削除する）
+	// メッセージが削除されている場合はErrMessageNotFoundを返す
+	Edit(ctx context.Context, channelID, messageID, content string, components []discordgo.MessageComponent) error
+}
+
+// Manager はチャンネルごとのダッシュボードメッセージを管理し、定期的に更新する
+type Manager struct {
+	renderer  Renderer
+	publisher Publisher
+	path      string
+	interval  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	messages map[string]string // チャンネルIDごとのメッセージID
+	pages    map[string]int    // チャンネルIDごとの表示中のページ（0始まり、ない場合は最初のページ）
+	last     map[int]string    // ページごとに最後に生成した本文
+}
+
+// New は新しいManagerを作成する
+// pathはメッセージIDを保存するファイルで、空の場合は再起動するとダッシュボードの更新を再開しない
+// intervalが0以下の場合はDefaultIntervalを使用する
+func New(renderer Renderer, publisher Publisher, path string, interval time.Duration) *Manager {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Manager{
+		renderer:  renderer,
+		publisher: publisher,
+		path:      path,
+		interval:  interval,
+		now:       time.Now,
+		messages:  make(map[string]string),
+		pages:     make(map[string]int),
+		last:      make(map[int]string),
+	}
+}
+
+// Interval は更新間隔を返す
+func (m *Manager) Interval() time.Duration {
+	return m.interval
+}
+
+// Channels はダッシュボードを表示しているチャンネルの一覧を返す
+func (m *Manager) Channels() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	channels := make([]string, 0, len(m.messages))
+	for channelID := range m.messages {
+		channels = append(channels, channelID)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Start はチャンネルにダッシュボードを送信し、定期的な更新の対象にする
+// すでにダッシュボードがある場合は古いメッセージの更新を停止し、新しいメッセージに置き換える
+func (m *Manager) Start(ctx context.Context, channelID string) error {
+	body, components, err := m.render(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	messageID, err := m.publisher.Send(ctx, channelID, m.withFooter(body), components)
+	if err != nil {
+		return fmt.Errorf("failed to send dashboard: %w", err)
+	}
+
+	m.mu.Lock()
+	old, oldPage := m.messages[channelID], m.pages[channelID]
+	m.messages[channelID] = messageID
+	delete(m.pages, channelID)
+	m.mu.Unlock()
+
+	if old != "" {
+		m.finish(ctx, channelID, old, oldPage, stoppedNote)
+	}
+	m.save(ctx)
+	return nil
+}
+
+// Stop はチャンネルのダッシュボードの更新を停止する
+// ダッシュボードがない場合はfalseを返す
+func (m *Manager) Stop(ctx context.Context, channelID string) bool {
+	m.mu.Lock()
+	messageID, ok := m.messages[channelID]
+	page := m.pages[channelID]
+	delete(m.messages, channelID)
+	delete(m.pages, channelID)
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	m.finish(ctx, channelID, messageID, page, stoppedNote)
+	m.save(ctx)
+	return true
+}
+
+// SetPage はダッシュボードのメッセージで表示するページを切り替えた場合に呼び、以降の更新でもそのページを表示する
+// messageIDがチャンネルのダッシュボードでない場合は何もしない
+func (m *Manager) SetPage(ctx context.Context, channelID, messageID string, page int) {
+	m.mu.Lock()
+	if m.messages[channelID] != messageID || m.pages[channelID] == page {
+		m.mu.Unlock()
+		return
+	}
+	if page > 0 {
+		m.pages[channelID] = page
+	} else {
+		delete(m.pages, channelID)
+	}
+	m.mu.Unlock()
+	m.save(ctx)
+}
+
+// Run はコンテキストがキャンセルされるまで定期的にダッシュボードを更新する
+// ファイルが指定されている場合は開始時にメッセージIDを読み込み、前回のダッシュボードの更新を再開する
+// 停止時はボタンを削除して停止中である旨を追記する
+func (m *Manager) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting dashboard updater",
+		logging.String("interval", m.interval.String()),
+		logging.String("path", m.path))
+
+	if m.path != "" {
+		if err := m.load(); err != nil {
+			logger.Warn(ctx, "Failed to load dashboards, starting empty", logging.ErrorField(err))
+		}
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	m.update(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			m.shutdown(ctx)
+			logger.Info(ctx, "Dashboard updater stopped")
+			return
+		case <-ticker.C:
+			m.update(ctx)
+		}
+	}
+}
+
+// update はすべてのダッシュボードを最新の内容に編集する
+func (m *Manager) update(ctx context.Context) {
+	messages := m.snapshot()
+	if len(messages) == 0 {
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+	rendered := make(map[int]renderedPage)
+	removed := false
+	for channelID, messageID := range messages {
+		page := m.page(channelID)
+		r, ok := rendered[page]
+		if !ok {
+			body, components, err := m.render(ctx, page)
+			if err != nil {
+				// 取得に失敗した場合は前回の内容のまま次の更新を待つ
+				logger.Warn(ctx, "Failed to render dashboard", logging.ErrorField(err))
+				return
+			}
+			r = renderedPage{content: m.withFooter(body), components: components}
+			rendered[page] = r
+		}
+
+		err := m.publisher.Edit(ctx, channelID, messageID, r.content, r.components)
+		switch {
+		case errors.Is(err, ErrMessageNotFound):
+			// メッセージが削除された場合は更新の対象から外す
+			logger.Info(ctx, "Dashboard message was deleted, stop updating",
+				logging.String("channel_id", channelID),
+				logging.String("message_id", messageID))
+			m.forget(channelID, messageID)
+			removed = true
+		case err != nil:
+			logger.Warn(ctx, "Failed to update dashboard",
+				logging.String("channel_id", channelID),
+				logging.ErrorField(err))
+		}
+	}
+	if removed {
+		m.save(ctx)
+	}
+}
+
+// shutdown はすべてのダッシュボードに停止中である旨を追記し、メッセージIDを保存する
+// 再起動後に更新を再開できるよう、メッセージIDは削除しない
+func (m *Manager) shutdown(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalEditTimeout)
+	defer cancel()
+
+	for channelID, messageID := range m.snapshot() {
+		m.finish(ctx, channelID, messageID, m.page(channelID), pausedNote)
+	}
+	m.save(ctx)
+}
+
+// finish は表示中のページで最後に生成した本文に注記を追記し、ボタンを削除する
+func (m *Manager) finish(ctx context.Context, channelID, messageID string, page int, note string) {
+	m.mu.Lock()
+	body := m.last[page]
+	m.mu.Unlock()
+
+	content := note
+	if body != "" {
+		content = truncate(body, messageLimit-len([]rune(note))-2) + "\n\n" + note
+	}
+	if err := m.publisher.Edit(ctx, channelID, messageID, content, nil); err != nil &&
+		!errors.Is(err, ErrMessageNotFound) {
+		logging.FromContext(ctx).Warn(ctx, "Failed to finish dashboard",
+			logging.String("channel_id", channelID),
+			logging.ErrorField(err))
+	}
+}
+
+// renderedPage は1回の更新で生成したページの内容
+type renderedPage struct {
+	content    string
+	components []discordgo.MessageComponent
+}
+
+// render は指定したページの本文と操作ボタンを生成し、本文を記録する
+func (m *Manager) render(ctx context.Context, page int) (string, []discordgo.MessageComponent, error) {
+	body, components, err := m.renderer.Render(ctx, page)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to render dashboard: %w", err)
+	}
+	m.mu.Lock()
+	m.last[page] = body
+	m.mu.Unlock()
+	return body, components, nil
+}
+
+// page はチャンネルのダッシュボードで表示中のページを返す
+func (m *Manager) page(channelID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pages[channelID]
+}
+
+// withFooter は本文に最終更新時刻を追記する（Discordの最大文字数を超える場合は本文を切り詰める）
+func (m *Manager) withFooter(body string) string {
+	footer := fmt.Sprintf("\n\n🔄 最終更新: %s（%sごとに更新）",
+		m.now().Local().Format("15:04:05"), FormatInterval(m.interval))
+	return truncate(body, messageLimit-len([]rune(footer))) + footer
+}
+
+// snapshot はチャンネルIDごとのメッセージIDのコピーを返す
+func (m *Manager) snapshot() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make(map[string]string, len(m.messages))
+	for channelID, messageID := range m.messages {
+		messages[channelID] = messageID
+	}
+	return messages
+}
+
+// forget はチャンネルのメッセージが変わっていない場合に更新の対象から外す
+func (m *Manager) forget(channelID, messageID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.messages[channelID] == messageID {
+		delete(m.messages, channelID)
+		delete(m.pages, channelID)
+	}
+}
+
+// save はメッセージIDをファイルに保存する（ファイルが指定されていない場合は何もしない）
+func (m *Manager) save(ctx context.Context) {
+	if m.path == "" {
+		return
+	}
+	if err := m.saveFile(); err != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to save dashboards", logging.ErrorField(err))
+	}
+}
+
+// truncate は文字列を最大limit文字に切り詰める
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	const ellipsis = "\n…"
+	return string(runes[:max(limit-len([]rune(ellipsis)), 0)]) + ellipsis
+}
+
+// FormatInterval は更新間隔を表示用に整形する
+func FormatInterval(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%d分", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d秒", int(d.Seconds()))
+}
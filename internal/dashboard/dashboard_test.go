@@ -0,0 +1,382 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// base はテストで使用する基準時刻
+var base = time.Date(2025, 1, 6, 12, 34, 56, 0, time.Local)
+
+// testButtons はテストで使用する操作ボタン
+var testButtons = []discordgo.MessageComponent{
+	discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "🛑 Minecraft を停止", CustomID: "stop_service_minecraft"},
+	}},
+}
+
+// newTestManager は現在時刻を固定したManagerを作成する
+func newTestManager(renderer Renderer, publisher Publisher, path string) *Manager {
+	m := New(renderer, publisher, path, 30*time.Second)
+	m.now = func() time.Time { return base }
+	return m
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "指定した間隔", interval: 30 * time.Second, want: 30 * time.Second},
+		{name: "0の場合はデフォルト", interval: 0, want: DefaultInterval},
+		{name: "負の場合はデフォルト", interval: -time.Second, want: DefaultInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(&MockRenderer{}, &MockPublisher{}, "", tt.interval).Interval(); got != tt.want {
+				t.Errorf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_Start(t *testing.T) {
+	tests := []struct {
+		name      string
+		renderer  *MockRenderer
+		publisher *MockPublisher
+		wantErr   bool
+	}{
+		{
+			name:      "ダッシュボードを送信",
+			renderer:  &MockRenderer{Content: "🖥️ report", Components: testButtons},
+			publisher: &MockPublisher{},
+		},
+		{
+			name:      "本文の生成に失敗",
+			renderer:  &MockRenderer{Err: errors.New("docker unavailable")},
+			publisher: &MockPublisher{},
+			wantErr:   true,
+		},
+		{
+			name:      "送信に失敗",
+			renderer:  &MockRenderer{Content: "🖥️ report"},
+			publisher: &MockPublisher{SendErr: errors.New("forbidden")},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(tt.renderer, tt.publisher, "")
+			err := m.Start(context.Background(), "channel-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if got := m.Channels(); len(got) != 0 {
+					t.Errorf("Channels() = %v, want none", got)
+				}
+				return
+			}
+
+			sent := tt.publisher.Sent()
+			if len(sent) != 1 {
+				t.Fatalf("Sent() = %d messages, want 1", len(sent))
+			}
+			want := "🖥️ report\n\n🔄 最終更新: 12:34:56（30秒ごとに更新）"
+			if sent[0].Content != want {
+				t.Errorf("content = %q, want %q", sent[0].Content, want)
+			}
+			if !reflect.DeepEqual(sent[0].Components, testButtons) {
+				t.Errorf("components = %+v, want the rendered buttons", sent[0].Components)
+			}
+			if got := m.Channels(); !reflect.DeepEqual(got, []string{"channel-1"}) {
+				t.Errorf("Channels() = %v, want [channel-1]", got)
+			}
+		})
+	}
+}
+
+func TestManager_Start_ReplacesExisting(t *testing.T) {
+	publisher := &MockPublisher{}
+	m := newTestManager(&MockRenderer{Content: "report", Components: testButtons}, publisher, "")
+
+	for range 2 {
+		if err := m.Start(context.Background(), "channel-1"); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	}
+
+	// 古いダッシュボードはボタンを削除して停止した旨を追記する
+	edits := publisher.Edits()
+	if len(edits) != 1 || edits[0].MessageID != "message-1" {
+		t.Fatalf("Edits() = %+v, want the old dashboard to be finished", edits)
+	}
+	if !strings.HasSuffix(edits[0].Content, stoppedNote) || len(edits[0].Components) != 0 {
+		t.Errorf("old dashboard = %+v, want stopped note without buttons", edits[0])
+	}
+	if got := m.snapshot(); !reflect.DeepEqual(got, map[string]string{"channel-1": "message-2"}) {
+		t.Errorf("messages = %v, want only the new dashboard", got)
+	}
+}
+
+func TestManager_Stop(t *testing.T) {
+	publisher := &MockPublisher{}
+	m := newTestManager(&MockRenderer{Content: "report", Components: testButtons}, publisher, "")
+	if err := m.Start(context.Background(), "channel-1"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if !m.Stop(context.Background(), "channel-1") {
+		t.Error("Stop() = false, want true")
+	}
+	if m.Stop(context.Background(), "channel-1") {
+		t.Error("Stop() for a stopped channel = true, want false")
+	}
+
+	edits := publisher.Edits()
+	if len(edits) != 1 || edits[0].Content != "report\n\n"+stoppedNote || len(edits[0].Components) != 0 {
+		t.Errorf("Edits() = %+v, want the dashboard to be finished once", edits)
+	}
+	if got := m.Channels(); len(got) != 0 {
+		t.Errorf("Channels() = %v, want none", got)
+	}
+}
+
+func TestManager_update(t *testing.T) {
+	tests := []struct {
+		name         string
+		renderer     *MockRenderer
+		publisher    *MockPublisher
+		wantEdits    int
+		wantChannels []string
+	}{
+		{
+			name:         "すべてのダッシュボードを編集",
+			renderer:     &MockRenderer{Content: "report", Components: testButtons},
+			publisher:    &MockPublisher{},
+			wantEdits:    2,
+			wantChannels: []string{"channel-1", "channel-2"},
+		},
+		{
+			name:         "削除されたメッセージは更新の対象から外す",
+			renderer:     &MockRenderer{Content: "report"},
+			publisher:    &MockPublisher{Deleted: map[string]bool{"message-1": true}},
+			wantEdits:    1,
+			wantChannels: []string{"channel-2"},
+		},
+		{
+			name:         "編集に失敗しても対象から外さない",
+			renderer:     &MockRenderer{Content: "report"},
+			publisher:    &MockPublisher{EditErr: errors.New("rate limited")},
+			wantChannels: []string{"channel-1", "channel-2"},
+		},
+		{
+			name:         "本文の生成に失敗した場合は編集しない",
+			renderer:     &MockRenderer{Err: errors.New("docker unavailable")},
+			publisher:    &MockPublisher{},
+			wantChannels: []string{"channel-1", "channel-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(tt.renderer, tt.publisher, "")
+			m.messages = map[string]string{"channel-1": "message-1", "channel-2": "message-2"}
+
+			m.update(context.Background())
+
+			edits := tt.publisher.Edits()
+			if len(edits) != tt.wantEdits {
+				t.Fatalf("Edits() = %d, want %d", len(edits), tt.wantEdits)
+			}
+			for _, e := range edits {
+				if !strings.HasPrefix(e.Content, "report\n\n🔄 最終更新: 12:34:56") {
+					t.Errorf("content = %q, want the report with footer", e.Content)
+				}
+			}
+			if got := m.Channels(); !reflect.DeepEqual(got, tt.wantChannels) {
+				t.Errorf("Channels() = %v, want %v", got, tt.wantChannels)
+			}
+		})
+	}
+}
+
+func TestManager_SetPage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dashboards.json")
+	publisher := &MockPublisher{}
+	m := newTestManager(&MockRenderer{Content: "report", Components: testButtons}, publisher, path)
+	m.messages = map[string]string{"channel-1": "message-1", "channel-2": "message-2"}
+
+	// 別のメッセージのページ切り替えは記録しない
+	m.SetPage(context.Background(), "channel-1", "message-1", 1)
+	m.SetPage(context.Background(), "channel-2", "message-9", 2)
+
+	// 更新してもページ切り替え後のページを表示し続ける
+	m.update(context.Background())
+	contents := make(map[string]string)
+	for _, e := range publisher.Edits() {
+		contents[e.ChannelID] = e.Content
+	}
+	if !strings.HasPrefix(contents["channel-1"], "report (page 2)\n\n") {
+		t.Errorf("channel-1 content = %q, want the second page", contents["channel-1"])
+	}
+	if !strings.HasPrefix(contents["channel-2"], "report\n\n") {
+		t.Errorf("channel-2 content = %q, want the first page", contents["channel-2"])
+	}
+
+	// 表示中のページは保存され、再起動後も引き継ぐ
+	loaded := New(&MockRenderer{}, &MockPublisher{}, path, 0)
+	if err := loaded.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if got := loaded.page("channel-1"); got != 1 {
+		t.Errorf("loaded page = %d, want 1", got)
+	}
+
+	// 新しいダッシュボードは最初のページから表示する
+	if err := m.Start(context.Background(), "channel-1"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if got := m.page("channel-1"); got != 0 {
+		t.Errorf("page after Start() = %d, want 0", got)
+	}
+}
+
+func TestManager_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dashboards.json")
+
+	// 前回のダッシュボードを保存しておく
+	saved := New(&MockRenderer{}, &MockPublisher{}, path, 0)
+	saved.messages["channel-1"] = "message-1"
+	if err := saved.saveFile(); err != nil {
+		t.Fatalf("saveFile() error = %v", err)
+	}
+
+	publisher := &MockPublisher{}
+	m := newTestManager(&MockRenderer{Content: "report", Components: testButtons}, publisher, path)
+	m.interval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(done)
+	}()
+
+	// 開始時に前回のダッシュボードの更新を再開する
+	deadline := time.Now().Add(time.Second)
+	for len(publisher.Edits()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+
+	edits := publisher.Edits()
+	if len(edits) != 2 {
+		t.Fatalf("Edits() = %+v, want an update and a final edit", edits)
+	}
+	if edits[0].MessageID != "message-1" || len(edits[0].Components) == 0 {
+		t.Errorf("first edit = %+v, want update with buttons", edits[0])
+	}
+	// 停止時はボタンを削除し、再開できるようメッセージIDを残す
+	if !strings.HasSuffix(edits[1].Content, pausedNote) || len(edits[1].Components) != 0 {
+		t.Errorf("final edit = %+v, want paused note without buttons", edits[1])
+	}
+	loaded := New(&MockRenderer{}, &MockPublisher{}, path, 0)
+	if err := loaded.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if got := loaded.Channels(); !reflect.DeepEqual(got, []string{"channel-1"}) {
+		t.Errorf("saved channels = %v, want [channel-1]", got)
+	}
+}
+
+func TestManager_load(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantErr      bool
+		wantChannels []string
+	}{
+		{name: "ファイルが存在しない", wantChannels: []string{"channel-0"}},
+		{name: "不正なJSON", content: "{", wantErr: true, wantChannels: []string{"channel-0"}},
+		{name: "未対応のバージョン", content: `{"version":99}`, wantErr: true, wantChannels: []string{"channel-0"}},
+		{
+			name:         "読み込む前に開始したダッシュボードは上書きしない",
+			content:      `{"version":1,"messages":{"channel-0":"old","channel-1":"message-1","channel-2":""}}`,
+			wantChannels: []string{"channel-0", "channel-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dashboards.json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			}
+
+			m := New(&MockRenderer{}, &MockPublisher{}, path, 0)
+			m.messages["channel-0"] = "message-0"
+			err := m.load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := m.Channels(); !reflect.DeepEqual(got, tt.wantChannels) {
+				t.Errorf("Channels() = %v, want %v", got, tt.wantChannels)
+			}
+			if m.messages["channel-0"] != "message-0" {
+				t.Errorf("channel-0 = %q, want message-0", m.messages["channel-0"])
+			}
+		})
+	}
+}
+
+func TestManager_withFooter(t *testing.T) {
+	m := newTestManager(&MockRenderer{}, &MockPublisher{}, "")
+
+	got := m.withFooter(strings.Repeat("あ", 3000))
+	if n := len([]rune(got)); n > messageLimit {
+		t.Errorf("content length = %d, want at most %d", n, messageLimit)
+	}
+	if !strings.HasSuffix(got, "🔄 最終更新: 12:34:56（30秒ごとに更新）") {
+		t.Errorf("content = %q, want the footer to be kept", got[len(got)-100:])
+	}
+}
+
+func TestFormatInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{name: "秒", d: 30 * time.Second, want: "30秒"},
+		{name: "分", d: 2 * time.Minute, want: "2分"},
+		{name: "分で割り切れない", d: 90 * time.Second, want: "90秒"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatInterval(tt.d); got != tt.want {
+				t.Errorf("FormatInterval(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// fileVersion は保存するファイルの形式のバージョン
+const fileVersion = 1
+
+// state はファイルに保存するダッシュボードの一覧
+type state struct {
+	Version  int               `json:"version"`
+	Messages map[string]string `json:"messages"`        // チャンネルIDごとのメッセージID
+	Pages    map[string]int    `json:"pages,omitempty"` // チャンネルIDごとの表示中のページ（最初のページは保存しない）
+}
+
+// saveFile はメッセージIDをJSON形式でファイルに保存する
+// 書き込み途中で停止しても既存のファイルを壊さないよう、一時ファイルに書き込んでから置き換える
+func (m *Manager) saveFile() error {
+	m.mu.Lock()
+	data, err := json.Marshal(state{Version: fileVersion, Messages: m.messages, Pages: m.pages})
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode dashboards: %w", err)
+	}
+
+	tmp := m.path + ".tmp"
+	// #nosec G306 - メッセージIDには機密情報を含まないが、履歴と同じく所有者のみに制限する
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write dashboard file: %w", err)
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace dashboard file: %w", err)
+	}
+	return nil
+}
+
+// load はファイルからメッセージIDを読み込む
+// ファイルが存在しない場合は何もせず、読み込む前に開始したダッシュボードは上書きしない
+func (m *Manager) load() error {
+	// #nosec G304 - ダッシュボードのファイルのパスは管理者が環境変数で指定する
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read dashboard file: %w", err)
+	}
+
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to decode dashboard file: %w", err)
+	}
+	if s.Version != fileVersion {
+		return fmt.Errorf("unsupported dashboard file version %d", s.Version)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for channelID, messageID := range s.Messages {
+		if _, exists := m.messages[channelID]; !exists && messageID != "" {
+			m.messages[channelID] = messageID
+			if page := s.Pages[channelID]; page > 0 {
+				m.pages[channelID] = page
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MockRenderer はテスト用のモック実装
+type MockRenderer struct {
+	Content    string
+	Components []discordgo.MessageComponent
+	Err        error
+}
+
+// Render は設定された本文と操作ボタンを返す（最初のページ以外は本文にページを追記する）
+func (r *MockRenderer) Render(_ context.Context, page int) (string, []discordgo.MessageComponent, error) {
+	if page > 0 && r.Err == nil {
+		return fmt.Sprintf("%s (page %d)", r.Content, page+1), r.Components, nil
+	}
+	return r.Content, r.Components, r.Err
+}
+
+// PublishedMessage はMockPublisherが送信・編集したメッセージ
+type PublishedMessage struct {
+	ChannelID  string
+	MessageID  string
+	Content    string
+	Components []discordgo.MessageComponent
+}
+
+// MockPublisher はテスト用のモック実装
+type MockPublisher struct {
+	mu      sync.Mutex
+	sent    []PublishedMessage
+	edits   []PublishedMessage
+	SendErr error
+	EditErr error
+	Deleted map[string]bool // 削除されたものとして扱うメッセージID
+}
+
+// Send は送信されたメッセージを記録し、連番のメッセージIDを返す
+func (p *MockPublisher) Send(
+	_ context.Context, channelID, content string, components []discordgo.MessageComponent,
+) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.SendErr != nil {
+		return "", p.SendErr
+	}
+	id := fmt.Sprintf("message-%d", len(p.sent)+1)
+	p.sent = append(p.sent, PublishedMessage{
+		ChannelID: channelID, MessageID: id, Content: content, Components: components,
+	})
+	return id, nil
+}
+
+// Edit は編集されたメッセージを記録する
+func (p *MockPublisher) Edit(
+	_ context.Context, channelID, messageID, content string, components []discordgo.MessageComponent,
+) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.EditErr != nil {
+		return p.EditErr
+	}
+	if p.Deleted[messageID] {
+		return ErrMessageNotFound
+	}
+	p.edits = append(p.edits, PublishedMessage{
+		ChannelID: channelID, MessageID: messageID, Content: content, Components: components,
+	})
+	return nil
+}
+
+// Sent は送信されたメッセージのコピーを返す
+func (p *MockPublisher) Sent() []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PublishedMessage(nil), p.sent...)
+}
+
+// Edits は編集されたメッセージのコピーを返す
+func (p *MockPublisher) Edits() []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PublishedMessage(nil), p.edits...)
+}
@@ -0,0 +1,248 @@
+// Package eventwatch はDockerのイベントを購読し、ゲームサーバーの状態変化を即座に通知する機能を提供します
+package eventwatch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/bot/security"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// DefaultLogLines は異常終了の通知に添付するログのデフォルトの行数
+	DefaultLogLines = 20
+
+	// minRetryDelay は購読が終了した後に再購読するまでの最初の待ち時間
+	minRetryDelay = 5 * time.Second
+	// maxRetryDelay は再購読するまでの最大の待ち時間
+	maxRetryDelay = time.Minute
+	// oomGracePeriod はOOMの後に停止のイベントを待つ時間（届かない場合はOOMだけを通知する）
+	oomGracePeriod = 3 * time.Second
+	// maxLogLength は通知に添付するログの最大文字数（Discordのメッセージ制限を考慮）
+	maxLogLength = 1500
+)
+
+// Subscriber はゲームコンテナのイベントを購読する（*docker.DefaultComposeService が実装する）
+type Subscriber interface {
+	SubscribeEvents(ctx context.Context, composePath string) (<-chan docker.ContainerEvent, <-chan error)
+}
+
+// Watcher はDockerのイベントを購読し、ゲームサーバーの起動・停止・異常を通知する
+type Watcher struct {
+	subscriber  Subscriber
+	compose     docker.ComposeService
+	notifier    notify.Notifier
+	composePath string
+	logLines    int
+	retryDelay  time.Duration
+	oomGrace    time.Duration
+
+	mu     sync.Mutex
+	killed map[string]bool        // シグナルで停止されたコンテナID（続く停止のイベントで使う）
+	oom    map[string]*time.Timer // OOMを検出したコンテナIDと、単独で通知するためのタイマー
+	health map[string]string      // コンテナIDごとの直前のヘルスチェックステータス
+}
+
+// New は新しいWatcherを作成する
+// logLinesは異常終了の通知に添付するログの行数で、0以下の場合はDefaultLogLinesを使用する
+func New(
+	subscriber Subscriber,
+	compose docker.ComposeService,
+	notifier notify.Notifier,
+	composePath string,
+	logLines int,
+) *Watcher {
+	if logLines <= 0 {
+		logLines = DefaultLogLines
+	}
+	return &Watcher{
+		subscriber:  subscriber,
+		compose:     compose,
+		notifier:    notifier,
+		composePath: composePath,
+		logLines:    logLines,
+		retryDelay:  minRetryDelay,
+		oomGrace:    oomGracePeriod,
+		killed:      make(map[string]bool),
+		oom:         make(map[string]*time.Timer),
+		health:      make(map[string]string),
+	}
+}
+
+// Run はコンテキストがキャンセルされるまでイベントを購読し続ける
+// 購読が終了した場合は間隔を空けて再購読する
+func (w *Watcher) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting Docker event watcher")
+
+	delay := w.retryDelay
+	for {
+		events, errs := w.subscriber.SubscribeEvents(ctx, w.composePath)
+		received := false
+		for event := range events {
+			received = true
+			w.handle(ctx, &event)
+		}
+
+		if ctx.Err() != nil {
+			w.stopTimers()
+			logger.Info(ctx, "Docker event watcher stopped")
+			return
+		}
+
+		// イベントを受信できていた場合は待ち時間を戻す
+		if received {
+			delay = w.retryDelay
+		}
+		var err error
+		select {
+		case err = <-errs:
+		default:
+		}
+		logger.Warn(ctx, "Docker event subscription ended, retrying",
+			logging.ErrorField(err), logging.String("delay", delay.String()))
+
+		select {
+		case <-ctx.Done():
+			w.stopTimers()
+			logger.Info(ctx, "Docker event watcher stopped")
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// handle は1件のイベントを処理し、必要であれば通知する
+func (w *Watcher) handle(ctx context.Context, event *docker.ContainerEvent) {
+	name := usermsg.FormatServiceName(event.Service)
+
+	switch event.Type {
+	case docker.EventStart:
+		w.notify(ctx, fmt.Sprintf("🟢 **%s** が起動しました", name))
+	case docker.EventRestart:
+		w.notify(ctx, fmt.Sprintf("🔄 **%s** が再起動しました", name))
+	case docker.EventKill:
+		w.mu.Lock()
+		w.killed[event.ContainerID] = true
+		w.mu.Unlock()
+	case docker.EventOOM:
+		w.handleOOM(ctx, event, name)
+	case docker.EventDie:
+		w.handleDie(ctx, event, name)
+	case docker.EventHealthStatus:
+		w.handleHealth(ctx, event, name)
+	}
+}
+
+// handleOOM はOOMを記録し、続けて停止のイベントが届かない場合はOOMだけを通知する
+func (w *Watcher) handleOOM(ctx context.Context, event *docker.ContainerEvent, name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.oom[event.ContainerID]; ok {
+		return
+	}
+	id := event.ContainerID
+	w.oom[id] = time.AfterFunc(w.oomGrace, func() {
+		w.mu.Lock()
+		_, pending := w.oom[id]
+		delete(w.oom, id)
+		w.mu.Unlock()
+		if pending {
+			w.notify(ctx, fmt.Sprintf("💥 **%s** でメモリ不足 (OOM) が発生しました", name))
+		}
+	})
+}
+
+// handleDie はコンテナの停止を通知する（異常終了の場合は直近のログを添付する）
+func (w *Watcher) handleDie(ctx context.Context, event *docker.ContainerEvent, name string) {
+	w.mu.Lock()
+	killed := w.killed[event.ContainerID]
+	timer, oom := w.oom[event.ContainerID]
+	if oom {
+		timer.Stop()
+	}
+	delete(w.killed, event.ContainerID)
+	delete(w.oom, event.ContainerID)
+	delete(w.health, event.ContainerID)
+	w.mu.Unlock()
+
+	var message string
+	switch {
+	case oom:
+		message = fmt.Sprintf("💥 **%s** がメモリ不足 (OOM) で強制終了されました (終了コード: %d)", name, event.ExitCode)
+	case killed || event.ExitCode == 0:
+		// 停止操作による終了はログを添付しない
+		w.notify(ctx, fmt.Sprintf("🔴 **%s** が停止しました (終了コード: %d)", name, event.ExitCode))
+		return
+	default:
+		message = fmt.Sprintf("🔴 **%s** が異常終了しました (終了コード: %d)", name, event.ExitCode)
+	}
+
+	w.notify(ctx, message+w.recentLogs(ctx, event.Service))
+}
+
+// handleHealth はヘルスチェックの異常と回復を通知する
+func (w *Watcher) handleHealth(ctx context.Context, event *docker.ContainerEvent, name string) {
+	w.mu.Lock()
+	prev := w.health[event.ContainerID]
+	w.health[event.ContainerID] = event.HealthStatus
+	w.mu.Unlock()
+
+	switch {
+	case event.HealthStatus == docker.HealthUnhealthy && prev != docker.HealthUnhealthy:
+		w.notify(ctx, fmt.Sprintf("❌ **%s** のヘルスチェックが異常です", name))
+	case event.HealthStatus == docker.HealthHealthy && prev == docker.HealthUnhealthy:
+		w.notify(ctx, fmt.Sprintf("✅ **%s** のヘルスチェックが回復しました", name))
+	}
+}
+
+// recentLogs は通知に添付する直近のログを返す（取得できない場合は空文字列）
+// ログの内容でコードブロックを閉じたりメンションで通知したりできないよう、Discord向けにエスケープする
+func (w *Watcher) recentLogs(ctx context.Context, service string) string {
+	logs, err := w.compose.GetContainerLogs(ctx, w.composePath, service, w.logLines)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to get logs for event notification",
+			logging.String("service", service), logging.ErrorField(err))
+		return ""
+	}
+	logs = strings.TrimSpace(logs)
+	if logs == "" {
+		return ""
+	}
+
+	// 長すぎる場合は末尾を優先して行単位で切り詰める（エスケープを分断しないようエスケープ前に切る）
+	if runes := []rune(logs); len(runes) > maxLogLength {
+		logs = string(runes[len(runes)-maxLogLength:])
+		if i := strings.IndexByte(logs, '\n'); i >= 0 {
+			logs = logs[i+1:]
+		}
+	}
+	return fmt.Sprintf("\n直近のログ:\n```\n%s\n```", security.SanitizeForDiscord(logs))
+}
+
+// notify は通知を送信する
+func (w *Watcher) notify(ctx context.Context, message string) {
+	if err := w.notifier.Notify(ctx, message); err != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to send event notification", logging.ErrorField(err))
+	}
+}
+
+// stopTimers は保留中のOOMの通知を破棄する
+func (w *Watcher) stopTimers() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for id, timer := range w.oom {
+		timer.Stop()
+		delete(w.oom, id)
+	}
+}
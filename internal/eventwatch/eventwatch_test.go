@@ -0,0 +1,266 @@
+package eventwatch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// subscriberFunc は関数をSubscriberとして使うためのアダプター
+type subscriberFunc func(ctx context.Context, composePath string) (<-chan docker.ContainerEvent, <-chan error)
+
+func (f subscriberFunc) SubscribeEvents(
+	ctx context.Context, composePath string) (<-chan docker.ContainerEvent, <-chan error) {
+	return f(ctx, composePath)
+}
+
+// newTestWatcher はログを返すComposeServiceを使ったWatcherを作成する
+func newTestWatcher(notifier notify.Notifier, logs string, logsErr error) *Watcher {
+	compose := &docker.MockComposeService{
+		GetContainerLogsFunc: func(string, string, int) (string, error) { return logs, logsErr },
+	}
+	w := New(nil, compose, notifier, "docker-compose.yml", 0)
+	w.oomGrace = 20 * time.Millisecond
+	return w
+}
+
+func TestWatcher_handle(t *testing.T) {
+	start := docker.ContainerEvent{Type: docker.EventStart, ContainerID: "abc", Service: "minecraft"}
+	die := func(code int) docker.ContainerEvent {
+		return docker.ContainerEvent{Type: docker.EventDie, ContainerID: "abc", Service: "minecraft", ExitCode: code}
+	}
+	health := func(status string) docker.ContainerEvent {
+		return docker.ContainerEvent{
+			Type: docker.EventHealthStatus, ContainerID: "abc", Service: "minecraft", HealthStatus: status,
+		}
+	}
+	kill := docker.ContainerEvent{Type: docker.EventKill, ContainerID: "abc", Service: "minecraft", Signal: "15"}
+	oom := docker.ContainerEvent{Type: docker.EventOOM, ContainerID: "abc", Service: "minecraft"}
+
+	tests := []struct {
+		name    string
+		events  []docker.ContainerEvent
+		logs    string
+		logsErr error
+		want    []string
+		wantNot []string
+	}{
+		{
+			name:   "起動",
+			events: []docker.ContainerEvent{start},
+			want:   []string{"🟢 **Minecraft** が起動しました"},
+		},
+		{
+			name:   "再起動",
+			events: []docker.ContainerEvent{{Type: docker.EventRestart, ContainerID: "abc", Service: "minecraft"}},
+			want:   []string{"🔄 **Minecraft** が再起動しました"},
+		},
+		{
+			name:   "異常終了はログを添付",
+			events: []docker.ContainerEvent{die(1)},
+			logs:   "Exception in server tick loop\n",
+			want:   []string{"🔴 **Minecraft** が異常終了しました (終了コード: 1)\n直近のログ:\n```\nException in server tick loop\n```"},
+		},
+		{
+			name:    "ログを取得できない場合はログなしで通知",
+			events:  []docker.ContainerEvent{die(1)},
+			logsErr: errors.New("docker unavailable"),
+			want:    []string{"🔴 **Minecraft** が異常終了しました (終了コード: 1)"},
+		},
+		{
+			name:    "停止操作による終了はログを添付しない",
+			events:  []docker.ContainerEvent{kill, die(143)},
+			logs:    "Stopping server\n",
+			want:    []string{"🔴 **Minecraft** が停止しました (終了コード: 143)"},
+			wantNot: []string{"直近のログ"},
+		},
+		{
+			name:   "OOMの後の停止はまとめて通知",
+			events: []docker.ContainerEvent{oom, die(137)},
+			logs:   "java.lang.OutOfMemoryError\n",
+			want: []string{
+				"💥 **Minecraft** がメモリ不足 (OOM) で強制終了されました (終了コード: 137)\n直近のログ:\n```\njava.lang.OutOfMemoryError\n```",
+			},
+		},
+		{
+			name:   "ヘルスチェック異常と回復",
+			events: []docker.ContainerEvent{health("starting"), health("healthy"), health("unhealthy"), health("healthy")},
+			want: []string{
+				"❌ **Minecraft** のヘルスチェックが異常です",
+				"✅ **Minecraft** のヘルスチェックが回復しました",
+			},
+		},
+		{
+			name:   "停止後はヘルスチェックの状態を引き継がない",
+			events: []docker.ContainerEvent{health("unhealthy"), die(0), health("healthy")},
+			want: []string{
+				"❌ **Minecraft** のヘルスチェックが異常です",
+				"🔴 **Minecraft** が停止しました (終了コード: 0)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &notify.MockNotifier{}
+			w := newTestWatcher(notifier, tt.logs, tt.logsErr)
+
+			for i := range tt.events {
+				w.handle(context.Background(), &tt.events[i])
+			}
+
+			// OOMの単独通知が送られないことも確認する
+			time.Sleep(2 * w.oomGrace)
+			got := notifier.Messages()
+			if len(got) != len(tt.want) {
+				t.Fatalf("messages = %q, want %q", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("messages[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+			for _, msg := range got {
+				for _, not := range tt.wantNot {
+					if strings.Contains(msg, not) {
+						t.Errorf("message %q should not contain %q", msg, not)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWatcher_handle_OOMWithoutDie(t *testing.T) {
+	notifier := &notify.MockNotifier{}
+	w := newTestWatcher(notifier, "", nil)
+
+	// 停止しなかった場合はOOMだけを通知する
+	w.handle(context.Background(), &docker.ContainerEvent{Type: docker.EventOOM, ContainerID: "abc", Service: "minecraft"})
+	w.handle(context.Background(), &docker.ContainerEvent{Type: docker.EventOOM, ContainerID: "abc", Service: "minecraft"})
+
+	deadline := time.Now().Add(time.Second)
+	for len(notifier.Messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(2 * w.oomGrace)
+	got := notifier.Messages()
+	if len(got) != 1 || got[0] != "💥 **Minecraft** でメモリ不足 (OOM) が発生しました" {
+		t.Errorf("messages = %q, want a single OOM notification", got)
+	}
+}
+
+func TestWatcher_recentLogs_Truncate(t *testing.T) {
+	var builder strings.Builder
+	for i := 0; i < 200; i++ {
+		builder.WriteString("line of server log output\n")
+	}
+	builder.WriteString("last line")
+	w := newTestWatcher(&notify.MockNotifier{}, builder.String(), nil)
+
+	got := w.recentLogs(context.Background(), "minecraft")
+	if len(got) > maxLogLength+50 {
+		t.Errorf("recentLogs() length = %d, want at most about %d", len(got), maxLogLength)
+	}
+	if !strings.Contains(got, "last line") {
+		t.Error("recentLogs() should keep the last lines")
+	}
+	if !strings.Contains(got, "```\nline of server log output\n") {
+		t.Error("recentLogs() should cut at a line boundary")
+	}
+}
+
+func TestWatcher_recentLogs_TruncateMultibyte(t *testing.T) {
+	// 改行を含まない長い行でも文字やエスケープの途中で切らない
+	w := newTestWatcher(&notify.MockNotifier{}, strings.Repeat("サーバー*", maxLogLength), nil)
+
+	got := w.recentLogs(context.Background(), "minecraft")
+	if !utf8.ValidString(got) {
+		t.Fatalf("recentLogs() = %q, should not split a multibyte character", got)
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(got, "\n直近のログ:\n```\n"), "\n```")
+	if n := utf8.RuneCountInString(strings.ReplaceAll(body, `\*`, "*")); n != maxLogLength {
+		t.Errorf("recentLogs() body has %d characters, want %d", n, maxLogLength)
+	}
+	if strings.Count(body, "*") != strings.Count(body, `\*`) {
+		t.Errorf("recentLogs() body = %q, every asterisk should stay escaped", body)
+	}
+}
+
+func TestWatcher_recentLogs_Escape(t *testing.T) {
+	w := newTestWatcher(&notify.MockNotifier{}, "[Server] ```\n@everyone <@&123456> join now", nil)
+
+	got := w.recentLogs(context.Background(), "minecraft")
+	if !strings.HasPrefix(got, "\n直近のログ:\n```\n") || !strings.HasSuffix(got, "\n```") {
+		t.Fatalf("recentLogs() = %q, want the logs in a code block", got)
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(got, "\n直近のログ:\n```\n"), "\n```")
+	if strings.Contains(body, "```") {
+		t.Errorf("recentLogs() body = %q, should not close the code block", body)
+	}
+	for _, mention := range []string{"@everyone", "<@&123456>"} {
+		if strings.Contains(body, mention) {
+			t.Errorf("recentLogs() body = %q, should not contain mention %q", body, mention)
+		}
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	subscriber := subscriberFunc(func(ctx context.Context, _ string) (<-chan docker.ContainerEvent, <-chan error) {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+
+		events := make(chan docker.ContainerEvent, 1)
+		errs := make(chan error, 1)
+		if call == 1 {
+			// 1回目は購読に失敗する
+			errs <- errors.New("connection refused")
+			close(events)
+			return events, errs
+		}
+		events <- docker.ContainerEvent{Type: docker.EventStart, ContainerID: "abc", Service: "minecraft"}
+		go func() {
+			<-ctx.Done()
+			close(events)
+		}()
+		return events, errs
+	})
+
+	notifier := &notify.MockNotifier{}
+	w := New(subscriber, &docker.MockComposeService{}, notifier, "docker-compose.yml", 0)
+	w.retryDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	// 再購読してイベントを受信する
+	deadline := time.Now().Add(time.Second)
+	for len(notifier.Messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := notifier.Messages(); len(got) != 1 || got[0] != "🟢 **Minecraft** が起動しました" {
+		t.Errorf("messages = %q, want start notification", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+}
@@ -0,0 +1,164 @@
+// Package health はウォッチドッグ自身のヘルスチェックとレディネスチェックを提供します
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMaxPollAge は監視が止まっているとみなすまでのデフォルトの時間
+const DefaultMaxPollAge = 15 * time.Minute
+
+// チェック結果のステータス
+const (
+	StatusOK      = "ok"
+	StatusError   = "error"
+	StatusPending = "pending"
+)
+
+// SessionState はDiscordのゲートウェイへの接続状態を返す
+type SessionState interface {
+	Connected() bool
+}
+
+// DockerPinger はDockerデーモンに接続できるかを確認する
+type DockerPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PollTracker は最後に監視に成功した時刻を返す
+type PollTracker interface {
+	LastPoll() time.Time
+}
+
+// Check は1項目分のチェック結果
+type Check struct {
+	Status   string     `json:"status"`
+	Error    string     `json:"error,omitempty"`
+	LastPoll *time.Time `json:"last_poll,omitempty"`
+}
+
+// Report はエンドポイントが返すチェック結果
+type Report struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Checker はボット・Docker・監視の状態を確認する
+type Checker struct {
+	discord    SessionState
+	docker     DockerPinger
+	poller     PollTracker
+	maxPollAge time.Duration
+	startedAt  time.Time
+	now        func() time.Time
+
+	mu sync.Mutex
+}
+
+// New は新しいCheckerを作成する
+// pollerがnilの場合（バックグラウンド監視が無効な場合）は監視の状態を確認しない
+// maxPollAgeは最後の監視の成功から異常とみなすまでの時間で、0以下の場合はDefaultMaxPollAgeを使用する
+func New(discord SessionState, docker DockerPinger, poller PollTracker, maxPollAge time.Duration) *Checker {
+	if maxPollAge <= 0 {
+		maxPollAge = DefaultMaxPollAge
+	}
+	return &Checker{
+		discord:    discord,
+		docker:     docker,
+		poller:     poller,
+		maxPollAge: maxPollAge,
+		startedAt:  time.Now(),
+		now:        time.Now,
+	}
+}
+
+// Healthz はプロセスが正常に動作しているかを返すハンドラー
+// 監視が止まっている場合のみ異常とし、DiscordとDockerの状態は情報として返す
+func (c *Checker) Healthz(w http.ResponseWriter, r *http.Request) {
+	report := c.check(r.Context())
+	report.Status = StatusOK
+	if report.Checks["monitor"].Status == StatusError {
+		report.Status = StatusError
+	}
+	writeReport(w, report)
+}
+
+// Readyz はDiscordとDockerに接続でき、操作を受け付けられるかを返すハンドラー
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := c.check(r.Context())
+	report.Status = StatusOK
+	for _, check := range report.Checks {
+		if check.Status != StatusOK {
+			report.Status = StatusError
+		}
+	}
+	writeReport(w, report)
+}
+
+// check はすべての項目をチェックする
+func (c *Checker) check(ctx context.Context) Report {
+	// Dockerへの問い合わせが重ならないように直列化する
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checks := map[string]Check{
+		"discord": c.checkDiscord(),
+		"docker":  c.checkDocker(ctx),
+	}
+	if c.poller != nil {
+		checks["monitor"] = c.checkMonitor()
+	}
+	return Report{Checks: checks}
+}
+
+// checkDiscord はDiscordのゲートウェイへの接続を確認する
+func (c *Checker) checkDiscord() Check {
+	if !c.discord.Connected() {
+		return Check{Status: StatusError, Error: "discord gateway is not connected"}
+	}
+	return Check{Status: StatusOK}
+}
+
+// checkDocker はDockerデーモンに接続できるかを確認する
+func (c *Checker) checkDocker(ctx context.Context) Check {
+	if err := c.docker.Ping(ctx); err != nil {
+		return Check{Status: StatusError, Error: err.Error()}
+	}
+	return Check{Status: StatusOK}
+}
+
+// checkMonitor は最後の監視の成功から時間が経ちすぎていないかを確認する
+// 起動直後でまだ一度も成功していない場合はmaxPollAgeまで待つ
+func (c *Checker) checkMonitor() Check {
+	now := c.now()
+	last := c.poller.LastPoll()
+	if last.IsZero() {
+		if now.Sub(c.startedAt) > c.maxPollAge {
+			return Check{Status: StatusError, Error: "monitor has never polled successfully"}
+		}
+		return Check{Status: StatusPending}
+	}
+
+	check := Check{Status: StatusOK, LastPoll: &last}
+	if age := now.Sub(last); age > c.maxPollAge {
+		check.Status = StatusError
+		check.Error = "last successful poll was " + age.Truncate(time.Second).String() + " ago"
+	}
+	return check
+}
+
+// writeReport はチェック結果をJSONで書き込む（異常の場合は503）
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == StatusOK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,176 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// sessionFunc は関数をSessionStateとして扱うためのアダプター
+type sessionFunc func() bool
+
+func (f sessionFunc) Connected() bool { return f() }
+
+// pingFunc は関数をDockerPingerとして扱うためのアダプター
+type pingFunc func() error
+
+func (f pingFunc) Ping(context.Context) error { return f() }
+
+// pollFunc は関数をPollTrackerとして扱うためのアダプター
+type pollFunc func() time.Time
+
+func (f pollFunc) LastPoll() time.Time { return f() }
+
+// request はハンドラーを呼び出してステータスコードとチェック結果を返す
+func request(t *testing.T, handler http.HandlerFunc) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return rec.Code, report
+}
+
+func TestChecker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		connected   bool
+		pingErr     error
+		lastPoll    time.Time
+		noPoller    bool
+		startedAt   time.Time
+		wantHealthz int
+		wantReadyz  int
+		wantChecks  map[string]string
+	}{
+		{
+			name:        "すべて正常",
+			connected:   true,
+			lastPoll:    now.Add(-time.Minute),
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusOK,
+			wantChecks:  map[string]string{"discord": StatusOK, "docker": StatusOK, "monitor": StatusOK},
+		},
+		{
+			name:        "Discordに未接続",
+			connected:   false,
+			lastPoll:    now.Add(-time.Minute),
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusServiceUnavailable,
+			wantChecks:  map[string]string{"discord": StatusError, "docker": StatusOK, "monitor": StatusOK},
+		},
+		{
+			name:        "Dockerに接続できない",
+			connected:   true,
+			pingErr:     errors.New("cannot connect to the Docker daemon"),
+			lastPoll:    now.Add(-time.Minute),
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusServiceUnavailable,
+			wantChecks:  map[string]string{"discord": StatusOK, "docker": StatusError, "monitor": StatusOK},
+		},
+		{
+			name:        "監視が止まっている",
+			connected:   true,
+			lastPoll:    now.Add(-time.Hour),
+			wantHealthz: http.StatusServiceUnavailable,
+			wantReadyz:  http.StatusServiceUnavailable,
+			wantChecks:  map[string]string{"discord": StatusOK, "docker": StatusOK, "monitor": StatusError},
+		},
+		{
+			name:        "起動直後で監視が未実行",
+			connected:   true,
+			startedAt:   now.Add(-time.Minute),
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusServiceUnavailable,
+			wantChecks:  map[string]string{"discord": StatusOK, "docker": StatusOK, "monitor": StatusPending},
+		},
+		{
+			name:        "起動後に一度も監視に成功していない",
+			connected:   true,
+			startedAt:   now.Add(-time.Hour),
+			wantHealthz: http.StatusServiceUnavailable,
+			wantReadyz:  http.StatusServiceUnavailable,
+			wantChecks:  map[string]string{"discord": StatusOK, "docker": StatusOK, "monitor": StatusError},
+		},
+		{
+			name:        "バックグラウンド監視が無効",
+			connected:   true,
+			noPoller:    true,
+			wantHealthz: http.StatusOK,
+			wantReadyz:  http.StatusOK,
+			wantChecks:  map[string]string{"discord": StatusOK, "docker": StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var poller PollTracker
+			if !tt.noPoller {
+				poller = pollFunc(func() time.Time { return tt.lastPoll })
+			}
+			c := New(
+				sessionFunc(func() bool { return tt.connected }),
+				pingFunc(func() error { return tt.pingErr }),
+				poller,
+				15*time.Minute,
+			)
+			c.now = func() time.Time { return now }
+			if !tt.startedAt.IsZero() {
+				c.startedAt = tt.startedAt
+			}
+
+			code, report := request(t, c.Healthz)
+			if code != tt.wantHealthz {
+				t.Errorf("Healthz status = %d, want %d (%+v)", code, tt.wantHealthz, report)
+			}
+
+			code, report = request(t, c.Readyz)
+			if code != tt.wantReadyz {
+				t.Errorf("Readyz status = %d, want %d (%+v)", code, tt.wantReadyz, report)
+			}
+			if len(report.Checks) != len(tt.wantChecks) {
+				t.Errorf("checks = %+v, want %v", report.Checks, tt.wantChecks)
+			}
+			for name, want := range tt.wantChecks {
+				if got := report.Checks[name].Status; got != want {
+					t.Errorf("checks[%s] = %s, want %s", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestChecker_ReportDetails(t *testing.T) {
+	lastPoll := time.Date(2025, 1, 1, 11, 59, 0, 0, time.UTC)
+	c := New(
+		sessionFunc(func() bool { return true }),
+		pingFunc(func() error { return errors.New("permission denied") }),
+		pollFunc(func() time.Time { return lastPoll }),
+		0,
+	)
+	c.now = func() time.Time { return lastPoll.Add(time.Minute) }
+
+	_, report := request(t, c.Readyz)
+	if report.Status != StatusError {
+		t.Errorf("status = %s, want %s", report.Status, StatusError)
+	}
+	if got := report.Checks["docker"].Error; got != "permission denied" {
+		t.Errorf("docker error = %q, want %q", got, "permission denied")
+	}
+	if got := report.Checks["monitor"].LastPoll; got == nil || !got.Equal(lastPoll) {
+		t.Errorf("monitor last_poll = %v, want %v", got, lastPoll)
+	}
+	if c.maxPollAge != DefaultMaxPollAge {
+		t.Errorf("maxPollAge = %v, want %v", c.maxPollAge, DefaultMaxPollAge)
+	}
+}
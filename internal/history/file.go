@@ -0,0 +1,73 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// fileVersion は保存するファイルの形式のバージョン
+const fileVersion = 1
+
+// snapshot はファイルに保存する履歴
+type snapshot struct {
+	Version int                  `json:"version"`
+	Series  map[string][][]Point `json:"series"`
+}
+
+// Save は履歴をJSON形式でファイルに保存する
+// 書き込み途中で停止しても既存のファイルを壊さないよう、一時ファイルに書き込んでから置き換える
+func (s *Store) Save(path string) error {
+	s.mu.RLock()
+	data, err := json.Marshal(snapshot{Version: fileVersion, Series: s.series})
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	// #nosec G306 - 履歴には機密情報を含まないが、監査ログと同じく所有者のみに制限する
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace history file: %w", err)
+	}
+	return nil
+}
+
+// Load はファイルから履歴を読み込み、現在の履歴を置き換える
+// ファイルが存在しない場合は何もしない
+func (s *Store) Load(path string) error {
+	// #nosec G304 - 履歴ファイルのパスは管理者が環境変数で指定する
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to decode history file: %w", err)
+	}
+	if snap.Version != fileVersion {
+		return fmt.Errorf("unsupported history file version %d", snap.Version)
+	}
+
+	// 段階の数が異なる場合は合わせる（足りない段階は空にする）
+	series := make(map[string][][]Point, len(snap.Series))
+	for key, points := range snap.Series {
+		normalized := make([][]Point, len(tiers))
+		copy(normalized, points)
+		series[key] = normalized
+	}
+
+	s.mu.Lock()
+	s.series = series
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package history はホストとゲームサーバーのリソース使用率の推移を記録・集計する機能を提供します
+package history
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// HostKey はホストの履歴を表すキー
+	HostKey = "host"
+
+	// maxPeaks は集計結果に含めるピークの件数
+	maxPeaks = 3
+)
+
+// tier はダウンサンプリングの1段階分の解像度と保持期間
+type tier struct {
+	resolution time.Duration
+	retention  time.Duration
+}
+
+// tiers は直近24時間を1分単位、直近7日間を15分単位で保持する
+var tiers = []tier{
+	{resolution: time.Minute, retention: 24 * time.Hour},
+	{resolution: 15 * time.Minute, retention: 7 * 24 * time.Hour},
+}
+
+// MaxWindow は集計できる最大の期間
+var MaxWindow = tiers[len(tiers)-1].retention
+
+// Aggregate は一定期間の値の集計
+type Aggregate struct {
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Sum   float64   `json:"sum"`
+	Count int       `json:"count"`
+	MaxAt time.Time `json:"max_at"` // 最大値を記録した時刻
+}
+
+// Avg は平均値を返す（値がない場合は0）
+func (a *Aggregate) Avg() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Sum / float64(a.Count)
+}
+
+// add は1件の値を加える
+func (a *Aggregate) add(v float64, t time.Time) {
+	a.merge(Aggregate{Min: v, Max: v, Sum: v, Count: 1, MaxAt: t})
+}
+
+// merge は別の集計を加える
+func (a *Aggregate) merge(o Aggregate) {
+	if o.Count == 0 {
+		return
+	}
+	if a.Count == 0 {
+		*a = o
+		return
+	}
+	a.Min = min(a.Min, o.Min)
+	if o.Max > a.Max {
+		a.Max = o.Max
+		a.MaxAt = o.MaxAt
+	}
+	a.Sum += o.Sum
+	a.Count += o.Count
+}
+
+// Point は1区間分のCPU使用率とメモリ使用率の集計
+type Point struct {
+	Start  time.Time `json:"start"`
+	CPU    Aggregate `json:"cpu"`
+	Memory Aggregate `json:"memory"`
+}
+
+// Summary は指定した期間の集計結果
+type Summary struct {
+	Key        string
+	Window     time.Duration
+	Resolution time.Duration // 集計に使用した区間の長さ
+	CPU        Aggregate
+	Memory     Aggregate
+	Points     []Point // 期間内の区間（古い順）
+	Peaks      []Point // CPU使用率の最大値が高い区間（高い順）
+}
+
+// Reader は記録した履歴を集計する
+type Reader interface {
+	// Summarize は直近window分の履歴を集計する（記録がない場合はCountが0）
+	Summarize(key string, window time.Duration) Summary
+	// Keys は履歴があるキーの一覧を返す
+	Keys() []string
+}
+
+// Store はキー（ホストまたはサービス名）ごとの履歴をメモリ上に保持する
+// ファイルへの保存と読み込みは Save / Load で行う
+type Store struct {
+	mu     sync.RWMutex
+	series map[string][][]Point // キーごと・段階ごとの区間（古い順）
+	now    func() time.Time
+}
+
+// NewStore は新しいStoreを作成する
+func NewStore() *Store {
+	return &Store{
+		series: make(map[string][][]Point),
+		now:    time.Now,
+	}
+}
+
+// Record は1件のサンプルを記録する
+func (s *Store) Record(key string, t time.Time, cpu, memory float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := s.series[key]
+	if series == nil {
+		series = make([][]Point, len(tiers))
+		s.series[key] = series
+	}
+
+	for i, tr := range tiers {
+		start := t.Truncate(tr.resolution)
+		points := series[i]
+		if n := len(points); n > 0 && !points[n-1].Start.Before(start) {
+			// 同じ区間（時計が戻った場合は最後の区間）に加える
+			points[n-1].CPU.add(cpu, t)
+			points[n-1].Memory.add(memory, t)
+			continue
+		}
+		p := Point{Start: start}
+		p.CPU.add(cpu, t)
+		p.Memory.add(memory, t)
+		series[i] = append(points, p)
+	}
+}
+
+// Prune は保持期間を過ぎた区間と、区間がなくなったキーを削除する
+func (s *Store) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, series := range s.series {
+		empty := true
+		for i, tr := range tiers {
+			cutoff := now.Add(-tr.retention)
+			points := series[i]
+			n := sort.Search(len(points), func(j int) bool { return points[j].Start.After(cutoff) })
+			if n > 0 {
+				series[i] = append([]Point(nil), points[n:]...)
+			}
+			if len(series[i]) > 0 {
+				empty = false
+			}
+		}
+		if empty {
+			delete(s.series, key)
+		}
+	}
+}
+
+// Summarize は直近window分の履歴を集計する
+// windowを保持できる最も細かい段階を使用し、windowがMaxWindowを超える場合はMaxWindowに切り詰める
+func (s *Store) Summarize(key string, window time.Duration) Summary {
+	window = min(window, MaxWindow)
+	level := len(tiers) - 1
+	for i, tr := range tiers {
+		if window <= tr.retention {
+			level = i
+			break
+		}
+	}
+	resolution := tiers[level].resolution
+	summary := Summary{Key: key, Window: window, Resolution: resolution}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.series[key]
+	if series == nil {
+		return summary
+	}
+
+	// 区間の終わりが期間内にあるものを集計する
+	from := s.now().Add(-window)
+	var points []Point
+	for _, p := range series[level] {
+		if p.Start.Add(resolution).After(from) {
+			points = append(points, p)
+		}
+	}
+	for i := range points {
+		summary.CPU.merge(points[i].CPU)
+		summary.Memory.merge(points[i].Memory)
+	}
+	summary.Points = points
+
+	peaks := append([]Point(nil), points...)
+	sort.SliceStable(peaks, func(i, j int) bool { return peaks[i].CPU.Max > peaks[j].CPU.Max })
+	summary.Peaks = peaks[:min(len(peaks), maxPeaks)]
+	return summary
+}
+
+// Keys は履歴があるキーの一覧を名前順で返す
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,269 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// base はテストで使用する基準時刻
+var base = time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+
+// newTestStore は現在時刻を固定したStoreを作成する
+func newTestStore(now time.Time) *Store {
+	s := NewStore()
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestAggregate(t *testing.T) {
+	var a Aggregate
+	if a.Avg() != 0 {
+		t.Errorf("Avg() of empty aggregate = %v, want 0", a.Avg())
+	}
+
+	a.add(20, base)
+	a.add(80, base.Add(time.Minute))
+	a.add(50, base.Add(2*time.Minute))
+
+	if a.Min != 20 || a.Max != 80 || a.Count != 3 || a.Avg() != 50 {
+		t.Errorf("aggregate = %+v, want min 20, max 80, avg 50", a)
+	}
+	if !a.MaxAt.Equal(base.Add(time.Minute)) {
+		t.Errorf("MaxAt = %v, want time of the maximum", a.MaxAt)
+	}
+}
+
+func TestStore_Record(t *testing.T) {
+	s := newTestStore(base)
+
+	// 同じ分のサンプルは1つの区間にまとめる
+	s.Record("minecraft", base, 10, 40)
+	s.Record("minecraft", base.Add(30*time.Second), 30, 60)
+	s.Record("minecraft", base.Add(time.Minute), 50, 50)
+	s.Record("minecraft", base.Add(20*time.Minute), 70, 50)
+
+	series := s.series["minecraft"]
+	if got := len(series[0]); got != 3 {
+		t.Fatalf("1-minute points = %d, want 3", got)
+	}
+	if p := series[0][0]; p.CPU.Count != 2 || p.CPU.Avg() != 20 || p.Memory.Max != 60 {
+		t.Errorf("first point = %+v, want 2 samples with avg CPU 20", p)
+	}
+
+	// 15分単位の区間にダウンサンプリングする
+	if got := len(series[1]); got != 2 {
+		t.Fatalf("15-minute points = %d, want 2", got)
+	}
+	if p := series[1][0]; p.CPU.Count != 3 || p.CPU.Max != 50 || !p.Start.Equal(base) {
+		t.Errorf("first 15-minute point = %+v, want 3 samples starting at %v", p, base)
+	}
+}
+
+func TestStore_Summarize(t *testing.T) {
+	now := base.Add(48 * time.Hour)
+	s := newTestStore(now)
+
+	// 2日前から1分ごとに記録し、2時間前と10分前にCPUのスパイクを発生させる
+	for ts := base; !ts.After(now); ts = ts.Add(time.Minute) {
+		cpu := 10.0
+		switch now.Sub(ts) {
+		case 2 * time.Hour:
+			cpu = 90
+		case 10 * time.Minute:
+			cpu = 70
+		}
+		s.Record("minecraft", ts, cpu, 40)
+	}
+	s.Prune(now)
+
+	tests := []struct {
+		name           string
+		window         time.Duration
+		wantResolution time.Duration
+		wantMax        float64
+		wantPeakAt     time.Time
+		wantCount      int
+	}{
+		{
+			name:           "1時間は1分単位",
+			window:         time.Hour,
+			wantResolution: time.Minute,
+			wantMax:        70,
+			wantPeakAt:     now.Add(-10 * time.Minute),
+			wantCount:      60,
+		},
+		{
+			name:           "24時間は1分単位",
+			window:         24 * time.Hour,
+			wantResolution: time.Minute,
+			wantMax:        90,
+			wantPeakAt:     now.Add(-2 * time.Hour),
+			wantCount:      24 * 60,
+		},
+		{
+			name:           "7日間は15分単位",
+			window:         7 * 24 * time.Hour,
+			wantResolution: 15 * time.Minute,
+			wantMax:        90,
+			wantPeakAt:     now.Add(-2 * time.Hour),
+			wantCount:      48*60 + 1,
+		},
+		{
+			name:           "最大期間を超える場合は切り詰める",
+			window:         30 * 24 * time.Hour,
+			wantResolution: 15 * time.Minute,
+			wantMax:        90,
+			wantPeakAt:     now.Add(-2 * time.Hour),
+			wantCount:      48*60 + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Summarize("minecraft", tt.window)
+			if got.Resolution != tt.wantResolution {
+				t.Errorf("Resolution = %v, want %v", got.Resolution, tt.wantResolution)
+			}
+			if got.CPU.Max != tt.wantMax || !got.CPU.MaxAt.Equal(tt.wantPeakAt) {
+				t.Errorf("CPU max = %v at %v, want %v at %v", got.CPU.Max, got.CPU.MaxAt, tt.wantMax, tt.wantPeakAt)
+			}
+			if got.CPU.Min != 10 || got.Memory.Avg() != 40 {
+				t.Errorf("CPU min = %v, memory avg = %v, want 10 and 40", got.CPU.Min, got.Memory.Avg())
+			}
+			// 1分単位の場合は期間の先頭の区間が1つ多く含まれることがある
+			if got.CPU.Count < tt.wantCount || got.CPU.Count > tt.wantCount+int(tt.wantResolution/time.Minute) {
+				t.Errorf("CPU count = %d, want about %d", got.CPU.Count, tt.wantCount)
+			}
+			if len(got.Peaks) != maxPeaks || got.Peaks[0].CPU.Max != tt.wantMax {
+				t.Errorf("Peaks = %+v, want %d peaks starting with %v", got.Peaks, maxPeaks, tt.wantMax)
+			}
+			// 区間は古い順のまま返す
+			for i := 1; i < len(got.Points); i++ {
+				if !got.Points[i-1].Start.Before(got.Points[i].Start) {
+					t.Fatalf("Points[%d] = %v is not after %v", i, got.Points[i].Start, got.Points[i-1].Start)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Summarize_NoData(t *testing.T) {
+	s := newTestStore(base)
+	s.Record("minecraft", base.Add(-2*time.Hour), 50, 50)
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "記録のないキー", key: "rust"},
+		{name: "期間内に記録がない", key: "minecraft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Summarize(tt.key, time.Hour)
+			if got.CPU.Count != 0 || len(got.Peaks) != 0 {
+				t.Errorf("Summarize() = %+v, want empty summary", got)
+			}
+			if got.Key != tt.key || got.Window != time.Hour {
+				t.Errorf("Summarize() key = %q, window = %v", got.Key, got.Window)
+			}
+		})
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	now := base.Add(8 * 24 * time.Hour)
+	s := newTestStore(now)
+	s.Record("old", base, 10, 10)
+	s.Record("minecraft", now.Add(-2*24*time.Hour), 10, 10)
+	s.Record("minecraft", now, 10, 10)
+
+	s.Prune(now)
+
+	// すべての区間が期限切れのキーは削除する
+	if got := s.Keys(); !reflect.DeepEqual(got, []string{"minecraft"}) {
+		t.Errorf("Keys() = %v, want [minecraft]", got)
+	}
+	// 1分単位は24時間、15分単位は7日間だけ保持する
+	if got := len(s.series["minecraft"][0]); got != 1 {
+		t.Errorf("1-minute points = %d, want 1", got)
+	}
+	if got := len(s.series["minecraft"][1]); got != 2 {
+		t.Errorf("15-minute points = %d, want 2", got)
+	}
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	s := newTestStore(base)
+	s.Record(HostKey, base, 30, 60)
+	s.Record("minecraft", base, 80, 40)
+
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file should be removed, stat error = %v", err)
+	}
+
+	loaded := newTestStore(base)
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Keys(); !reflect.DeepEqual(got, []string{HostKey, "minecraft"}) {
+		t.Errorf("Keys() = %v, want [host minecraft]", got)
+	}
+	if got := loaded.Summarize("minecraft", time.Hour); got.CPU.Max != 80 || !got.CPU.MaxAt.Equal(base) {
+		t.Errorf("Summarize() after Load = %+v, want saved sample", got.CPU)
+	}
+}
+
+func TestStore_Load(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		wantKeys []string
+	}{
+		{name: "ファイルが存在しない", wantKeys: []string{}},
+		{name: "不正なJSON", content: "{", wantErr: true},
+		{name: "未対応のバージョン", content: `{"version":99,"series":{}}`, wantErr: true},
+		{
+			name:     "段階が足りない",
+			content:  `{"version":1,"series":{"minecraft":[[{"start":"2025-01-06T12:00:00Z","cpu":{"count":1}}]]}}`,
+			wantKeys: []string{"minecraft"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			}
+
+			s := newTestStore(base)
+			err := s.Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := s.Keys(); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("Keys() = %v, want %v", got, tt.wantKeys)
+			}
+			// 足りない段階にも記録できる
+			s.Record("minecraft", base, 10, 10)
+			if got := len(s.series["minecraft"]); got != len(tiers) {
+				t.Errorf("tiers = %d, want %d", got, len(tiers))
+			}
+		})
+	}
+}
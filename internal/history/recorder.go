@@ -0,0 +1,124 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+const (
+	// DefaultInterval はデフォルトの記録間隔
+	DefaultInterval = time.Minute
+
+	// saveEvery は履歴をファイルに保存する間隔（記録の回数）
+	saveEvery = 10
+)
+
+// Recorder はホストとコンテナのリソース使用率を定期的にStoreへ記録する
+type Recorder struct {
+	store       *Store
+	monitor     system.Monitor
+	compose     docker.ComposeService
+	composePath string
+	path        string
+	interval    time.Duration
+	now         func() time.Time
+}
+
+// NewRecorder は新しいRecorderを作成する
+// pathは履歴を保存するファイルで、空の場合はメモリ上にのみ保持する
+// intervalが0以下の場合はDefaultIntervalを使用する
+func NewRecorder(
+	store *Store,
+	monitor system.Monitor,
+	compose docker.ComposeService,
+	composePath string,
+	path string,
+	interval time.Duration,
+) *Recorder {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Recorder{
+		store:       store,
+		monitor:     monitor,
+		compose:     compose,
+		composePath: composePath,
+		path:        path,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// Run はコンテキストがキャンセルされるまで定期的に記録する
+// ファイルが指定されている場合は開始時に読み込み、定期的および停止時に保存する
+func (r *Recorder) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting metrics history recorder",
+		logging.String("interval", r.interval.String()),
+		logging.String("path", r.path))
+
+	if r.path != "" {
+		if err := r.store.Load(r.path); err != nil {
+			logger.Warn(ctx, "Failed to load metrics history, starting empty", logging.ErrorField(err))
+		}
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.record(ctx)
+	for count := 1; ; count++ {
+		select {
+		case <-ctx.Done():
+			r.save(ctx)
+			logger.Info(ctx, "Metrics history recorder stopped")
+			return
+		case <-ticker.C:
+			r.record(ctx)
+			if count%saveEvery == 0 {
+				r.save(ctx)
+			}
+		}
+	}
+}
+
+// record はホストとコンテナのリソース使用率を1回分記録する
+func (r *Recorder) record(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	now := r.now()
+
+	if info, err := r.monitor.GetSystemInfo(ctx); err != nil {
+		logger.Warn(ctx, "Failed to get system info for history", logging.ErrorField(err))
+	} else {
+		r.store.Record(HostKey, now, info.CPUUsagePercent, info.MemoryUsedPercent)
+	}
+
+	// 一部のコンテナの取得に失敗した場合も取得できた分は記録する
+	stats, err := r.compose.GetAllContainersStats(ctx, r.composePath)
+	if err != nil {
+		logger.Warn(ctx, "Failed to get container stats for history", logging.ErrorField(err))
+	}
+	for i := range stats {
+		key := stats[i].Service
+		if key == "" {
+			key = stats[i].Name
+		}
+		r.store.Record(key, now, stats[i].CPUPercent, stats[i].MemoryPercent)
+	}
+
+	r.store.Prune(now)
+}
+
+// save は履歴をファイルに保存する（ファイルが指定されていない場合は何もしない）
+func (r *Recorder) save(ctx context.Context) {
+	if r.path == "" {
+		return
+	}
+	if err := r.store.Save(r.path); err != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to save metrics history", logging.ErrorField(err))
+	}
+}
@@ -0,0 +1,108 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+func TestRecorder_record(t *testing.T) {
+	tests := []struct {
+		name     string
+		monitor  *system.MockMonitor
+		stats    []docker.ContainerStats
+		statsErr error
+		wantKeys []string
+	}{
+		{
+			name:     "ホストとサービスごとに記録",
+			monitor:  &system.MockMonitor{SystemInfo: &system.SystemInfo{CPUUsagePercent: 30, MemoryUsedPercent: 60}},
+			stats:    []docker.ContainerStats{{Name: "game-minecraft-1", Service: "minecraft", CPUPercent: 80}},
+			wantKeys: []string{HostKey, "minecraft"},
+		},
+		{
+			name:     "サービス名がない場合はコンテナ名",
+			monitor:  &system.MockMonitor{SystemInfo: &system.SystemInfo{}},
+			stats:    []docker.ContainerStats{{Name: "standalone"}},
+			wantKeys: []string{HostKey, "standalone"},
+		},
+		{
+			name:     "ホストの情報を取得できない",
+			monitor:  &system.MockMonitor{Err: errors.New("failed")},
+			stats:    []docker.ContainerStats{{Name: "game-minecraft-1", Service: "minecraft"}},
+			wantKeys: []string{"minecraft"},
+		},
+		{
+			name:     "一部のコンテナの取得に失敗しても取得できた分は記録",
+			monitor:  &system.MockMonitor{SystemInfo: &system.SystemInfo{}},
+			stats:    []docker.ContainerStats{{Name: "game-minecraft-1", Service: "minecraft"}},
+			statsErr: &docker.ContainerStatsError{},
+			wantKeys: []string{HostKey, "minecraft"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compose := &docker.MockComposeService{
+				GetAllContainersStatsFunc: func(string) ([]docker.ContainerStats, error) { return tt.stats, tt.statsErr },
+			}
+			store := newTestStore(base)
+			r := NewRecorder(store, tt.monitor, compose, "docker-compose.yml", "", 0)
+			r.now = func() time.Time { return base }
+
+			r.record(context.Background())
+
+			if got := store.Keys(); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("Keys() = %v, want %v", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestRecorder_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	// 前回保存した履歴を読み込んでから記録する
+	saved := NewStore()
+	saved.Record("rust", time.Now(), 10, 10)
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{CPUUsagePercent: 30}}
+	store := NewStore()
+	r := NewRecorder(store, monitor, &docker.MockComposeService{}, "docker-compose.yml", path, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for len(store.Keys()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+
+	// 停止時に保存する
+	loaded := NewStore()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Keys(); !reflect.DeepEqual(got, []string{HostKey, "rust"}) {
+		t.Errorf("Keys() after Run = %v, want [host rust]", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Package httpserver はメトリクスなどを公開するHTTPサーバーを提供します
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// readHeaderTimeout はリクエストヘッダーの読み込みのタイムアウト
+	readHeaderTimeout = 10 * time.Second
+	// shutdownTimeout は停止時に処理中のリクエストを待つ時間
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server はエンドポイントを登録して公開するHTTPサーバー
+type Server struct {
+	addr string
+	mux  *http.ServeMux
+}
+
+// New は指定されたアドレスで待ち受ける新しいServerを作成する
+func New(addr string) *Server {
+	return &Server{
+		addr: addr,
+		mux:  http.NewServeMux(),
+	}
+}
+
+// Handle はエンドポイントを登録する
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Run はコンテキストがキャンセルされるまでリクエストを処理する
+func (s *Server) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		logger.Error(ctx, "Failed to start HTTP server",
+			logging.String("addr", s.addr), logging.ErrorField(err))
+		return
+	}
+
+	logger.Info(ctx, "Starting HTTP server", logging.String("addr", ln.Addr().String()))
+	if err := s.serve(ctx, ln); err != nil {
+		logger.Error(ctx, "HTTP server stopped unexpectedly", logging.ErrorField(err))
+		return
+	}
+	logger.Info(ctx, "HTTP server stopped")
+}
+
+// serve はlnでリクエストを処理し、コンテキストがキャンセルされたら停止する
+func (s *Server) serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
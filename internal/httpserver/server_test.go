@@ -0,0 +1,89 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServer_serve(t *testing.T) {
+	s := New("127.0.0.1:0")
+	s.Handle("/metrics", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.serve(ctx, ln)
+	}()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "登録したエンドポイント", path: "/metrics", wantCode: http.StatusOK, wantBody: "ok"},
+		{name: "未登録のパス", path: "/unknown", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+ln.Addr().String()+tt.path, http.NoBody)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("GET %s error = %v", tt.path, err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("GET %s status = %d, want %d", tt.path, resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("GET %s body = %q, want %q", tt.path, body, tt.wantBody)
+				}
+			}
+		})
+	}
+
+	// コンテキストのキャンセルで停止する
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve() did not return after context cancel")
+	}
+}
+
+func TestServer_Run_ListenError(t *testing.T) {
+	// 待ち受けに失敗した場合はすぐに戻る
+	s := New("invalid-address")
+	done := make(chan struct{})
+	go func() {
+		s.Run(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return on listen error")
+	}
+}
@@ -0,0 +1,218 @@
+// Package idle はプレイヤーがいない状態が続いたゲームサーバーを自動的に停止する機能を提供します
+package idle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// DefaultInterval はデフォルトのチェック間隔
+	DefaultInterval = time.Minute
+
+	// startCustomIDPrefix はmonitorコマンドの起動ボタンと同じカスタムID（押されると同じ処理で起動する）
+	startCustomIDPrefix = "start_service_"
+)
+
+// ServicePolicy はサービスごとにプレイヤーがいない状態が続いてから停止するまでの時間を返す（0の場合は停止しない）
+type ServicePolicy interface {
+	IdleShutdownAfter(service string) time.Duration
+}
+
+// Stopper はゲームサーバーのプレイヤー数を確認し、プレイヤーがいない状態が続いたら停止する
+type Stopper struct {
+	compose     docker.ComposeService
+	prober      gameprobe.Prober
+	notifier    notify.Notifier
+	recorder    audit.Recorder
+	locks       *oplock.Locker
+	services    ServicePolicy
+	composePath string
+	interval    time.Duration
+	now         func() time.Time
+
+	emptySince map[string]time.Time // サービスごとにプレイヤーがいないことを最初に確認した時刻
+}
+
+// New は新しいStopperを作成する（proberがnilの場合はデフォルトのタイムアウトで作成）
+func New(
+	compose docker.ComposeService,
+	prober gameprobe.Prober,
+	notifier notify.Notifier,
+	recorder audit.Recorder,
+	locks *oplock.Locker,
+	services ServicePolicy,
+	composePath string,
+	interval time.Duration,
+) *Stopper {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if prober == nil {
+		prober = gameprobe.NewProber(0)
+	}
+	if locks == nil {
+		locks = oplock.New()
+	}
+	return &Stopper{
+		compose:     compose,
+		prober:      prober,
+		notifier:    notifier,
+		recorder:    recorder,
+		locks:       locks,
+		services:    services,
+		composePath: composePath,
+		interval:    interval,
+		now:         time.Now,
+		emptySince:  make(map[string]time.Time),
+	}
+}
+
+// Run はコンテキストがキャンセルされるまで定期的にチェックを行う
+func (s *Stopper) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting idle shutdown", logging.String("interval", s.interval.String()))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Idle shutdown stopped")
+			return
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+// poll は1回分のチェックを行い、プレイヤーがいない状態が続いたサービスを停止する
+func (s *Stopper) poll(ctx context.Context) {
+	containers, err := s.compose.ListGameContainers(ctx, s.composePath)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to list game containers", logging.ErrorField(err))
+		return
+	}
+
+	now := s.now()
+	checked := make(map[string]bool, len(containers))
+	for i := range containers {
+		checked[containers[i].Service] = true
+		s.check(ctx, &containers[i], now)
+	}
+	// 削除されたサービスの記録を残さない
+	for service := range s.emptySince {
+		if !checked[service] {
+			delete(s.emptySince, service)
+		}
+	}
+}
+
+// check は1コンテナ分のプレイヤー数を確認し、プレイヤーがいない状態が続いていれば停止する
+func (s *Stopper) check(ctx context.Context, c *docker.ContainerInfo, now time.Time) {
+	after := s.services.IdleShutdownAfter(c.Service)
+	if !s.empty(ctx, c, after) {
+		delete(s.emptySince, c.Service)
+		return
+	}
+
+	since, ok := s.emptySince[c.Service]
+	if !ok {
+		s.emptySince[c.Service] = now
+		return
+	}
+	if now.Sub(since) < after {
+		return
+	}
+	s.shutdown(ctx, c.Service, after)
+}
+
+// empty はコンテナが停止の対象で、プレイヤーがいないことを確認できたかを返す
+// 停止中のコンテナやクエリに応答しないゲームサーバー（起動中の可能性がある）はプレイヤーがいないとみなさない
+func (s *Stopper) empty(ctx context.Context, c *docker.ContainerInfo, after time.Duration) bool {
+	if after <= 0 || !strings.EqualFold(c.State, docker.StateRunning) {
+		return false
+	}
+	target, ok := gameprobe.TargetFor(c)
+	if !ok {
+		return false
+	}
+	status, err := s.prober.Probe(ctx, target)
+	if err != nil {
+		logging.FromContext(ctx).Debug(ctx, "Failed to query game server for idle shutdown",
+			logging.String("service", c.Service),
+			logging.ErrorField(err))
+		return false
+	}
+	return status.Players == 0
+}
+
+// shutdown はサービスを停止し、起動ボタン付きで通知する
+func (s *Stopper) shutdown(ctx context.Context, service string, after time.Duration) {
+	logger := logging.FromContext(ctx)
+	name := usermsg.FormatServiceName(service)
+
+	if !s.locks.TryLock(service) {
+		logger.Debug(ctx, "Service is being operated, skipping idle shutdown",
+			logging.String("service", service))
+		return
+	}
+	defer s.locks.Unlock(service)
+
+	// 失敗した場合も、改めてプレイヤーがいない状態が続くまで再試行しない
+	delete(s.emptySince, service)
+
+	logger.Info(ctx, "Stopping idle service",
+		logging.String("service", service),
+		logging.String("idle", after.String()))
+
+	entry := audit.Entry{
+		Actor:   audit.SystemActor(audit.SourceIdle),
+		Service: service,
+		Action:  audit.ActionStop,
+		Source:  audit.SourceIdle,
+	}
+	err := audit.Track(ctx, s.recorder, entry, func() error {
+		return s.compose.StopService(ctx, s.composePath, service)
+	})
+	if err != nil {
+		logger.Error(ctx, "Idle shutdown failed",
+			logging.String("service", service),
+			logging.ErrorField(err))
+		s.notify(ctx, fmt.Sprintf("❌ **%s** の自動停止に失敗しました: %v", name, err), nil)
+		return
+	}
+
+	s.notify(ctx, fmt.Sprintf("💤 **%s** はプレイヤーがいない状態が%s続いたため停止しました", name, formatIdle(after)),
+		[]notify.Action{{Label: fmt.Sprintf("🚀 %s を起動", name), CustomID: startCustomIDPrefix + service}})
+}
+
+// notify は通知を送信する（通知先が未設定の場合は何もしない）
+func (s *Stopper) notify(ctx context.Context, content string, actions []notify.Action) {
+	if s.notifier == nil {
+		return
+	}
+	if err := notify.NotifyWithActions(ctx, s.notifier, content, actions); err != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to send idle shutdown notification", logging.ErrorField(err))
+	}
+}
+
+// formatIdle は停止するまでの時間を「2時間」「90分」の形式で返す
+func formatIdle(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d時間", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d分", int(d.Minutes()))
+}
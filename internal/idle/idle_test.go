@@ -0,0 +1,267 @@
+package idle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/gameprobe"
+)
+
+// servicePolicyFunc は関数をServicePolicyとして扱うためのアダプター
+type servicePolicyFunc func(service string) time.Duration
+
+func (f servicePolicyFunc) IdleShutdownAfter(service string) time.Duration {
+	return f(service)
+}
+
+// testEnv はテスト用のStopperと依存関係
+type testEnv struct {
+	stopper  *Stopper
+	notifier *notify.MockNotifier
+	audit    *audit.MockStore
+	locks    *oplock.Locker
+	state    string
+	players  map[string]int // サービスごとのプレイヤー数（含まれないサービスはクエリに応答しない）
+	stops    []string
+	stopErr  error
+	clock    time.Time
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := &testEnv{
+		notifier: &notify.MockNotifier{},
+		audit:    &audit.MockStore{},
+		locks:    oplock.New(),
+		state:    "running",
+		players:  map[string]int{"minecraft": 0, "valheim": 0},
+		clock:    time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC),
+	}
+	compose := &docker.MockComposeService{
+		ListGameContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{Name: "mc", Service: "minecraft", State: env.state, Labels: map[string]string{
+					"game.type": "minecraft", gameprobe.LabelQueryPort: "25565",
+				}},
+				{Name: "valheim", Service: "valheim", State: env.state, Labels: map[string]string{
+					"game.type": "valheim", gameprobe.LabelQueryPort: "2457",
+				}},
+				// クエリを設定していないサービスは対象外
+				{Name: "proxy", Service: "proxy", State: env.state, Labels: map[string]string{"game.type": "proxy"}},
+			}, nil
+		},
+		StopServiceFunc: func(_, service string) error {
+			env.stops = append(env.stops, service)
+			return env.stopErr
+		},
+	}
+	prober := &gameprobe.MockProber{
+		ProbeFunc: func(_ context.Context, target gameprobe.Target) (*gameprobe.Status, error) {
+			players, ok := env.players[target.Service]
+			if !ok {
+				return nil, errors.New("timeout")
+			}
+			return &gameprobe.Status{Players: players}, nil
+		},
+	}
+	// valheim のみ自動停止を無効にする
+	services := servicePolicyFunc(func(service string) time.Duration {
+		if service == "valheim" {
+			return 0
+		}
+		return 30 * time.Minute
+	})
+	env.stopper = New(compose, prober, env.notifier, env.audit, env.locks, services, "docker-compose.yml", time.Minute)
+	env.stopper.now = func() time.Time { return env.clock }
+	return env
+}
+
+// advance は時計を進めてからチェックを行う
+func (e *testEnv) advance(d time.Duration) {
+	e.clock = e.clock.Add(d)
+	e.stopper.poll(context.Background())
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "指定した間隔", interval: 30 * time.Second, want: 30 * time.Second},
+		{name: "0の場合はデフォルト", interval: 0, want: DefaultInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(&docker.MockComposeService{}, nil, nil, nil, nil, nil, "docker-compose.yml", tt.interval)
+			if s.interval != tt.want {
+				t.Errorf("interval = %v, want %v", s.interval, tt.want)
+			}
+			if s.prober == nil || s.locks == nil {
+				t.Error("default prober or locks not set")
+			}
+		})
+	}
+}
+
+func TestStopper_poll_StopsIdleService(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.advance(0)
+	env.advance(29 * time.Minute)
+	if len(env.stops) != 0 {
+		t.Fatalf("stopped before idle duration: %v", env.stops)
+	}
+
+	env.advance(time.Minute)
+	if !reflect.DeepEqual(env.stops, []string{"minecraft"}) {
+		t.Fatalf("stops = %v, want [minecraft]", env.stops)
+	}
+
+	msgs := env.notifier.Messages()
+	if len(msgs) != 1 || msgs[0] != "💤 **Minecraft** はプレイヤーがいない状態が30分続いたため停止しました" {
+		t.Errorf("notifications = %v", msgs)
+	}
+	wantActions := []notify.Action{{Label: "🚀 Minecraft を起動", CustomID: "start_service_minecraft"}}
+	if actions := env.notifier.Actions(); len(actions) != 1 || !reflect.DeepEqual(actions[0], wantActions) {
+		t.Errorf("actions = %v, want %v", actions, wantActions)
+	}
+
+	entries := env.audit.Entries()
+	if len(entries) != 1 || entries[0].Action != audit.ActionStop || entries[0].Source != audit.SourceIdle ||
+		entries[0].Result != audit.ResultSuccess {
+		t.Errorf("audit entries = %+v", entries)
+	}
+
+	// 停止後は改めてプレイヤーがいない状態が続くまで停止しない
+	env.advance(time.Minute)
+	if len(env.stops) != 1 {
+		t.Errorf("stopped again: %v", env.stops)
+	}
+}
+
+func TestStopper_poll_ResetsIdleTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		reset func(env *testEnv)
+	}{
+		{name: "プレイヤーが接続", reset: func(env *testEnv) { env.players["minecraft"] = 1 }},
+		{name: "クエリに応答しない", reset: func(env *testEnv) { delete(env.players, "minecraft") }},
+		{name: "コンテナが停止", reset: func(env *testEnv) { env.state = "exited" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.advance(0)
+			env.advance(20 * time.Minute)
+
+			tt.reset(env)
+			env.advance(time.Minute)
+
+			env.players["minecraft"] = 0
+			env.state = "running"
+			env.advance(time.Minute)
+			env.advance(20 * time.Minute)
+			if len(env.stops) != 0 {
+				t.Errorf("stopped although idle time was reset: %v", env.stops)
+			}
+
+			env.advance(10 * time.Minute)
+			if !reflect.DeepEqual(env.stops, []string{"minecraft"}) {
+				t.Errorf("stops = %v, want [minecraft]", env.stops)
+			}
+		})
+	}
+}
+
+func TestStopper_poll_SkipsLockedService(t *testing.T) {
+	env := newTestEnv(t)
+	env.advance(0)
+
+	env.locks.TryLock("minecraft")
+	env.advance(30 * time.Minute)
+	if len(env.stops) != 0 {
+		t.Fatalf("stopped while service was locked: %v", env.stops)
+	}
+
+	// 操作が終わった後の次のチェックで停止する
+	env.locks.Unlock("minecraft")
+	env.advance(time.Minute)
+	if !reflect.DeepEqual(env.stops, []string{"minecraft"}) {
+		t.Errorf("stops = %v, want [minecraft]", env.stops)
+	}
+}
+
+func TestStopper_poll_StopFailure(t *testing.T) {
+	env := newTestEnv(t)
+	env.stopErr = errors.New("daemon error")
+
+	env.advance(0)
+	env.advance(30 * time.Minute)
+
+	msgs := env.notifier.Messages()
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "❌ **Minecraft** の自動停止に失敗しました: daemon error") {
+		t.Errorf("notifications = %v", msgs)
+	}
+	if actions := env.notifier.Actions(); len(actions) != 1 || actions[0] != nil {
+		t.Errorf("actions = %v, want no buttons", actions)
+	}
+	if entries := env.audit.Entries(); len(entries) != 1 || entries[0].Result != audit.ResultFailure {
+		t.Errorf("audit entries = %+v", entries)
+	}
+
+	// 失敗した場合もすぐには再試行しない
+	env.advance(time.Minute)
+	if len(env.stops) != 1 {
+		t.Errorf("stops = %v, want 1 attempt", env.stops)
+	}
+}
+
+func TestStopper_Run_StopsOnCancel(t *testing.T) {
+	env := newTestEnv(t)
+	env.stopper.interval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		env.stopper.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+}
+
+func TestFormatIdle(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 2 * time.Hour, want: "2時間"},
+		{d: 30 * time.Minute, want: "30分"},
+		{d: 90 * time.Minute, want: "90分"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatIdle(tt.d); got != tt.want {
+				t.Errorf("formatIdle(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+// bytesPerGB はSystemInfoのGB単位の値をバイトに変換する係数
+const bytesPerGB = 1024 * 1024 * 1024
+
+var (
+	containerLabels = []string{"service", "name"}
+
+	hostCPUUsage = newDesc("host_cpu_usage_percent", "Host CPU usage in percent.")
+	hostMemUsed  = newDesc("host_memory_used_bytes", "Host memory in use.")
+	hostMemTotal = newDesc("host_memory_total_bytes", "Host memory total.")
+	hostMemUsage = newDesc("host_memory_used_percent", "Host memory usage in percent.")
+	hostDiskFree = newDesc("host_disk_free_bytes", "Host disk space available.")
+	hostDiskSize = newDesc("host_disk_total_bytes", "Host disk size.")
+	hostDiskUsed = newDesc("host_disk_used_percent", "Host disk usage in percent.")
+
+	containerCPUUsage   = newDesc("container_cpu_usage_percent", "Container CPU usage in percent.", containerLabels...)
+	containerMemUsage   = newDesc("container_memory_usage_bytes", "Container memory in use.", containerLabels...)
+	containerMemLimit   = newDesc("container_memory_limit_bytes", "Container memory limit.", containerLabels...)
+	containerMemPercent = newDesc("container_memory_usage_percent",
+		"Container memory usage in percent of the limit.", containerLabels...)
+	containerNetRx = newDesc("container_network_receive_bytes_total",
+		"Bytes received by the container over all networks.", containerLabels...)
+	containerNetTx = newDesc("container_network_transmit_bytes_total",
+		"Bytes transmitted by the container over all networks.", containerLabels...)
+	containerBlockRead = newDesc("container_block_read_bytes_total",
+		"Bytes read by the container from block devices.", containerLabels...)
+	containerBlockWrite = newDesc("container_block_write_bytes_total",
+		"Bytes written by the container to block devices.", containerLabels...)
+	containerPIDs     = newDesc("container_pids", "Number of processes in the container.", containerLabels...)
+	containerRestarts = newDesc("container_restarts_total",
+		"Number of times Docker restarted the container.", containerLabels...)
+
+	containerRunning = newDesc("container_running", "Whether the container is running (1) or not (0).",
+		containerLabels...)
+	containerState = newDesc("container_state", "Current container state, always 1.",
+		append(containerLabels, "state")...)
+	containerHealthy = newDesc("container_healthy",
+		"Whether the container health check passes (1) or not (0). Absent without a health check.",
+		containerLabels...)
+)
+
+// newDesc はwatchdog_を接頭辞とするメトリクスの定義を作成する
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+// ResourceCollector はホストとコンテナのリソース使用状況をスクレイプ時に収集する
+type ResourceCollector struct {
+	ctx         context.Context
+	monitor     system.Monitor
+	compose     docker.ComposeService
+	composePath string
+}
+
+// NewResourceCollector は新しいResourceCollectorを作成する
+func NewResourceCollector(
+	ctx context.Context,
+	monitor system.Monitor,
+	compose docker.ComposeService,
+	composePath string,
+) *ResourceCollector {
+	return &ResourceCollector{
+		ctx:         ctx,
+		monitor:     monitor,
+		compose:     compose,
+		composePath: composePath,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		hostCPUUsage, hostMemUsed, hostMemTotal, hostMemUsage, hostDiskFree, hostDiskSize, hostDiskUsed,
+		containerCPUUsage, containerMemUsage, containerMemLimit, containerMemPercent,
+		containerNetRx, containerNetTx, containerBlockRead, containerBlockWrite, containerPIDs, containerRestarts,
+		containerRunning, containerState, containerHealthy,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+// 取得に失敗した項目は出力せず、ログに記録する
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	logger := logging.FromContext(c.ctx)
+
+	if info, err := c.monitor.GetSystemInfo(c.ctx); err != nil {
+		logger.Warn(c.ctx, "Failed to get system info for metrics", logging.ErrorField(err))
+	} else {
+		collectHost(ch, info)
+	}
+
+	if containers, err := c.compose.ListContainers(c.ctx, c.composePath); err != nil {
+		logger.Warn(c.ctx, "Failed to list containers for metrics", logging.ErrorField(err))
+	} else {
+		for i := range containers {
+			collectContainerState(ch, &containers[i])
+		}
+	}
+
+	// 一部のコンテナで失敗した場合も取得できた分は出力する
+	stats, err := c.compose.GetAllContainersStats(c.ctx, c.composePath)
+	if err != nil {
+		logger.Warn(c.ctx, "Failed to get container stats for metrics", logging.ErrorField(err))
+	}
+	for i := range stats {
+		collectContainerStats(ch, &stats[i])
+	}
+}
+
+// collectHost はホストのメトリクスを出力する
+func collectHost(ch chan<- prometheus.Metric, info *system.SystemInfo) {
+	gauge(ch, hostCPUUsage, info.CPUUsagePercent)
+	gauge(ch, hostMemUsed, info.MemoryUsedGB*bytesPerGB)
+	gauge(ch, hostMemTotal, info.MemoryTotalGB*bytesPerGB)
+	gauge(ch, hostMemUsage, info.MemoryUsedPercent)
+	gauge(ch, hostDiskFree, info.DiskFreeGB*bytesPerGB)
+	gauge(ch, hostDiskSize, info.DiskTotalGB*bytesPerGB)
+	gauge(ch, hostDiskUsed, info.DiskUsedPercent)
+}
+
+// collectContainerState はコンテナの状態とヘルスチェックのメトリクスを出力する
+func collectContainerState(ch chan<- prometheus.Metric, info *docker.ContainerInfo) {
+	running := 0.0
+	if strings.EqualFold(info.State, docker.StateRunning) {
+		running = 1
+	}
+	gauge(ch, containerRunning, running, info.Service, info.Name)
+	gauge(ch, containerState, 1, info.Service, info.Name, strings.ToLower(info.State))
+
+	if info.HealthStatus == "" {
+		return
+	}
+	healthy := 0.0
+	if strings.EqualFold(info.HealthStatus, docker.HealthHealthy) {
+		healthy = 1
+	}
+	gauge(ch, containerHealthy, healthy, info.Service, info.Name)
+}
+
+// collectContainerStats はコンテナのリソース使用状況のメトリクスを出力する
+func collectContainerStats(ch chan<- prometheus.Metric, stats *docker.ContainerStats) {
+	labels := []string{stats.Service, stats.Name}
+	gauge(ch, containerCPUUsage, stats.CPUPercent, labels...)
+	gauge(ch, containerMemUsage, float64(stats.MemoryUsageBytes), labels...)
+	gauge(ch, containerMemLimit, float64(stats.MemoryLimitBytes), labels...)
+	gauge(ch, containerMemPercent, stats.MemoryPercent, labels...)
+	counter(ch, containerNetRx, float64(stats.NetworkRxBytes), labels...)
+	counter(ch, containerNetTx, float64(stats.NetworkTxBytes), labels...)
+	counter(ch, containerBlockRead, float64(stats.BlockReadBytes), labels...)
+	counter(ch, containerBlockWrite, float64(stats.BlockWriteBytes), labels...)
+	gauge(ch, containerPIDs, float64(stats.PIDs), labels...)
+	counter(ch, containerRestarts, float64(stats.RestartCount), labels...)
+}
+
+func gauge(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+}
+
+func counter(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+}
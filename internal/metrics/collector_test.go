@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+// assertNotContains は出力に指定した文字列が含まれないことを確認する
+func assertNotContains(t *testing.T, output string, substrs ...string) {
+	t.Helper()
+	for _, substr := range substrs {
+		if strings.Contains(output, substr) {
+			t.Errorf("metrics output unexpectedly contains %q", substr)
+		}
+	}
+}
+
+func TestResourceCollector_Collect(t *testing.T) {
+	monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{
+		CPUUsagePercent:   42.5,
+		MemoryUsedGB:      2,
+		MemoryTotalGB:     8,
+		MemoryUsedPercent: 25,
+		DiskFreeGB:        100,
+		DiskTotalGB:       200,
+		DiskUsedPercent:   50,
+	}}
+	compose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{Service: "minecraft", Name: "game-minecraft-1", State: "running", HealthStatus: "healthy"},
+				{Service: "terraria", Name: "game-terraria-1", State: "running", HealthStatus: "unhealthy"},
+				{Service: "factorio", Name: "game-factorio-1", State: "exited"},
+			}, nil
+		},
+		GetAllContainersStatsFunc: func(string) ([]docker.ContainerStats, error) {
+			return []docker.ContainerStats{{
+				Service:          "minecraft",
+				Name:             "game-minecraft-1",
+				CPUPercent:       12.5,
+				MemoryPercent:    50,
+				MemoryUsageBytes: 1024,
+				MemoryLimitBytes: 2048,
+				NetworkRxBytes:   100,
+				NetworkTxBytes:   200,
+				BlockReadBytes:   300,
+				BlockWriteBytes:  400,
+				PIDs:             24,
+				RestartCount:     2,
+			}}, nil
+		},
+	}
+
+	m := New()
+	if err := m.Register(NewResourceCollector(context.Background(), monitor, compose, "docker-compose.yml")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	output := scrape(t, m)
+	assertContains(t, output,
+		"watchdog_host_cpu_usage_percent 42.5",
+		"watchdog_host_memory_used_bytes 2.147483648e+09",
+		"watchdog_host_memory_total_bytes 8.589934592e+09",
+		"watchdog_host_memory_used_percent 25",
+		"watchdog_host_disk_used_percent 50",
+		`watchdog_container_cpu_usage_percent{name="game-minecraft-1",service="minecraft"} 12.5`,
+		`watchdog_container_memory_usage_bytes{name="game-minecraft-1",service="minecraft"} 1024`,
+		`watchdog_container_memory_limit_bytes{name="game-minecraft-1",service="minecraft"} 2048`,
+		`watchdog_container_memory_usage_percent{name="game-minecraft-1",service="minecraft"} 50`,
+		`watchdog_container_network_receive_bytes_total{name="game-minecraft-1",service="minecraft"} 100`,
+		`watchdog_container_network_transmit_bytes_total{name="game-minecraft-1",service="minecraft"} 200`,
+		`watchdog_container_block_read_bytes_total{name="game-minecraft-1",service="minecraft"} 300`,
+		`watchdog_container_block_write_bytes_total{name="game-minecraft-1",service="minecraft"} 400`,
+		`watchdog_container_running{name="game-minecraft-1",service="minecraft"} 1`,
+		`watchdog_container_running{name="game-factorio-1",service="factorio"} 0`,
+		`watchdog_container_state{name="game-factorio-1",service="factorio",state="exited"} 1`,
+		`watchdog_container_healthy{name="game-minecraft-1",service="minecraft"} 1`,
+		`watchdog_container_healthy{name="game-terraria-1",service="terraria"} 0`,
+	)
+	// ヘルスチェックがないコンテナはhealthyを出力しない
+	assertNotContains(t, output, `watchdog_container_healthy{name="game-factorio-1"`)
+}
+
+func TestResourceCollector_Collect_Errors(t *testing.T) {
+	monitor := &system.MockMonitor{Err: errors.New("failed to read /proc")}
+	compose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return nil, errors.New("docker unavailable")
+		},
+		GetAllContainersStatsFunc: func(string) ([]docker.ContainerStats, error) {
+			return nil, errors.New("docker unavailable")
+		},
+	}
+
+	m := New()
+	collector := NewResourceCollector(context.Background(), monitor, m.InstrumentCompose(compose), "docker-compose.yml")
+	if err := m.Register(collector); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// 取得に失敗した項目は出力しない
+	output := scrape(t, m)
+	assertNotContains(t, output, "watchdog_host_cpu_usage_percent ", "watchdog_container_running{")
+
+	// 失敗はDocker APIのエラーとして記録される（コレクターは並行して収集されるため次回のスクレイプで確認）
+	assertContains(t, scrape(t, m),
+		`watchdog_docker_api_errors_total{operation="list_containers"}`,
+		`watchdog_docker_api_errors_total{operation="all_containers_stats"}`,
+	)
+}
+
+func TestResourceCollector_Collect_PartialStats(t *testing.T) {
+	compose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) { return nil, nil },
+		GetAllContainersStatsFunc: func(string) ([]docker.ContainerStats, error) {
+			return []docker.ContainerStats{{Service: "minecraft", Name: "game-minecraft-1", CPUPercent: 12.5}},
+				&docker.ContainerStatsError{Failures: []*docker.DockerError{
+					docker.NewDockerContainerError("stats", "game-rust-1", errors.New("timeout")),
+				}}
+		},
+	}
+
+	m := New()
+	collector := NewResourceCollector(context.Background(), &system.MockMonitor{SystemInfo: &system.SystemInfo{}}, compose,
+		"docker-compose.yml")
+	if err := m.Register(collector); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// 一部のコンテナで失敗しても取得できた分は出力する
+	output := scrape(t, m)
+	assertContains(t, output, `watchdog_container_cpu_usage_percent{name="game-minecraft-1",service="minecraft"} 12.5`)
+	assertNotContains(t, output, `name="game-rust-1"`)
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// Docker APIの操作名（docker_api_errors_totalのoperationラベル）
+const (
+	opListContainers     = "list_containers"
+	opListGameContainers = "list_game_containers"
+	opStartService       = "start_service"
+	opStopService        = "stop_service"
+	opContainerStats     = "container_stats"
+	opAllContainersStats = "all_containers_stats"
+	opRestartContainer   = "restart_container"
+	opContainerLogs      = "container_logs"
+	opContainerEnv       = "container_env"
+	opPing               = "ping"
+)
+
+// instrumentedCompose はDocker APIの失敗を記録するComposeService
+type instrumentedCompose struct {
+	docker.ComposeService
+	metrics *Metrics
+}
+
+// InstrumentCompose はDocker APIの失敗を操作ごとに記録するComposeServiceを返す
+// mがnilの場合はcomposeをそのまま返す
+func (m *Metrics) InstrumentCompose(compose docker.ComposeService) docker.ComposeService {
+	if m == nil {
+		return compose
+	}
+	return &instrumentedCompose{ComposeService: compose, metrics: m}
+}
+
+// ListContainers returns a list of containers managed by docker-compose
+func (c *instrumentedCompose) ListContainers(ctx context.Context, composePath string) ([]docker.ContainerInfo, error) {
+	containers, err := c.ComposeService.ListContainers(ctx, composePath)
+	c.metrics.ObserveDockerError(opListContainers, err)
+	return containers, err
+}
+
+// ListGameContainers returns a list of game containers
+func (c *instrumentedCompose) ListGameContainers(
+	ctx context.Context, composePath string,
+) ([]docker.ContainerInfo, error) {
+	containers, err := c.ComposeService.ListGameContainers(ctx, composePath)
+	c.metrics.ObserveDockerError(opListGameContainers, err)
+	return containers, err
+}
+
+// StartService starts a specific service
+func (c *instrumentedCompose) StartService(ctx context.Context, composePath, serviceName string) error {
+	err := c.ComposeService.StartService(ctx, composePath, serviceName)
+	c.metrics.ObserveDockerError(opStartService, err)
+	return err
+}
+
+// StopService stops a specific service
+func (c *instrumentedCompose) StopService(ctx context.Context, composePath, serviceName string) error {
+	err := c.ComposeService.StopService(ctx, composePath, serviceName)
+	c.metrics.ObserveDockerError(opStopService, err)
+	return err
+}
+
+// GetContainerStats gets resource usage stats for a specific container
+func (c *instrumentedCompose) GetContainerStats(
+	ctx context.Context, containerName string,
+) (*docker.ContainerStats, error) {
+	stats, err := c.ComposeService.GetContainerStats(ctx, containerName)
+	c.metrics.ObserveDockerError(opContainerStats, err)
+	return stats, err
+}
+
+// GetAllContainersStats gets resource usage stats for all containers
+func (c *instrumentedCompose) GetAllContainersStats(
+	ctx context.Context, composePath string,
+) ([]docker.ContainerStats, error) {
+	stats, err := c.ComposeService.GetAllContainersStats(ctx, composePath)
+	c.metrics.ObserveDockerError(opAllContainersStats, err)
+	return stats, err
+}
+
+// RestartContainer restarts a specific container
+func (c *instrumentedCompose) RestartContainer(ctx context.Context, composePath, serviceName string) error {
+	err := c.ComposeService.RestartContainer(ctx, composePath, serviceName)
+	c.metrics.ObserveDockerError(opRestartContainer, err)
+	return err
+}
+
+// GetContainerLogs gets logs from a specific container
+func (c *instrumentedCompose) GetContainerLogs(
+	ctx context.Context, composePath, serviceName string, lines int,
+) (string, error) {
+	logs, err := c.ComposeService.GetContainerLogs(ctx, composePath, serviceName, lines)
+	c.metrics.ObserveDockerError(opContainerLogs, err)
+	return logs, err
+}
+
+// GetContainerEnv returns the environment variables of a specific container
+func (c *instrumentedCompose) GetContainerEnv(ctx context.Context, containerName string) (map[string]string, error) {
+	env, err := c.ComposeService.GetContainerEnv(ctx, containerName)
+	c.metrics.ObserveDockerError(opContainerEnv, err)
+	return env, err
+}
+
+// Ping checks whether the Docker daemon is reachable
+func (c *instrumentedCompose) Ping(ctx context.Context) error {
+	err := c.ComposeService.Ping(ctx)
+	c.metrics.ObserveDockerError(opPing, err)
+	return err
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+func TestMetrics_InstrumentCompose(t *testing.T) {
+	errDocker := errors.New("docker unavailable")
+	mock := &docker.MockComposeService{
+		ListContainersFunc:        func(string) ([]docker.ContainerInfo, error) { return nil, errDocker },
+		ListGameContainersFunc:    func(string) ([]docker.ContainerInfo, error) { return nil, nil },
+		StartServiceFunc:          func(string, string) error { return errDocker },
+		StopServiceFunc:           func(string, string) error { return errDocker },
+		GetContainerStatsFunc:     func(string) (*docker.ContainerStats, error) { return nil, errDocker },
+		GetAllContainersStatsFunc: func(string) ([]docker.ContainerStats, error) { return nil, errDocker },
+		RestartContainerFunc:      func(string, string) error { return errDocker },
+		GetContainerLogsFunc:      func(string, string, int) (string, error) { return "", errDocker },
+		GetContainerEnvFunc:       func(string) (map[string]string, error) { return nil, errDocker },
+		PingFunc:                  func() error { return errDocker },
+	}
+
+	m := New()
+	compose := m.InstrumentCompose(mock)
+
+	// エラーはそのまま呼び出し元に返す
+	if _, err := compose.ListContainers(context.Background(), "docker-compose.yml"); !errors.Is(err, errDocker) {
+		t.Errorf("ListContainers() error = %v, want %v", err, errDocker)
+	}
+	_, _ = compose.ListGameContainers(context.Background(), "docker-compose.yml")
+	_ = compose.StartService(context.Background(), "docker-compose.yml", "minecraft")
+	_ = compose.StopService(context.Background(), "docker-compose.yml", "minecraft")
+	_ = compose.StopService(context.Background(), "docker-compose.yml", "minecraft")
+	_, _ = compose.GetContainerStats(context.Background(), "minecraft")
+	_, _ = compose.GetAllContainersStats(context.Background(), "docker-compose.yml")
+	_ = compose.RestartContainer(context.Background(), "docker-compose.yml", "minecraft")
+	_, _ = compose.GetContainerLogs(context.Background(), "docker-compose.yml", "minecraft", 10)
+	_, _ = compose.GetContainerEnv(context.Background(), "minecraft-1")
+	_ = compose.Ping(context.Background())
+
+	output := scrape(t, m)
+	assertContains(t, output,
+		`watchdog_docker_api_errors_total{operation="list_containers"} 1`,
+		`watchdog_docker_api_errors_total{operation="start_service"} 1`,
+		`watchdog_docker_api_errors_total{operation="stop_service"} 2`,
+		`watchdog_docker_api_errors_total{operation="container_stats"} 1`,
+		`watchdog_docker_api_errors_total{operation="all_containers_stats"} 1`,
+		`watchdog_docker_api_errors_total{operation="restart_container"} 1`,
+		`watchdog_docker_api_errors_total{operation="container_logs"} 1`,
+		`watchdog_docker_api_errors_total{operation="container_env"} 1`,
+		`watchdog_docker_api_errors_total{operation="ping"} 1`,
+	)
+	assertNotContains(t, output, `operation="list_game_containers"`)
+}
+
+func TestMetrics_InstrumentCompose_Nil(t *testing.T) {
+	var m *Metrics
+	mock := &docker.MockComposeService{}
+
+	// nilの場合は元のComposeServiceをそのまま返す
+	if got := m.InstrumentCompose(mock); got != mock {
+		t.Errorf("InstrumentCompose() = %v, want original service", got)
+	}
+}
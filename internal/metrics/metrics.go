@@ -0,0 +1,91 @@
+// Package metrics はPrometheus形式のメトリクスを提供します
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace はメトリクス名の接頭辞
+const namespace = "watchdog"
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// Metrics はボット内部のメトリクスと、それを公開するレジストリを管理する
+// nilの場合は何も記録しない
+type Metrics struct {
+	registry        *prometheus.Registry
+	commands        *prometheus.CounterVec
+	commandDuration *prometheus.HistogramVec
+	dockerErrors    *prometheus.CounterVec
+}
+
+// New は新しいMetricsを作成する
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Number of bot commands executed.",
+		}, []string{"command", "result"}),
+		commandDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "command_duration_seconds",
+			Help:      "Time taken to execute bot commands.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"command"}),
+		dockerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "docker_api_errors_total",
+			Help:      "Number of failed Docker API operations.",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		m.commands,
+		m.commandDuration,
+		m.dockerErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Register は追加のコレクターを登録する
+func (m *Metrics) Register(c prometheus.Collector) error {
+	return m.registry.Register(c)
+}
+
+// Handler は/metricsで公開するHTTPハンドラーを返す
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveCommand はコマンドの実行回数と実行時間を記録する
+func (m *Metrics) ObserveCommand(command string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	m.commands.WithLabelValues(command, result).Inc()
+	m.commandDuration.WithLabelValues(command).Observe(duration.Seconds())
+}
+
+// ObserveDockerError はDocker APIの操作が失敗した場合に記録する
+func (m *Metrics) ObserveDockerError(operation string, err error) {
+	if m == nil || err == nil {
+		return
+	}
+	m.dockerErrors.WithLabelValues(operation).Inc()
+}
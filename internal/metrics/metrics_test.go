@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape は/metricsの出力を取得する
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return string(body)
+}
+
+// assertContains は出力にすべての行が含まれることを確認する
+func assertContains(t *testing.T, output string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(output, line) {
+			t.Errorf("metrics output does not contain %q", line)
+		}
+	}
+}
+
+func TestMetrics_ObserveCommand(t *testing.T) {
+	m := New()
+	m.ObserveCommand("monitor", 1500*time.Millisecond, nil)
+	m.ObserveCommand("monitor", 500*time.Millisecond, nil)
+	m.ObserveCommand("restart", time.Second, errors.New("failed"))
+
+	assertContains(t, scrape(t, m),
+		`watchdog_commands_total{command="monitor",result="success"} 2`,
+		`watchdog_commands_total{command="restart",result="error"} 1`,
+		`watchdog_command_duration_seconds_sum{command="monitor"} 2`,
+		`watchdog_command_duration_seconds_count{command="restart"} 1`,
+	)
+}
+
+func TestMetrics_ObserveDockerError(t *testing.T) {
+	m := New()
+	m.ObserveDockerError(opStopService, errors.New("failed"))
+	m.ObserveDockerError(opStopService, errors.New("failed"))
+	m.ObserveDockerError(opListContainers, nil)
+
+	output := scrape(t, m)
+	assertContains(t, output, `watchdog_docker_api_errors_total{operation="stop_service"} 2`)
+	if strings.Contains(output, `operation="list_containers"`) {
+		t.Error("successful operation should not be counted")
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+
+	// nilの場合は何もしない
+	m.ObserveCommand("ping", time.Second, nil)
+	m.ObserveDockerError(opStopService, errors.New("failed"))
+}
+
+func TestMetrics_Handler_GoRuntime(t *testing.T) {
+	assertContains(t, scrape(t, New()), "go_goroutines ", "process_start_time_seconds ")
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// MockNotifier はテスト用のモック実装
+type MockNotifier struct {
+	mu       sync.Mutex
+	messages []string
+	actions  [][]Action
+	Err      error
+}
+
+// Notify は送信されたメッセージを記録する
+func (m *MockNotifier) Notify(ctx context.Context, content string) error {
+	return m.NotifyWithActions(ctx, content, nil)
+}
+
+// NotifyWithActions は送信されたメッセージとボタンを記録する
+func (m *MockNotifier) NotifyWithActions(_ context.Context, content string, actions []Action) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.messages = append(m.messages, content)
+	m.actions = append(m.actions, actions)
+	return nil
+}
+
+// Messages は記録されたメッセージのコピーを返す
+func (m *MockNotifier) Messages() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	messages := make([]string, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
+
+// Actions は記録されたメッセージごとのボタンのコピーを返す（ボタンのないメッセージはnil）
+func (m *MockNotifier) Actions() [][]Action {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	actions := make([][]Action, len(m.actions))
+	copy(actions, m.actions)
+	return actions
+}
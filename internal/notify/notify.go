@@ -0,0 +1,31 @@
+// Package notify はDiscordチャンネルなどへの通知送信を抽象化します
+package notify
+
+import "context"
+
+// Notifier は通知メッセージを送信するインターフェース
+type Notifier interface {
+	// Notify は通知メッセージを送信する
+	Notify(ctx context.Context, content string) error
+}
+
+// Action は通知メッセージにボタンとして添付する操作
+type Action struct {
+	Label    string // ボタンの表示名
+	CustomID string // ボタンが押されたときに処理するインタラクションのカスタムID
+}
+
+// ActionNotifier はボタン付きの通知メッセージを送信するインターフェース
+type ActionNotifier interface {
+	Notifier
+	// NotifyWithActions はボタン付きの通知メッセージを送信する
+	NotifyWithActions(ctx context.Context, content string, actions []Action) error
+}
+
+// NotifyWithActions はNotifierがボタンに対応している場合はボタン付きで、対応していない場合はメッセージのみ送信する
+func NotifyWithActions(ctx context.Context, n Notifier, content string, actions []Action) error {
+	if an, ok := n.(ActionNotifier); ok && len(actions) > 0 {
+		return an.NotifyWithActions(ctx, content, actions)
+	}
+	return n.Notify(ctx, content)
+}
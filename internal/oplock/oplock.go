@@ -0,0 +1,41 @@
+// Package oplock はサービスごとの操作ロックを提供します
+package oplock
+
+import (
+	"sync"
+	"time"
+)
+
+// Locker はサービス名をキーとした操作ロック
+// 手動操作と自動操作が同じサービスを同時に操作しないように共有して使用する
+type Locker struct {
+	ops     sync.Map
+	lastOps sync.Map // サービス名をキーとした最後の操作完了時刻
+}
+
+// New は新しいLockerを作成する
+func New() *Locker {
+	return &Locker{}
+}
+
+// TryLock はサービスのロックを取得する。既に操作中の場合はfalseを返す
+func (l *Locker) TryLock(service string) bool {
+	_, loaded := l.ops.LoadOrStore(service, true)
+	return !loaded
+}
+
+// Unlock はサービスのロックを解放し、操作完了時刻を記録する
+func (l *Locker) Unlock(service string) {
+	l.lastOps.Store(service, time.Now())
+	l.ops.Delete(service)
+}
+
+// LastOperation はサービスに対する最後の操作の完了時刻を返す（操作がない場合はゼロ値）
+func (l *Locker) LastOperation(service string) time.Time {
+	if v, ok := l.lastOps.Load(service); ok {
+		if t, ok := v.(time.Time); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
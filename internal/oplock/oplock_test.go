@@ -0,0 +1,70 @@
+package oplock
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLocker_TryLock(t *testing.T) {
+	l := New()
+
+	if !l.TryLock("minecraft") {
+		t.Fatal("TryLock() = false on first call, want true")
+	}
+	if l.TryLock("minecraft") {
+		t.Error("TryLock() = true while locked, want false")
+	}
+	if !l.TryLock("terraria") {
+		t.Error("TryLock() = false for another service, want true")
+	}
+
+	l.Unlock("minecraft")
+	if !l.TryLock("minecraft") {
+		t.Error("TryLock() = false after Unlock, want true")
+	}
+}
+
+func TestLocker_LastOperation(t *testing.T) {
+	l := New()
+
+	if got := l.LastOperation("minecraft"); !got.IsZero() {
+		t.Errorf("LastOperation() = %v before any operation, want zero", got)
+	}
+
+	before := time.Now()
+	l.TryLock("minecraft")
+	if got := l.LastOperation("minecraft"); !got.IsZero() {
+		t.Errorf("LastOperation() = %v while locked, want zero", got)
+	}
+
+	l.Unlock("minecraft")
+	if got := l.LastOperation("minecraft"); got.Before(before) {
+		t.Errorf("LastOperation() = %v, want after %v", got, before)
+	}
+	if got := l.LastOperation("terraria"); !got.IsZero() {
+		t.Errorf("LastOperation() for another service = %v, want zero", got)
+	}
+}
+
+func TestLocker_Concurrent(t *testing.T) {
+	l := New()
+
+	var acquired int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l.TryLock("minecraft") {
+				atomic.AddInt32(&acquired, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if acquired != 1 {
+		t.Errorf("acquired = %d, want 1", acquired)
+	}
+}
@@ -0,0 +1,78 @@
+// Package permission はDiscordユーザーの権限レベルを定義します
+package permission
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level は権限レベル（大きいほど強い権限）
+type Level int
+
+const (
+	// LevelNone は権限なし
+	LevelNone Level = iota
+	// LevelViewer は状態の閲覧のみ可能
+	LevelViewer
+	// LevelOperator はサービスの起動・停止・再起動が可能
+	LevelOperator
+	// LevelAdmin はすべての操作が可能
+	LevelAdmin
+)
+
+// levelNames は設定ファイルで使用する権限レベル名
+var levelNames = map[string]Level{
+	"none":     LevelNone,
+	"viewer":   LevelViewer,
+	"operator": LevelOperator,
+	"admin":    LevelAdmin,
+}
+
+// ParseLevel は権限レベル名を解析する
+func ParseLevel(s string) (Level, error) {
+	level, ok := levelNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return LevelNone, fmt.Errorf("invalid permission level %q (expected none, viewer, operator or admin)", s)
+	}
+	return level, nil
+}
+
+// String は権限レベル名を返す
+func (l Level) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case LevelViewer:
+		return "viewer"
+	case LevelOperator:
+		return "operator"
+	case LevelAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// User は権限を判定するDiscordユーザー
+type User struct {
+	ID      string
+	RoleIDs []string
+}
+
+// Requirement は操作に必要な権限
+type Requirement struct {
+	Level Level
+	// Service はサービス単位の権限を確認する場合の対象サービス名（空の場合は全体の権限のみ）
+	Service string
+}
+
+// Checker はユーザーの権限レベルを判定する
+type Checker interface {
+	// PermissionLevel はユーザーの指定されたサービスに対する権限レベルを返す
+	PermissionLevel(user User, service string) Level
+}
+
+// Allowed はユーザーが必要な権限を持っているかを返す
+func Allowed(checker Checker, user User, req Requirement) bool {
+	return checker.PermissionLevel(user, req.Service) >= req.Level
+}
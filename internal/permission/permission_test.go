@@ -0,0 +1,78 @@
+package permission
+
+import "testing"
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Level
+		wantErr bool
+	}{
+		{input: "none", want: LevelNone},
+		{input: "viewer", want: LevelViewer},
+		{input: "Operator", want: LevelOperator},
+		{input: " admin ", want: LevelAdmin},
+		{input: "owner", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	for name, level := range levelNames {
+		if got := level.String(); got != name {
+			t.Errorf("Level(%d).String() = %q, want %q", int(level), got, name)
+		}
+	}
+	if got := Level(99).String(); got != "Level(99)" {
+		t.Errorf("Level(99).String() = %q", got)
+	}
+}
+
+// checkerFunc は関数をCheckerとして扱うためのアダプター
+type checkerFunc func(user User, service string) Level
+
+func (f checkerFunc) PermissionLevel(user User, service string) Level {
+	return f(user, service)
+}
+
+func TestAllowed(t *testing.T) {
+	// minecraftのみoperator、それ以外はviewer
+	checker := checkerFunc(func(_ User, service string) Level {
+		if service == "minecraft" {
+			return LevelOperator
+		}
+		return LevelViewer
+	})
+	user := User{ID: "user-1"}
+
+	tests := []struct {
+		name string
+		req  Requirement
+		want bool
+	}{
+		{name: "閲覧", req: Requirement{Level: LevelViewer}, want: true},
+		{name: "付与されたサービスの操作", req: Requirement{Level: LevelOperator, Service: "minecraft"}, want: true},
+		{name: "付与されていないサービスの操作", req: Requirement{Level: LevelOperator, Service: "terraria"}, want: false},
+		{name: "管理者権限", req: Requirement{Level: LevelAdmin, Service: "minecraft"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Allowed(checker, user, tt.req); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
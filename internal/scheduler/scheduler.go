@@ -0,0 +1,387 @@
+// Package scheduler はゲームサーバーの定期再起動と停止時間帯を実行する機能を提供します
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
+)
+
+const (
+	// DefaultInterval はデフォルトのチェック間隔
+	DefaultInterval = 15 * time.Second
+
+	// maxLateness はこれより遅れたイベントを実行しない（スリープ復帰時など）
+	maxLateness = 5 * time.Minute
+)
+
+// ScheduleProvider はサービスごとのスケジュールを提供する
+type ScheduleProvider interface {
+	ScheduledServices() []string
+	ServiceSchedule(service string) config.ScheduleConfig
+}
+
+// Action はスケジュールで実行する操作の種類
+type Action int
+
+const (
+	// ActionRestart はサービスを再起動する
+	ActionRestart Action = iota
+	// ActionStop はサービスを停止する
+	ActionStop
+	// ActionStart はサービスを起動する
+	ActionStart
+)
+
+// String は操作の表示名を返す
+func (a Action) String() string {
+	switch a {
+	case ActionRestart:
+		return "再起動"
+	case ActionStop:
+		return "停止"
+	case ActionStart:
+		return "起動"
+	default:
+		return "不明な操作"
+	}
+}
+
+// auditAction は監査ログに記録する操作名を返す
+func (a Action) auditAction() string {
+	switch a {
+	case ActionStop:
+		return audit.ActionStop
+	case ActionStart:
+		return audit.ActionStart
+	default:
+		return audit.ActionRestart
+	}
+}
+
+// Event はスケジュールから生成された1回分のイベント
+type Event struct {
+	At      time.Time
+	Service string
+	Action  Action
+	// Warning は事前通知の場合に操作までの残り時間（0の場合は操作そのもの）
+	Warning time.Duration
+}
+
+// Scheduler はスケジュールに従ってサービスを操作する
+type Scheduler struct {
+	compose     docker.ComposeService
+	notifier    notify.Notifier
+	recorder    audit.Recorder
+	locks       *oplock.Locker
+	schedules   ScheduleProvider
+	broadcaster *rcon.Broadcaster // 事前通知をゲーム内にも送信する（nilの場合は送信しない）
+	composePath string
+	interval    time.Duration
+	now         func() time.Time
+
+	last time.Time // 前回チェックした時刻
+}
+
+// New は新しいSchedulerを作成する
+// broadcasterを指定した場合は、事前通知をRCONでゲーム内のプレイヤーにも送信する
+func New(
+	compose docker.ComposeService,
+	notifier notify.Notifier,
+	recorder audit.Recorder,
+	locks *oplock.Locker,
+	schedules ScheduleProvider,
+	broadcaster *rcon.Broadcaster,
+	composePath string,
+	interval time.Duration,
+) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if locks == nil {
+		locks = oplock.New()
+	}
+	return &Scheduler{
+		compose:     compose,
+		notifier:    notifier,
+		recorder:    recorder,
+		locks:       locks,
+		schedules:   schedules,
+		broadcaster: broadcaster,
+		composePath: composePath,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// Run はコンテキストがキャンセルされるまでスケジュールを実行する
+func (s *Scheduler) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting scheduler",
+		logging.Any("services", s.schedules.ScheduledServices()))
+
+	// 起動前のイベントは実行しない
+	s.last = s.now()
+
+	// 停止時間帯の途中で起動した場合は、稼働しているサービスを改めて停止する
+	s.enforceStopWindows(ctx, s.last)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Scheduler stopped")
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick は前回のチェック以降に到来したイベントを実行する
+func (s *Scheduler) tick(ctx context.Context) {
+	now := s.now()
+	events := s.Events(s.last, now)
+	s.last = now
+
+	for _, e := range events {
+		if now.Sub(e.At) > maxLateness {
+			logging.FromContext(ctx).Warn(ctx, "Skipping stale scheduled event",
+				logging.String("service", e.Service),
+				logging.String("action", e.Action.String()),
+				logging.String("at", e.At.Format(time.RFC3339)))
+			continue
+		}
+		s.execute(ctx, e)
+	}
+}
+
+// Events は (from, to] の範囲に到来するイベントを時刻順に返す
+func (s *Scheduler) Events(from, to time.Time) []Event {
+	var events []Event
+	for _, service := range s.schedules.ScheduledServices() {
+		schedule := s.schedules.ServiceSchedule(service)
+		// 日付をまたぐ停止時間帯と事前通知のため前後1日も確認する
+		for day := startOfDay(from).AddDate(0, 0, -1); !day.After(to.AddDate(0, 0, 1)); day = day.AddDate(0, 0, 1) {
+			for _, e := range dayEvents(service, schedule, day) {
+				if e.At.After(from) && !e.At.After(to) {
+					events = append(events, e)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+	return events
+}
+
+// enforceStopWindows は停止時間帯の中にあるサービスのうち、稼働しているものを停止する
+func (s *Scheduler) enforceStopWindows(ctx context.Context, now time.Time) {
+	var active []string
+	for _, service := range s.schedules.ScheduledServices() {
+		if inStopWindow(s.schedules.ServiceSchedule(service), now) {
+			active = append(active, service)
+		}
+	}
+	if len(active) == 0 {
+		return
+	}
+
+	containers, err := s.compose.ListContainers(ctx, s.composePath)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to list containers for stop windows", logging.ErrorField(err))
+		return
+	}
+	running := make(map[string]bool, len(containers))
+	for i := range containers {
+		if strings.EqualFold(containers[i].State, docker.StateRunning) {
+			running[containers[i].Service] = true
+		}
+	}
+	for _, service := range active {
+		if running[service] {
+			s.execute(ctx, Event{At: now, Service: service, Action: ActionStop})
+		}
+	}
+}
+
+// stopPeriod は停止時間帯の1回分（停止する時刻から起動する時刻まで）
+type stopPeriod struct {
+	from time.Time
+	to   time.Time
+}
+
+// contains は時刻が停止時間帯の中にあるかを返す（起動する時刻は含まない）
+func (p stopPeriod) contains(t time.Time) bool {
+	return !t.Before(p.from) && t.Before(p.to)
+}
+
+// stopPeriods は指定した日に開始する停止時間帯を返す
+func stopPeriods(schedule config.ScheduleConfig, day time.Time) []stopPeriod {
+	var periods []stopPeriod
+	for _, w := range schedule.StopWindows {
+		if !w.Matches(day.Weekday()) {
+			continue
+		}
+		stopAt := w.From.On(day)
+		startAt := w.To.On(day)
+		if !startAt.After(stopAt) {
+			startAt = w.To.On(day.AddDate(0, 0, 1))
+		}
+		periods = append(periods, stopPeriod{from: stopAt, to: startAt})
+	}
+	return periods
+}
+
+// inStopWindow は時刻がいずれかの停止時間帯の中にあるかを返す（前日に開始して日付をまたぐ時間帯も含む）
+func inStopWindow(schedule config.ScheduleConfig, t time.Time) bool {
+	day := startOfDay(t)
+	for _, d := range []time.Time{day.AddDate(0, 0, -1), day} {
+		for _, p := range stopPeriods(schedule, d) {
+			if p.contains(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dayEvents は指定した日に開始するスケジュールのイベントを返す
+// 停止時間帯の中にある再起動は、停止中のサービスを起動してしまうため事前通知も含めて生成しない
+func dayEvents(service string, schedule config.ScheduleConfig, day time.Time) []Event {
+	var events []Event
+	withWarnings := func(at time.Time, action Action) {
+		for _, w := range schedule.Warnings {
+			events = append(events, Event{At: at.Add(-w), Service: service, Action: action, Warning: w})
+		}
+		events = append(events, Event{At: at, Service: service, Action: action})
+	}
+
+	for _, r := range schedule.Restarts {
+		if !r.Matches(day.Weekday()) {
+			continue
+		}
+		if at := r.At.On(day); !inStopWindow(schedule, at) {
+			withWarnings(at, ActionRestart)
+		}
+	}
+	for _, p := range stopPeriods(schedule, day) {
+		withWarnings(p.from, ActionStop)
+		events = append(events, Event{At: p.to, Service: service, Action: ActionStart})
+	}
+	return events
+}
+
+// execute はイベントを実行する
+func (s *Scheduler) execute(ctx context.Context, e Event) {
+	name := usermsg.FormatServiceName(e.Service)
+	if e.Warning > 0 {
+		s.notify(ctx, fmt.Sprintf("⏰ **%s** は%sに定期%sします", name, formatWarning(e.Warning), e.Action))
+		s.broadcast(ctx, e.Service, fmt.Sprintf("[Watchdog] %sにサーバーを定期%sします", formatWarning(e.Warning), e.Action))
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+	if !s.locks.TryLock(e.Service) {
+		logger.Warn(ctx, "Service is being operated, skipping scheduled action",
+			logging.String("service", e.Service),
+			logging.String("action", e.Action.String()))
+		s.notify(ctx, fmt.Sprintf("⚠️ **%s** は操作中のため定期%sをスキップしました", name, e.Action))
+		return
+	}
+	defer s.locks.Unlock(e.Service)
+
+	logger.Info(ctx, "Executing scheduled action",
+		logging.String("service", e.Service),
+		logging.String("action", e.Action.String()))
+
+	entry := audit.Entry{
+		Actor:   audit.SystemActor(audit.SourceScheduler),
+		Service: e.Service,
+		Action:  e.Action.auditAction(),
+		Source:  audit.SourceScheduler,
+	}
+	err := audit.Track(ctx, s.recorder, entry, func() error {
+		switch e.Action {
+		case ActionRestart:
+			return s.compose.RestartContainer(ctx, s.composePath, e.Service)
+		case ActionStop:
+			return s.compose.StopService(ctx, s.composePath, e.Service)
+		case ActionStart:
+			return s.compose.StartService(ctx, s.composePath, e.Service)
+		}
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(ctx, "Scheduled action failed",
+			logging.String("service", e.Service),
+			logging.String("action", e.Action.String()),
+			logging.ErrorField(err))
+		s.notify(ctx, fmt.Sprintf("❌ **%s** の定期%sに失敗しました: %v", name, e.Action, err))
+		return
+	}
+	s.notify(ctx, fmt.Sprintf("%s **%s** を定期%sしました", actionIcon(e.Action), name, e.Action))
+}
+
+// notify は通知を送信する（通知先が未設定の場合は何もしない）
+func (s *Scheduler) notify(ctx context.Context, content string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.Notify(ctx, content); err != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to send schedule notification", logging.ErrorField(err))
+	}
+}
+
+// broadcast はゲーム内のプレイヤーに通知する（失敗しても操作は続ける）
+func (s *Scheduler) broadcast(ctx context.Context, service, message string) {
+	if err := s.broadcaster.Broadcast(ctx, service, message); err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to broadcast schedule warning",
+			logging.String("service", service), logging.ErrorField(err))
+	}
+}
+
+// actionIcon は操作に対応するアイコンを返す
+func actionIcon(a Action) string {
+	switch a {
+	case ActionStop:
+		return "🛑"
+	case ActionStart:
+		return "🟢"
+	default:
+		return "🔄"
+	}
+}
+
+// formatWarning は残り時間を「15分後」のような表示に変換する
+func formatWarning(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%d時間後", int(d/time.Hour))
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%d分後", int(d/time.Minute))
+	default:
+		return fmt.Sprintf("%d秒後", int(d/time.Second))
+	}
+}
+
+// startOfDay は日付の0時を返す
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,341 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/config"
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/rcon"
+)
+
+// 2025-01-20 は月曜日
+var baseDay = time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+
+func at(day, hour, minute int) time.Time {
+	return baseDay.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		ScheduleWarnings: []time.Duration{15 * time.Minute, time.Minute},
+		Services: map[string]config.ServiceConfig{
+			"minecraft": {Schedule: config.ScheduleConfig{
+				Restarts: []config.RestartSchedule{{At: config.ClockTime{Hour: 3}}},
+			}},
+			"terraria": {Schedule: config.ScheduleConfig{
+				StopWindows: []config.StopWindow{{
+					From: config.ClockTime{Hour: 23},
+					To:   config.ClockTime{Hour: 6},
+					Days: []config.Weekday{config.Weekday(time.Monday)},
+				}},
+				Warnings: []time.Duration{5 * time.Minute},
+			}},
+		},
+	}
+}
+
+func TestScheduler_Events(t *testing.T) {
+	s := New(&docker.MockComposeService{}, nil, nil, nil, newTestConfig(), nil, "docker-compose.yml", 0)
+
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want []Event
+	}{
+		{
+			name: "再起動と事前通知",
+			from: at(0, 2, 0),
+			to:   at(0, 3, 0),
+			want: []Event{
+				{At: at(0, 2, 45), Service: "minecraft", Action: ActionRestart, Warning: 15 * time.Minute},
+				{At: at(0, 2, 59), Service: "minecraft", Action: ActionRestart, Warning: time.Minute},
+				{At: at(0, 3, 0), Service: "minecraft", Action: ActionRestart},
+			},
+		},
+		{
+			name: "開始時刻ちょうどのイベントは含まない",
+			from: at(0, 3, 0),
+			to:   at(0, 4, 0),
+			want: nil,
+		},
+		{
+			name: "日付をまたぐ停止時間帯（月曜のみ）",
+			from: at(0, 22, 0),
+			to:   at(1, 7, 0),
+			want: []Event{
+				{At: at(0, 22, 55), Service: "terraria", Action: ActionStop, Warning: 5 * time.Minute},
+				{At: at(0, 23, 0), Service: "terraria", Action: ActionStop},
+				{At: at(1, 2, 45), Service: "minecraft", Action: ActionRestart, Warning: 15 * time.Minute},
+				{At: at(1, 2, 59), Service: "minecraft", Action: ActionRestart, Warning: time.Minute},
+				{At: at(1, 3, 0), Service: "minecraft", Action: ActionRestart},
+				{At: at(1, 6, 0), Service: "terraria", Action: ActionStart},
+			},
+		},
+		{
+			name: "曜日が一致しない日は停止しない",
+			from: at(1, 22, 0),
+			to:   at(1, 23, 30),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Events(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Events() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_Events_SkipsRestartsInStopWindow(t *testing.T) {
+	cfg := &config.Config{
+		ScheduleWarnings: []time.Duration{15 * time.Minute},
+		Services: map[string]config.ServiceConfig{
+			"minecraft": {Schedule: config.ScheduleConfig{
+				Restarts: []config.RestartSchedule{
+					{At: config.ClockTime{Hour: 3}},
+					{At: config.ClockTime{Hour: 12}},
+				},
+				// 月曜のみ23時〜翌4時に停止
+				StopWindows: []config.StopWindow{{
+					From: config.ClockTime{Hour: 23},
+					To:   config.ClockTime{Hour: 4},
+					Days: []config.Weekday{config.Weekday(time.Monday)},
+				}},
+			}},
+		},
+	}
+	s := New(&docker.MockComposeService{}, nil, nil, nil, cfg, nil, "docker-compose.yml", 0)
+
+	// 火曜3時の再起動は月曜から続く停止時間帯の中にあるため、事前通知も含めて実行しない
+	got := s.Events(at(1, 0, 0), at(1, 13, 0))
+	want := []Event{
+		{At: at(1, 4, 0), Service: "minecraft", Action: ActionStart},
+		{At: at(1, 11, 45), Service: "minecraft", Action: ActionRestart, Warning: 15 * time.Minute},
+		{At: at(1, 12, 0), Service: "minecraft", Action: ActionRestart},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Events() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// 停止時間帯のない水曜は再起動する
+	if got := s.Events(at(2, 2, 0), at(2, 3, 0)); len(got) != 2 {
+		t.Errorf("Events() on Wednesday = %+v, want restart and warning", got)
+	}
+}
+
+func TestScheduler_enforceStopWindows(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   time.Time
+		state string
+		want  []string
+	}{
+		{name: "停止時間帯に稼働中", now: at(0, 23, 30), state: "running", want: []string{"stop terraria"}},
+		{name: "日付をまたいだ停止時間帯に稼働中", now: at(1, 5, 0), state: "running", want: []string{"stop terraria"}},
+		{name: "停止時間帯に停止中", now: at(0, 23, 30), state: "exited", want: nil},
+		{name: "停止時間帯の外", now: at(1, 7, 0), state: "running", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []string
+			compose := &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return []docker.ContainerInfo{
+						{Service: "terraria", State: tt.state},
+						{Service: "minecraft", State: "running"},
+					}, nil
+				},
+				StopServiceFunc: func(_, service string) error {
+					operations = append(operations, "stop "+service)
+					return nil
+				},
+			}
+			s := New(compose, &notify.MockNotifier{}, nil, nil, newTestConfig(), nil, "docker-compose.yml", 0)
+			s.enforceStopWindows(context.Background(), tt.now)
+			if !reflect.DeepEqual(operations, tt.want) {
+				t.Errorf("operations = %v, want %v", operations, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_tick(t *testing.T) {
+	var operations []string
+	compose := &docker.MockComposeService{
+		RestartContainerFunc: func(_, service string) error {
+			operations = append(operations, "restart "+service)
+			return nil
+		},
+		StopServiceFunc: func(_, service string) error {
+			operations = append(operations, "stop "+service)
+			return errors.New("stop failed")
+		},
+	}
+	notifier := &notify.MockNotifier{}
+	store := &audit.MockStore{}
+	s := New(compose, notifier, store, nil, newTestConfig(), nil, "docker-compose.yml", time.Minute)
+
+	clock := at(0, 2, 50)
+	s.now = func() time.Time { return clock }
+	s.last = clock
+
+	// 事前通知
+	clock = at(0, 2, 59)
+	s.tick(context.Background())
+	if len(operations) != 0 {
+		t.Fatalf("operations before scheduled time: %v", operations)
+	}
+	if msgs := notifier.Messages(); len(msgs) != 1 || msgs[0] != "⏰ **Minecraft** は1分後に定期再起動します" {
+		t.Errorf("warning notifications = %v", msgs)
+	}
+
+	// 再起動
+	clock = at(0, 3, 0)
+	s.tick(context.Background())
+	if !reflect.DeepEqual(operations, []string{"restart minecraft"}) {
+		t.Errorf("operations = %v, want [restart minecraft]", operations)
+	}
+	if msgs := notifier.Messages(); len(msgs) != 2 || msgs[1] != "🔄 **Minecraft** を定期再起動しました" {
+		t.Errorf("restart notifications = %v", msgs)
+	}
+
+	// 停止の失敗を通知
+	clock = at(0, 23, 0)
+	s.last = at(0, 22, 59)
+	s.tick(context.Background())
+	msgs := notifier.Messages()
+	if !strings.Contains(msgs[len(msgs)-1], "❌ **Terraria** の定期停止に失敗しました: stop failed") {
+		t.Errorf("failure notification not sent: %v", msgs)
+	}
+
+	// 事前通知は記録せず、操作のみ記録する
+	var recorded []string
+	for _, e := range store.Entries() {
+		recorded = append(recorded, e.Action+" "+e.Service+" "+e.Result)
+		if e.Source != audit.SourceScheduler || e.Username != audit.SourceScheduler {
+			t.Errorf("unexpected audit entry: %+v", e)
+		}
+	}
+	if want := []string{"restart minecraft success", "stop terraria failure"}; !reflect.DeepEqual(recorded, want) {
+		t.Errorf("audit entries = %v, want %v", recorded, want)
+	}
+}
+
+func TestScheduler_tick_SkipsLockedAndStaleEvents(t *testing.T) {
+	restarted := false
+	compose := &docker.MockComposeService{
+		RestartContainerFunc: func(_, _ string) error {
+			restarted = true
+			return nil
+		},
+	}
+	notifier := &notify.MockNotifier{}
+	locks := oplock.New()
+	s := New(compose, notifier, nil, locks, newTestConfig(), nil, "docker-compose.yml", time.Minute)
+
+	// 手動操作中
+	locks.TryLock("minecraft")
+	s.last = at(0, 2, 59)
+	s.now = func() time.Time { return at(0, 3, 0) }
+	s.tick(context.Background())
+	if restarted {
+		t.Error("restarted while service was locked")
+	}
+	if msgs := notifier.Messages(); len(msgs) != 1 || !strings.Contains(msgs[0], "操作中のため定期再起動をスキップしました") {
+		t.Errorf("skip notification = %v", msgs)
+	}
+	locks.Unlock("minecraft")
+
+	// スリープ復帰などで大きく遅れたイベントは実行しない
+	s.last = at(1, 2, 0)
+	s.now = func() time.Time { return at(1, 4, 0) }
+	s.tick(context.Background())
+	if restarted {
+		t.Error("stale scheduled restart was executed")
+	}
+}
+
+func TestScheduler_tick_Broadcast(t *testing.T) {
+	compose := &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{{
+				Name:    "mc",
+				Service: "minecraft",
+				State:   "running",
+				Labels:  map[string]string{rcon.LabelPort: "25575", rcon.LabelPassword: "secret"},
+			}}, nil
+		},
+	}
+	var commands []string
+	executor := &rcon.MockExecutor{
+		ExecuteFunc: func(_ context.Context, _ rcon.Target, command string) (string, error) {
+			commands = append(commands, command)
+			return "", nil
+		},
+	}
+	broadcaster := rcon.NewBroadcaster(rcon.NewRunner(compose, "docker-compose.yml", executor), nil)
+	s := New(compose, &notify.MockNotifier{}, nil, nil, newTestConfig(), broadcaster, "docker-compose.yml", time.Minute)
+
+	// 事前通知のみゲーム内にも通知する
+	s.last = at(0, 2, 44)
+	s.now = func() time.Time { return at(0, 2, 45) }
+	s.tick(context.Background())
+	s.last = at(0, 2, 59)
+	s.now = func() time.Time { return at(0, 3, 0) }
+	s.tick(context.Background())
+
+	want := []string{"say [Watchdog] 15分後にサーバーを定期再起動します"}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("broadcast commands = %q, want %q", commands, want)
+	}
+}
+
+func TestFormatWarning(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 2 * time.Hour, want: "2時間後"},
+		{d: 15 * time.Minute, want: "15分後"},
+		{d: 90 * time.Minute, want: "90分後"},
+		{d: 30 * time.Second, want: "30秒後"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatWarning(tt.d); got != tt.want {
+				t.Errorf("formatWarning(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_Run_StopsOnCancel(t *testing.T) {
+	s := New(&docker.MockComposeService{}, nil, nil, nil, newTestConfig(), nil, "docker-compose.yml", time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+}
@@ -0,0 +1,298 @@
+// Package supervisor はクラッシュまたは異常状態のゲームコンテナを自動的に再起動する機能を提供します
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// DefaultInterval はデフォルトのチェック間隔
+	DefaultInterval = 30 * time.Second
+)
+
+// ServicePolicy はサービスごとに自動再起動が有効かを判定する
+type ServicePolicy interface {
+	AutoRestartEnabled(service string) bool
+}
+
+// Policy は自動再起動の試行回数と間隔の設定
+type Policy struct {
+	// MaxAttempts はWindow内に試行する最大回数
+	MaxAttempts int
+	// Window は試行回数を数える期間
+	Window time.Duration
+	// Retry は試行間隔の設定（BaseDelay・MaxDelay・Backoffを使用）
+	Retry docker.RetryConfig
+}
+
+// restartState はサービスごとの自動再起動の状態
+type restartState struct {
+	attempts      []time.Time // Window内の試行時刻
+	nextAttempt   time.Time   // 次に試行できる時刻
+	recovering    bool        // 再起動シーケンス中か
+	gaveUp        bool        // 試行回数の上限に達して諦めたか
+	seenRunning   bool        // 前回のチェックで実行中だったか
+	lastRunningAt time.Time   // 最後に実行中を確認した時刻
+	lastAttemptAt time.Time   // 最後の再起動の試行を終えた時刻（操作ロックに記録された完了時刻）
+}
+
+// Supervisor はゲームコンテナを監視し、停止・異常を検知したら再起動する
+type Supervisor struct {
+	compose     docker.ComposeService
+	notifier    notify.Notifier
+	recorder    audit.Recorder
+	locks       *oplock.Locker
+	services    ServicePolicy
+	policy      Policy
+	composePath string
+	interval    time.Duration
+	now         func() time.Time
+
+	states map[string]*restartState
+}
+
+// New は新しいSupervisorを作成する
+func New(
+	compose docker.ComposeService,
+	notifier notify.Notifier,
+	recorder audit.Recorder,
+	locks *oplock.Locker,
+	services ServicePolicy,
+	policy Policy,
+	composePath string,
+	interval time.Duration,
+) *Supervisor {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if locks == nil {
+		locks = oplock.New()
+	}
+	if policy.Retry.Backoff == nil {
+		policy.Retry.Backoff = docker.ExponentialBackoff
+	}
+	return &Supervisor{
+		compose:     compose,
+		notifier:    notifier,
+		recorder:    recorder,
+		locks:       locks,
+		services:    services,
+		policy:      policy,
+		composePath: composePath,
+		interval:    interval,
+		now:         time.Now,
+		states:      make(map[string]*restartState),
+	}
+}
+
+// Run はコンテキストがキャンセルされるまで定期的にチェックを行う
+func (s *Supervisor) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting auto-restart supervisor",
+		logging.String("interval", s.interval.String()),
+		logging.Int("max_attempts", s.policy.MaxAttempts),
+		logging.String("window", s.policy.Window.String()))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Auto-restart supervisor stopped")
+			return
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+// poll は1回分のチェックを行い、必要であれば再起動する
+func (s *Supervisor) poll(ctx context.Context) {
+	containers, err := s.compose.ListGameContainers(ctx, s.composePath)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to list game containers", logging.ErrorField(err))
+		return
+	}
+
+	now := s.now()
+	for i := range containers {
+		s.check(ctx, &containers[i], now)
+	}
+}
+
+// check は1コンテナ分の状態を確認し、必要であれば再起動する
+func (s *Supervisor) check(ctx context.Context, c *docker.ContainerInfo, now time.Time) {
+	st := s.state(c.Service)
+	st.prune(now, s.policy.Window)
+
+	reason := s.restartReason(c, st)
+	if strings.EqualFold(c.State, docker.StateRunning) {
+		st.seenRunning = true
+		st.lastRunningAt = now
+	} else {
+		st.seenRunning = false
+	}
+
+	if reason == "" {
+		// 正常に戻ったら再起動シーケンスを終了する（試行履歴はWindowが過ぎるまで保持する）
+		if strings.EqualFold(c.State, docker.StateRunning) && !isUnhealthy(c) {
+			st.recovering = false
+			st.gaveUp = false
+		}
+		return
+	}
+	if !s.services.AutoRestartEnabled(c.Service) {
+		return
+	}
+
+	st.recovering = true
+	s.tryRestart(ctx, c.Service, reason, st, now)
+}
+
+// restartReason は再起動が必要な理由を返す（不要な場合は空文字）
+func (s *Supervisor) restartReason(c *docker.ContainerInfo, st *restartState) string {
+	stopped := strings.EqualFold(c.State, docker.StateExited)
+	lastOp := s.locks.LastOperation(c.Service)
+
+	switch {
+	case stopped && st.recovering && lastOp.After(st.lastAttemptAt):
+		// 自動再起動の後に手動操作や予定された停止などで停止された
+		return ""
+	case isUnhealthy(c):
+		return "ヘルスチェック異常"
+	case !stopped:
+		return ""
+	case st.recovering:
+		// 自動再起動後も停止したまま
+		return fmt.Sprintf("コンテナが停止 (状態: %s)", c.State)
+	case !st.seenRunning:
+		// 起動時から停止していたコンテナや意図的に停止されたコンテナは対象外
+		return ""
+	case lastOp.After(st.lastRunningAt):
+		// 前回のチェック以降に手動操作などで停止された
+		return ""
+	default:
+		return fmt.Sprintf("コンテナが停止 (状態: %s)", c.State)
+	}
+}
+
+// tryRestart は試行回数と間隔の制限内で再起動を試みる
+func (s *Supervisor) tryRestart(ctx context.Context, service, reason string, st *restartState, now time.Time) {
+	logger := logging.FromContext(ctx)
+	name := usermsg.FormatServiceName(service)
+
+	// 一度諦めたサービスは正常に戻るまで再試行しない
+	if st.gaveUp {
+		return
+	}
+	if len(st.attempts) >= s.policy.MaxAttempts {
+		st.gaveUp = true
+		logger.Warn(ctx, "Auto-restart gave up",
+			logging.String("service", service),
+			logging.Int("attempts", len(st.attempts)))
+		s.notify(ctx, fmt.Sprintf("🛑 **%s** の自動再起動を中止しました（%v以内に%d回試行）。手動で確認してください",
+			name, s.policy.Window, len(st.attempts)))
+		return
+	}
+	if now.Before(st.nextAttempt) {
+		return
+	}
+
+	if !s.locks.TryLock(service) {
+		logger.Debug(ctx, "Service is being operated, skipping auto-restart",
+			logging.String("service", service))
+		return
+	}
+	defer func() {
+		s.locks.Unlock(service)
+		// 自身の操作の完了時刻を記録し、以降の手動操作と区別する
+		st.lastAttemptAt = s.locks.LastOperation(service)
+	}()
+
+	st.attempts = append(st.attempts, now)
+	attempt := len(st.attempts)
+	st.nextAttempt = now.Add(s.policy.delay(attempt - 1))
+
+	logger.Info(ctx, "Auto-restarting service",
+		logging.String("service", service),
+		logging.String("reason", reason),
+		logging.Int("attempt", attempt))
+
+	entry := audit.Entry{
+		Actor:   audit.SystemActor(audit.SourceSupervisor),
+		Service: service,
+		Action:  audit.ActionRestart,
+		Source:  audit.SourceSupervisor,
+	}
+	err := audit.Track(ctx, s.recorder, entry, func() error {
+		return s.compose.RestartContainer(ctx, s.composePath, service)
+	})
+	if err != nil {
+		logger.Error(ctx, "Auto-restart failed",
+			logging.String("service", service),
+			logging.ErrorField(err))
+		s.notify(ctx, fmt.Sprintf("❌ **%s** の自動再起動に失敗しました (%d/%d回目, 理由: %s): %v",
+			name, attempt, s.policy.MaxAttempts, reason, err))
+		return
+	}
+
+	s.notify(ctx, fmt.Sprintf("🔄 **%s** を自動再起動しました (%d/%d回目, 理由: %s)",
+		name, attempt, s.policy.MaxAttempts, reason))
+}
+
+// notify は通知を送信する（通知先が未設定の場合は何もしない）
+func (s *Supervisor) notify(ctx context.Context, content string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.Notify(ctx, content); err != nil {
+		logging.FromContext(ctx).Error(ctx, "Failed to send auto-restart notification", logging.ErrorField(err))
+	}
+}
+
+// state はサービスの状態を返す（存在しない場合は作成する）
+func (s *Supervisor) state(service string) *restartState {
+	st, ok := s.states[service]
+	if !ok {
+		st = &restartState{}
+		s.states[service] = st
+	}
+	return st
+}
+
+// prune はWindowより古い試行履歴を削除する
+func (st *restartState) prune(now time.Time, window time.Duration) {
+	kept := st.attempts[:0]
+	for _, t := range st.attempts {
+		if now.Sub(t) < window {
+			kept = append(kept, t)
+		}
+	}
+	st.attempts = kept
+}
+
+// delay は試行回数に応じた次の試行までの間隔を返す
+func (p Policy) delay(attempt int) time.Duration {
+	d := p.Retry.Backoff(attempt, p.Retry.BaseDelay)
+	if p.Retry.MaxDelay > 0 && d > p.Retry.MaxDelay {
+		return p.Retry.MaxDelay
+	}
+	return d
+}
+
+// isUnhealthy はコンテナのヘルスチェックが異常かを返す
+func isUnhealthy(c *docker.ContainerInfo) bool {
+	return strings.EqualFold(c.HealthStatus, docker.HealthUnhealthy)
+}
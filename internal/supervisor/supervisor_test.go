@@ -0,0 +1,297 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/audit"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/internal/oplock"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// servicePolicyFunc は関数をServicePolicyとして扱うためのアダプター
+type servicePolicyFunc func(service string) bool
+
+func (f servicePolicyFunc) AutoRestartEnabled(service string) bool {
+	return f(service)
+}
+
+// allEnabled はすべてのサービスで自動再起動を有効にする
+var allEnabled = servicePolicyFunc(func(string) bool { return true })
+
+// testEnv はテスト用のSupervisorと依存関係
+type testEnv struct {
+	sup        *Supervisor
+	notifier   *notify.MockNotifier
+	audit      *audit.MockStore
+	locks      *oplock.Locker
+	containers []docker.ContainerInfo
+	restarts   []string
+	restartErr error
+	clock      time.Time
+}
+
+func newTestEnv(t *testing.T, services ServicePolicy) *testEnv {
+	t.Helper()
+
+	env := &testEnv{
+		notifier: &notify.MockNotifier{},
+		audit:    &audit.MockStore{},
+		locks:    oplock.New(),
+		clock:    time.Now(),
+	}
+	compose := &docker.MockComposeService{
+		ListGameContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return env.containers, nil
+		},
+		RestartContainerFunc: func(_, service string) error {
+			env.restarts = append(env.restarts, service)
+			return env.restartErr
+		},
+	}
+	policy := Policy{
+		MaxAttempts: 2,
+		Window:      time.Hour,
+		Retry: docker.RetryConfig{
+			BaseDelay: 10 * time.Second,
+			MaxDelay:  docker.MaxRetryDelay,
+			Backoff:   docker.LinearBackoff,
+		},
+	}
+	env.sup = New(compose, env.notifier, env.audit, env.locks, services, policy, "docker-compose.yml", time.Minute)
+	env.sup.now = func() time.Time { return env.clock }
+	return env
+}
+
+// advance は時計を進めてからチェックを行う
+func (e *testEnv) advance(d time.Duration) {
+	e.clock = e.clock.Add(d)
+	e.sup.poll(context.Background())
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "指定した間隔", interval: time.Minute, want: time.Minute},
+		{name: "0の場合はデフォルト", interval: 0, want: DefaultInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(&docker.MockComposeService{}, nil, nil, nil, allEnabled, Policy{}, "docker-compose.yml", tt.interval)
+			if s.interval != tt.want {
+				t.Errorf("New() interval = %v, want %v", s.interval, tt.want)
+			}
+			if s.locks == nil {
+				t.Error("New() locks not initialized")
+			}
+			if s.policy.Retry.Backoff == nil {
+				t.Error("New() backoff not initialized")
+			}
+		})
+	}
+}
+
+func TestSupervisor_poll_RestartsCrashedContainer(t *testing.T) {
+	env := newTestEnv(t, allEnabled)
+
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "running"}}
+	env.advance(0)
+	if len(env.restarts) != 0 {
+		t.Fatalf("running container restarted: %v", env.restarts)
+	}
+
+	// クラッシュを検知して再起動
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "exited"}}
+	env.advance(time.Minute)
+	if len(env.restarts) != 1 {
+		t.Fatalf("restarts = %v, want 1 restart", env.restarts)
+	}
+	messages := env.notifier.Messages()
+	if len(messages) != 1 || !strings.Contains(messages[0], "🔄 **Minecraft** を自動再起動しました (1/2回目") {
+		t.Errorf("unexpected notifications: %v", messages)
+	}
+	if entries := env.audit.Entries(); len(entries) != 1 ||
+		entries[0].Source != audit.SourceSupervisor || entries[0].Result != audit.ResultSuccess {
+		t.Errorf("audit entries = %+v", entries)
+	}
+
+	// 復旧後は何もしない
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "running"}}
+	env.advance(time.Minute)
+	if len(env.restarts) != 1 {
+		t.Errorf("restarts after recovery = %v, want 1", env.restarts)
+	}
+}
+
+func TestSupervisor_poll_BackoffAndGiveUp(t *testing.T) {
+	env := newTestEnv(t, allEnabled)
+	env.restartErr = errors.New("container failed to start")
+
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "running"}}
+	env.advance(0)
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "exited"}}
+
+	// 1回目の試行
+	env.advance(time.Minute)
+	if len(env.restarts) != 1 {
+		t.Fatalf("restarts = %d, want 1", len(env.restarts))
+	}
+	if msg := env.notifier.Messages()[0]; !strings.Contains(msg, "❌ **Minecraft** の自動再起動に失敗しました (1/2回目") {
+		t.Errorf("unexpected notification: %s", msg)
+	}
+
+	// バックオフ期間中（LinearBackoff: 10秒）は再試行しない
+	env.advance(5 * time.Second)
+	if len(env.restarts) != 1 {
+		t.Fatalf("restarted during backoff, restarts = %d", len(env.restarts))
+	}
+
+	// バックオフ経過後に2回目の試行
+	env.advance(5 * time.Second)
+	if len(env.restarts) != 2 {
+		t.Fatalf("restarts = %d, want 2", len(env.restarts))
+	}
+
+	// 上限に達したら諦めて通知（以降は通知しない）
+	env.advance(10 * time.Minute)
+	env.advance(10 * time.Minute)
+	if len(env.restarts) != 2 {
+		t.Errorf("restarts after give up = %d, want 2", len(env.restarts))
+	}
+	messages := env.notifier.Messages()
+	if len(messages) != 3 {
+		t.Fatalf("notifications = %d, want 3: %v", len(messages), messages)
+	}
+	if !strings.Contains(messages[2], "🛑 **Minecraft** の自動再起動を中止しました") {
+		t.Errorf("give-up notification not sent: %s", messages[2])
+	}
+
+	// Windowが過ぎても正常に戻るまでは再試行しない
+	env.advance(2 * time.Hour)
+	if len(env.restarts) != 2 {
+		t.Errorf("restarted after give up, restarts = %d", len(env.restarts))
+	}
+}
+
+func TestSupervisor_poll_Unhealthy(t *testing.T) {
+	env := newTestEnv(t, allEnabled)
+
+	// unhealthyは初回から再起動対象
+	env.containers = []docker.ContainerInfo{{Service: "terraria", State: "running", HealthStatus: "unhealthy"}}
+	env.advance(0)
+	if len(env.restarts) != 1 || env.restarts[0] != "terraria" {
+		t.Fatalf("restarts = %v, want [terraria]", env.restarts)
+	}
+	if msg := env.notifier.Messages()[0]; !strings.Contains(msg, "理由: ヘルスチェック異常") {
+		t.Errorf("notification does not contain reason: %s", msg)
+	}
+}
+
+func TestSupervisor_poll_SkipsIntentionalStops(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(env *testEnv)
+	}{
+		{
+			name: "起動時から停止しているコンテナ",
+			setup: func(env *testEnv) {
+				env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "exited"}}
+			},
+		},
+		{
+			name: "手動操作で停止されたコンテナ",
+			setup: func(env *testEnv) {
+				env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "running"}}
+				env.advance(0)
+				// 停止ボタンなどによる操作
+				env.locks.TryLock("minecraft")
+				env.locks.Unlock("minecraft")
+				env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "exited"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, allEnabled)
+			env.clock = time.Now().Add(-time.Hour)
+			tt.setup(env)
+
+			env.advance(time.Minute)
+			env.advance(time.Minute)
+			if len(env.restarts) != 0 {
+				t.Errorf("intentionally stopped container restarted: %v", env.restarts)
+			}
+		})
+	}
+}
+
+func TestSupervisor_poll_SkipsStopDuringRecovery(t *testing.T) {
+	env := newTestEnv(t, allEnabled)
+
+	// 異常を検知して再起動したが、まだ回復していない
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "running", HealthStatus: "unhealthy"}}
+	env.advance(0)
+	if len(env.restarts) != 1 {
+		t.Fatalf("restarts = %v, want 1 restart", env.restarts)
+	}
+
+	// 回復中に停止ボタンや予定された停止で停止された
+	env.locks.TryLock("minecraft")
+	env.locks.Unlock("minecraft")
+	env.containers = []docker.ContainerInfo{{Service: "minecraft", State: "exited"}}
+	env.advance(time.Minute)
+	env.advance(time.Minute)
+	if len(env.restarts) != 1 {
+		t.Errorf("container stopped during recovery restarted: %v", env.restarts)
+	}
+}
+
+func TestSupervisor_poll_RespectsLockAndServicePolicy(t *testing.T) {
+	env := newTestEnv(t, servicePolicyFunc(func(service string) bool { return service != "terraria" }))
+
+	env.containers = []docker.ContainerInfo{
+		{Service: "minecraft", State: "running", HealthStatus: "unhealthy"},
+		{Service: "terraria", State: "running", HealthStatus: "unhealthy"},
+	}
+
+	// 手動操作中は再起動しない
+	env.locks.TryLock("minecraft")
+	env.advance(0)
+	if len(env.restarts) != 0 {
+		t.Fatalf("restarted while locked or disabled: %v", env.restarts)
+	}
+
+	// ロック解放後は再起動する（無効なサービスは対象外）
+	env.locks.Unlock("minecraft")
+	env.advance(time.Minute)
+	if len(env.restarts) != 1 || env.restarts[0] != "minecraft" {
+		t.Errorf("restarts = %v, want [minecraft]", env.restarts)
+	}
+}
+
+func TestSupervisor_Run_StopsOnCancel(t *testing.T) {
+	s := New(&docker.MockComposeService{}, nil, nil, nil, allEnabled, Policy{}, "docker-compose.yml", time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+}
@@ -0,0 +1,302 @@
+// Package watcher はシステムとゲームサーバーを定期的に監視し、異常を通知する機能を提供します
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/alert"
+	"github.com/hideA88/game-server-watchdog/internal/bot/usermsg"
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+const (
+	// DefaultInterval はデフォルトの監視間隔
+	DefaultInterval = 5 * time.Minute
+)
+
+// Watcher はシステムとゲームコンテナを定期的にチェックし、状態の変化を通知する
+type Watcher struct {
+	monitor     system.Monitor
+	compose     docker.ComposeService
+	notifier    notify.Notifier
+	composePath string
+	interval    time.Duration
+	thresholds  alert.ThresholdProvider
+	now         func() time.Time
+
+	mu             sync.Mutex
+	skipContainers bool // コンテナの状態変化を通知しない
+	initialized    bool
+	lastPoll       time.Time // 最後に監視に成功した時刻
+	dockerFailed   bool
+	containers     map[string]docker.ContainerInfo // サービス名をキーとした前回のコンテナ状態
+	alerts         map[string]alert.Alert          // 前回発生していたアラート
+}
+
+// New は新しいWatcherを作成する
+func New(
+	monitor system.Monitor,
+	compose docker.ComposeService,
+	notifier notify.Notifier,
+	thresholds alert.ThresholdProvider,
+	composePath string,
+	interval time.Duration,
+) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if thresholds == nil {
+		thresholds = alert.DefaultThresholds()
+	}
+	return &Watcher{
+		monitor:     monitor,
+		compose:     compose,
+		notifier:    notifier,
+		composePath: composePath,
+		interval:    interval,
+		thresholds:  thresholds,
+		now:         time.Now,
+		containers:  make(map[string]docker.ContainerInfo),
+		alerts:      make(map[string]alert.Alert),
+	}
+}
+
+// Run はコンテキストがキャンセルされるまで定期的に監視を行う
+func (w *Watcher) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting health check watcher",
+		logging.String("interval", w.interval.String()))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "Health check watcher stopped")
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll は1回分の監視を行い、変化があれば通知する
+func (w *Watcher) poll(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	sysInfo, sysErr := w.monitor.GetSystemInfo(ctx)
+	if sysErr != nil {
+		logger.Warn(ctx, "Failed to get system info", logging.ErrorField(sysErr))
+		sysInfo = nil
+	}
+
+	containers, containerErr := w.compose.ListGameContainers(ctx, w.composePath)
+	if containerErr != nil {
+		logger.Warn(ctx, "Failed to list game containers", logging.ErrorField(containerErr))
+	}
+
+	stats, statsErr := w.compose.GetAllContainersStats(ctx, w.composePath)
+	if statsErr != nil {
+		logger.Warn(ctx, "Failed to get container stats", logging.ErrorField(statsErr))
+	}
+
+	w.mu.Lock()
+	var events []string
+	events = append(events, w.diffDocker(containerErr)...)
+	if containerErr == nil {
+		changes := w.diffContainers(containers)
+		if !w.skipContainers {
+			events = append(events, changes...)
+		}
+	}
+	events = append(events, w.diffAlerts(alert.Check(sysInfo, stats, w.thresholds), unmeasured(sysErr, statsErr))...)
+	w.initialized = true
+	if sysErr == nil && containerErr == nil {
+		w.lastPoll = w.now()
+	}
+	w.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := w.notifier.Notify(ctx, buildMessage(events)); err != nil {
+		logger.Error(ctx, "Failed to send alert notification", logging.ErrorField(err))
+	}
+}
+
+// SkipContainerChanges はコンテナの起動・停止とヘルスチェックの変化を通知しないようにする
+// Dockerのイベントで状態変化を通知する場合に、同じ変化を二重に通知しないために使用する
+func (w *Watcher) SkipContainerChanges() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.skipContainers = true
+}
+
+// LastPoll は最後にシステム情報とコンテナ情報の取得に成功した時刻を返す（未成功の場合はゼロ値）
+func (w *Watcher) LastPoll() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastPoll
+}
+
+// diffDocker はDockerへの接続状態の変化を検出する
+func (w *Watcher) diffDocker(err error) []string {
+	switch {
+	case err != nil && !w.dockerFailed:
+		w.dockerFailed = true
+		if docker.IsPermissionDenied(err) {
+			return []string{"⚠️ Docker権限エラーのためコンテナ情報を取得できません"}
+		}
+		return []string{fmt.Sprintf("⚠️ コンテナ情報の取得に失敗しました: %v", err)}
+	case err == nil && w.dockerFailed:
+		w.dockerFailed = false
+		return []string{"✅ コンテナ情報の取得が回復しました"}
+	default:
+		return nil
+	}
+}
+
+// diffContainers は前回からのゲームコンテナの状態変化を検出する
+func (w *Watcher) diffContainers(containers []docker.ContainerInfo) []string {
+	current := make(map[string]docker.ContainerInfo, len(containers))
+	for i := range containers {
+		current[containers[i].Service] = containers[i]
+	}
+
+	var events []string
+	if w.initialized {
+		for _, service := range sortedKeys(current) {
+			events = append(events, containerEvents(w.containers[service], current[service])...)
+		}
+		for _, service := range sortedKeys(w.containers) {
+			if _, ok := current[service]; !ok {
+				events = append(events, fmt.Sprintf("❓ **%s** のコンテナが見つからなくなりました",
+					usermsg.FormatServiceName(service)))
+			}
+		}
+	}
+
+	w.containers = current
+	return events
+}
+
+// containerEvents は1コンテナ分の状態変化をメッセージに変換する
+func containerEvents(prev, curr docker.ContainerInfo) []string {
+	name := usermsg.FormatServiceName(curr.Service)
+	wasRunning := strings.EqualFold(prev.State, docker.StateRunning)
+	isRunning := strings.EqualFold(curr.State, docker.StateRunning)
+
+	var events []string
+	switch {
+	case prev.Service == "":
+		// 新しく検出されたコンテナは停止していた場合のみ通知する
+		if !isRunning {
+			events = append(events, fmt.Sprintf("🔴 **%s** が停止しています (状態: %s)", name, curr.State))
+		}
+	case wasRunning && !isRunning:
+		events = append(events, fmt.Sprintf("🔴 **%s** が停止しました (状態: %s)", name, curr.State))
+	case !wasRunning && isRunning:
+		events = append(events, fmt.Sprintf("🟢 **%s** が起動しました", name))
+	}
+
+	wasUnhealthy := strings.EqualFold(prev.HealthStatus, docker.HealthUnhealthy)
+	isUnhealthy := strings.EqualFold(curr.HealthStatus, docker.HealthUnhealthy)
+	switch {
+	case !wasUnhealthy && isUnhealthy:
+		events = append(events, fmt.Sprintf("❌ **%s** のヘルスチェックが異常です", name))
+	case wasUnhealthy && !isUnhealthy && isRunning:
+		events = append(events, fmt.Sprintf("✅ **%s** のヘルスチェックが回復しました", name))
+	}
+
+	return events
+}
+
+// diffAlerts は前回からのアラートの発生・解消を検出する
+// 今回測定できなかったコンポーネントのアラートは解消とみなさず、前回の状態を引き継ぐ
+func (w *Watcher) diffAlerts(alerts []alert.Alert, skipped func(alert.Alert) bool) []string {
+	current := make(map[string]alert.Alert, len(alerts))
+	for _, a := range alerts {
+		current[a.Key()] = a
+	}
+	for key, a := range w.alerts {
+		if _, ok := current[key]; !ok && skipped(a) {
+			current[key] = a
+		}
+	}
+
+	var events []string
+	for _, key := range sortedKeys(current) {
+		if _, ok := w.alerts[key]; !ok {
+			a := current[key]
+			events = append(events, fmt.Sprintf("⚠️ %s: %s (%.1f%%)", a.Component, a.Message, a.Value))
+		}
+	}
+	for _, key := range sortedKeys(w.alerts) {
+		if _, ok := current[key]; !ok {
+			a := w.alerts[key]
+			events = append(events, fmt.Sprintf("✅ %s: %s 状態が解消されました", a.Component, a.Message))
+		}
+	}
+
+	w.alerts = current
+	return events
+}
+
+// unmeasured はアラートの対象が今回の監視で測定できなかったかどうかを判定する関数を返す
+// 一部のコンテナの統計情報のみ取得に失敗した場合は、失敗したコンテナのみを測定できなかったとみなす
+func unmeasured(sysErr, statsErr error) func(alert.Alert) bool {
+	var partial *docker.ContainerStatsError
+	failed := make(map[string]bool)
+	if errors.As(statsErr, &partial) {
+		for _, failure := range partial.Failures {
+			service := failure.Service
+			if service == "" {
+				service = alert.ServiceFromContainerName(failure.Container)
+			}
+			failed[usermsg.FormatServiceName(service)] = true
+		}
+	}
+
+	return func(a alert.Alert) bool {
+		switch {
+		case a.Component == alert.ComponentHost:
+			return sysErr != nil
+		case partial != nil:
+			return failed[a.Component]
+		default:
+			return statsErr != nil
+		}
+	}
+}
+
+// buildMessage は通知メッセージを組み立てる
+func buildMessage(events []string) string {
+	var builder strings.Builder
+	builder.WriteString("🔔 **監視通知**\n")
+	for _, event := range events {
+		builder.WriteString("- " + event + "\n")
+	}
+	return builder.String()
+}
+
+// sortedKeys はマップのキーをソートして返す
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,316 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideA88/game-server-watchdog/internal/notify"
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+	"github.com/hideA88/game-server-watchdog/pkg/system"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "指定した間隔", interval: time.Minute, want: time.Minute},
+		{name: "0の場合はデフォルト", interval: 0, want: DefaultInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := New(&system.MockMonitor{}, &docker.MockComposeService{}, &notify.MockNotifier{}, nil,
+				"docker-compose.yml", tt.interval)
+			if w.interval != tt.want {
+				t.Errorf("New() interval = %v, want %v", w.interval, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatcher_poll_ContainerStateChanges(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		{Service: "minecraft", State: "running", HealthStatus: "healthy"},
+		{Service: "terraria", State: "running", HealthStatus: "none"},
+	}
+	compose := &docker.MockComposeService{
+		ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+			return containers, nil
+		},
+	}
+	monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{}}
+	notifier := &notify.MockNotifier{}
+	w := New(monitor, compose, notifier, nil, "docker-compose.yml", time.Minute)
+	ctx := context.Background()
+
+	// 初回はベースラインとして記録されるだけ
+	w.poll(ctx)
+	if got := len(notifier.Messages()); got != 0 {
+		t.Fatalf("first poll sent %d notifications, want 0", got)
+	}
+
+	// minecraftがクラッシュし、terrariaがunhealthyになる
+	containers = []docker.ContainerInfo{
+		{Service: "minecraft", State: "exited", HealthStatus: "none"},
+		{Service: "terraria", State: "running", HealthStatus: "unhealthy"},
+	}
+	w.poll(ctx)
+
+	messages := notifier.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("second poll sent %d notifications, want 1", len(messages))
+	}
+	for _, want := range []string{
+		"🔴 **Minecraft** が停止しました (状態: exited)",
+		"❌ **Terraria** のヘルスチェックが異常です",
+	} {
+		if !strings.Contains(messages[0], want) {
+			t.Errorf("notification does not contain %q\nGot: %s", want, messages[0])
+		}
+	}
+
+	// 変化がなければ通知しない
+	w.poll(ctx)
+	if got := len(notifier.Messages()); got != 1 {
+		t.Errorf("unchanged poll sent notification, total = %d, want 1", got)
+	}
+
+	// 回復とコンテナの消失
+	containers = []docker.ContainerInfo{
+		{Service: "minecraft", State: "running", HealthStatus: "none"},
+	}
+	w.poll(ctx)
+
+	messages = notifier.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("recovery poll total notifications = %d, want 2", len(messages))
+	}
+	for _, want := range []string{
+		"🟢 **Minecraft** が起動しました",
+		"❓ **Terraria** のコンテナが見つからなくなりました",
+	} {
+		if !strings.Contains(messages[1], want) {
+			t.Errorf("notification does not contain %q\nGot: %s", want, messages[1])
+		}
+	}
+}
+
+func TestWatcher_SkipContainerChanges(t *testing.T) {
+	containers := []docker.ContainerInfo{{Service: "minecraft", State: "running"}}
+	compose := &docker.MockComposeService{
+		ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+			return containers, nil
+		},
+	}
+	monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{}}
+	notifier := &notify.MockNotifier{}
+	w := New(monitor, compose, notifier, nil, "docker-compose.yml", time.Minute)
+	w.SkipContainerChanges()
+	ctx := context.Background()
+
+	// コンテナの状態変化は通知しない
+	w.poll(ctx)
+	containers = []docker.ContainerInfo{{Service: "minecraft", State: "exited"}}
+	w.poll(ctx)
+	if got := notifier.Messages(); len(got) != 0 {
+		t.Errorf("container change notified while skipped: %v", got)
+	}
+
+	// リソースのアラートは通知する
+	monitor.SystemInfo = &system.SystemInfo{CPUUsagePercent: 95.0}
+	w.poll(ctx)
+	if got := notifier.Messages(); len(got) != 1 || !strings.Contains(got[0], "CPU使用率が高い") {
+		t.Errorf("resource alert not notified, got %v", got)
+	}
+}
+
+func TestWatcher_poll_ResourceAlerts(t *testing.T) {
+	monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{CPUUsagePercent: 95.0}}
+	compose := &docker.MockComposeService{
+		GetAllContainersStatsFunc: func(_ string) ([]docker.ContainerStats, error) {
+			return []docker.ContainerStats{
+				{Name: "project_minecraft_1", MemoryPercent: 97.0},
+			}, nil
+		},
+	}
+	notifier := &notify.MockNotifier{}
+	w := New(monitor, compose, notifier, nil, "docker-compose.yml", time.Minute)
+	ctx := context.Background()
+
+	// 閾値超過は初回から通知される
+	w.poll(ctx)
+	messages := notifier.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("first poll sent %d notifications, want 1", len(messages))
+	}
+	for _, want := range []string{
+		"⚠️ ホストサーバー: CPU使用率が高い (95.0%)",
+		"⚠️ Minecraft: メモリ使用率が高い (97.0%)",
+	} {
+		if !strings.Contains(messages[0], want) {
+			t.Errorf("notification does not contain %q\nGot: %s", want, messages[0])
+		}
+	}
+
+	// 継続中のアラートは再通知しない
+	w.poll(ctx)
+	if got := len(notifier.Messages()); got != 1 {
+		t.Errorf("ongoing alert re-notified, total = %d, want 1", got)
+	}
+
+	// 解消を通知
+	monitor.SystemInfo = &system.SystemInfo{CPUUsagePercent: 10.0}
+	w.poll(ctx)
+	messages = notifier.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("resolved poll total notifications = %d, want 2", len(messages))
+	}
+	if !strings.Contains(messages[1], "✅ ホストサーバー: CPU使用率が高い 状態が解消されました") {
+		t.Errorf("notification does not contain resolved alert\nGot: %s", messages[1])
+	}
+}
+
+func TestWatcher_poll_AlertsKeptWhenNotMeasured(t *testing.T) {
+	statsFailure := &docker.ContainerStatsError{Failures: []*docker.DockerError{
+		{Operation: "stats", Container: "project_minecraft_1", Service: "minecraft"},
+	}}
+	tests := []struct {
+		name     string
+		sysErr   error
+		statsErr error
+		stats    []docker.ContainerStats
+	}{
+		{name: "システム情報とコンテナ統計情報の取得に失敗", sysErr: errors.New("sysinfo failed"),
+			statsErr: errors.New("cannot connect to the Docker daemon")},
+		{name: "一部のコンテナの統計情報の取得に失敗", sysErr: errors.New("sysinfo failed"), statsErr: statsFailure,
+			stats: []docker.ContainerStats{{Name: "project_rust_1", Service: "rust", MemoryPercent: 10.0}}},
+	}
+
+	breaching := []docker.ContainerStats{{Name: "project_minecraft_1", Service: "minecraft", MemoryPercent: 97.0}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{CPUUsagePercent: 95.0}}
+			stats, statsErr := breaching, error(nil)
+			compose := &docker.MockComposeService{
+				GetAllContainersStatsFunc: func(_ string) ([]docker.ContainerStats, error) {
+					return stats, statsErr
+				},
+			}
+			notifier := &notify.MockNotifier{}
+			w := New(monitor, compose, notifier, nil, "docker-compose.yml", time.Minute)
+			ctx := context.Background()
+
+			w.poll(ctx)
+			if got := len(notifier.Messages()); got != 1 {
+				t.Fatalf("first poll sent %d notifications, want 1", got)
+			}
+
+			// 測定できなかったアラートは解消とみなさない
+			monitor.Err = tt.sysErr
+			stats, statsErr = tt.stats, tt.statsErr
+			w.poll(ctx)
+			if messages := notifier.Messages(); len(messages) != 1 {
+				t.Fatalf("failed poll sent notifications: %v", messages[1:])
+			}
+
+			// 回復後も閾値を超えたままなら再通知しない
+			monitor.Err = nil
+			stats, statsErr = breaching, nil
+			w.poll(ctx)
+			if messages := notifier.Messages(); len(messages) != 1 {
+				t.Errorf("ongoing alerts re-notified after recovery: %v", messages[1:])
+			}
+		})
+	}
+}
+
+func TestWatcher_poll_DockerFailure(t *testing.T) {
+	listErr := errors.New("cannot connect to the Docker daemon")
+	compose := &docker.MockComposeService{
+		ListGameContainersFunc: func(_ string) ([]docker.ContainerInfo, error) {
+			return nil, listErr
+		},
+	}
+	notifier := &notify.MockNotifier{}
+	w := New(&system.MockMonitor{SystemInfo: &system.SystemInfo{}}, compose, notifier, nil,
+		"docker-compose.yml", time.Minute)
+	ctx := context.Background()
+
+	w.poll(ctx)
+	w.poll(ctx)
+	messages := notifier.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("docker failure notifications = %d, want 1", len(messages))
+	}
+	if !strings.Contains(messages[0], "コンテナ情報の取得に失敗しました") {
+		t.Errorf("notification does not contain failure message\nGot: %s", messages[0])
+	}
+
+	listErr = nil
+	w.poll(ctx)
+	messages = notifier.Messages()
+	if len(messages) != 2 || !strings.Contains(messages[1], "コンテナ情報の取得が回復しました") {
+		t.Errorf("expected recovery notification, got %v", messages)
+	}
+}
+
+func TestWatcher_LastPoll(t *testing.T) {
+	tests := []struct {
+		name       string
+		monitorErr error
+		dockerErr  error
+		wantPolled bool
+	}{
+		{name: "取得に成功", wantPolled: true},
+		{name: "システム情報の取得に失敗", monitorErr: errors.New("failed to read /proc")},
+		{name: "コンテナ情報の取得に失敗", dockerErr: errors.New("docker unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compose := &docker.MockComposeService{
+				ListGameContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return nil, tt.dockerErr
+				},
+			}
+			monitor := &system.MockMonitor{SystemInfo: &system.SystemInfo{}, Err: tt.monitorErr}
+			w := New(monitor, compose, &notify.MockNotifier{}, nil, "docker-compose.yml", time.Minute)
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			w.now = func() time.Time { return now }
+
+			if !w.LastPoll().IsZero() {
+				t.Fatalf("LastPoll() before poll = %v, want zero", w.LastPoll())
+			}
+
+			w.poll(context.Background())
+			if got := w.LastPoll(); got.Equal(now) != tt.wantPolled {
+				t.Errorf("LastPoll() = %v, want polled = %v", got, tt.wantPolled)
+			}
+		})
+	}
+}
+
+func TestWatcher_Run_StopsOnCancel(t *testing.T) {
+	notifier := &notify.MockNotifier{}
+	w := New(&system.MockMonitor{SystemInfo: &system.SystemInfo{}}, &docker.MockComposeService{},
+		notifier, nil, "docker-compose.yml", time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+}
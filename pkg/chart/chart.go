@@ -0,0 +1,284 @@
+// Package chart は外部のサービスやライブラリに依存せずに時系列の折れ線グラフをPNG画像として描画する機能を提供します
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultWidth はデフォルトの画像の幅（ピクセル）
+	DefaultWidth = 800
+	// DefaultHeight はデフォルトの画像の高さ（ピクセル）
+	DefaultHeight = 300
+
+	// yTicks はY軸の目盛りの区切りの数
+	yTicks = 4
+	// xTicks はX軸の目盛りの数
+	xTicks = 5
+	// padding は画像の端と描画領域の間隔（ピクセル）
+	padding = 10
+	// lineWidth は折れ線の太さ（ピクセル）
+	lineWidth = 2
+)
+
+// Discordのダークテーマに合わせた配色
+var (
+	backgroundColor = color.RGBA{R: 0x2b, G: 0x2d, B: 0x31, A: 0xff}
+	gridColor       = color.RGBA{R: 0x44, G: 0x47, B: 0x4d, A: 0xff}
+	textColor       = color.RGBA{R: 0xdb, G: 0xde, B: 0xe1, A: 0xff}
+
+	// ColorBlue はCPU使用率などに使う青色
+	ColorBlue = color.RGBA{R: 0x58, G: 0x65, B: 0xf2, A: 0xff}
+	// ColorGreen はメモリ使用率などに使う緑色
+	ColorGreen = color.RGBA{R: 0x57, G: 0xf2, B: 0x87, A: 0xff}
+)
+
+// ErrNoData は描画する値がない場合のエラー
+var ErrNoData = errors.New("no data to render")
+
+// Point は時系列の1点
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series は1本の折れ線（Pointsは古い順）
+type Series struct {
+	Name   string
+	Color  color.RGBA
+	Points []Point
+}
+
+// LineChart は時系列の折れ線グラフ
+type LineChart struct {
+	Title  string
+	Series []Series
+	// MaxValue はY軸の最大値（値がこれを超える場合は切りのよい値に広げる、0の場合は値から決める）
+	MaxValue float64
+	// Unit はY軸の目盛りに付ける単位（例: "%"）
+	Unit string
+	// MaxGap は線をつなぐ点の最大の間隔（これより離れた点の間は途切れさせる、0の場合は常につなぐ）
+	MaxGap time.Duration
+	// Width と Height は画像の大きさ（0の場合はDefaultWidth・DefaultHeight）
+	Width  int
+	Height int
+}
+
+// Render はグラフをPNG形式で書き込む
+func (c *LineChart) Render(w io.Writer) error {
+	img, err := c.Draw()
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return nil
+}
+
+// Draw はグラフを画像として描画する
+func (c *LineChart) Draw() (*image.RGBA, error) {
+	start, end, maxValue, ok := c.bounds()
+	if !ok {
+		return nil, ErrNoData
+	}
+
+	width, height := c.Width, c.Height
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillRect(img, 0, 0, width, height, backgroundColor)
+
+	// 上にタイトルと凡例、左にY軸、下にX軸の目盛りを置く
+	left := padding + textWidth(c.yLabel(maxValue)) + padding
+	top := padding + textHeight + padding
+	right := width - padding - charAdvance
+	bottom := height - padding - textHeight - padding
+	if right <= left || bottom <= top {
+		return nil, fmt.Errorf("chart size %dx%d is too small", width, height)
+	}
+	plot := image.Rect(left, top, right, bottom)
+
+	c.drawHeader(img, plot)
+	c.drawYAxis(img, plot, maxValue)
+	drawXAxis(img, plot, start, end)
+
+	span := end.Sub(start)
+	toPixel := func(p Point) (int, int) {
+		x := plot.Min.X
+		if span > 0 {
+			x += int(math.Round(float64(plot.Dx()) * float64(p.Time.Sub(start)) / float64(span)))
+		}
+		y := plot.Max.Y - int(math.Round(float64(plot.Dy())*math.Max(p.Value, 0)/maxValue))
+		return x, y
+	}
+	for _, s := range c.Series {
+		for i, p := range s.Points {
+			x, y := toPixel(p)
+			if i == 0 || (c.MaxGap > 0 && p.Time.Sub(s.Points[i-1].Time) > c.MaxGap) {
+				drawLine(img, x, y, x, y, s.Color)
+				continue
+			}
+			px, py := toPixel(s.Points[i-1])
+			drawLine(img, px, py, x, y, s.Color)
+		}
+	}
+	return img, nil
+}
+
+// bounds は描画する期間とY軸の最大値を返す（点がない場合はfalse）
+func (c *LineChart) bounds() (start, end time.Time, maxValue float64, ok bool) {
+	maxValue = c.MaxValue
+	for _, s := range c.Series {
+		for _, p := range s.Points {
+			if !ok || p.Time.Before(start) {
+				start = p.Time
+			}
+			if !ok || p.Time.After(end) {
+				end = p.Time
+			}
+			ok = true
+			if p.Value > maxValue {
+				maxValue = niceCeil(p.Value)
+			}
+		}
+	}
+	if maxValue <= 0 {
+		maxValue = 1
+	}
+	return start, end, maxValue, ok
+}
+
+// drawHeader はタイトルと凡例を描画する
+func (c *LineChart) drawHeader(img *image.RGBA, plot image.Rectangle) {
+	drawText(img, plot.Min.X, padding, c.Title, textColor)
+
+	// 凡例は右から並べる
+	x := plot.Max.X
+	for i := len(c.Series) - 1; i >= 0; i-- {
+		s := c.Series[i]
+		x -= textWidth(s.Name)
+		drawText(img, x, padding, s.Name, textColor)
+		x -= textHeight + charAdvance/2
+		fillRect(img, x, padding, textHeight, textHeight, s.Color)
+		x -= charAdvance * 2
+	}
+}
+
+// drawYAxis はY軸の目盛りと横の補助線を描画する
+func (c *LineChart) drawYAxis(img *image.RGBA, plot image.Rectangle, maxValue float64) {
+	for i := 0; i <= yTicks; i++ {
+		value := maxValue * float64(i) / yTicks
+		y := plot.Max.Y - plot.Dy()*i/yTicks
+		fillRect(img, plot.Min.X, y, plot.Dx()+1, 1, gridColor)
+
+		label := c.yLabel(value)
+		drawText(img, plot.Min.X-padding-textWidth(label), y-textHeight/2, label, textColor)
+	}
+}
+
+// yLabel はY軸の目盛りの表示を返す
+func (c *LineChart) yLabel(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f%s", value, c.Unit)
+	}
+	return fmt.Sprintf("%.1f%s", value, c.Unit)
+}
+
+// drawXAxis はX軸の目盛りを描画する（期間が2日を超える場合は日付、それ以外は時刻）
+func drawXAxis(img *image.RGBA, plot image.Rectangle, start, end time.Time) {
+	layout := "15:04"
+	if end.Sub(start) > 48*time.Hour {
+		layout = "01/02"
+	}
+
+	ticks := xTicks
+	if !end.After(start) {
+		ticks = 1
+	}
+	for i := 0; i < ticks; i++ {
+		x := plot.Min.X
+		t := start
+		if ticks > 1 {
+			x += plot.Dx() * i / (ticks - 1)
+			t = start.Add(end.Sub(start) * time.Duration(i) / time.Duration(ticks-1))
+		}
+		fillRect(img, x, plot.Min.Y, 1, plot.Dy(), gridColor)
+
+		label := t.Local().Format(layout)
+		lx := min(max(x-textWidth(label)/2, 0), img.Bounds().Dx()-textWidth(label))
+		drawText(img, lx, plot.Max.Y+padding, label, textColor)
+	}
+}
+
+// niceCeil は値以上の切りのよい値（1, 2, 2.5, 5 × 10のべき乗）を返す
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if v <= m*exp {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+// drawLine は2点の間に太さlineWidthの線を描画する（ブレゼンハムのアルゴリズム）
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		fillRect(img, x0-lineWidth/2, y0-lineWidth/2, lineWidth, lineWidth, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// fillRect は左上を(x, y)とする矩形を塗りつぶす（画像の外側は無視する）
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	r := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
+
+// abs は整数の絶対値を返す
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
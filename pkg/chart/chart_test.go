@@ -0,0 +1,209 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// base はテストで使用する基準時刻
+var base = time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+
+// points は1分間隔の点を作成する
+func points(values ...float64) []Point {
+	ps := make([]Point, len(values))
+	for i, v := range values {
+		ps[i] = Point{Time: base.Add(time.Duration(i) * time.Minute), Value: v}
+	}
+	return ps
+}
+
+// countColor は指定した色のピクセル数を数える
+func countColor(img *image.RGBA, r image.Rectangle, c [4]uint8) int {
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			p := img.RGBAAt(x, y)
+			if [4]uint8{p.R, p.G, p.B, p.A} == c {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestLineChart_Render(t *testing.T) {
+	c := &LineChart{
+		Title:    "minecraft",
+		MaxValue: 100,
+		Unit:     "%",
+		Series: []Series{
+			{Name: "CPU", Color: ColorBlue, Points: points(10, 50, 90, 30)},
+			{Name: "MEM", Color: ColorGreen, Points: points(40, 42, 45, 44)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := c.Render(&buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Render() wrote invalid PNG: %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(DefaultWidth, DefaultHeight) {
+		t.Errorf("image size = %v, want %dx%d", got, DefaultWidth, DefaultHeight)
+	}
+}
+
+func TestLineChart_Draw(t *testing.T) {
+	blue := [4]uint8{ColorBlue.R, ColorBlue.G, ColorBlue.B, ColorBlue.A}
+	green := [4]uint8{ColorGreen.R, ColorGreen.G, ColorGreen.B, ColorGreen.A}
+
+	tests := []struct {
+		name      string
+		chart     LineChart
+		wantBlue  bool
+		wantGreen bool
+	}{
+		{
+			name: "2本の折れ線",
+			chart: LineChart{Series: []Series{
+				{Name: "CPU", Color: ColorBlue, Points: points(10, 20)},
+				{Name: "MEM", Color: ColorGreen, Points: points(30, 40)},
+			}},
+			wantBlue:  true,
+			wantGreen: true,
+		},
+		{
+			name:     "1点だけの系列",
+			chart:    LineChart{Series: []Series{{Name: "CPU", Color: ColorBlue, Points: points(50)}}},
+			wantBlue: true,
+		},
+		{
+			name: "点のない系列は描画しない",
+			chart: LineChart{Series: []Series{
+				{Name: "CPU", Color: ColorBlue, Points: points(50, 60)},
+				{Name: "", Color: ColorGreen},
+			}},
+			wantBlue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := tt.chart.Draw()
+			if err != nil {
+				t.Fatalf("Draw() error = %v", err)
+			}
+			// 凡例を除いた描画領域で線の色を確認する
+			area := image.Rect(0, padding+textHeight+padding, img.Bounds().Dx(), img.Bounds().Dy())
+			if got := countColor(img, area, blue) > 0; got != tt.wantBlue {
+				t.Errorf("blue line drawn = %v, want %v", got, tt.wantBlue)
+			}
+			if got := countColor(img, area, green) > 0; got != tt.wantGreen {
+				t.Errorf("green line drawn = %v, want %v", got, tt.wantGreen)
+			}
+		})
+	}
+}
+
+func TestLineChart_Draw_MaxGap(t *testing.T) {
+	ps := []Point{
+		{Time: base, Value: 50},
+		{Time: base.Add(time.Minute), Value: 50},
+		{Time: base.Add(time.Hour), Value: 50},
+	}
+	blue := [4]uint8{ColorBlue.R, ColorBlue.G, ColorBlue.B, ColorBlue.A}
+	area := image.Rect(0, padding+textHeight+padding, DefaultWidth, DefaultHeight)
+
+	joined, err := (&LineChart{Series: []Series{{Color: ColorBlue, Points: ps}}, MaxValue: 100}).Draw()
+	if err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+	split, err := (&LineChart{Series: []Series{{Color: ColorBlue, Points: ps}}, MaxValue: 100, MaxGap: 2 * time.Minute}).Draw()
+	if err != nil {
+		t.Fatalf("Draw() error = %v", err)
+	}
+
+	// 間隔が空いた点の間は線をつながない
+	if j, s := countColor(joined, area, blue), countColor(split, area, blue); s >= j/2 {
+		t.Errorf("split line pixels = %d, joined = %d, want the gap to be left blank", s, j)
+	}
+}
+
+func TestLineChart_Draw_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		chart   LineChart
+		wantErr error
+	}{
+		{name: "系列がない", chart: LineChart{}, wantErr: ErrNoData},
+		{name: "点がない", chart: LineChart{Series: []Series{{Name: "CPU"}}}, wantErr: ErrNoData},
+		{name: "小さすぎる", chart: LineChart{Series: []Series{{Points: points(1)}}, Width: 20, Height: 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.chart.Draw()
+			if err == nil {
+				t.Fatal("Draw() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Draw() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLineChart_bounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxValue float64
+		values   []float64
+		want     float64
+	}{
+		{name: "最大値の指定", maxValue: 100, values: []float64{10, 50}, want: 100},
+		{name: "最大値を超える場合は広げる", maxValue: 100, values: []float64{10, 180}, want: 200},
+		{name: "最大値の指定なし", values: []float64{3, 7}, want: 10},
+		{name: "すべて0", values: []float64{0, 0}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LineChart{MaxValue: tt.maxValue, Series: []Series{{Points: points(tt.values...)}}}
+			start, end, got, ok := c.bounds()
+			if !ok || got != tt.want {
+				t.Errorf("bounds() max = %v, ok = %v, want %v", got, ok, tt.want)
+			}
+			if !start.Equal(base) || !end.Equal(base.Add(time.Duration(len(tt.values)-1)*time.Minute)) {
+				t.Errorf("bounds() = %v - %v, want range of points", start, end)
+			}
+		})
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want float64
+	}{
+		{v: 0, want: 0},
+		{v: 0.3, want: 0.5},
+		{v: 1, want: 1},
+		{v: 1.2, want: 2},
+		{v: 23, want: 25},
+		{v: 101, want: 200},
+		{v: 420, want: 500},
+		{v: 600, want: 1000},
+	}
+
+	for _, tt := range tests {
+		if got := niceCeil(tt.v); got != tt.want {
+			t.Errorf("niceCeil(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
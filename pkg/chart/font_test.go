@@ -0,0 +1,34 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawText(t *testing.T) {
+	white := [4]uint8{0xff, 0xff, 0xff, 0xff}
+	tests := []struct {
+		name string
+		text string
+		want int // 描画されるドット数
+	}{
+		{name: "数字", text: "1", want: 10},
+		{name: "小文字は大文字で描画", text: "i", want: 11},
+		{name: "フォントにない文字は空白", text: "あ", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 50, 50))
+			drawText(img, 0, 0, tt.text, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+			if got := countColor(img, img.Bounds(), white); got != tt.want*fontScale*fontScale {
+				t.Errorf("drawText(%q) drew %d pixels, want %d", tt.text, got, tt.want*fontScale*fontScale)
+			}
+		})
+	}
+
+	if got, want := textWidth("100%"), 4*charAdvance-fontScale; got != want {
+		t.Errorf("textWidth() = %d, want %d", got, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
@@ -18,8 +19,10 @@ import (
 
 // DefaultComposeService implements ComposeService using Docker API
 type DefaultComposeService struct {
-	client      *client.Client
-	projectName string
+	client        *client.Client
+	projectName   string
+	stopPolicy    StopPolicy    // サービスごとの停止方法（nilの場合はラベルのみ）
+	commandSender CommandSender // 停止前のRCONコマンドの送信先
 }
 
 // NewDefaultComposeService creates a new DefaultComposeService
@@ -57,8 +60,9 @@ func (s *DefaultComposeService) getProjectName(composePath string) string {
 }
 
 // listContainersWithFilter は指定されたフィルターでコンテナを一覧表示する内部メソッド
-func (s *DefaultComposeService) listContainersWithFilter(filterArgs filters.Args) ([]ContainerInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), ListOperationTimeout)
+func (s *DefaultComposeService) listContainersWithFilter(
+	ctx context.Context, filterArgs filters.Args) ([]ContainerInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, ListOperationTimeout)
 	defer cancel()
 
 	containers, err := s.client.ContainerList(ctx, container.ListOptions{
@@ -99,7 +103,7 @@ func (s *DefaultComposeService) listContainersWithFilter(filterArgs filters.Args
 
 		// 稼働時間を計算
 		var runningFor string
-		if state == containerStateRunning && inspect.State.StartedAt != "" {
+		if state == StateRunning && inspect.State.StartedAt != "" {
 			startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt)
 			if err == nil {
 				duration := time.Since(startedAt)
@@ -117,7 +121,9 @@ func (s *DefaultComposeService) listContainersWithFilter(filterArgs filters.Args
 			RunningFor:   runningFor,
 			Ports:        ports,
 			HealthStatus: healthStatus,
+			RestartCount: inspect.RestartCount,
 			CreatedAt:    time.Unix(containers[i].Created, 0),
+			Labels:       containers[i].Labels,
 		}
 
 		result = append(result, info)
@@ -127,14 +133,14 @@ func (s *DefaultComposeService) listContainersWithFilter(filterArgs filters.Args
 }
 
 // ListContainers lists all containers managed by docker-compose
-func (s *DefaultComposeService) ListContainers(composePath string) ([]ContainerInfo, error) {
+func (s *DefaultComposeService) ListContainers(ctx context.Context, composePath string) ([]ContainerInfo, error) {
 	projectName := s.getProjectName(composePath)
 
 	// Docker Composeのラベルでフィルター
 	filterArgs := filters.NewArgs()
 	filterArgs.Add("label", fmt.Sprintf("%s=%s", LabelDockerComposeProject, projectName))
 
-	containers, err := s.listContainersWithFilter(filterArgs)
+	containers, err := s.listContainersWithFilter(ctx, filterArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
@@ -145,7 +151,7 @@ func (s *DefaultComposeService) ListContainers(composePath string) ([]ContainerI
 // ListGameContainers lists only game containers (with game.type label)
 // Returns containers that have the "game.type" label, excluding
 // infrastructure containers like watchdog
-func (s *DefaultComposeService) ListGameContainers(composePath string) ([]ContainerInfo, error) {
+func (s *DefaultComposeService) ListGameContainers(ctx context.Context, composePath string) ([]ContainerInfo, error) {
 	projectName := s.getProjectName(composePath)
 
 	// Docker Composeのラベルとgame.typeラベルでフィルター
@@ -153,7 +159,7 @@ func (s *DefaultComposeService) ListGameContainers(composePath string) ([]Contai
 	filterArgs.Add("label", fmt.Sprintf("%s=%s", LabelDockerComposeProject, projectName))
 	filterArgs.Add("label", LabelGameType)
 
-	containers, err := s.listContainersWithFilter(filterArgs)
+	containers, err := s.listContainersWithFilter(ctx, filterArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers with %s label: %w", LabelGameType, err)
 	}
@@ -162,65 +168,104 @@ func (s *DefaultComposeService) ListGameContainers(composePath string) ([]Contai
 }
 
 // StartService starts a specific service
-func (s *DefaultComposeService) StartService(composePath, serviceName string) error {
-	return s.executeServiceOperation(composePath, serviceName, "start",
+func (s *DefaultComposeService) StartService(ctx context.Context, composePath, serviceName string) error {
+	return s.executeServiceOperation(ctx, composePath, serviceName, "start",
 		func(ctx context.Context, c container.Summary) error {
+			ctx, cancel := context.WithTimeout(ctx, ServiceOperationTimeout)
+			defer cancel()
 			return s.client.ContainerStart(ctx, c.ID, container.StartOptions{})
 		})
 }
 
 // StopService stops a specific service
-func (s *DefaultComposeService) StopService(composePath, serviceName string) error {
-	return s.executeServiceOperation(composePath, serviceName, "stop",
+// 停止前のコマンドを実行してから、サービスごとのシグナルとタイムアウトで停止する
+func (s *DefaultComposeService) StopService(ctx context.Context, composePath, serviceName string) error {
+	return s.executeServiceOperation(ctx, composePath, serviceName, "stop",
 		func(ctx context.Context, c container.Summary) error {
-			return s.client.ContainerStop(ctx, c.ID, container.StopOptions{})
+			return s.stopContainer(ctx, serviceName, c, false)
 		})
 }
 
 // GetContainerStats gets resource usage stats for a specific container
-func (s *DefaultComposeService) GetContainerStats(containerName string) (*ContainerStats, error) {
-	// コンテナを名前で検索
-	containerSummary, err := s.findContainerByName(containerName)
+func (s *DefaultComposeService) GetContainerStats(ctx context.Context, containerName string) (*ContainerStats, error) {
+	// コンテナを名前で検索（再起動回数も同じ詳細情報から取得する）
+	info, err := s.inspectContainerByName(ctx, containerName)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.collectContainerStats(ctx, info)
+}
+
+// collectContainerStats gets resource usage stats for a container whose ID is already known
+// 再起動回数は統計情報に含まれないため、infoの値を使う
+func (s *DefaultComposeService) collectContainerStats(
+	ctx context.Context, info *ContainerInfo,
+) (*ContainerStats, error) {
 	// 統計情報を取得
-	stats, err := s.getContainerStatsData(containerSummary.ID)
+	stats, err := s.getContainerStatsData(ctx, info.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 統計情報を計算
-	return s.calculateContainerStats(containerSummary, stats), nil
+	return calculateContainerStats(info, stats), nil
 }
 
-// findContainerByName finds a container by its name
-func (s *DefaultComposeService) findContainerByName(containerName string) (*container.Summary, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), QueryOperationTimeout)
+// GetContainerEnv returns the environment variables of a specific container
+func (s *DefaultComposeService) GetContainerEnv(ctx context.Context, containerName string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryOperationTimeout)
 	defer cancel()
 
-	// コンテナ名でフィルター
-	filterArgs := filters.NewArgs()
-	filterArgs.Add("name", containerName)
-
-	containers, err := s.client.ContainerList(ctx, container.ListOptions{
-		Filters: filterArgs,
-	})
+	inspect, err := s.client.ContainerInspect(ctx, containerName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find container: %w", err)
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Config == nil {
+		return map[string]string{}, nil
 	}
+	return parseEnv(inspect.Config.Env), nil
+}
 
-	if len(containers) == 0 {
-		return nil, fmt.Errorf("container %s not found", containerName)
+// parseEnv は KEY=VALUE 形式の環境変数をマップに変換する
+func parseEnv(env []string) map[string]string {
+	result := make(map[string]string, len(env))
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		if key != "" {
+			result[key] = value
+		}
 	}
+	return result
+}
 
-	return &containers[0], nil
+// inspectContainerByName finds a container by its name and returns the fields needed for stats
+func (s *DefaultComposeService) inspectContainerByName(
+	ctx context.Context, containerName string) (*ContainerInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryOperationTimeout)
+	defer cancel()
+
+	inspect, err := s.client.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find container %s: %w", containerName, err)
+	}
+
+	var service string
+	if inspect.Config != nil {
+		service = inspect.Config.Labels[LabelDockerComposeService]
+	}
+	return &ContainerInfo{
+		ID:           inspect.ID,
+		Name:         strings.TrimPrefix(inspect.Name, "/"),
+		Service:      service,
+		RestartCount: inspect.RestartCount,
+	}, nil
 }
 
 // getContainerStatsData retrieves and parses container stats from Docker API
-func (s *DefaultComposeService) getContainerStatsData(containerID string) (*container.StatsResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), QueryOperationTimeout)
+func (s *DefaultComposeService) getContainerStatsData(
+	ctx context.Context, containerID string) (*container.StatsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryOperationTimeout)
 	defer cancel()
 
 	// Stats APIを呼び出し
@@ -250,57 +295,44 @@ func (s *DefaultComposeService) getContainerStatsData(containerID string) (*cont
 }
 
 // calculateContainerStats calculates all container statistics and returns ContainerStats
-func (s *DefaultComposeService) calculateContainerStats(
-	containerSummary *container.Summary, stats *container.StatsResponse) *ContainerStats {
-	// CPU使用率を計算
-	cpuPercent := calculateCPUPercent(stats)
-
-	// メモリ使用率を計算
-	memPercent := s.calculateMemoryPercent(stats)
-	memUsage := s.formatMemoryUsage(stats)
-
-	// ネットワークI/Oを計算
-	networkIO := s.calculateNetworkIO(stats)
-
-	// ブロックI/Oを計算
-	blockIO := s.calculateBlockIO(stats)
+func calculateContainerStats(info *ContainerInfo, stats *container.StatsResponse) *ContainerStats {
+	// ネットワークI/OとブロックI/Oを集計
+	rxBytes, txBytes := sumNetworkIO(stats)
+	readBytes, writeBytes := sumBlockIO(stats)
 
 	return &ContainerStats{
-		ContainerID:   containerSummary.ID[:12],
-		Name:          strings.TrimPrefix(containerSummary.Names[0], "/"),
-		CPUPercent:    cpuPercent,
-		MemoryPercent: memPercent,
-		MemoryUsage:   memUsage,
-		NetworkIO:     networkIO,
-		BlockIO:       blockIO,
+		ContainerID:      shortContainerID(info.ID),
+		Name:             info.Name,
+		Service:          info.Service,
+		RestartCount:     info.RestartCount,
+		CPUPercent:       calculateCPUPercent(stats),
+		MemoryPercent:    calculateMemoryPercent(stats),
+		MemoryUsageBytes: stats.MemoryStats.Usage,
+		MemoryLimitBytes: stats.MemoryStats.Limit,
+		NetworkRxBytes:   rxBytes,
+		NetworkTxBytes:   txBytes,
+		BlockReadBytes:   readBytes,
+		BlockWriteBytes:  writeBytes,
+		PIDs:             stats.PidsStats.Current,
 	}
 }
 
 // calculateMemoryPercent calculates memory usage percentage
-func (s *DefaultComposeService) calculateMemoryPercent(stats *container.StatsResponse) float64 {
+func calculateMemoryPercent(stats *container.StatsResponse) float64 {
 	return float64(stats.MemoryStats.Usage) / float64(stats.MemoryStats.Limit) * 100.0
 }
 
-// formatMemoryUsage formats memory usage as "used / total"
-func (s *DefaultComposeService) formatMemoryUsage(stats *container.StatsResponse) string {
-	return fmt.Sprintf("%.2fGiB / %.2fGiB",
-		float64(stats.MemoryStats.Usage)/1024/1024/1024,
-		float64(stats.MemoryStats.Limit)/1024/1024/1024)
-}
-
-// calculateNetworkIO calculates network I/O statistics
-func (s *DefaultComposeService) calculateNetworkIO(stats *container.StatsResponse) string {
-	var rxBytes, txBytes uint64
+// sumNetworkIO sums received and transmitted bytes over all networks
+func sumNetworkIO(stats *container.StatsResponse) (rxBytes, txBytes uint64) {
 	for _, v := range stats.Networks {
 		rxBytes += v.RxBytes
 		txBytes += v.TxBytes
 	}
-	return fmt.Sprintf("%s / %s", formatBytes(rxBytes), formatBytes(txBytes))
+	return rxBytes, txBytes
 }
 
-// calculateBlockIO calculates block I/O statistics
-func (s *DefaultComposeService) calculateBlockIO(stats *container.StatsResponse) string {
-	var readBytes, writeBytes uint64
+// sumBlockIO sums read and written bytes over all block devices
+func sumBlockIO(stats *container.StatsResponse) (readBytes, writeBytes uint64) {
 	for _, v := range stats.BlkioStats.IoServiceBytesRecursive {
 		switch v.Op {
 		case "read":
@@ -309,32 +341,90 @@ func (s *DefaultComposeService) calculateBlockIO(stats *container.StatsResponse)
 			writeBytes += v.Value
 		}
 	}
-	return fmt.Sprintf("%s / %s", formatBytes(readBytes), formatBytes(writeBytes))
+	return readBytes, writeBytes
 }
 
-// GetAllContainersStats gets resource usage stats for all containers
-func (s *DefaultComposeService) GetAllContainersStats(composePath string) ([]ContainerStats, error) {
-	containers, err := s.ListContainers(composePath)
+// GetAllContainersStats gets resource usage stats for all running containers
+// 統計情報は最大MaxConcurrentStatsRequests件ずつ並行して取得する
+// 一部のコンテナで取得に失敗した場合は、取得できた統計情報と*ContainerStatsErrorを返す
+func (s *DefaultComposeService) GetAllContainersStats(
+	ctx context.Context, composePath string,
+) ([]ContainerStats, error) {
+	containers, err := s.ListContainers(ctx, composePath)
 	if err != nil {
 		return nil, err
 	}
 
+	var running []ContainerInfo
+	for i := range containers {
+		if strings.EqualFold(containers[i].State, StateRunning) {
+			running = append(running, containers[i])
+		}
+	}
+
+	// ListContainersで取得したIDをそのまま使い、名前での再検索を避ける
+	results := make([]*ContainerStats, len(running))
+	errs := make([]error, len(running))
+	forEachLimit(len(running), MaxConcurrentStatsRequests, func(i int) {
+		results[i], errs[i] = s.collectContainerStats(ctx, &running[i])
+	})
+
+	return mergeContainerStats(running, results, errs)
+}
+
+// forEachLimit calls fn for each index in [0, n) with at most limit calls running at once
+func forEachLimit(n, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// mergeContainerStats collects successful results in container order and wraps the failures
+func mergeContainerStats(
+	containers []ContainerInfo, results []*ContainerStats, errs []error) ([]ContainerStats, error) {
 	var stats []ContainerStats
+	var failures []*DockerError
 	for i := range containers {
-		if strings.EqualFold(containers[i].State, containerStateRunning) {
-			stat, err := s.GetContainerStats(containers[i].Name)
-			if err != nil {
-				continue
-			}
-			stats = append(stats, *stat)
+		if errs[i] != nil {
+			failure := NewDockerContainerError("stats", containers[i].Name, errs[i])
+			failure.Service = containers[i].Service
+			failures = append(failures, failure)
+			continue
 		}
+		stats = append(stats, *results[i])
 	}
 
+	if len(failures) > 0 {
+		return stats, &ContainerStatsError{Failures: failures}
+	}
 	return stats, nil
 }
 
+// shortContainerID returns the 12-character form of a container ID
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
 // executeServiceOperation executes a common service operation pattern
-func (s *DefaultComposeService) executeServiceOperation(composePath, serviceName, operation string,
+// タイムアウトはコンテナごとに操作側で設定する（停止・再起動は停止前のコマンドと停止を待つ時間を含めるため）
+// 途中で中断するとコンテナが停止前のコマンドだけ実行された状態になるため、ctxのキャンセルはコンテナの検索までに適用する
+func (s *DefaultComposeService) executeServiceOperation(ctx context.Context, composePath, serviceName, operation string,
 	containerOp func(context.Context, container.Summary) error) error {
 	if !IsValidServiceName(serviceName) {
 		return fmt.Errorf("%w: %s", ErrInvalidServiceName, serviceName)
@@ -343,7 +433,7 @@ func (s *DefaultComposeService) executeServiceOperation(composePath, serviceName
 	projectName := s.getProjectName(composePath)
 
 	// サービスに属するコンテナを検索
-	containers, err := s.findServiceContainers(projectName, serviceName)
+	containers, err := s.findServiceContainers(ctx, projectName, serviceName)
 	if err != nil {
 		return err
 	}
@@ -352,8 +442,7 @@ func (s *DefaultComposeService) executeServiceOperation(composePath, serviceName
 		return fmt.Errorf("service %s not found", serviceName)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), ServiceOperationTimeout)
-	defer cancel()
+	ctx = context.WithoutCancel(ctx)
 
 	// すべてのコンテナに操作を実行
 	for i := range containers {
@@ -366,15 +455,17 @@ func (s *DefaultComposeService) executeServiceOperation(composePath, serviceName
 }
 
 // RestartContainer restarts a specific container
-func (s *DefaultComposeService) RestartContainer(composePath, serviceName string) error {
-	return s.executeServiceOperation(composePath, serviceName, "restart",
+// 停止前のコマンドを実行してから、サービスごとのシグナルとタイムアウトで再起動する
+func (s *DefaultComposeService) RestartContainer(ctx context.Context, composePath, serviceName string) error {
+	return s.executeServiceOperation(ctx, composePath, serviceName, "restart",
 		func(ctx context.Context, c container.Summary) error {
-			return s.client.ContainerRestart(ctx, c.ID, container.StopOptions{})
+			return s.stopContainer(ctx, serviceName, c, true)
 		})
 }
 
 // GetContainerLogs gets logs from a specific container
-func (s *DefaultComposeService) GetContainerLogs(composePath, serviceName string, lines int) (string, error) {
+func (s *DefaultComposeService) GetContainerLogs(
+	ctx context.Context, composePath, serviceName string, lines int) (string, error) {
 	if !IsValidServiceName(serviceName) {
 		return "", fmt.Errorf("%w: %s", ErrInvalidServiceName, serviceName)
 	}
@@ -388,7 +479,7 @@ func (s *DefaultComposeService) GetContainerLogs(composePath, serviceName string
 	projectName := s.getProjectName(composePath)
 
 	// サービスに属するコンテナを検索
-	containers, err := s.findServiceContainers(projectName, serviceName)
+	containers, err := s.findServiceContainers(ctx, projectName, serviceName)
 	if err != nil {
 		return "", err
 	}
@@ -397,7 +488,7 @@ func (s *DefaultComposeService) GetContainerLogs(composePath, serviceName string
 		return "", fmt.Errorf("service %s not found", serviceName)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), ListOperationTimeout)
+	ctx, cancel := context.WithTimeout(ctx, ListOperationTimeout)
 	defer cancel()
 
 	// ログを取得
@@ -429,12 +520,13 @@ func (s *DefaultComposeService) GetContainerLogs(composePath, serviceName string
 }
 
 // findServiceContainers finds containers belonging to a specific service
-func (s *DefaultComposeService) findServiceContainers(projectName, serviceName string) ([]container.Summary, error) {
+func (s *DefaultComposeService) findServiceContainers(
+	ctx context.Context, projectName, serviceName string) ([]container.Summary, error) {
 	filterArgs := filters.NewArgs()
 	filterArgs.Add("label", fmt.Sprintf("%s=%s", LabelDockerComposeProject, projectName))
 	filterArgs.Add("label", fmt.Sprintf("%s=%s", LabelDockerComposeService, serviceName))
 
-	ctx, cancel := context.WithTimeout(context.Background(), QueryOperationTimeout)
+	ctx, cancel := context.WithTimeout(ctx, QueryOperationTimeout)
 	defer cancel()
 
 	return s.client.ContainerList(ctx, container.ListOptions{
@@ -449,8 +541,14 @@ func calculateCPUPercent(stats *container.StatsResponse) float64 {
 	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
 
+	// cgroup v2ではPercpuUsageが空のため、OnlineCPUsがあればそちらを使う
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0.0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
 	if systemDelta > 0.0 && cpuDelta > 0.0 {
-		cpuPercent := (cpuDelta / systemDelta) * float64(len(stats.CPUStats.CPUUsage.PercpuUsage)) * 100.0
+		cpuPercent := (cpuDelta / systemDelta) * onlineCPUs * 100.0
 		return cpuPercent
 	}
 
@@ -473,20 +571,6 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
-// formatBytes formats bytes into a human-readable string
-func formatBytes(bytes uint64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%dB", bytes)
-	}
-	div, exp := uint64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
-
 // cleanDockerLogs removes Docker's log format headers
 func cleanDockerLogs(logs string) string {
 	lines := strings.Split(logs, "\n")
@@ -507,6 +591,22 @@ func cleanDockerLogs(logs string) string {
 	return strings.Join(cleaned, "\n")
 }
 
+// Ping checks whether the Docker daemon is reachable
+func (s *DefaultComposeService) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryOperationTimeout)
+	defer cancel()
+
+	if _, err := s.client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping Docker daemon: %w", err)
+	}
+	return nil
+}
+
+// StatsStreamer returns the Docker client used to stream container stats
+func (s *DefaultComposeService) StatsStreamer() StatsStreamer {
+	return s.client
+}
+
 // Close closes the Docker client connection
 func (s *DefaultComposeService) Close() error {
 	if s.client != nil {
@@ -1,6 +1,10 @@
 package docker
 
 import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -107,6 +111,21 @@ func TestCalculateCPUPercent(t *testing.T) {
 			},
 			expected: 100.0, // (1000000000 / 2000000000) * 2 * 100 = 100%
 		},
+		{
+			name: "OnlineCPUsを使用（cgroup v2）",
+			stats: &container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 3000000000},
+					SystemUsage: 8000000000,
+					OnlineCPUs:  4,
+				},
+				PreCPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 1000000000},
+					SystemUsage: 4000000000,
+				},
+			},
+			expected: 200.0, // (2000000000 / 4000000000) * 4 * 100 = 200%
+		},
 		{
 			name: "CPU使用率0%",
 			stats: &container.StatsResponse{
@@ -217,69 +236,6 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
-func TestFormatBytes(t *testing.T) {
-	tests := []struct {
-		name     string
-		bytes    uint64
-		expected string
-	}{
-		{
-			name:     "バイト単位",
-			bytes:    512,
-			expected: "512B",
-		},
-		{
-			name:     "キロバイト単位",
-			bytes:    1536, // 1.5KB
-			expected: "1.5KB",
-		},
-		{
-			name:     "メガバイト単位",
-			bytes:    2097152, // 2MB
-			expected: "2.0MB",
-		},
-		{
-			name:     "ギガバイト単位",
-			bytes:    3221225472, // 3GB
-			expected: "3.0GB",
-		},
-		{
-			name:     "テラバイト単位",
-			bytes:    1099511627776, // 1TB
-			expected: "1.0TB",
-		},
-		{
-			name:     "0バイト",
-			bytes:    0,
-			expected: "0B",
-		},
-		{
-			name:     "1バイト",
-			bytes:    1,
-			expected: "1B",
-		},
-		{
-			name:     "1023バイト（1KB未満）",
-			bytes:    1023,
-			expected: "1023B",
-		},
-		{
-			name:     "1024バイト（ちょうど1KB）",
-			bytes:    1024,
-			expected: "1.0KB",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := formatBytes(tt.bytes)
-			if result != tt.expected {
-				t.Errorf("formatBytes() = %v, want %v", result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestCleanDockerLogs(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -333,8 +289,7 @@ func TestCleanDockerLogs(t *testing.T) {
 	}
 }
 
-// DefaultComposeServiceのメソッドのテスト（calculateMemoryPercentなど）
-func TestDefaultComposeService_calculateMemoryPercent(t *testing.T) {
+func TestCalculateMemoryPercent(t *testing.T) {
 	tests := []struct {
 		name     string
 		stats    *container.StatsResponse
@@ -374,8 +329,7 @@ func TestDefaultComposeService_calculateMemoryPercent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := &DefaultComposeService{}
-			result := service.calculateMemoryPercent(tt.stats)
+			result := calculateMemoryPercent(tt.stats)
 			if result != tt.expected {
 				t.Errorf("calculateMemoryPercent() = %v, want %v", result, tt.expected)
 			}
@@ -383,50 +337,12 @@ func TestDefaultComposeService_calculateMemoryPercent(t *testing.T) {
 	}
 }
 
-func TestDefaultComposeService_formatMemoryUsage(t *testing.T) {
-	tests := []struct {
-		name     string
-		stats    *container.StatsResponse
-		expected string
-	}{
-		{
-			name: "正常なメモリ使用量",
-			stats: &container.StatsResponse{
-				MemoryStats: container.MemoryStats{
-					Usage: 536870912,  // 512MB = 0.5GiB
-					Limit: 1073741824, // 1GB = 1GiB
-				},
-			},
-			expected: "0.50GiB / 1.00GiB",
-		},
-		{
-			name: "大きなメモリ使用量",
-			stats: &container.StatsResponse{
-				MemoryStats: container.MemoryStats{
-					Usage: 8589934592,  // 8GB
-					Limit: 17179869184, // 16GB
-				},
-			},
-			expected: "8.00GiB / 16.00GiB",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			service := &DefaultComposeService{}
-			result := service.formatMemoryUsage(tt.stats)
-			if result != tt.expected {
-				t.Errorf("formatMemoryUsage() = %v, want %v", result, tt.expected)
-			}
-		})
-	}
-}
-
-func TestDefaultComposeService_calculateNetworkIO(t *testing.T) {
+func TestSumNetworkIO(t *testing.T) {
 	tests := []struct {
-		name     string
-		stats    *container.StatsResponse
-		expected string
+		name   string
+		stats  *container.StatsResponse
+		wantRx uint64
+		wantTx uint64
 	}{
 		{
 			name: "正常なネットワークI/O",
@@ -442,33 +358,33 @@ func TestDefaultComposeService_calculateNetworkIO(t *testing.T) {
 					},
 				},
 			},
-			expected: "1.5MB / 3.0MB", // 合計値
+			wantRx: 1560576, // 合計値
+			wantTx: 3121152,
 		},
 		{
 			name: "ネットワークなし",
 			stats: &container.StatsResponse{
 				Networks: map[string]container.NetworkStats{},
 			},
-			expected: "0B / 0B",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := &DefaultComposeService{}
-			result := service.calculateNetworkIO(tt.stats)
-			if result != tt.expected {
-				t.Errorf("calculateNetworkIO() = %v, want %v", result, tt.expected)
+			rx, tx := sumNetworkIO(tt.stats)
+			if rx != tt.wantRx || tx != tt.wantTx {
+				t.Errorf("sumNetworkIO() = %d, %d, want %d, %d", rx, tx, tt.wantRx, tt.wantTx)
 			}
 		})
 	}
 }
 
-func TestDefaultComposeService_calculateBlockIO(t *testing.T) {
+func TestSumBlockIO(t *testing.T) {
 	tests := []struct {
-		name     string
-		stats    *container.StatsResponse
-		expected string
+		name      string
+		stats     *container.StatsResponse
+		wantRead  uint64
+		wantWrite uint64
 	}{
 		{
 			name: "正常なブロックI/O",
@@ -483,7 +399,8 @@ func TestDefaultComposeService_calculateBlockIO(t *testing.T) {
 					},
 				},
 			},
-			expected: "1.5MB / 3.0MB", // 合計値
+			wantRead:  1560576, // 合計値
+			wantWrite: 3121152,
 		},
 		{
 			name: "ブロックI/Oなし",
@@ -492,16 +409,215 @@ func TestDefaultComposeService_calculateBlockIO(t *testing.T) {
 					IoServiceBytesRecursive: []container.BlkioStatEntry{},
 				},
 			},
-			expected: "0B / 0B",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := &DefaultComposeService{}
-			result := service.calculateBlockIO(tt.stats)
-			if result != tt.expected {
-				t.Errorf("calculateBlockIO() = %v, want %v", result, tt.expected)
+			read, write := sumBlockIO(tt.stats)
+			if read != tt.wantRead || write != tt.wantWrite {
+				t.Errorf("sumBlockIO() = %d, %d, want %d, %d", read, write, tt.wantRead, tt.wantWrite)
+			}
+		})
+	}
+}
+
+func TestCalculateContainerStats(t *testing.T) {
+	info := &ContainerInfo{
+		ID:           "0123456789abcdef",
+		Name:         "project-minecraft-1",
+		Service:      "minecraft",
+		RestartCount: 3,
+	}
+	stats := &container.StatsResponse{}
+	stats.MemoryStats.Usage = 512 * 1024 * 1024
+	stats.MemoryStats.Limit = 2 * 1024 * 1024 * 1024
+	stats.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: 100, TxBytes: 200},
+		"eth1": {RxBytes: 10, TxBytes: 20},
+	}
+	stats.BlkioStats.IoServiceBytesRecursive = []container.BlkioStatEntry{
+		{Op: "read", Value: 300},
+		{Op: "write", Value: 400},
+	}
+	stats.PidsStats.Current = 42
+
+	got := calculateContainerStats(info, stats)
+
+	want := ContainerStats{
+		ContainerID:      "0123456789ab",
+		Name:             "project-minecraft-1",
+		Service:          "minecraft",
+		RestartCount:     3,
+		MemoryPercent:    25,
+		MemoryUsageBytes: 512 * 1024 * 1024,
+		MemoryLimitBytes: 2 * 1024 * 1024 * 1024,
+		NetworkRxBytes:   110,
+		NetworkTxBytes:   220,
+		BlockReadBytes:   300,
+		BlockWriteBytes:  400,
+		PIDs:             42,
+	}
+	if *got != want {
+		t.Errorf("calculateContainerStats() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestForEachLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		limit int
+		want  int32 // 同時実行数の上限
+	}{
+		{name: "上限より多い件数", n: 10, limit: 3, want: 3},
+		{name: "上限より少ない件数", n: 2, limit: 4, want: 2},
+		{name: "上限が0の場合は直列", n: 3, limit: 0, want: 1},
+		{name: "0件", n: 0, limit: 4, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak int32
+			var mu sync.Mutex
+			called := make(map[int]int)
+
+			forEachLimit(tt.n, tt.limit, func(i int) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+
+				mu.Lock()
+				called[i]++
+				mu.Unlock()
+			})
+
+			if len(called) != tt.n {
+				t.Errorf("forEachLimit() called %d indexes, want %d", len(called), tt.n)
+			}
+			for i, count := range called {
+				if count != 1 {
+					t.Errorf("forEachLimit() called index %d %d times, want 1", i, count)
+				}
+			}
+			if peak > tt.want {
+				t.Errorf("forEachLimit() ran %d at once, want at most %d", peak, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeContainerStats(t *testing.T) {
+	errStats := errors.New("stats timeout")
+	containers := []ContainerInfo{
+		{Name: "project-minecraft-1", Service: "minecraft"},
+		{Name: "project-rust-1", Service: "rust"},
+		{Name: "project-terraria-1", Service: "terraria"},
+	}
+
+	tests := []struct {
+		name       string
+		results    []*ContainerStats
+		errs       []error
+		wantStats  []ContainerStats
+		wantFailed []string
+	}{
+		{
+			name: "すべて成功",
+			results: []*ContainerStats{
+				{Name: "project-minecraft-1"}, {Name: "project-rust-1"}, {Name: "project-terraria-1"},
+			},
+			errs:      make([]error, 3),
+			wantStats: []ContainerStats{{Name: "project-minecraft-1"}, {Name: "project-rust-1"}, {Name: "project-terraria-1"}},
+		},
+		{
+			name:       "一部失敗",
+			results:    []*ContainerStats{{Name: "project-minecraft-1"}, nil, {Name: "project-terraria-1"}},
+			errs:       []error{nil, errStats, nil},
+			wantStats:  []ContainerStats{{Name: "project-minecraft-1"}, {Name: "project-terraria-1"}},
+			wantFailed: []string{"project-rust-1"},
+		},
+		{
+			name:       "すべて失敗",
+			results:    make([]*ContainerStats, 3),
+			errs:       []error{errStats, errStats, errStats},
+			wantFailed: []string{"project-minecraft-1", "project-rust-1", "project-terraria-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := mergeContainerStats(containers, tt.results, tt.errs)
+
+			if !reflect.DeepEqual(stats, tt.wantStats) {
+				t.Errorf("mergeContainerStats() stats = %+v, want %+v", stats, tt.wantStats)
+			}
+			if tt.wantFailed == nil {
+				if err != nil {
+					t.Errorf("mergeContainerStats() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var statsErr *ContainerStatsError
+			if !errors.As(err, &statsErr) {
+				t.Fatalf("mergeContainerStats() error = %v, want *ContainerStatsError", err)
+			}
+			if got := statsErr.Containers(); !reflect.DeepEqual(got, tt.wantFailed) {
+				t.Errorf("Containers() = %v, want %v", got, tt.wantFailed)
+			}
+			if !errors.Is(err, errStats) {
+				t.Errorf("errors.Is(err, errStats) = false, want true")
+			}
+		})
+	}
+}
+
+func TestShortContainerID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "完全なID", id: "0123456789abcdef0123", want: "0123456789ab"},
+		{name: "短縮済みのID", id: "0123456789ab", want: "0123456789ab"},
+		{name: "空文字列", id: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortContainerID(tt.id); got != tt.want {
+				t.Errorf("shortContainerID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  []string
+		want map[string]string
+	}{
+		{
+			name: "KEY=VALUE",
+			env:  []string{"RCON_PORT=25575", "RCON_PASSWORD=a=b", "EMPTY="},
+			want: map[string]string{"RCON_PORT": "25575", "RCON_PASSWORD": "a=b", "EMPTY": ""},
+		},
+		{name: "値がない", env: []string{"FLAG", "=value"}, want: map[string]string{"FLAG": ""}},
+		{name: "空", env: nil, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEnv(tt.env); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEnv(%v) = %v, want %v", tt.env, got, tt.want)
 			}
 		})
 	}
@@ -532,15 +648,6 @@ func BenchmarkCalculateCPUPercent(b *testing.B) {
 	}
 }
 
-func BenchmarkFormatBytes(b *testing.B) {
-	bytes := uint64(1073741824) // 1GB
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		formatBytes(bytes)
-	}
-}
-
 func BenchmarkCleanDockerLogs(b *testing.B) {
 	logs := "\x01\x00\x00\x00\x00\x00\x00\x0cHello World\n\x01\x00\x00\x00\x00\x00\x00\x09Test log\n"
 
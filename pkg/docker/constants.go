@@ -16,6 +16,8 @@ const (
 	ListOperationTimeout = 10 * time.Second
 	// QueryOperationTimeout はクエリ操作のタイムアウト時間
 	QueryOperationTimeout = 5 * time.Second
+	// MaxConcurrentStatsRequests は統計情報を並行して取得するコンテナの最大数
+	MaxConcurrentStatsRequests = 4
 
 	// LabelDockerComposeProject はDocker Composeプロジェクト名のラベル
 	LabelDockerComposeProject = "com.docker.compose.project"
@@ -24,7 +26,15 @@ const (
 	// LabelGameType はゲームコンテナを識別するためのラベル
 	LabelGameType = "game.type"
 
-	// Container states
-	// containerStateRunning は実行中のコンテナの状態
-	containerStateRunning = "running"
+	// StateRunning は実行中のコンテナの状態
+	StateRunning = "running"
+	// StateExited は終了したコンテナの状態
+	StateExited = "exited"
+	// StateStopped は停止中のコンテナの状態
+	StateStopped = "stopped"
+
+	// HealthHealthy は正常状態のヘルスチェックステータス
+	HealthHealthy = "healthy"
+	// HealthUnhealthy は異常状態のヘルスチェックステータス
+	HealthUnhealthy = "unhealthy"
 )
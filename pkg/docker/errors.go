@@ -84,6 +84,39 @@ func (e *DockerError) Is(target error) bool {
 	return errors.Is(e.Cause, target)
 }
 
+// ContainerStatsError は一部のコンテナの統計情報を取得できなかった際のエラー
+// GetAllContainersStatsは取得できた統計情報と一緒にこのエラーを返します
+type ContainerStatsError struct {
+	Failures []*DockerError // 取得に失敗したコンテナごとのエラー
+}
+
+// Error はエラーメッセージを返します
+func (e *ContainerStatsError) Error() string {
+	msgs := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		msgs[i] = failure.Error()
+	}
+	return fmt.Sprintf("failed to get stats for %d container(s): %s", len(e.Failures), strings.Join(msgs, "; "))
+}
+
+// Unwrap はコンテナごとのエラーを返します
+func (e *ContainerStatsError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure
+	}
+	return errs
+}
+
+// Containers は統計情報を取得できなかったコンテナ名を返します
+func (e *ContainerStatsError) Containers() []string {
+	names := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		names[i] = failure.Container
+	}
+	return names
+}
+
 // NewDockerError は新しいDockerErrorを作成します
 func NewDockerError(operation string, cause error) *DockerError {
 	return &DockerError{
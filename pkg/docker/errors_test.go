@@ -412,3 +412,32 @@ func TestDockerError_ErrorWrapping(t *testing.T) {
 		t.Errorf("Extracted DockerError.Operation = %q, want %q", dockerErrPtr.Operation, "connect")
 	}
 }
+
+func TestContainerStatsError(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	err := &ContainerStatsError{Failures: []*DockerError{
+		NewDockerContainerError("stats", "project-minecraft-1", errTimeout),
+		NewDockerContainerError("stats", "project-rust-1", ErrContainerNotFound),
+	}}
+
+	want := "failed to get stats for 2 container(s): " +
+		"docker operation 'stats' failed for container 'project-minecraft-1': timeout; " +
+		"docker operation 'stats' failed for container 'project-rust-1': container not found"
+	if got := err.Error(); got != want {
+		t.Errorf("ContainerStatsError.Error() = %q, want %q", got, want)
+	}
+
+	// コンテナごとのエラーを判定できる
+	if !errors.Is(err, errTimeout) || !errors.Is(err, ErrContainerNotFound) {
+		t.Error("errors.Is() should match each container error")
+	}
+
+	wrapped := fmt.Errorf("monitor: %w", err)
+	var statsErr *ContainerStatsError
+	if !errors.As(wrapped, &statsErr) {
+		t.Fatal("errors.As() should find ContainerStatsError")
+	}
+	if got := statsErr.Containers(); len(got) != 2 || got[0] != "project-minecraft-1" || got[1] != "project-rust-1" {
+		t.Errorf("Containers() = %v, want [project-minecraft-1 project-rust-1]", got)
+	}
+}
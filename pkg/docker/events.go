@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ContainerEventType はコンテナイベントの種類
+type ContainerEventType string
+
+// 購読するコンテナイベントの種類
+const (
+	EventStart        ContainerEventType = "start"
+	EventDie          ContainerEventType = "die"
+	EventKill         ContainerEventType = "kill"
+	EventOOM          ContainerEventType = "oom"
+	EventRestart      ContainerEventType = "restart"
+	EventHealthStatus ContainerEventType = "health_status"
+)
+
+// ContainerEvent はDockerから受信したコンテナの状態変化
+type ContainerEvent struct {
+	Type         ContainerEventType
+	ContainerID  string
+	Name         string
+	Service      string
+	ExitCode     int    // EventDieの場合の終了コード
+	Signal       string // EventKillの場合のシグナル
+	HealthStatus string // EventHealthStatusの場合のステータス（starting / healthy / unhealthy）
+	Time         time.Time
+}
+
+// EventSource はDockerのイベントを購読する（*client.Client が実装する）
+type EventSource interface {
+	Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
+}
+
+// SubscribeEvents subscribes to state changes of the game containers in the compose project
+// 購読が終了した場合（Dockerデーモンの再起動など）はイベントのチャネルを閉じ、エラーのチャネルに1件送る
+func (s *DefaultComposeService) SubscribeEvents(
+	ctx context.Context, composePath string) (<-chan ContainerEvent, <-chan error) {
+	return subscribeEvents(ctx, s.client, s.getProjectName(composePath))
+}
+
+// subscribeEvents subscribes to container events of the project and converts them to ContainerEvent
+func subscribeEvents(
+	ctx context.Context, source EventSource, projectName string) (<-chan ContainerEvent, <-chan error) {
+	filterArgs := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("label", fmt.Sprintf("%s=%s", LabelDockerComposeProject, projectName)),
+		filters.Arg("label", LabelGameType),
+	)
+	for _, eventType := range []ContainerEventType{
+		EventStart, EventDie, EventKill, EventOOM, EventRestart, EventHealthStatus,
+	} {
+		filterArgs.Add("event", string(eventType))
+	}
+
+	messages, errs := source.Events(ctx, events.ListOptions{Filters: filterArgs})
+
+	out := make(chan ContainerEvent)
+	outErr := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				outErr <- ctx.Err()
+				return
+			case err := <-errs:
+				outErr <- err
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					outErr <- fmt.Errorf("docker event stream closed")
+					return
+				}
+				event, ok := parseEvent(&msg)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					outErr <- ctx.Err()
+					return
+				}
+			}
+		}
+	}()
+
+	return out, outErr
+}
+
+// parseEvent converts a Docker event message to ContainerEvent
+// 対象外のイベントの場合はfalseを返す
+func parseEvent(msg *events.Message) (ContainerEvent, bool) {
+	if msg.Type != events.ContainerEventType {
+		return ContainerEvent{}, false
+	}
+
+	attrs := msg.Actor.Attributes
+	event := ContainerEvent{
+		ContainerID: shortContainerID(msg.Actor.ID),
+		Name:        attrs["name"],
+		Service:     attrs[LabelDockerComposeService],
+		Time:        time.Unix(msg.Time, 0),
+	}
+	if msg.TimeNano != 0 {
+		event.Time = time.Unix(0, msg.TimeNano)
+	}
+
+	// ヘルスチェックのイベントは "health_status: healthy" の形式で届く
+	action, detail, _ := strings.Cut(string(msg.Action), ":")
+	switch ContainerEventType(action) {
+	case EventStart, EventOOM, EventRestart:
+		event.Type = ContainerEventType(action)
+	case EventDie:
+		event.Type = EventDie
+		event.ExitCode, _ = strconv.Atoi(attrs["exitCode"])
+	case EventKill:
+		event.Type = EventKill
+		event.Signal = attrs["signal"]
+	case EventHealthStatus:
+		event.Type = EventHealthStatus
+		event.HealthStatus = strings.TrimSpace(detail)
+		if event.HealthStatus == "" {
+			return ContainerEvent{}, false
+		}
+	default:
+		return ContainerEvent{}, false
+	}
+
+	return event, true
+}
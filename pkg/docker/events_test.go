@@ -0,0 +1,173 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestParseEvent(t *testing.T) {
+	actor := func(attrs map[string]string) events.Actor {
+		base := map[string]string{
+			"name":                    "game-minecraft-1",
+			LabelDockerComposeService: "minecraft",
+		}
+		for k, v := range attrs {
+			base[k] = v
+		}
+		return events.Actor{ID: "0123456789abcdef", Attributes: base}
+	}
+
+	tests := []struct {
+		name   string
+		msg    events.Message
+		want   ContainerEvent
+		wantOK bool
+	}{
+		{
+			name:   "起動",
+			msg:    events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Actor: actor(nil), Time: 100},
+			want:   ContainerEvent{Type: EventStart},
+			wantOK: true,
+		},
+		{
+			name: "終了コード付きの停止",
+			msg: events.Message{
+				Type: events.ContainerEventType, Action: events.ActionDie,
+				Actor: actor(map[string]string{"exitCode": "137"}), Time: 100,
+			},
+			want:   ContainerEvent{Type: EventDie, ExitCode: 137},
+			wantOK: true,
+		},
+		{
+			name: "シグナルの送信",
+			msg: events.Message{
+				Type: events.ContainerEventType, Action: events.ActionKill,
+				Actor: actor(map[string]string{"signal": "15"}), Time: 100,
+			},
+			want:   ContainerEvent{Type: EventKill, Signal: "15"},
+			wantOK: true,
+		},
+		{
+			name:   "メモリ不足",
+			msg:    events.Message{Type: events.ContainerEventType, Action: events.ActionOOM, Actor: actor(nil), Time: 100},
+			want:   ContainerEvent{Type: EventOOM},
+			wantOK: true,
+		},
+		{
+			name:   "再起動",
+			msg:    events.Message{Type: events.ContainerEventType, Action: events.ActionRestart, Actor: actor(nil), Time: 100},
+			want:   ContainerEvent{Type: EventRestart},
+			wantOK: true,
+		},
+		{
+			name: "ヘルスチェック異常",
+			msg: events.Message{
+				Type: events.ContainerEventType, Action: events.ActionHealthStatusUnhealthy, Actor: actor(nil), Time: 100,
+			},
+			want:   ContainerEvent{Type: EventHealthStatus, HealthStatus: "unhealthy"},
+			wantOK: true,
+		},
+		{
+			name: "ステータスのないヘルスチェック",
+			msg: events.Message{
+				Type: events.ContainerEventType, Action: events.ActionHealthStatus, Actor: actor(nil), Time: 100,
+			},
+			wantOK: false,
+		},
+		{
+			name:   "対象外のイベント",
+			msg:    events.Message{Type: events.ContainerEventType, Action: events.ActionPause, Actor: actor(nil), Time: 100},
+			wantOK: false,
+		},
+		{
+			name:   "コンテナ以外のイベント",
+			msg:    events.Message{Type: events.NetworkEventType, Action: events.ActionConnect, Actor: actor(nil), Time: 100},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseEvent(&tt.msg)
+			if ok != tt.wantOK {
+				t.Fatalf("parseEvent() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			want := tt.want
+			want.ContainerID = "0123456789ab"
+			want.Name = "game-minecraft-1"
+			want.Service = "minecraft"
+			want.Time = time.Unix(100, 0)
+			if got != want {
+				t.Errorf("parseEvent() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+// fakeEventSource はテスト用のEventSource
+type fakeEventSource struct {
+	options  events.ListOptions
+	messages chan events.Message
+	errs     chan error
+}
+
+func (f *fakeEventSource) Events(_ context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	f.options = options
+	return f.messages, f.errs
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	source := &fakeEventSource{messages: make(chan events.Message, 2), errs: make(chan error, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out, errs := subscribeEvents(ctx, source, "game")
+
+	// プロジェクトとゲームコンテナのラベルで絞り込む
+	if !source.options.Filters.ExactMatch("label", "com.docker.compose.project=game") ||
+		!source.options.Filters.ExactMatch("label", LabelGameType) {
+		t.Errorf("Events() filters = %v, want project and game labels", source.options.Filters)
+	}
+	if !source.options.Filters.ExactMatch("event", "health_status") {
+		t.Errorf("Events() filters = %v, want health_status events", source.options.Filters)
+	}
+
+	source.messages <- events.Message{Type: events.ContainerEventType, Action: events.ActionPause}
+	source.messages <- events.Message{
+		Type: events.ContainerEventType, Action: events.ActionStart,
+		Actor: events.Actor{ID: "abc", Attributes: map[string]string{"name": "game-minecraft-1"}},
+	}
+
+	// 対象外のイベントは送らない
+	select {
+	case event := <-out:
+		if event.Type != EventStart || event.Name != "game-minecraft-1" {
+			t.Errorf("event = %+v, want start of game-minecraft-1", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+
+	// 購読が終了したらチャネルを閉じてエラーを返す
+	errStream := errors.New("unexpected EOF")
+	source.errs <- errStream
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("event channel should be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event channel was not closed")
+	}
+	if err := <-errs; !errors.Is(err, errStream) {
+		t.Errorf("error = %v, want %v", err, errStream)
+	}
+}
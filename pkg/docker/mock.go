@@ -1,6 +1,9 @@
 package docker
 
+import "context"
+
 // MockComposeService is a mock implementation of ComposeService for testing
+// モックの関数にはctxを渡さない
 type MockComposeService struct {
 	ListContainersFunc        func(composePath string) ([]ContainerInfo, error)
 	ListGameContainersFunc    func(composePath string) ([]ContainerInfo, error)
@@ -10,10 +13,12 @@ type MockComposeService struct {
 	GetAllContainersStatsFunc func(composePath string) ([]ContainerStats, error)
 	RestartContainerFunc      func(composePath, serviceName string) error
 	GetContainerLogsFunc      func(composePath, serviceName string, lines int) (string, error)
+	GetContainerEnvFunc       func(containerName string) (map[string]string, error)
+	PingFunc                  func() error
 }
 
 // ListContainers calls the mock function
-func (m *MockComposeService) ListContainers(composePath string) ([]ContainerInfo, error) {
+func (m *MockComposeService) ListContainers(_ context.Context, composePath string) ([]ContainerInfo, error) {
 	if m.ListContainersFunc != nil {
 		return m.ListContainersFunc(composePath)
 	}
@@ -21,16 +26,16 @@ func (m *MockComposeService) ListContainers(composePath string) ([]ContainerInfo
 }
 
 // ListGameContainers calls the mock function
-func (m *MockComposeService) ListGameContainers(composePath string) ([]ContainerInfo, error) {
+func (m *MockComposeService) ListGameContainers(ctx context.Context, composePath string) ([]ContainerInfo, error) {
 	if m.ListGameContainersFunc != nil {
 		return m.ListGameContainersFunc(composePath)
 	}
 	// デフォルトではListContainersと同じ動作
-	return m.ListContainers(composePath)
+	return m.ListContainers(ctx, composePath)
 }
 
 // StartService calls the mock function
-func (m *MockComposeService) StartService(composePath, serviceName string) error {
+func (m *MockComposeService) StartService(_ context.Context, composePath, serviceName string) error {
 	if m.StartServiceFunc != nil {
 		return m.StartServiceFunc(composePath, serviceName)
 	}
@@ -38,7 +43,7 @@ func (m *MockComposeService) StartService(composePath, serviceName string) error
 }
 
 // StopService calls the mock function
-func (m *MockComposeService) StopService(composePath, serviceName string) error {
+func (m *MockComposeService) StopService(_ context.Context, composePath, serviceName string) error {
 	if m.StopServiceFunc != nil {
 		return m.StopServiceFunc(composePath, serviceName)
 	}
@@ -46,7 +51,7 @@ func (m *MockComposeService) StopService(composePath, serviceName string) error
 }
 
 // GetContainerStats calls the mock function
-func (m *MockComposeService) GetContainerStats(containerName string) (*ContainerStats, error) {
+func (m *MockComposeService) GetContainerStats(_ context.Context, containerName string) (*ContainerStats, error) {
 	if m.GetContainerStatsFunc != nil {
 		return m.GetContainerStatsFunc(containerName)
 	}
@@ -54,7 +59,7 @@ func (m *MockComposeService) GetContainerStats(containerName string) (*Container
 }
 
 // GetAllContainersStats calls the mock function
-func (m *MockComposeService) GetAllContainersStats(composePath string) ([]ContainerStats, error) {
+func (m *MockComposeService) GetAllContainersStats(_ context.Context, composePath string) ([]ContainerStats, error) {
 	if m.GetAllContainersStatsFunc != nil {
 		return m.GetAllContainersStatsFunc(composePath)
 	}
@@ -62,7 +67,7 @@ func (m *MockComposeService) GetAllContainersStats(composePath string) ([]Contai
 }
 
 // RestartContainer calls the mock function
-func (m *MockComposeService) RestartContainer(composePath, serviceName string) error {
+func (m *MockComposeService) RestartContainer(_ context.Context, composePath, serviceName string) error {
 	if m.RestartContainerFunc != nil {
 		return m.RestartContainerFunc(composePath, serviceName)
 	}
@@ -70,13 +75,31 @@ func (m *MockComposeService) RestartContainer(composePath, serviceName string) e
 }
 
 // GetContainerLogs calls the mock function
-func (m *MockComposeService) GetContainerLogs(composePath, serviceName string, lines int) (string, error) {
+func (m *MockComposeService) GetContainerLogs(
+	_ context.Context, composePath, serviceName string, lines int,
+) (string, error) {
 	if m.GetContainerLogsFunc != nil {
 		return m.GetContainerLogsFunc(composePath, serviceName, lines)
 	}
 	return "", nil
 }
 
+// GetContainerEnv calls the mock function
+func (m *MockComposeService) GetContainerEnv(_ context.Context, containerName string) (map[string]string, error) {
+	if m.GetContainerEnvFunc != nil {
+		return m.GetContainerEnvFunc(containerName)
+	}
+	return nil, nil
+}
+
+// Ping calls the mock function
+func (m *MockComposeService) Ping(_ context.Context) error {
+	if m.PingFunc != nil {
+		return m.PingFunc()
+	}
+	return nil
+}
+
 // Close is a no-op for the mock
 func (m *MockComposeService) Close() error {
 	return nil
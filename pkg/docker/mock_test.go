@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"errors"
 	"testing"
 )
@@ -52,7 +53,7 @@ func TestMockComposeService_StartService(t *testing.T) {
 			m := &MockComposeService{
 				StartServiceFunc: tt.mockFunc,
 			}
-			err := m.StartService(tt.composePath, tt.serviceName)
+			err := m.StartService(context.Background(), tt.composePath, tt.serviceName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("StartService() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -108,10 +109,40 @@ func TestMockComposeService_StopService(t *testing.T) {
 			m := &MockComposeService{
 				StopServiceFunc: tt.mockFunc,
 			}
-			err := m.StopService(tt.composePath, tt.serviceName)
+			err := m.StopService(context.Background(), tt.composePath, tt.serviceName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("StopService() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestMockComposeService_Ping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mockFunc func() error
+		wantErr  bool
+	}{
+		{
+			name:     "ping error",
+			mockFunc: func() error { return errors.New("daemon unreachable") },
+			wantErr:  true,
+		},
+		{
+			name:     "nil function returns nil",
+			mockFunc: nil,
+			wantErr:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MockComposeService{PingFunc: tt.mockFunc}
+			if err := m.Ping(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// DefaultStatsWindow はコンテナごとに保持する直近のサンプル数のデフォルト値
+	DefaultStatsWindow = 60
+	// DefaultStatsSyncInterval はストリームを購読するコンテナを見直す間隔のデフォルト値
+	DefaultStatsSyncInterval = 15 * time.Second
+	// statsStaleAfter はこの時間更新されていないサンプルをキャッシュから返さない
+	statsStaleAfter = 10 * time.Second
+)
+
+// StatsStreamer はコンテナの統計情報を取得する（*client.Client が実装する）
+type StatsStreamer interface {
+	ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error)
+}
+
+// StatsCache は実行中のコンテナごとに統計情報のストリームを購読し、最新のサンプルを返すComposeService
+// キャッシュにないコンテナの統計情報と、統計情報以外の操作は元のComposeServiceに委譲する
+type StatsCache struct {
+	ComposeService
+	streamer    StatsStreamer
+	composePath string
+	window      int
+	interval    time.Duration
+	now         func() time.Time
+
+	mu         sync.RWMutex
+	synced     bool
+	containers []ContainerInfo        // ListContainersの順に並べた実行中のコンテナ
+	entries    map[string]*statsEntry // コンテナ名をキーとした購読中のストリーム
+}
+
+// statsEntry は1コンテナ分のストリームと直近のサンプル
+type statsEntry struct {
+	info    ContainerInfo
+	cancel  context.CancelFunc
+	history []ContainerStats // 古い順
+	updated time.Time
+}
+
+// NewStatsCache は新しいStatsCacheを作成する
+// windowは保持するサンプル数、intervalは購読するコンテナを見直す間隔で、0以下の場合はデフォルト値を使用する
+func NewStatsCache(
+	compose ComposeService,
+	streamer StatsStreamer,
+	composePath string,
+	window int,
+	interval time.Duration,
+) *StatsCache {
+	if window <= 0 {
+		window = DefaultStatsWindow
+	}
+	if interval <= 0 {
+		interval = DefaultStatsSyncInterval
+	}
+	return &StatsCache{
+		ComposeService: compose,
+		streamer:       streamer,
+		composePath:    composePath,
+		window:         window,
+		interval:       interval,
+		now:            time.Now,
+		entries:        make(map[string]*statsEntry),
+	}
+}
+
+// Run はコンテキストがキャンセルされるまで実行中のコンテナのストリームを購読し続ける
+func (c *StatsCache) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	logger.Info(ctx, "Starting container stats cache",
+		logging.String("interval", c.interval.String()),
+		logging.Int("window", c.window))
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.sync(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			c.stopAll()
+			logger.Info(ctx, "Container stats cache stopped")
+			return
+		case <-ticker.C:
+			c.sync(ctx)
+		}
+	}
+}
+
+// sync は実行中のコンテナを取得し、新しいコンテナの購読を開始して停止したコンテナの購読を終了する
+func (c *StatsCache) sync(ctx context.Context) {
+	containers, err := c.ComposeService.ListContainers(ctx, c.composePath)
+	if err != nil {
+		logging.FromContext(ctx).Warn(ctx, "Failed to list containers for stats cache", logging.ErrorField(err))
+		return
+	}
+
+	running := make(map[string]ContainerInfo, len(containers))
+	var ordered []ContainerInfo
+	for i := range containers {
+		if strings.EqualFold(containers[i].State, StateRunning) {
+			running[containers[i].Name] = containers[i]
+			ordered = append(ordered, containers[i])
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, entry := range c.entries {
+		// 停止したコンテナと、同じ名前で作り直されたコンテナの購読を終了する
+		if info, ok := running[name]; !ok || info.ID != entry.info.ID {
+			entry.cancel()
+			delete(c.entries, name)
+		}
+	}
+	for i := range ordered {
+		name := ordered[i].Name
+		if entry, ok := c.entries[name]; ok {
+			entry.info = ordered[i]
+			continue
+		}
+		streamCtx, cancel := context.WithCancel(ctx)
+		entry := &statsEntry{info: ordered[i], cancel: cancel}
+		c.entries[name] = entry
+		go c.stream(streamCtx, name, ordered[i].ID, entry)
+	}
+	c.containers = ordered
+	c.synced = true
+}
+
+// stream はストリームが終了するまで統計情報を受信してキャッシュする
+// ストリームが正常に終了した場合はコンテナが停止したとみなし、次の見直しまで統計情報を返さない
+func (c *StatsCache) stream(ctx context.Context, name, containerID string, entry *statsEntry) {
+	stopped := false
+	defer func() { c.remove(name, entry, stopped) }()
+
+	resp, err := c.streamer.ContainerStats(ctx, containerID, true)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.FromContext(ctx).Debug(ctx, "Failed to stream container stats",
+				logging.String("container", name), logging.ErrorField(err))
+		}
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// コンテキストがキャンセルされたらストリームを閉じて受信を終了する
+	stop := context.AfterFunc(ctx, func() { _ = resp.Body.Close() })
+	defer stop()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var stats container.StatsResponse
+		if err := decoder.Decode(&stats); err != nil {
+			stopped = errors.Is(err, io.EOF)
+			return
+		}
+		c.record(name, entry, &stats)
+	}
+}
+
+// record は受信したサンプルを直近のサンプルに追加する
+func (c *StatsCache) record(name string, entry *statsEntry, stats *container.StatsResponse) {
+	// 最初のサンプルは前回の値がなくCPU使用率を計算できないため記録しない
+	if stats.PreCPUStats.SystemUsage == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[name] != entry {
+		return
+	}
+	sample := calculateContainerStats(&entry.info, stats)
+	entry.history = append(entry.history, *sample)
+	if len(entry.history) > c.window {
+		entry.history = entry.history[len(entry.history)-c.window:]
+	}
+	entry.updated = c.now()
+}
+
+// remove は終了したストリームをキャッシュから取り除く
+// stoppedがfalseの場合、コンテナの統計情報は次の見直しまでDockerから直接取得する
+func (c *StatsCache) remove(name string, entry *statsEntry, stopped bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[name] != entry {
+		return
+	}
+	entry.cancel()
+	delete(c.entries, name)
+	if !stopped {
+		return
+	}
+	for i := range c.containers {
+		if c.containers[i].Name == name {
+			c.containers = append(c.containers[:i:i], c.containers[i+1:]...)
+			break
+		}
+	}
+}
+
+// stopAll はすべての購読を終了する
+func (c *StatsCache) stopAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, entry := range c.entries {
+		entry.cancel()
+		delete(c.entries, name)
+	}
+	c.containers = nil
+	c.synced = false
+}
+
+// latest はキャッシュされている最新のサンプルを返す（古い場合やない場合はfalse）
+// 呼び出し側でc.muを読み取りロックしていること
+func (c *StatsCache) latest(name string) (ContainerStats, bool) {
+	entry, ok := c.entries[name]
+	if !ok || len(entry.history) == 0 || c.now().Sub(entry.updated) > statsStaleAfter {
+		return ContainerStats{}, false
+	}
+	return entry.history[len(entry.history)-1], true
+}
+
+// GetContainerStats returns the cached stats of a container, or gets them from Docker if not cached
+func (c *StatsCache) GetContainerStats(ctx context.Context, containerName string) (*ContainerStats, error) {
+	c.mu.RLock()
+	stats, ok := c.latest(containerName)
+	c.mu.RUnlock()
+	if ok {
+		return &stats, nil
+	}
+	return c.ComposeService.GetContainerStats(ctx, containerName)
+}
+
+// GetAllContainersStats returns the cached stats of all running containers
+// まだサンプルのないコンテナの統計情報だけをDockerから取得する
+func (c *StatsCache) GetAllContainersStats(ctx context.Context, composePath string) ([]ContainerStats, error) {
+	c.mu.RLock()
+	if !c.synced || composePath != c.composePath {
+		c.mu.RUnlock()
+		return c.ComposeService.GetAllContainersStats(ctx, composePath)
+	}
+
+	containers := make([]ContainerInfo, len(c.containers))
+	copy(containers, c.containers)
+	results := make([]*ContainerStats, len(containers))
+	var missing []int
+	for i := range containers {
+		if stats, ok := c.latest(containers[i].Name); ok {
+			results[i] = &stats
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	c.mu.RUnlock()
+
+	errs := make([]error, len(containers))
+	forEachLimit(len(missing), MaxConcurrentStatsRequests, func(i int) {
+		j := missing[i]
+		results[j], errs[j] = c.ComposeService.GetContainerStats(ctx, containers[j].Name)
+	})
+
+	return mergeContainerStats(containers, results, errs)
+}
+
+// History returns the recent samples of a container, oldest first
+func (c *StatsCache) History(containerName string) []ContainerStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[containerName]
+	if !ok {
+		return nil
+	}
+	history := make([]ContainerStats, len(entry.history))
+	copy(history, entry.history)
+	return history
+}
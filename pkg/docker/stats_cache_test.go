@@ -0,0 +1,316 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// fakeStreamer はテスト用にパイプで統計情報を送るStatsStreamer
+type fakeStreamer struct {
+	mu      sync.Mutex
+	streams map[string]*io.PipeWriter
+	err     error
+}
+
+func newFakeStreamer() *fakeStreamer {
+	return &fakeStreamer{streams: make(map[string]*io.PipeWriter)}
+}
+
+func (f *fakeStreamer) ContainerStats(_ context.Context, containerID string, _ bool) (container.StatsResponseReader, error) {
+	if f.err != nil {
+		return container.StatsResponseReader{}, f.err
+	}
+	r, w := io.Pipe()
+	f.mu.Lock()
+	f.streams[containerID] = w
+	f.mu.Unlock()
+	return container.StatsResponseReader{Body: r}, nil
+}
+
+// writer はストリームが開かれるまで待って書き込み側を返す
+func (f *fakeStreamer) writer(t *testing.T, containerID string) *io.PipeWriter {
+	t.Helper()
+	var w *io.PipeWriter
+	waitFor(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		w = f.streams[containerID]
+		return w != nil
+	})
+	return w
+}
+
+// send はストリームに1サンプル分の統計情報を書き込む
+func (f *fakeStreamer) send(t *testing.T, containerID string, cpuTotal, memUsage uint64) {
+	t.Helper()
+	stats := container.StatsResponse{}
+	stats.PreCPUStats.SystemUsage = 1000
+	stats.CPUStats.SystemUsage = 2000
+	stats.CPUStats.CPUUsage.TotalUsage = cpuTotal
+	stats.CPUStats.OnlineCPUs = 1
+	stats.MemoryStats.Usage = memUsage
+	stats.MemoryStats.Limit = 1000
+	if err := json.NewEncoder(f.writer(t, containerID)).Encode(stats); err != nil {
+		t.Fatalf("failed to send stats: %v", err)
+	}
+}
+
+// waitFor は条件を満たすまで待つ
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// newTestStatsCache はminecraftとrustが実行中、terrariaが停止中のStatsCacheを作成する
+func newTestStatsCache(t *testing.T, streamer *fakeStreamer, fallback *[]string) *StatsCache {
+	t.Helper()
+	var mu sync.Mutex
+	compose := &MockComposeService{
+		ListContainersFunc: func(string) ([]ContainerInfo, error) {
+			return []ContainerInfo{
+				{ID: "aaa", Name: "game-minecraft-1", Service: "minecraft", State: "running", RestartCount: 2},
+				{ID: "bbb", Name: "game-rust-1", Service: "rust", State: "running"},
+				{ID: "ccc", Name: "game-terraria-1", Service: "terraria", State: "exited"},
+			}, nil
+		},
+		GetContainerStatsFunc: func(name string) (*ContainerStats, error) {
+			mu.Lock()
+			*fallback = append(*fallback, name)
+			mu.Unlock()
+			return &ContainerStats{Name: name, CPUPercent: 1}, nil
+		},
+	}
+	return NewStatsCache(compose, streamer, "docker-compose.yml", 3, 0)
+}
+
+func TestStatsCache_GetContainerStats(t *testing.T) {
+	streamer := newFakeStreamer()
+	var fallback []string
+	cache := newTestStatsCache(t, streamer, &fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.sync(ctx)
+
+	// 前回の値がない最初のサンプルは記録しない
+	first, _ := json.Marshal(container.StatsResponse{})
+	if _, err := streamer.writer(t, "aaa").Write(first); err != nil {
+		t.Fatalf("failed to send stats: %v", err)
+	}
+	streamer.send(t, "aaa", 500, 250)
+	waitFor(t, func() bool { return len(cache.History("game-minecraft-1")) == 1 })
+
+	stats, err := cache.GetContainerStats(context.Background(), "game-minecraft-1")
+	if err != nil {
+		t.Fatalf("GetContainerStats() error = %v", err)
+	}
+	if stats.CPUPercent != 50 || stats.MemoryPercent != 25 || stats.RestartCount != 2 || stats.Service != "minecraft" {
+		t.Errorf("GetContainerStats() = %+v, want cached sample", stats)
+	}
+	if len(fallback) != 0 {
+		t.Errorf("GetContainerStats() fell back to Docker for %v", fallback)
+	}
+
+	// サンプルのないコンテナはDockerから取得する
+	if _, err := cache.GetContainerStats(context.Background(), "game-rust-1"); err != nil {
+		t.Fatalf("GetContainerStats() error = %v", err)
+	}
+	if len(fallback) != 1 || fallback[0] != "game-rust-1" {
+		t.Errorf("fallback = %v, want [game-rust-1]", fallback)
+	}
+
+	// 古いサンプルは使用しない
+	cache.now = func() time.Time { return time.Now().Add(statsStaleAfter + time.Second) }
+	if _, err := cache.GetContainerStats(context.Background(), "game-minecraft-1"); err != nil {
+		t.Fatalf("GetContainerStats() error = %v", err)
+	}
+	if len(fallback) != 2 {
+		t.Errorf("fallback = %v, want stale sample to be fetched from Docker", fallback)
+	}
+}
+
+func TestStatsCache_GetAllContainersStats(t *testing.T) {
+	streamer := newFakeStreamer()
+	var fallback []string
+	cache := newTestStatsCache(t, streamer, &fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.sync(ctx)
+
+	streamer.send(t, "bbb", 200, 100)
+	waitFor(t, func() bool { return len(cache.History("game-rust-1")) == 1 })
+
+	stats, err := cache.GetAllContainersStats(context.Background(), "docker-compose.yml")
+	if err != nil {
+		t.Fatalf("GetAllContainersStats() error = %v", err)
+	}
+
+	// 実行中のコンテナだけを一覧の順に返し、サンプルのないコンテナだけDockerから取得する
+	if len(stats) != 2 || stats[0].Name != "game-minecraft-1" || stats[1].Name != "game-rust-1" {
+		t.Fatalf("GetAllContainersStats() = %+v, want minecraft and rust", stats)
+	}
+	if stats[1].CPUPercent != 20 {
+		t.Errorf("rust CPUPercent = %v, want cached 20", stats[1].CPUPercent)
+	}
+	if len(fallback) != 1 || fallback[0] != "game-minecraft-1" {
+		t.Errorf("fallback = %v, want [game-minecraft-1]", fallback)
+	}
+}
+
+func TestStatsCache_GetAllContainersStats_Delegates(t *testing.T) {
+	tests := []struct {
+		name        string
+		synced      bool
+		composePath string
+	}{
+		{name: "購読開始前", synced: false, composePath: "docker-compose.yml"},
+		{name: "別のcomposeファイル", synced: true, composePath: "other/docker-compose.yml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			compose := &MockComposeService{
+				ListContainersFunc: func(string) ([]ContainerInfo, error) { return nil, nil },
+				GetAllContainersStatsFunc: func(string) ([]ContainerStats, error) {
+					called = true
+					return nil, nil
+				},
+			}
+			cache := NewStatsCache(compose, newFakeStreamer(), "docker-compose.yml", 0, 0)
+			if tt.synced {
+				cache.sync(context.Background())
+			}
+
+			if _, err := cache.GetAllContainersStats(context.Background(), tt.composePath); err != nil {
+				t.Fatalf("GetAllContainersStats() error = %v", err)
+			}
+			if !called {
+				t.Error("GetAllContainersStats() should delegate to the underlying service")
+			}
+		})
+	}
+}
+
+func TestStatsCache_History(t *testing.T) {
+	streamer := newFakeStreamer()
+	var fallback []string
+	cache := newTestStatsCache(t, streamer, &fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.sync(ctx)
+
+	for i := uint64(1); i <= 5; i++ {
+		streamer.send(t, "aaa", i*100, 0)
+	}
+	waitFor(t, func() bool {
+		history := cache.History("game-minecraft-1")
+		return len(history) == 3 && history[2].CPUPercent == 50
+	})
+
+	// 直近のwindow件だけを古い順に保持する
+	history := cache.History("game-minecraft-1")
+	for i, want := range []float64{30, 40, 50} {
+		if history[i].CPUPercent != want {
+			t.Errorf("History()[%d].CPUPercent = %v, want %v", i, history[i].CPUPercent, want)
+		}
+	}
+	if got := cache.History("game-terraria-1"); got != nil {
+		t.Errorf("History() for stopped container = %v, want nil", got)
+	}
+}
+
+func TestStatsCache_StreamEnd(t *testing.T) {
+	streamer := newFakeStreamer()
+	var fallback []string
+	cache := newTestStatsCache(t, streamer, &fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.sync(ctx)
+
+	streamer.send(t, "bbb", 200, 100)
+	waitFor(t, func() bool { return len(cache.History("game-rust-1")) == 1 })
+
+	// ストリームが終了したコンテナは停止したとみなして一覧から外す
+	_ = streamer.writer(t, "bbb").Close()
+	waitFor(t, func() bool { return cache.History("game-rust-1") == nil })
+
+	stats, err := cache.GetAllContainersStats(context.Background(), "docker-compose.yml")
+	if err != nil {
+		t.Fatalf("GetAllContainersStats() error = %v", err)
+	}
+	if len(stats) != 1 || stats[0].Name != "game-minecraft-1" {
+		t.Errorf("GetAllContainersStats() = %+v, want only minecraft", stats)
+	}
+}
+
+func TestStatsCache_StreamError(t *testing.T) {
+	streamer := newFakeStreamer()
+	streamer.err = errors.New("connection refused")
+	var fallback []string
+	cache := newTestStatsCache(t, streamer, &fallback)
+
+	cache.sync(context.Background())
+	waitFor(t, func() bool {
+		cache.mu.RLock()
+		defer cache.mu.RUnlock()
+		return len(cache.entries) == 0
+	})
+
+	// ストリームを開けなかったコンテナは一覧に残し、Dockerから取得する
+	stats, err := cache.GetAllContainersStats(context.Background(), "docker-compose.yml")
+	if err != nil {
+		t.Fatalf("GetAllContainersStats() error = %v", err)
+	}
+	if len(stats) != 2 || len(fallback) != 2 {
+		t.Errorf("GetAllContainersStats() = %+v, fallback = %v, want both from Docker", stats, fallback)
+	}
+}
+
+func TestStatsCache_Run(t *testing.T) {
+	streamer := newFakeStreamer()
+	var fallback []string
+	cache := newTestStatsCache(t, streamer, &fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cache.Run(ctx)
+		close(done)
+	}()
+
+	w := streamer.writer(t, "aaa")
+	streamer.send(t, "aaa", 100, 0)
+	waitFor(t, func() bool { return len(cache.History("game-minecraft-1")) == 1 })
+
+	// 停止するとすべての購読を終了する
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after context cancel")
+	}
+	waitFor(t, func() bool {
+		_, err := w.Write([]byte(" "))
+		return err != nil
+	})
+	if got := cache.History("game-minecraft-1"); got != nil {
+		t.Errorf("History() after stop = %v, want nil", got)
+	}
+}
@@ -0,0 +1,220 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/hideA88/game-server-watchdog/pkg/logging"
+)
+
+const (
+	// LabelStopTimeout は停止シグナルを送ってから強制終了するまでの時間のラベル（"120s" や秒数）
+	LabelStopTimeout = "game.stop.timeout"
+	// LabelStopSignal は停止シグナルのラベル（例: SIGINT）
+	LabelStopSignal = "game.stop.signal"
+	// LabelStopExec は停止前にコンテナ内で実行するコマンドのラベル（; 区切りで複数指定）
+	LabelStopExec = "game.stop.exec"
+	// LabelStopRCON は停止前にRCONで送信するコマンドのラベル（; 区切りで複数指定）
+	LabelStopRCON = "game.stop.rcon"
+
+	// labelCommandSeparator はラベルで複数のコマンドを指定する場合の区切り文字
+	labelCommandSeparator = ";"
+	// PreStopCommandTimeout は停止前に実行するコマンド1つあたりのタイムアウト時間
+	PreStopCommandTimeout = 30 * time.Second
+)
+
+// StopSettings はサービスの停止・再起動の方法
+type StopSettings struct {
+	// Timeout は停止シグナルを送ってから強制終了するまでの時間（0の場合はコンテナの設定、未設定の場合はDockerのデフォルトの10秒）
+	Timeout time.Duration
+	// Signal は停止シグナル（空の場合はコンテナの設定）
+	Signal string
+	// Exec は停止前にコンテナ内で実行するコマンド（sh -c で実行する）
+	Exec []string
+	// RCON は停止前にRCONで送信するコマンド（例: save-all）
+	RCON []string
+}
+
+// StopPolicy はサービスごとの停止方法を返す
+type StopPolicy interface {
+	StopSettings(service string) StopSettings
+}
+
+// CommandSender はサービスのゲームサーバーにRCONでコマンドを送信する
+type CommandSender interface {
+	Run(ctx context.Context, service, command string) (string, error)
+}
+
+// StopSettingsFor は設定とコンテナのラベルから停止方法を返す（項目ごとに設定がラベルより優先される）
+func StopSettingsFor(configured StopSettings, labels map[string]string) StopSettings {
+	settings := configured
+	if settings.Timeout == 0 {
+		settings.Timeout = parseStopTimeout(labels[LabelStopTimeout])
+	}
+	if settings.Signal == "" {
+		settings.Signal = strings.TrimSpace(labels[LabelStopSignal])
+	}
+	if len(settings.Exec) == 0 {
+		settings.Exec = splitLabelCommands(labels[LabelStopExec])
+	}
+	if len(settings.RCON) == 0 {
+		settings.RCON = splitLabelCommands(labels[LabelStopRCON])
+	}
+	return settings
+}
+
+// parseStopTimeout は "120s" のような時間か秒数を読み込む（不正な値の場合は0）
+func parseStopTimeout(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
+
+// splitLabelCommands はラベルの値を ; で区切ったコマンドに分割する
+func splitLabelCommands(value string) []string {
+	var commands []string
+	for _, command := range strings.Split(value, labelCommandSeparator) {
+		if command = strings.TrimSpace(command); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// options はDocker APIに渡す停止のオプションを返す
+func (s StopSettings) options() container.StopOptions {
+	options := container.StopOptions{Signal: s.Signal}
+	if s.Timeout > 0 {
+		timeout := int(math.Ceil(s.Timeout.Seconds()))
+		options.Timeout = &timeout
+	}
+	return options
+}
+
+// operationTimeout はコンテナ1つの停止・再起動を待つ時間を返す
+// 停止前のコマンドと、停止シグナルを送ってから強制終了するまでの時間を含める
+func (s StopSettings) operationTimeout() time.Duration {
+	commands := len(s.Exec) + len(s.RCON)
+	return ServiceOperationTimeout + s.Timeout + time.Duration(commands)*PreStopCommandTimeout
+}
+
+// SetStopPolicy はサービスごとの停止方法を設定する
+// senderがnilの場合は停止前のRCONコマンドを送信しない
+func (s *DefaultComposeService) SetStopPolicy(policy StopPolicy, sender CommandSender) {
+	s.stopPolicy = policy
+	s.commandSender = sender
+}
+
+// stopSettings はサービスのコンテナの停止方法を返す
+func (s *DefaultComposeService) stopSettings(service string, c container.Summary) StopSettings {
+	var configured StopSettings
+	if s.stopPolicy != nil {
+		configured = s.stopPolicy.StopSettings(service)
+	}
+	return StopSettingsFor(configured, c.Labels)
+}
+
+// stopContainer は停止前のコマンドを実行してから、設定したシグナルとタイムアウトでコンテナを停止・再起動する
+func (s *DefaultComposeService) stopContainer(
+	ctx context.Context, service string, c container.Summary, restart bool,
+) error {
+	settings := s.stopSettings(service, c)
+	ctx, cancel := context.WithTimeout(ctx, settings.operationTimeout())
+	defer cancel()
+
+	if strings.EqualFold(c.State, StateRunning) {
+		s.preStop(ctx, service, c.ID, settings)
+	}
+	if restart {
+		return s.client.ContainerRestart(ctx, c.ID, settings.options())
+	}
+	return s.client.ContainerStop(ctx, c.ID, settings.options())
+}
+
+// preStop は停止前のコマンドを順に実行する
+// ワールドの保存などに失敗しても停止できなくならないように、失敗は記録して停止を続ける
+func (s *DefaultComposeService) preStop(ctx context.Context, service, containerID string, settings StopSettings) {
+	logger := logging.FromContext(ctx)
+	for _, command := range settings.Exec {
+		if err := s.execInContainer(ctx, containerID, command); err != nil {
+			logger.Warn(ctx, "Pre-stop exec command failed",
+				logging.String("service", service),
+				logging.String("command", command),
+				logging.ErrorField(err))
+		}
+	}
+	for _, command := range settings.RCON {
+		if err := s.sendRCON(ctx, service, command); err != nil {
+			logger.Warn(ctx, "Pre-stop RCON command failed",
+				logging.String("service", service),
+				logging.String("command", command),
+				logging.ErrorField(err))
+		}
+	}
+}
+
+// sendRCON は停止前のRCONコマンドを送信する
+func (s *DefaultComposeService) sendRCON(ctx context.Context, service, command string) error {
+	if s.commandSender == nil {
+		return errors.New("rcon is not available")
+	}
+	ctx, cancel := context.WithTimeout(ctx, PreStopCommandTimeout)
+	defer cancel()
+	_, err := s.commandSender.Run(ctx, service, command)
+	return err
+}
+
+// execInContainer はコンテナ内でコマンドを実行し、終了するまで待つ
+func (s *DefaultComposeService) execInContainer(ctx context.Context, containerID, command string) error {
+	ctx, cancel := context.WithTimeout(ctx, PreStopCommandTimeout)
+	defer cancel()
+
+	exec, err := s.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          []string{"sh", "-c", command},
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	resp, err := s.client.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to start exec: %w", err)
+	}
+	defer resp.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := resp.Conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set exec deadline: %w", err)
+		}
+	}
+	// 出力を読み終えた時点でコマンドは終了している
+	if _, err := io.Copy(io.Discard, resp.Reader); err != nil {
+		return fmt.Errorf("failed to wait for exec: %w", err)
+	}
+
+	inspect, err := s.client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("exec exited with code %d", inspect.ExitCode)
+	}
+	return nil
+}
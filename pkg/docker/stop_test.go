@@ -0,0 +1,169 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// stopPolicyFunc は関数をStopPolicyとして扱うためのアダプター
+type stopPolicyFunc func(service string) StopSettings
+
+func (f stopPolicyFunc) StopSettings(service string) StopSettings {
+	return f(service)
+}
+
+// commandSenderFunc は関数をCommandSenderとして扱うためのアダプター
+type commandSenderFunc func(ctx context.Context, service, command string) (string, error)
+
+func (f commandSenderFunc) Run(ctx context.Context, service, command string) (string, error) {
+	return f(ctx, service, command)
+}
+
+func TestStopSettingsFor(t *testing.T) {
+	labels := map[string]string{
+		LabelStopTimeout: "120",
+		LabelStopSignal:  "SIGINT",
+		LabelStopExec:    "rcon-cli save-all; sync",
+		LabelStopRCON:    "save-all;;stop",
+	}
+
+	tests := []struct {
+		name       string
+		configured StopSettings
+		labels     map[string]string
+		want       StopSettings
+	}{
+		{
+			name:       "ラベルのみ",
+			configured: StopSettings{},
+			labels:     labels,
+			want: StopSettings{
+				Timeout: 2 * time.Minute,
+				Signal:  "SIGINT",
+				Exec:    []string{"rcon-cli save-all", "sync"},
+				RCON:    []string{"save-all", "stop"},
+			},
+		},
+		{
+			name:       "設定がラベルより優先",
+			configured: StopSettings{Timeout: time.Minute, RCON: []string{"save-all flush"}},
+			labels:     labels,
+			want: StopSettings{
+				Timeout: time.Minute,
+				Signal:  "SIGINT",
+				Exec:    []string{"rcon-cli save-all", "sync"},
+				RCON:    []string{"save-all flush"},
+			},
+		},
+		{
+			name:       "設定もラベルもない",
+			configured: StopSettings{},
+			labels:     nil,
+			want:       StopSettings{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StopSettingsFor(tt.configured, tt.labels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StopSettingsFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStopTimeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "90", want: 90 * time.Second},
+		{value: "2m", want: 2 * time.Minute},
+		{value: " 30s ", want: 30 * time.Second},
+		{value: "", want: 0},
+		{value: "0", want: 0},
+		{value: "-10", want: 0},
+		{value: "forever", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseStopTimeout(tt.value); got != tt.want {
+				t.Errorf("parseStopTimeout(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopSettings_options(t *testing.T) {
+	if got := (StopSettings{}).options(); got.Timeout != nil || got.Signal != "" {
+		t.Errorf("options() = %+v, want Docker defaults", got)
+	}
+
+	got := StopSettings{Timeout: 1500 * time.Millisecond, Signal: "SIGINT"}.options()
+	if got.Timeout == nil || *got.Timeout != 2 || got.Signal != "SIGINT" {
+		t.Errorf("options() = %+v, want 2 seconds and SIGINT", got)
+	}
+}
+
+func TestStopSettings_operationTimeout(t *testing.T) {
+	settings := StopSettings{Timeout: time.Minute, Exec: []string{"sync"}, RCON: []string{"save-all", "stop"}}
+	want := ServiceOperationTimeout + time.Minute + 3*PreStopCommandTimeout
+	if got := settings.operationTimeout(); got != want {
+		t.Errorf("operationTimeout() = %v, want %v", got, want)
+	}
+}
+
+func TestDefaultComposeService_stopSettings(t *testing.T) {
+	s := &DefaultComposeService{}
+	c := container.Summary{Labels: map[string]string{LabelStopTimeout: "60", LabelStopSignal: "SIGINT"}}
+
+	// 設定がない場合はラベルのみ
+	if got := s.stopSettings("minecraft", c); got.Timeout != time.Minute || got.Signal != "SIGINT" {
+		t.Errorf("stopSettings() = %+v", got)
+	}
+
+	s.SetStopPolicy(stopPolicyFunc(func(service string) StopSettings {
+		if service == "minecraft" {
+			return StopSettings{Timeout: 5 * time.Minute}
+		}
+		return StopSettings{}
+	}), nil)
+	if got := s.stopSettings("minecraft", c); got.Timeout != 5*time.Minute || got.Signal != "SIGINT" {
+		t.Errorf("stopSettings(minecraft) = %+v", got)
+	}
+	if got := s.stopSettings("terraria", c); got.Timeout != time.Minute {
+		t.Errorf("stopSettings(terraria) = %+v", got)
+	}
+}
+
+func TestDefaultComposeService_preStop_RCON(t *testing.T) {
+	var sent []string
+	s := &DefaultComposeService{}
+	s.SetStopPolicy(nil, commandSenderFunc(func(ctx context.Context, service, command string) (string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("RCON command sent without deadline")
+		}
+		sent = append(sent, service+": "+command)
+		if command == "save-all" {
+			return "", errors.New("connection refused")
+		}
+		return "", nil
+	}))
+
+	// 失敗しても残りのコマンドを送信する
+	s.preStop(context.Background(), "minecraft", "abc", StopSettings{RCON: []string{"save-all", "stop"}})
+	if want := []string{"minecraft: save-all", "minecraft: stop"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+
+	// 送信先がない場合は何もしない
+	if err := (&DefaultComposeService{}).sendRCON(context.Background(), "minecraft", "stop"); err == nil {
+		t.Error("sendRCON() without sender error = nil")
+	}
+}
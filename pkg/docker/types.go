@@ -1,6 +1,9 @@
 package docker
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // ContainerInfo represents information about a Docker container
 type ContainerInfo struct {
@@ -13,38 +16,52 @@ type ContainerInfo struct {
 	RunningFor   string
 	Ports        []string
 	HealthStatus string
+	RestartCount int // Dockerによる再起動回数
 	CreatedAt    time.Time
+	Labels       map[string]string
 }
 
 // ContainerStats represents container resource usage statistics
+// 表示用の整形は呼び出し側で行う（バイト数はそのままの値を保持する）
 type ContainerStats struct {
-	ContainerID   string
-	Name          string
-	CPUPercent    float64
-	MemoryPercent float64
-	MemoryUsage   string // e.g., "1.5GiB / 2GiB"
-	NetworkIO     string // e.g., "1.2MB / 3.4MB"
-	BlockIO       string // e.g., "5.6MB / 7.8MB"
+	ContainerID      string
+	Name             string
+	Service          string // docker-composeのサービス名（ラベルから取得）
+	CPUPercent       float64
+	MemoryPercent    float64
+	MemoryUsageBytes uint64
+	MemoryLimitBytes uint64
+	NetworkRxBytes   uint64 // 全ネットワークの受信バイト数の合計
+	NetworkTxBytes   uint64 // 全ネットワークの送信バイト数の合計
+	BlockReadBytes   uint64
+	BlockWriteBytes  uint64
+	PIDs             uint64 // コンテナ内のプロセス数
+	RestartCount     int    // Dockerによる再起動回数
 }
 
 // ComposeService represents Docker Compose operations
+// 問い合わせはctxのキャンセルとタイムアウトに従う。起動・停止・再起動はコンテナの検索後に開始した操作を中断しない
 type ComposeService interface {
 	// ListContainers returns a list of containers managed by docker-compose
-	ListContainers(composePath string) ([]ContainerInfo, error)
+	ListContainers(ctx context.Context, composePath string) ([]ContainerInfo, error)
 	// ListGameContainers returns a list of game containers (with game.type label)
-	ListGameContainers(composePath string) ([]ContainerInfo, error)
+	ListGameContainers(ctx context.Context, composePath string) ([]ContainerInfo, error)
 	// StartService starts a specific service
-	StartService(composePath string, serviceName string) error
+	StartService(ctx context.Context, composePath string, serviceName string) error
 	// StopService stops a specific service
-	StopService(composePath string, serviceName string) error
+	StopService(ctx context.Context, composePath string, serviceName string) error
 	// GetContainerStats gets resource usage stats for a specific container
-	GetContainerStats(containerName string) (*ContainerStats, error)
+	GetContainerStats(ctx context.Context, containerName string) (*ContainerStats, error)
 	// GetAllContainersStats gets resource usage stats for all containers
-	GetAllContainersStats(composePath string) ([]ContainerStats, error)
+	GetAllContainersStats(ctx context.Context, composePath string) ([]ContainerStats, error)
 	// RestartContainer restarts a specific container
-	RestartContainer(composePath string, serviceName string) error
+	RestartContainer(ctx context.Context, composePath string, serviceName string) error
 	// GetContainerLogs gets logs from a specific container
-	GetContainerLogs(composePath string, serviceName string, lines int) (string, error)
+	GetContainerLogs(ctx context.Context, composePath string, serviceName string, lines int) (string, error)
+	// GetContainerEnv returns the environment variables of a specific container
+	GetContainerEnv(ctx context.Context, containerName string) (map[string]string, error)
+	// Ping checks whether the Docker daemon is reachable
+	Ping(ctx context.Context) error
 	// Close closes the Docker client connection
 	Close() error
 }
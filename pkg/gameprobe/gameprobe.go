@@ -0,0 +1,132 @@
+// Package gameprobe はゲームサーバーのクエリプロトコル（Minecraft Server List Ping、Source A2S）で
+// サーバーが実際に応答しているか、接続中のプレイヤー数などを取得する機能を提供します
+package gameprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout はクエリ1回あたりのデフォルトのタイムアウト時間
+const DefaultTimeout = 3 * time.Second
+
+// Protocol はゲームサーバーのクエリプロトコル
+type Protocol string
+
+// 対応しているクエリプロトコル
+const (
+	// ProtocolMinecraft はMinecraft Java EditionのServer List Ping（TCP）
+	ProtocolMinecraft Protocol = "minecraft"
+	// ProtocolSource はValveのSource Engine Query（A2S_INFO / A2S_PLAYER、UDP）
+	ProtocolSource Protocol = "source"
+)
+
+// ErrUnsupportedProtocol は対応していないクエリプロトコルの場合のエラー
+var ErrUnsupportedProtocol = errors.New("unsupported query protocol")
+
+// Status はゲームサーバーから取得した状態
+type Status struct {
+	Name        string        // サーバー名（MinecraftではMOTD）
+	Map         string        // マップ名（Minecraftでは空）
+	Version     string        // ゲームのバージョン
+	Players     int           // 接続中のプレイヤー数
+	MaxPlayers  int           // 最大プレイヤー数
+	PlayerNames []string      // 接続中のプレイヤー名（サーバーが返した分だけ）
+	Latency     time.Duration // 状態の問い合わせに応答するまでの時間
+}
+
+// Target はクエリを送信するゲームサーバー
+type Target struct {
+	Container string // コンテナ名
+	Service   string // docker-composeのサービス名
+	Protocol  Protocol
+	Host      string
+	Port      int
+}
+
+// Address はクエリを送信するアドレス（host:port）を返す
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// Prober はゲームサーバーにクエリを送信して状態を取得する
+type Prober interface {
+	Probe(ctx context.Context, target Target) (*Status, error)
+}
+
+// DefaultProber はプロトコルに応じたクエリでゲームサーバーの状態を取得する
+type DefaultProber struct {
+	timeout time.Duration
+}
+
+// NewProber は新しいDefaultProberを作成する（timeoutが0以下の場合はDefaultTimeout）
+func NewProber(timeout time.Duration) *DefaultProber {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &DefaultProber{timeout: timeout}
+}
+
+// Probe はゲームサーバーにクエリを送信して状態を取得する
+func (p *DefaultProber) Probe(ctx context.Context, target Target) (*Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var status *Status
+	var err error
+	switch target.Protocol {
+	case ProtocolMinecraft:
+		status, err = queryMinecraft(ctx, target.Host, target.Port)
+	case ProtocolSource:
+		status, err = querySource(ctx, target.Address())
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedProtocol, target.Protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s (%s): %w", target.Address(), target.Protocol, err)
+	}
+	return status, nil
+}
+
+// Result は1つのゲームサーバーへのクエリの結果
+type Result struct {
+	Status *Status
+	Err    error
+}
+
+// Online はゲームサーバーが応答したかどうかを返す
+func (r Result) Online() bool {
+	return r.Err == nil && r.Status != nil
+}
+
+// ProbeAll はすべてのゲームサーバーに並行してクエリを送信し、コンテナ名をキーとした結果を返す
+func ProbeAll(ctx context.Context, prober Prober, targets []Target) map[string]Result {
+	results := make(map[string]Result, len(targets))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := prober.Probe(ctx, target)
+			mu.Lock()
+			results[target.Container] = Result{Status: status, Err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// deadline はコンテキストの期限（期限がない場合はDefaultTimeout後）を返す
+func deadline(ctx context.Context) time.Time {
+	if d, ok := ctx.Deadline(); ok {
+		return d
+	}
+	return time.Now().Add(DefaultTimeout)
+}
@@ -0,0 +1,97 @@
+package gameprobe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewProber(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{name: "指定したタイムアウト", timeout: time.Second, want: time.Second},
+		{name: "0の場合はデフォルト", timeout: 0, want: DefaultTimeout},
+		{name: "負の場合はデフォルト", timeout: -time.Second, want: DefaultTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewProber(tt.timeout).timeout; got != tt.want {
+				t.Errorf("NewProber(%v).timeout = %v, want %v", tt.timeout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultProber_Probe(t *testing.T) {
+	port := minecraftServer(t, func(conn net.Conn, _ []byte) {
+		_, _ = conn.Write(minecraftResponse(`{"version":{"name":"1.20.4"},"players":{"max":20,"online":1}}`))
+	})
+
+	prober := NewProber(time.Second)
+	got, err := prober.Probe(context.Background(), Target{Protocol: ProtocolMinecraft, Host: "127.0.0.1", Port: port})
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if got.Version != "1.20.4" || got.Players != 1 || got.MaxPlayers != 20 {
+		t.Errorf("Probe() = %+v", got)
+	}
+	if got.Latency <= 0 {
+		t.Errorf("Probe() Latency = %v, want > 0", got.Latency)
+	}
+}
+
+func TestDefaultProber_Probe_Errors(t *testing.T) {
+	t.Run("未対応のプロトコル", func(t *testing.T) {
+		_, err := NewProber(time.Second).Probe(context.Background(), Target{Protocol: "gamespy", Host: "localhost", Port: 1})
+		if !errors.Is(err, ErrUnsupportedProtocol) {
+			t.Errorf("Probe() error = %v, want %v", err, ErrUnsupportedProtocol)
+		}
+	})
+
+	t.Run("タイムアウト", func(t *testing.T) {
+		port := minecraftServer(t, nil)
+		start := time.Now()
+		_, err := NewProber(100*time.Millisecond).Probe(
+			context.Background(), Target{Protocol: ProtocolMinecraft, Host: "127.0.0.1", Port: port})
+		if err == nil {
+			t.Fatal("Probe() error = nil, want timeout")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Probe() took %v, want about 100ms", elapsed)
+		}
+	})
+}
+
+func TestProbeAll(t *testing.T) {
+	prober := &MockProber{
+		ProbeFunc: func(_ context.Context, target Target) (*Status, error) {
+			if target.Port == 0 {
+				return nil, errors.New("connection refused")
+			}
+			return &Status{Players: target.Port}, nil
+		},
+	}
+	targets := []Target{
+		{Container: "mc-1", Port: 3},
+		{Container: "valheim-1", Port: 0},
+	}
+
+	got := ProbeAll(context.Background(), prober, targets)
+	if len(got) != 2 {
+		t.Fatalf("ProbeAll() returned %d results, want 2", len(got))
+	}
+	if r := got["mc-1"]; !r.Online() || r.Status.Players != 3 {
+		t.Errorf("ProbeAll()[mc-1] = %+v, want online with 3 players", r)
+	}
+	if r := got["valheim-1"]; r.Online() || r.Err == nil {
+		t.Errorf("ProbeAll()[valheim-1] = %+v, want error", r)
+	}
+	if got := ProbeAll(context.Background(), prober, nil); len(got) != 0 {
+		t.Errorf("ProbeAll(nil) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,163 @@
+package gameprobe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minecraftMaxResponse はServer List Pingの応答の最大バイト数
+	minecraftMaxResponse = 1 << 20
+	// minecraftStatusState はハンドシェイクで指定する次の状態（ステータス）
+	minecraftStatusState = 1
+	// minecraftAnyVersion はハンドシェイクで指定するプロトコルバージョン（-1をVarIntの符号なし表現にしたもの）
+	minecraftAnyVersion = 0xFFFFFFFF
+)
+
+// formatCodePattern はMOTDに含まれる書式コード（§ + 1文字）
+var formatCodePattern = regexp.MustCompile("§.")
+
+// minecraftStatus はServer List Pingの応答のJSON
+type minecraftStatus struct {
+	Version struct {
+		Name string `json:"name"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description"`
+}
+
+// chatComponent はMOTDのチャットコンポーネント
+type chatComponent struct {
+	Text  string          `json:"text"`
+	Extra []chatComponent `json:"extra"`
+}
+
+// queryMinecraft はMinecraft Java EditionのServer List Pingでサーバーの状態を取得する
+func queryMinecraft(ctx context.Context, host string, port int) (*Status, error) {
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(deadline(ctx)); err != nil {
+		return nil, err
+	}
+
+	// ハンドシェイク（プロトコルバージョン-1はバージョンを問わない問い合わせ）とステータス要求
+	handshake := []byte{0x00}
+	handshake = binary.AppendUvarint(handshake, minecraftAnyVersion)
+	handshake = binary.AppendUvarint(handshake, uint64(len(host)))
+	handshake = append(handshake, host...)
+	handshake = binary.BigEndian.AppendUint16(handshake, uint16(port))
+	handshake = binary.AppendUvarint(handshake, minecraftStatusState)
+
+	request := binary.AppendUvarint(nil, uint64(len(handshake)))
+	request = append(request, handshake...)
+	request = append(request, 0x01, 0x00)
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	payload, err := readMinecraftPacket(bufio.NewReader(conn))
+	if err != nil {
+		return nil, err
+	}
+	status, err := parseMinecraftStatus(payload)
+	if err != nil {
+		return nil, err
+	}
+	status.Latency = time.Since(start)
+	return status, nil
+}
+
+// readMinecraftPacket はステータス応答のパケットからJSON文字列を取り出す
+func readMinecraftPacket(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read packet length: %w", err)
+	}
+	if length == 0 || length > minecraftMaxResponse {
+		return nil, fmt.Errorf("invalid packet length: %d", length)
+	}
+	packet := bufio.NewReader(io.LimitReader(r, int64(length)))
+
+	id, err := binary.ReadUvarint(packet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read packet id: %w", err)
+	}
+	if id != 0x00 {
+		return nil, fmt.Errorf("unexpected packet id: %#x", id)
+	}
+
+	size, err := binary.ReadUvarint(packet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response length: %w", err)
+	}
+	if size >= length {
+		return nil, fmt.Errorf("invalid response length: %d", size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(packet, payload); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return payload, nil
+}
+
+// parseMinecraftStatus はServer List Pingの応答のJSONを解析する
+func parseMinecraftStatus(payload []byte) (*Status, error) {
+	var resp minecraftStatus
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+
+	status := &Status{
+		Name:       parseDescription(resp.Description),
+		Version:    resp.Version.Name,
+		Players:    resp.Players.Online,
+		MaxPlayers: resp.Players.Max,
+	}
+	for _, player := range resp.Players.Sample {
+		status.PlayerNames = append(status.PlayerNames, player.Name)
+	}
+	return status, nil
+}
+
+// parseDescription はMOTD（文字列またはチャットコンポーネント）から書式コードを除いたテキストを返す
+func parseDescription(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		var component chatComponent
+		if err := json.Unmarshal(raw, &component); err != nil {
+			return ""
+		}
+		text = component.plainText()
+	}
+	return strings.TrimSpace(formatCodePattern.ReplaceAllString(text, ""))
+}
+
+// plainText はチャットコンポーネントとその子のテキストを連結して返す
+func (c chatComponent) plainText() string {
+	var builder strings.Builder
+	builder.WriteString(c.Text)
+	for _, extra := range c.Extra {
+		builder.WriteString(extra.plainText())
+	}
+	return builder.String()
+}
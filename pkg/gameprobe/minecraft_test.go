@@ -0,0 +1,189 @@
+package gameprobe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// minecraftServer はServer List Pingに応答するテスト用のサーバーを起動し、ポートを返す
+// respondがnilの場合は接続を受け付けるだけで応答しない
+func minecraftServer(t *testing.T, respond func(conn net.Conn, handshake []byte)) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return
+		}
+		handshake := make([]byte, length)
+		if _, err := io.ReadFull(r, handshake); err != nil {
+			return
+		}
+		request := make([]byte, 2)
+		if _, err := io.ReadFull(r, request); err != nil {
+			return
+		}
+		if respond != nil {
+			respond(conn, handshake)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r)
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// minecraftResponse はJSONをステータス応答のパケットにする
+func minecraftResponse(payload string) []byte {
+	body := binary.AppendUvarint([]byte{0x00}, uint64(len(payload)))
+	body = append(body, payload...)
+	return append(binary.AppendUvarint(nil, uint64(len(body))), body...)
+}
+
+func TestQueryMinecraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    *Status
+	}{
+		{
+			name: "正常系 - MOTDが文字列",
+			payload: `{"version":{"name":"1.20.4","protocol":765},` +
+				`"players":{"max":20,"online":2,"sample":[{"name":"Steve","id":"a"},{"name":"Alex","id":"b"}]},` +
+				`"description":"§aA Minecraft Server"}`,
+			want: &Status{
+				Name:        "A Minecraft Server",
+				Version:     "1.20.4",
+				Players:     2,
+				MaxPlayers:  20,
+				PlayerNames: []string{"Steve", "Alex"},
+			},
+		},
+		{
+			name: "正常系 - MOTDがチャットコンポーネント",
+			payload: `{"version":{"name":"Paper 1.21"},"players":{"max":10,"online":0},` +
+				`"description":{"text":"Hello ","extra":[{"text":"§lWorld"}]}}`,
+			want: &Status{
+				Name:       "Hello World",
+				Version:    "Paper 1.21",
+				MaxPlayers: 10,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handshakes := make(chan []byte, 1)
+			port := minecraftServer(t, func(conn net.Conn, handshake []byte) {
+				handshakes <- handshake
+				_, _ = conn.Write(minecraftResponse(tt.payload))
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			got, err := queryMinecraft(ctx, "127.0.0.1", port)
+			if err != nil {
+				t.Fatalf("queryMinecraft() error = %v", err)
+			}
+			if got.Latency <= 0 {
+				t.Errorf("queryMinecraft() Latency = %v, want > 0", got.Latency)
+			}
+			got.Latency = 0
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queryMinecraft() = %+v, want %+v", got, tt.want)
+			}
+
+			// ハンドシェイク: パケットID、プロトコルバージョン、ホスト、ポート、次の状態
+			want := []byte{0x00}
+			want = binary.AppendUvarint(want, minecraftAnyVersion)
+			want = binary.AppendUvarint(want, uint64(len("127.0.0.1")))
+			want = append(want, "127.0.0.1"...)
+			want = binary.BigEndian.AppendUint16(want, uint16(port))
+			want = append(want, minecraftStatusState)
+			if handshake := <-handshakes; !reflect.DeepEqual(handshake, want) {
+				t.Errorf("handshake = %x, want %x", handshake, want)
+			}
+		})
+	}
+}
+
+func TestQueryMinecraft_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(conn net.Conn, handshake []byte)
+	}{
+		{
+			name:    "異常系 - 応答しない",
+			respond: nil,
+		},
+		{
+			name: "異常系 - パケットIDが違う",
+			respond: func(conn net.Conn, _ []byte) {
+				_, _ = conn.Write([]byte{0x02, 0x01, 0x00})
+			},
+		},
+		{
+			name: "異常系 - JSONが不正",
+			respond: func(conn net.Conn, _ []byte) {
+				_, _ = conn.Write(minecraftResponse("{"))
+			},
+		},
+		{
+			name: "異常系 - 応答が途中で切れる",
+			respond: func(conn net.Conn, _ []byte) {
+				_, _ = conn.Write(minecraftResponse(`{"players":{}}`)[:5])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := minecraftServer(t, tt.respond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if got, err := queryMinecraft(ctx, "127.0.0.1", port); err == nil {
+				t.Errorf("queryMinecraft() = %+v, want error", got)
+			}
+		})
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "文字列", raw: `"§6Welcome§r "`, want: "Welcome"},
+		{name: "チャットコンポーネント", raw: `{"text":"","extra":[{"text":"a"},{"text":"b","extra":[{"text":"c"}]}]}`, want: "abc"},
+		{name: "空", raw: `null`, want: ""},
+		{name: "不正な形式", raw: `123`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDescription(json.RawMessage(tt.raw)); got != tt.want {
+				t.Errorf("parseDescription(%s) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
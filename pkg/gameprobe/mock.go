@@ -0,0 +1,16 @@
+package gameprobe
+
+import "context"
+
+// MockProber はテスト用のモック実装
+type MockProber struct {
+	ProbeFunc func(ctx context.Context, target Target) (*Status, error)
+}
+
+// Probe はProbeFuncの結果を返す（ProbeFuncがnilの場合は空の状態を返す）
+func (m *MockProber) Probe(ctx context.Context, target Target) (*Status, error) {
+	if m.ProbeFunc != nil {
+		return m.ProbeFunc(ctx, target)
+	}
+	return &Status{}, nil
+}
@@ -0,0 +1,213 @@
+package gameprobe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// sourceMaxPacket はSource Engine Queryの1パケットの最大バイト数
+	sourceMaxPacket = 1400
+
+	// A2Sのリクエスト・レスポンスの種類
+	a2sInfoRequest    = 'T'
+	a2sInfoResponse   = 'I'
+	a2sPlayerRequest  = 'U'
+	a2sPlayerResponse = 'D'
+	a2sChallenge      = 'A'
+)
+
+var (
+	// a2sSinglePacket は分割されていないパケットのヘッダー
+	a2sSinglePacket = []byte{0xFF, 0xFF, 0xFF, 0xFF}
+	// a2sSplitPacket は分割されたパケットのヘッダー
+	a2sSplitPacket = []byte{0xFE, 0xFF, 0xFF, 0xFF}
+	// a2sInfoPayload はA2S_INFOのリクエストの本文
+	a2sInfoPayload = []byte("Source Engine Query\x00")
+	// a2sNoChallenge はチャレンジ番号を取得する前のA2S_PLAYERのチャレンジ番号
+	a2sNoChallenge = []byte{0xFF, 0xFF, 0xFF, 0xFF}
+
+	// errSplitPacket は分割されたパケットの応答の場合のエラー（未対応）
+	errSplitPacket = errors.New("split packet responses are not supported")
+)
+
+// querySource はA2S_INFOでサーバーの状態を取得し、A2S_PLAYERでプレイヤー名を取得する
+// プレイヤー名を取得できない場合（A2S_PLAYERを無効にしているサーバーなど）はA2S_INFOの結果だけを返す
+func querySource(ctx context.Context, address string) (*Status, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(deadline(ctx)); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	info, err := a2sRequest(conn, a2sInfoRequest, a2sInfoPayload, true)
+	if err != nil {
+		return nil, fmt.Errorf("A2S_INFO: %w", err)
+	}
+	if info[0] != a2sInfoResponse {
+		return nil, fmt.Errorf("A2S_INFO: unexpected response type: %#x", info[0])
+	}
+	status, err := parseA2SInfo(info[1:])
+	if err != nil {
+		return nil, fmt.Errorf("A2S_INFO: %w", err)
+	}
+	status.Latency = time.Since(start)
+
+	players, err := a2sRequest(conn, a2sPlayerRequest, a2sNoChallenge, false)
+	if err == nil && players[0] == a2sPlayerResponse {
+		if names, err := parseA2SPlayers(players[1:]); err == nil {
+			status.PlayerNames = names
+		}
+	}
+	return status, nil
+}
+
+// a2sRequest はリクエストを送信して応答の本文（ヘッダーを除く、先頭は応答の種類）を返す
+// チャレンジ番号が返された場合は、チャレンジ番号を付けて再送する
+// appendChallengeがtrueの場合は本文の後ろに、falseの場合は本文の代わりにチャレンジ番号を付ける
+func a2sRequest(conn net.Conn, kind byte, payload []byte, appendChallenge bool) ([]byte, error) {
+	resp, err := a2sExchange(conn, kind, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp[0] != a2sChallenge {
+		return resp, nil
+	}
+	if len(resp) < 5 {
+		return nil, errors.New("truncated challenge")
+	}
+
+	challenge := resp[1:5]
+	if appendChallenge {
+		payload = append(append([]byte{}, payload...), challenge...)
+	} else {
+		payload = challenge
+	}
+	resp, err = a2sExchange(conn, kind, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp[0] == a2sChallenge {
+		return nil, errors.New("challenge was not accepted")
+	}
+	return resp, nil
+}
+
+// a2sExchange はリクエストを1回送信して応答を1つ受信する
+func a2sExchange(conn net.Conn, kind byte, payload []byte) ([]byte, error) {
+	request := append(append(append([]byte{}, a2sSinglePacket...), kind), payload...)
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, sourceMaxPacket)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case n >= len(a2sSplitPacket) && bytes.Equal(buf[:len(a2sSplitPacket)], a2sSplitPacket):
+		return nil, errSplitPacket
+	case n <= len(a2sSinglePacket) || !bytes.Equal(buf[:len(a2sSinglePacket)], a2sSinglePacket):
+		return nil, errors.New("invalid response header")
+	}
+	return buf[len(a2sSinglePacket):n], nil
+}
+
+// parseA2SInfo はA2S_INFOの応答（応答の種類を除く）を解析する
+func parseA2SInfo(data []byte) (*Status, error) {
+	r := &a2sReader{data: data}
+	r.readByte() // プロトコルバージョン
+	status := &Status{
+		Name: r.readString(),
+		Map:  r.readString(),
+	}
+	r.readString() // ゲームのディレクトリ
+	r.readString() // ゲーム名
+	r.skip(2)      // Steam App ID
+	status.Players = int(r.readByte())
+	status.MaxPlayers = int(r.readByte())
+	r.skip(5) // Bot数・サーバーの種類・OS・パスワードの有無・VAC
+	status.Version = r.readString()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return status, nil
+}
+
+// parseA2SPlayers はA2S_PLAYERの応答（応答の種類を除く）からプレイヤー名を取り出す
+// 接続処理中のプレイヤーは名前が空のため除く
+func parseA2SPlayers(data []byte) ([]string, error) {
+	r := &a2sReader{data: data}
+	count := int(r.readByte())
+	names := make([]string, 0, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		r.readByte() // インデックス
+		name := r.readString()
+		r.skip(8) // スコアと接続時間
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return names, nil
+}
+
+// a2sReader はA2Sの応答を先頭から読み込む（途中で足りなくなった場合はerrを設定する）
+type a2sReader struct {
+	data []byte
+	err  error
+}
+
+// readByte は1バイト読み込む
+func (r *a2sReader) readByte() byte {
+	if r.err != nil || len(r.data) < 1 {
+		r.fail()
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+// readString はNUL終端の文字列を読み込む
+func (r *a2sReader) readString() string {
+	if r.err != nil {
+		return ""
+	}
+	end := bytes.IndexByte(r.data, 0x00)
+	if end < 0 {
+		r.fail()
+		return ""
+	}
+	s := string(r.data[:end])
+	r.data = r.data[end+1:]
+	return s
+}
+
+// skip はnバイト読み飛ばす
+func (r *a2sReader) skip(n int) {
+	if r.err != nil || len(r.data) < n {
+		r.fail()
+		return
+	}
+	r.data = r.data[n:]
+}
+
+// fail は応答が途中で途切れていることを記録する
+func (r *a2sReader) fail() {
+	if r.err == nil {
+		r.err = errors.New("truncated response")
+	}
+}
@@ -0,0 +1,243 @@
+package gameprobe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sourceServer はA2Sのリクエストに応答するテスト用のサーバーを起動し、アドレスを返す
+// respondがnilを返した場合は応答しない
+func sourceServer(t *testing.T, respond func(request []byte) []byte) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, sourceMaxPacket)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := respond(append([]byte{}, buf[:n]...)); resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// a2sPacket はヘッダーを付けたA2Sの応答を作成する
+func a2sPacket(kind byte, body ...[]byte) []byte {
+	packet := append(append([]byte{}, a2sSinglePacket...), kind)
+	for _, b := range body {
+		packet = append(packet, b...)
+	}
+	return packet
+}
+
+// cstr はNUL終端の文字列を返す
+func cstr(s string) []byte {
+	return append([]byte(s), 0x00)
+}
+
+// a2sInfoBody はA2S_INFOの応答の本文を作成する
+func a2sInfoBody(name, mapName, version string, players, maxPlayers byte) []byte {
+	var body []byte
+	body = append(body, 17) // プロトコルバージョン
+	body = append(body, cstr(name)...)
+	body = append(body, cstr(mapName)...)
+	body = append(body, cstr("valheim")...)
+	body = append(body, cstr("Valheim")...)
+	body = binary.LittleEndian.AppendUint16(body, 0)
+	body = append(body, players, maxPlayers, 0, 'd', 'l', 0, 0)
+	body = append(body, cstr(version)...)
+	return body
+}
+
+// a2sPlayerBody はA2S_PLAYERの応答の本文を作成する
+func a2sPlayerBody(names ...string) []byte {
+	body := []byte{byte(len(names))}
+	for i, name := range names {
+		body = append(body, byte(i))
+		body = append(body, cstr(name)...)
+		body = binary.LittleEndian.AppendUint32(body, 10)
+		body = binary.LittleEndian.AppendUint32(body, math.Float32bits(120.5))
+	}
+	return body
+}
+
+func TestQuerySource(t *testing.T) {
+	challenge := []byte{0x01, 0x02, 0x03, 0x04}
+	infoRequest := a2sPacket(a2sInfoRequest, a2sInfoPayload)
+	info := a2sPacket(a2sInfoResponse, a2sInfoBody("My Server", "Dedicated", "0.217.46", 3, 10))
+
+	tests := []struct {
+		name    string
+		respond func(request []byte) []byte
+		want    *Status
+	}{
+		{
+			name: "正常系 - チャレンジなし",
+			respond: func(request []byte) []byte {
+				switch {
+				case bytes.Equal(request, infoRequest):
+					return info
+				case bytes.Equal(request, a2sPacket(a2sPlayerRequest, a2sNoChallenge)):
+					return a2sPacket(a2sChallenge, challenge)
+				case bytes.Equal(request, a2sPacket(a2sPlayerRequest, challenge)):
+					return a2sPacket(a2sPlayerResponse, a2sPlayerBody("viking", "", "raven"))
+				}
+				return nil
+			},
+			want: &Status{
+				Name:        "My Server",
+				Map:         "Dedicated",
+				Version:     "0.217.46",
+				Players:     3,
+				MaxPlayers:  10,
+				PlayerNames: []string{"viking", "raven"},
+			},
+		},
+		{
+			name: "正常系 - A2S_INFOにチャレンジが必要",
+			respond: func(request []byte) []byte {
+				switch {
+				case bytes.Equal(request, infoRequest):
+					return a2sPacket(a2sChallenge, challenge)
+				case bytes.Equal(request, a2sPacket(a2sInfoRequest, a2sInfoPayload, challenge)):
+					return info
+				case bytes.Equal(request, a2sPacket(a2sPlayerRequest, a2sNoChallenge)):
+					return a2sPacket(a2sPlayerResponse, a2sPlayerBody())
+				}
+				return nil
+			},
+			want: &Status{
+				Name:        "My Server",
+				Map:         "Dedicated",
+				Version:     "0.217.46",
+				Players:     3,
+				MaxPlayers:  10,
+				PlayerNames: []string{},
+			},
+		},
+		{
+			name: "正常系 - A2S_PLAYERに応答しない場合はA2S_INFOの結果だけ",
+			respond: func(request []byte) []byte {
+				if bytes.Equal(request, infoRequest) {
+					return info
+				}
+				return nil
+			},
+			want: &Status{
+				Name:       "My Server",
+				Map:        "Dedicated",
+				Version:    "0.217.46",
+				Players:    3,
+				MaxPlayers: 10,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := sourceServer(t, tt.respond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			got, err := querySource(ctx, address)
+			if err != nil {
+				t.Fatalf("querySource() error = %v", err)
+			}
+			if got.Latency <= 0 {
+				t.Errorf("querySource() Latency = %v, want > 0", got.Latency)
+			}
+			got.Latency = 0
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("querySource() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuerySource_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(request []byte) []byte
+	}{
+		{
+			name:    "異常系 - 応答しない",
+			respond: func([]byte) []byte { return nil },
+		},
+		{
+			name: "異常系 - 分割されたパケット",
+			respond: func([]byte) []byte {
+				return append(append([]byte{}, a2sSplitPacket...), 0x00, 0x00, 0x00, 0x00)
+			},
+		},
+		{
+			name:    "異常系 - ヘッダーが不正",
+			respond: func([]byte) []byte { return []byte{0x00, 0x01, 0x02, 0x03, 0x04} },
+		},
+		{
+			name:    "異常系 - 応答の種類が違う",
+			respond: func([]byte) []byte { return a2sPacket(a2sPlayerResponse, a2sPlayerBody()) },
+		},
+		{
+			name:    "異常系 - 応答が途中で切れる",
+			respond: func([]byte) []byte { return a2sPacket(a2sInfoResponse, []byte{17}, cstr("My Server")) },
+		},
+		{
+			name:    "異常系 - チャレンジを繰り返す",
+			respond: func([]byte) []byte { return a2sPacket(a2sChallenge, []byte{0x01, 0x02, 0x03, 0x04}) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := sourceServer(t, tt.respond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if got, err := querySource(ctx, address); err == nil {
+				t.Errorf("querySource() = %+v, want error", got)
+			}
+		})
+	}
+}
+
+func TestParseA2SPlayers(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []string
+		wantErr bool
+	}{
+		{name: "正常系 - 接続処理中のプレイヤーを除く", data: a2sPlayerBody("a", "", "b"), want: []string{"a", "b"}},
+		{name: "正常系 - 0人", data: a2sPlayerBody(), want: []string{}},
+		{name: "異常系 - 途中で切れる", data: a2sPlayerBody("a", "b")[:8], wantErr: true},
+		{name: "異常系 - 空", data: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseA2SPlayers(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseA2SPlayers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseA2SPlayers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package gameprobe
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+const (
+	// LabelQueryPort はクエリを送信するポートのラベル（指定したコンテナだけにクエリを送信する）
+	LabelQueryPort = "game.query.port"
+	// LabelQueryHost はクエリを送信するホストのラベル（省略時はサービス名）
+	LabelQueryHost = "game.query.host"
+	// LabelQueryProtocol はクエリプロトコルのラベル（省略時はgame.typeから判定する）
+	LabelQueryProtocol = "game.query.protocol"
+)
+
+// sourceGames はSource Engine Query（A2S）に対応しているゲームのgame.type
+var sourceGames = map[string]bool{
+	"valheim":  true,
+	"rust":     true,
+	"ark":      true,
+	"cs2":      true,
+	"csgo":     true,
+	"tf2":      true,
+	"gmod":     true,
+	"7dtd":     true,
+	"arma3":    true,
+	"dayz":     true,
+	"conan":    true,
+	"palworld": true,
+	"unturned": true,
+}
+
+// TargetFor はコンテナのラベルからクエリの送信先を返す
+// game.query.portがない場合やプロトコルを判定できない場合はfalseを返す
+func TargetFor(container *docker.ContainerInfo) (Target, bool) {
+	port, err := strconv.Atoi(container.Labels[LabelQueryPort])
+	if err != nil || port <= 0 || port > 65535 {
+		return Target{}, false
+	}
+
+	protocol, ok := protocolFor(container.Labels)
+	if !ok {
+		return Target{}, false
+	}
+
+	host := container.Labels[LabelQueryHost]
+	if host == "" {
+		host = container.Service
+	}
+	if host == "" {
+		host = container.Name
+	}
+
+	return Target{
+		Container: container.Name,
+		Service:   container.Service,
+		Protocol:  protocol,
+		Host:      host,
+		Port:      port,
+	}, true
+}
+
+// Targets はクエリの送信先を設定しているコンテナの送信先を返す
+func Targets(containers []docker.ContainerInfo) []Target {
+	var targets []Target
+	for i := range containers {
+		if target, ok := TargetFor(&containers[i]); ok {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// protocolFor はgame.query.protocol、なければgame.typeからクエリプロトコルを判定する
+func protocolFor(labels map[string]string) (Protocol, bool) {
+	if protocol := strings.ToLower(labels[LabelQueryProtocol]); protocol != "" {
+		switch Protocol(protocol) {
+		case ProtocolMinecraft, ProtocolSource:
+			return Protocol(protocol), true
+		default:
+			return "", false
+		}
+	}
+
+	gameType := strings.ToLower(labels[docker.LabelGameType])
+	switch {
+	case gameType == string(ProtocolMinecraft):
+		return ProtocolMinecraft, true
+	case sourceGames[gameType]:
+		return ProtocolSource, true
+	default:
+		return "", false
+	}
+}
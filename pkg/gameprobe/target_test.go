@@ -0,0 +1,145 @@
+package gameprobe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+func TestTargetFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		container docker.ContainerInfo
+		want      Target
+		wantOK    bool
+	}{
+		{
+			name: "正常系 - game.typeからMinecraftと判定",
+			container: docker.ContainerInfo{
+				Name:    "mc-1",
+				Service: "minecraft",
+				Labels:  map[string]string{"game.type": "minecraft", LabelQueryPort: "25565"},
+			},
+			want: Target{
+				Container: "mc-1", Service: "minecraft", Protocol: ProtocolMinecraft, Host: "minecraft", Port: 25565,
+			},
+			wantOK: true,
+		},
+		{
+			name: "正常系 - game.typeからSourceと判定",
+			container: docker.ContainerInfo{
+				Name:    "valheim-1",
+				Service: "valheim",
+				Labels:  map[string]string{"game.type": "Valheim", LabelQueryPort: "2457"},
+			},
+			want: Target{
+				Container: "valheim-1", Service: "valheim", Protocol: ProtocolSource, Host: "valheim", Port: 2457,
+			},
+			wantOK: true,
+		},
+		{
+			name: "正常系 - プロトコルとホストを指定",
+			container: docker.ContainerInfo{
+				Name:    "custom-1",
+				Service: "custom",
+				Labels: map[string]string{
+					"game.type":        "custom",
+					LabelQueryPort:     "27015",
+					LabelQueryProtocol: "source",
+					LabelQueryHost:     "host.docker.internal",
+				},
+			},
+			want: Target{
+				Container: "custom-1", Service: "custom", Protocol: ProtocolSource, Host: "host.docker.internal",
+				Port: 27015,
+			},
+			wantOK: true,
+		},
+		{
+			name: "正常系 - サービス名がない場合はコンテナ名",
+			container: docker.ContainerInfo{
+				Name:   "mc-1",
+				Labels: map[string]string{"game.type": "minecraft", LabelQueryPort: "25565"},
+			},
+			want:   Target{Container: "mc-1", Protocol: ProtocolMinecraft, Host: "mc-1", Port: 25565},
+			wantOK: true,
+		},
+		{
+			name: "異常系 - ポートの指定がない",
+			container: docker.ContainerInfo{
+				Name:   "mc-1",
+				Labels: map[string]string{"game.type": "minecraft"},
+			},
+		},
+		{
+			name: "異常系 - ポートが不正",
+			container: docker.ContainerInfo{
+				Name:   "mc-1",
+				Labels: map[string]string{"game.type": "minecraft", LabelQueryPort: "70000"},
+			},
+		},
+		{
+			name: "異常系 - プロトコルを判定できない",
+			container: docker.ContainerInfo{
+				Name:   "terraria-1",
+				Labels: map[string]string{"game.type": "terraria", LabelQueryPort: "7777"},
+			},
+		},
+		{
+			name: "異常系 - 未対応のプロトコル",
+			container: docker.ContainerInfo{
+				Name:   "mc-1",
+				Labels: map[string]string{"game.type": "minecraft", LabelQueryPort: "25565", LabelQueryProtocol: "gamespy"},
+			},
+		},
+		{
+			name:      "異常系 - ラベルがない",
+			container: docker.ContainerInfo{Name: "mc-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TargetFor(&tt.container)
+			if ok != tt.wantOK {
+				t.Fatalf("TargetFor() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("TargetFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargets(t *testing.T) {
+	containers := []docker.ContainerInfo{
+		{Name: "mc-1", Service: "minecraft", Labels: map[string]string{"game.type": "minecraft", LabelQueryPort: "25565"}},
+		{Name: "terraria-1", Service: "terraria", Labels: map[string]string{"game.type": "terraria"}},
+		{Name: "rust-1", Service: "rust", Labels: map[string]string{"game.type": "rust", LabelQueryPort: "28015"}},
+	}
+
+	got := Targets(containers)
+	want := []Target{
+		{Container: "mc-1", Service: "minecraft", Protocol: ProtocolMinecraft, Host: "minecraft", Port: 25565},
+		{Container: "rust-1", Service: "rust", Protocol: ProtocolSource, Host: "rust", Port: 28015},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTarget_Address(t *testing.T) {
+	tests := []struct {
+		target Target
+		want   string
+	}{
+		{target: Target{Host: "minecraft", Port: 25565}, want: "minecraft:25565"},
+		{target: Target{Host: "::1", Port: 27015}, want: "[::1]:27015"},
+	}
+	for _, tt := range tests {
+		if got := tt.target.Address(); got != tt.want {
+			t.Errorf("Address() = %q, want %q", got, tt.want)
+		}
+	}
+}
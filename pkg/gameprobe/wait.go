@@ -0,0 +1,31 @@
+package gameprobe
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPollInterval はプレイヤーがいなくなるまで待つ場合のクエリの送信間隔
+const DefaultPollInterval = 30 * time.Second
+
+// WaitUntilEmpty は接続中のプレイヤーが0人になるまでintervalごとにクエリを送信して待つ
+// intervalが0以下の場合はDefaultPollIntervalを使用する
+// クエリに応答しない間は人数が分からないため待ち続け、ctxが終了した場合はctxのエラーを返す
+func WaitUntilEmpty(ctx context.Context, prober Prober, target Target, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if status, err := prober.Probe(ctx, target); err == nil && status.Players == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
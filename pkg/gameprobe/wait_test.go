@@ -0,0 +1,57 @@
+package gameprobe
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitUntilEmpty(t *testing.T) {
+	t.Run("プレイヤーがいなくなったら終了", func(t *testing.T) {
+		// 2人 → 応答なし → 0人
+		var calls atomic.Int32
+		prober := &MockProber{
+			ProbeFunc: func(context.Context, Target) (*Status, error) {
+				switch calls.Add(1) {
+				case 1:
+					return &Status{Players: 2}, nil
+				case 2:
+					return nil, errors.New("i/o timeout")
+				default:
+					return &Status{Players: 0}, nil
+				}
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := WaitUntilEmpty(ctx, prober, Target{}, 10*time.Millisecond); err != nil {
+			t.Fatalf("WaitUntilEmpty() error = %v", err)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Errorf("Probe() called %d times, want 3", got)
+		}
+	})
+
+	t.Run("最初から0人の場合はすぐに終了", func(t *testing.T) {
+		if err := WaitUntilEmpty(context.Background(), &MockProber{}, Target{}, time.Hour); err != nil {
+			t.Errorf("WaitUntilEmpty() error = %v", err)
+		}
+	})
+
+	t.Run("タイムアウト", func(t *testing.T) {
+		prober := &MockProber{
+			ProbeFunc: func(context.Context, Target) (*Status, error) {
+				return &Status{Players: 1}, nil
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := WaitUntilEmpty(ctx, prober, Target{}, 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("WaitUntilEmpty() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package rcon
+
+import "context"
+
+// MockExecutor はテスト用のモック実装
+type MockExecutor struct {
+	ExecuteFunc func(ctx context.Context, target Target, command string) (string, error)
+}
+
+// Execute はExecuteFuncの結果を返す（ExecuteFuncがnilの場合は空の応答を返す）
+func (m *MockExecutor) Execute(ctx context.Context, target Target, command string) (string, error) {
+	if m.ExecuteFunc != nil {
+		return m.ExecuteFunc(ctx, target, command)
+	}
+	return "", nil
+}
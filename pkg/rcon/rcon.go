@@ -0,0 +1,221 @@
+// Package rcon はSource RCONプロトコル（MinecraftのRCONも同じプロトコル）で
+// ゲームサーバーにコマンドを送信し、応答を取得する機能を提供します
+package rcon
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultTimeout はコマンド1回あたりのデフォルトのタイムアウト時間
+const DefaultTimeout = 5 * time.Second
+
+const (
+	// パケットの種類
+	packetResponseValue = 0 // SERVERDATA_RESPONSE_VALUE
+	packetExecCommand   = 2 // SERVERDATA_EXECCOMMAND
+	packetAuthResponse  = 2 // SERVERDATA_AUTH_RESPONSE
+	packetAuth          = 3 // SERVERDATA_AUTH
+
+	// リクエストID（1回の接続で1つのコマンドだけを送信するため固定）
+	authRequestID    = 1
+	commandRequestID = 2
+	// endRequestID は応答の終わりを検出するために送信する空のパケットのID
+	// サーバーは受信した順に応答するため、このIDの応答が届いた時点でコマンドの応答はすべて届いている
+	endRequestID = 3
+	// authFailedID は認証に失敗した場合に返されるID
+	authFailedID = -1
+
+	// MaxCommandLength は送信できるコマンドの最大長（バイト）
+	MaxCommandLength = 1446
+	// maxPacketSize は受信するパケットの最大サイズ
+	maxPacketSize = 1 << 16
+	// maxResponseSize は1つのコマンドの応答の最大サイズ（超えた分は切り捨てる）
+	maxResponseSize = 1 << 16
+	// packetHeaderSize はパケットのサイズに含まれるIDと種類、2つのNULのバイト数
+	packetHeaderSize = 10
+)
+
+var (
+	// ErrAuthFailed はパスワードが間違っている場合のエラー
+	ErrAuthFailed = errors.New("rcon authentication failed")
+	// ErrCommandTooLong はコマンドがMaxCommandLengthを超える場合のエラー
+	ErrCommandTooLong = fmt.Errorf("rcon command exceeds %d bytes", MaxCommandLength)
+	// ErrUnsafeCommand はコマンドの区切り文字や制御文字を含むコマンドの場合のエラー
+	ErrUnsafeCommand = errors.New("rcon command contains a separator or control character")
+
+	// formattingCodes はMinecraftの書式コード（§ + 1文字）
+	formattingCodes = regexp.MustCompile(`§.`)
+)
+
+// Target はコマンドを送信するゲームサーバー
+type Target struct {
+	Container string // コンテナ名
+	Service   string // docker-composeのサービス名
+	Host      string
+	Port      int
+	Password  string
+}
+
+// Address はコマンドを送信するアドレス（host:port）を返す
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// ValidateCommand はコマンドを1つのコマンドとして送信できるかを検証する
+// Sourceのサーバーは ; や改行で区切った複数のコマンドを実行するため、許可リストを迂回できないように拒否する
+func ValidateCommand(command string) error {
+	for _, r := range command {
+		if r == ';' || unicode.IsControl(r) {
+			return ErrUnsafeCommand
+		}
+	}
+	return nil
+}
+
+// Executor はゲームサーバーにRCONコマンドを送信して応答を取得する
+type Executor interface {
+	Execute(ctx context.Context, target Target, command string) (string, error)
+}
+
+// DefaultExecutor はTCPでゲームサーバーに接続してRCONコマンドを送信する
+type DefaultExecutor struct {
+	timeout time.Duration
+}
+
+// NewExecutor は新しいDefaultExecutorを作成する（timeoutが0以下の場合はDefaultTimeout）
+func NewExecutor(timeout time.Duration) *DefaultExecutor {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &DefaultExecutor{timeout: timeout}
+}
+
+// Execute は認証してからコマンドを送信し、応答を返す（Minecraftの書式コードは取り除く）
+func (e *DefaultExecutor) Execute(ctx context.Context, target Target, command string) (string, error) {
+	if len(command) > MaxCommandLength {
+		return "", ErrCommandTooLong
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	response, err := execute(ctx, target, command)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute rcon command on %s: %w", target.Address(), err)
+	}
+	return formattingCodes.ReplaceAllString(response, ""), nil
+}
+
+// execute は1回の接続で認証とコマンドの送信を行う
+func execute(ctx context.Context, target Target, command string) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", target.Address())
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if d, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(d); err != nil {
+			return "", err
+		}
+	}
+	r := bufio.NewReader(conn)
+
+	if err := authenticate(conn, r, target.Password); err != nil {
+		return "", err
+	}
+
+	// コマンドと、応答の終わりを検出するための空のパケットを続けて送信する
+	if err := writePacket(conn, commandRequestID, packetExecCommand, command); err != nil {
+		return "", err
+	}
+	if err := writePacket(conn, endRequestID, packetResponseValue, ""); err != nil {
+		return "", err
+	}
+
+	var response strings.Builder
+	for {
+		id, _, body, err := readPacket(r)
+		if err != nil {
+			return "", err
+		}
+		switch id {
+		case commandRequestID:
+			if response.Len()+len(body) <= maxResponseSize {
+				response.WriteString(body)
+			}
+		case endRequestID:
+			return response.String(), nil
+		}
+	}
+}
+
+// authenticate はパスワードで認証する
+// Sourceのサーバーは認証の応答の前に空のSERVERDATA_RESPONSE_VALUEを返すため読み飛ばす
+func authenticate(w io.Writer, r *bufio.Reader, password string) error {
+	if err := writePacket(w, authRequestID, packetAuth, password); err != nil {
+		return err
+	}
+	for {
+		id, kind, _, err := readPacket(r)
+		if err != nil {
+			return err
+		}
+		if kind != packetAuthResponse {
+			continue
+		}
+		if id == authFailedID {
+			return ErrAuthFailed
+		}
+		return nil
+	}
+}
+
+// writePacket はパケットを送信する
+func writePacket(w io.Writer, id, kind int32, body string) error {
+	if len(body) > MaxCommandLength {
+		return ErrCommandTooLong
+	}
+	size := uint32(packetHeaderSize + len(body)) // #nosec G115 - 本文の長さはMaxCommandLength以下に制限している
+
+	packet := make([]byte, 0, 4+packetHeaderSize+len(body))
+	packet = binary.LittleEndian.AppendUint32(packet, size)
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(id))   // #nosec G115 - ビットパターンをそのまま送信する
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(kind)) // #nosec G115 - ビットパターンをそのまま送信する
+	packet = append(packet, body...)
+	packet = append(packet, 0x00, 0x00)
+	_, err := w.Write(packet)
+	return err
+}
+
+// readPacket はパケットを1つ受信し、ID・種類・本文を返す
+func readPacket(r io.Reader) (id, kind int32, body string, err error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, 0, "", err
+	}
+	size := binary.LittleEndian.Uint32(header[0:4])
+	if size < packetHeaderSize || size > maxPacketSize {
+		return 0, 0, "", fmt.Errorf("invalid rcon packet size: %d", size)
+	}
+	id = int32(binary.LittleEndian.Uint32(header[4:8]))    // #nosec G115 - 符号付きの値として受信する
+	kind = int32(binary.LittleEndian.Uint32(header[8:12])) // #nosec G115 - 符号付きの値として受信する
+
+	payload := make([]byte, size-8)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, 0, "", err
+	}
+	// 本文はNUL終端で、最後にもう1つNULが続く
+	return id, kind, strings.TrimRight(string(payload), "\x00"), nil
+}
@@ -0,0 +1,296 @@
+package rcon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// packet はテスト用のサーバーが受信・送信するパケット
+type packet struct {
+	id   int32
+	kind int32
+	body string
+}
+
+// rconServer はRCONのリクエストに応答するテスト用のサーバーを起動し、接続先を返す
+// handleは受信したパケットごとに呼ばれ、返したパケットを送信する
+func rconServer(t *testing.T, handle func(p packet) []packet) Target {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			id, kind, body, err := readPacket(r)
+			if err != nil {
+				return
+			}
+			for _, resp := range handle(packet{id: id, kind: kind, body: body}) {
+				if err := writeTestPacket(conn, resp); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return Target{Host: "127.0.0.1", Port: addr.Port, Password: "secret"}
+}
+
+// writeTestPacket はMaxCommandLengthを超える本文も送信できるようにパケットを書き込む
+func writeTestPacket(conn net.Conn, p packet) error {
+	var buf bytes.Buffer
+	if len(p.body) <= MaxCommandLength {
+		if err := writePacket(&buf, p.id, p.kind, p.body); err != nil {
+			return err
+		}
+	} else {
+		for i := 0; i < len(p.body); i += MaxCommandLength {
+			end := min(i+MaxCommandLength, len(p.body))
+			if err := writePacket(&buf, p.id, p.kind, p.body[i:end]); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := conn.Write(buf.Bytes())
+	return err
+}
+
+// minecraftLike はMinecraftのように応答するハンドラーを返す
+// 認証の応答の前に空の応答を返さず、不明な種類のパケットには「Unknown request」を返す
+func minecraftLike(response string) func(p packet) []packet {
+	return func(p packet) []packet {
+		switch p.kind {
+		case packetAuth:
+			if p.body != "secret" {
+				return []packet{{id: authFailedID, kind: packetAuthResponse}}
+			}
+			return []packet{{id: p.id, kind: packetAuthResponse}}
+		case packetExecCommand:
+			return []packet{{id: p.id, kind: packetResponseValue, body: response}}
+		default:
+			return []packet{{id: p.id, kind: packetResponseValue, body: "Unknown request 0"}}
+		}
+	}
+}
+
+func TestDefaultExecutor_Execute(t *testing.T) {
+	long := strings.Repeat("a", 3000)
+
+	tests := []struct {
+		name    string
+		handle  func(p packet) []packet
+		command string
+		want    string
+	}{
+		{
+			name:    "正常系 - Minecraft",
+			handle:  minecraftLike("§6There are 2 of a max of 20 players online: §rSteve, Alex"),
+			command: "list",
+			want:    "There are 2 of a max of 20 players online: Steve, Alex",
+		},
+		{
+			name: "正常系 - Source（認証の前に空の応答）",
+			handle: func(p packet) []packet {
+				switch p.kind {
+				case packetAuth:
+					return []packet{{id: p.id, kind: packetResponseValue}, {id: p.id, kind: packetAuthResponse}}
+				case packetExecCommand:
+					return []packet{{id: p.id, kind: packetResponseValue, body: "hostname: My Server\n"}}
+				default:
+					// 空のパケットを返した後に0x01000000の本文のパケットを続けて返す
+					return []packet{
+						{id: p.id, kind: packetResponseValue},
+						{id: p.id, kind: packetResponseValue, body: "\x00\x01"},
+					}
+				}
+			},
+			command: "status",
+			want:    "hostname: My Server\n",
+		},
+		{
+			name:    "正常系 - 複数のパケットに分かれた応答",
+			handle:  minecraftLike(long),
+			command: "help",
+			want:    long,
+		},
+		{
+			name:    "正常系 - 応答なし",
+			handle:  minecraftLike(""),
+			command: "save-all",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			target := rconServer(t, func(p packet) []packet {
+				if p.kind == packetExecCommand {
+					commands = append(commands, p.body)
+				}
+				return tt.handle(p)
+			})
+
+			got, err := NewExecutor(time.Second).Execute(context.Background(), target, tt.command)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+			if len(commands) != 1 || commands[0] != tt.command {
+				t.Errorf("commands = %q, want [%q]", commands, tt.command)
+			}
+		})
+	}
+}
+
+func TestDefaultExecutor_Execute_Errors(t *testing.T) {
+	t.Run("パスワードが違う", func(t *testing.T) {
+		target := rconServer(t, minecraftLike(""))
+		target.Password = "wrong"
+		_, err := NewExecutor(time.Second).Execute(context.Background(), target, "list")
+		if !errors.Is(err, ErrAuthFailed) {
+			t.Errorf("Execute() error = %v, want %v", err, ErrAuthFailed)
+		}
+	})
+
+	t.Run("コマンドが長すぎる", func(t *testing.T) {
+		_, err := NewExecutor(time.Second).Execute(context.Background(), Target{}, strings.Repeat("a", MaxCommandLength+1))
+		if !errors.Is(err, ErrCommandTooLong) {
+			t.Errorf("Execute() error = %v, want %v", err, ErrCommandTooLong)
+		}
+	})
+
+	t.Run("応答しない", func(t *testing.T) {
+		target := rconServer(t, func(packet) []packet { return nil })
+		start := time.Now()
+		if _, err := NewExecutor(100*time.Millisecond).Execute(context.Background(), target, "list"); err == nil {
+			t.Error("Execute() error = nil, want timeout")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Execute() took %v, want about 100ms", elapsed)
+		}
+	})
+
+	t.Run("接続できない", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		port := ln.Addr().(*net.TCPAddr).Port
+		ln.Close()
+
+		target := Target{Host: "127.0.0.1", Port: port, Password: "secret"}
+		if _, err := NewExecutor(time.Second).Execute(context.Background(), target, "list"); err == nil {
+			t.Error("Execute() error = nil, want connection error")
+		}
+	})
+}
+
+func TestNewExecutor(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{name: "指定したタイムアウト", timeout: time.Second, want: time.Second},
+		{name: "0の場合はデフォルト", timeout: 0, want: DefaultTimeout},
+		{name: "負の場合はデフォルト", timeout: -time.Second, want: DefaultTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewExecutor(tt.timeout).timeout; got != tt.want {
+				t.Errorf("NewExecutor(%v).timeout = %v, want %v", tt.timeout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPacket(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writePacket(&buf, 7, packetExecCommand, "list"); err != nil {
+		t.Fatalf("writePacket() error = %v", err)
+	}
+	want := []byte{
+		14, 0, 0, 0, // サイズ
+		7, 0, 0, 0, // ID
+		2, 0, 0, 0, // 種類
+		'l', 'i', 's', 't', 0, 0,
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("writePacket() = %v, want %v", buf.Bytes(), want)
+	}
+
+	id, kind, body, err := readPacket(&buf)
+	if err != nil || id != 7 || kind != packetExecCommand || body != "list" {
+		t.Errorf("readPacket() = %d, %d, %q, %v", id, kind, body, err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "サイズが小さすぎる", data: []byte{4, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "サイズが大きすぎる", data: []byte{0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "途中で切れる", data: want[:14]},
+		{name: "空", data: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := readPacket(bytes.NewReader(tt.data)); err == nil {
+				t.Error("readPacket() error = nil")
+			}
+		})
+	}
+}
+
+func TestTarget_Address(t *testing.T) {
+	if got := (Target{Host: "minecraft", Port: 25575}).Address(); got != "minecraft:25575" {
+		t.Errorf("Address() = %q", got)
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		wantErr bool
+	}{
+		{name: "単一のコマンド", command: "say hello world"},
+		{name: "日本語", command: "say こんにちは"},
+		{name: "セミコロン", command: "status; rcon_password x", wantErr: true},
+		{name: "改行", command: "list\nstop", wantErr: true},
+		{name: "復帰文字", command: "list\rstop", wantErr: true},
+		{name: "タブ", command: "list\tstop", wantErr: true},
+		{name: "NUL文字", command: "list\x00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommand(tt.command)
+			if tt.wantErr && !errors.Is(err, ErrUnsafeCommand) {
+				t.Errorf("ValidateCommand(%q) error = %v, want %v", tt.command, err, ErrUnsafeCommand)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateCommand(%q) error = %v", tt.command, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// DefaultBroadcastFormat はゲーム内にメッセージを通知するコマンドのデフォルトの書式（%sをメッセージに置き換える）
+const DefaultBroadcastFormat = "say %s"
+
+var (
+	// ErrServiceNotFound はサービスのコンテナが見つからない場合のエラー
+	ErrServiceNotFound = errors.New("service not found")
+	// ErrNotRunning はサービスのコンテナが稼働していない場合のエラー
+	ErrNotRunning = errors.New("service is not running")
+	// ErrNotConfigured はサービスのRCONのポートまたはパスワードが見つからない場合のエラー
+	ErrNotConfigured = errors.New("rcon is not configured for the service")
+)
+
+// Runner はdocker-composeのサービス名からRCONの接続先を探してコマンドを送信する
+type Runner struct {
+	compose     docker.ComposeService
+	composePath string
+	executor    Executor
+}
+
+// NewRunner は新しいRunnerを作成する（executorがnilの場合はデフォルトのタイムアウトで作成）
+func NewRunner(compose docker.ComposeService, composePath string, executor Executor) *Runner {
+	if executor == nil {
+		executor = NewExecutor(0)
+	}
+	return &Runner{
+		compose:     compose,
+		composePath: composePath,
+		executor:    executor,
+	}
+}
+
+// Target はサービスのコンテナのラベルと環境変数からRCONの接続先を返す
+func (r *Runner) Target(ctx context.Context, service string) (Target, error) {
+	containers, err := r.compose.ListContainers(ctx, r.composePath)
+	if err != nil {
+		return Target{}, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	for i := range containers {
+		container := &containers[i]
+		if container.Service != service {
+			continue
+		}
+		if !strings.EqualFold(container.State, docker.StateRunning) {
+			return Target{}, ErrNotRunning
+		}
+		env, err := r.compose.GetContainerEnv(ctx, container.Name)
+		if err != nil {
+			return Target{}, fmt.Errorf("failed to get container environment: %w", err)
+		}
+		target, ok := TargetFor(container, env)
+		if !ok {
+			return Target{}, ErrNotConfigured
+		}
+		return target, nil
+	}
+	return Target{}, ErrServiceNotFound
+}
+
+// Run はサービスにRCONコマンドを送信し、応答を返す
+func (r *Runner) Run(ctx context.Context, service, command string) (string, error) {
+	target, err := r.Target(ctx, service)
+	if err != nil {
+		return "", err
+	}
+	return r.executor.Execute(ctx, target, command)
+}
+
+// BroadcastFormats はサービスごとのゲーム内通知のコマンドの書式を提供する（空の場合はDefaultBroadcastFormat）
+type BroadcastFormats interface {
+	RCONBroadcastFormat(service string) string
+}
+
+// Broadcaster は再起動・停止の前にゲーム内のプレイヤーにメッセージを通知する
+type Broadcaster struct {
+	runner  *Runner
+	formats BroadcastFormats
+}
+
+// NewBroadcaster は新しいBroadcasterを作成する（formatsがnilの場合はすべてのサービスでDefaultBroadcastFormat）
+func NewBroadcaster(runner *Runner, formats BroadcastFormats) *Broadcaster {
+	return &Broadcaster{runner: runner, formats: formats}
+}
+
+// Broadcast はサービスのゲーム内にメッセージを通知する
+// Broadcasterがnilの場合や、RCONを設定していないサービス・稼働していないサービスの場合は何もしない
+func (b *Broadcaster) Broadcast(ctx context.Context, service, message string) error {
+	if b == nil {
+		return nil
+	}
+	format := ""
+	if b.formats != nil {
+		format = b.formats.RCONBroadcastFormat(service)
+	}
+	_, err := b.runner.Run(ctx, service, BroadcastCommand(format, message))
+	if errors.Is(err, ErrNotConfigured) || errors.Is(err, ErrNotRunning) {
+		return nil
+	}
+	return err
+}
+
+// BroadcastCommand は書式の%sをメッセージに置き換えたコマンドを返す（formatが空の場合はDefaultBroadcastFormat）
+// メッセージの改行は1つのコマンドとして送信するために空白に置き換える
+func BroadcastCommand(format, message string) string {
+	if format == "" {
+		format = DefaultBroadcastFormat
+	}
+	message = strings.Join(strings.Fields(message), " ")
+	return strings.Replace(format, "%s", message, 1)
+}
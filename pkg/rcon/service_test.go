@@ -0,0 +1,202 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+// testCompose はRCONを設定したminecraftと設定していないterraria、停止中のvalheimを返す
+func testCompose() *docker.MockComposeService {
+	return &docker.MockComposeService{
+		ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+			return []docker.ContainerInfo{
+				{Name: "mc-1", Service: "minecraft", State: "running"},
+				{Name: "terraria-1", Service: "terraria", State: "running"},
+				{Name: "valheim-1", Service: "valheim", State: "exited"},
+			}, nil
+		},
+		GetContainerEnvFunc: func(name string) (map[string]string, error) {
+			if name == "mc-1" {
+				return map[string]string{EnvPort: "25575", EnvPassword: "secret"}, nil
+			}
+			return map[string]string{}, nil
+		},
+	}
+}
+
+// formatFunc は関数をBroadcastFormatsとして扱うためのアダプター
+type formatFunc func(service string) string
+
+func (f formatFunc) RCONBroadcastFormat(service string) string {
+	return f(service)
+}
+
+func TestRunner_Run(t *testing.T) {
+	var gotTarget Target
+	var gotCommand string
+	executor := &MockExecutor{
+		ExecuteFunc: func(_ context.Context, target Target, command string) (string, error) {
+			gotTarget = target
+			gotCommand = command
+			return "There are 0 players online", nil
+		},
+	}
+	runner := NewRunner(testCompose(), "docker-compose.yml", executor)
+
+	got, err := runner.Run(context.Background(), "minecraft", "list")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got != "There are 0 players online" || gotCommand != "list" {
+		t.Errorf("Run() = %q, command = %q", got, gotCommand)
+	}
+	want := Target{Container: "mc-1", Service: "minecraft", Host: "minecraft", Port: 25575, Password: "secret"}
+	if gotTarget != want {
+		t.Errorf("target = %+v, want %+v", gotTarget, want)
+	}
+}
+
+func TestRunner_Run_Errors(t *testing.T) {
+	errDocker := errors.New("docker error")
+
+	tests := []struct {
+		name    string
+		compose *docker.MockComposeService
+		service string
+		wantErr error
+	}{
+		{name: "サービスが見つからない", compose: testCompose(), service: "rust", wantErr: ErrServiceNotFound},
+		{name: "稼働していない", compose: testCompose(), service: "valheim", wantErr: ErrNotRunning},
+		{name: "RCONを設定していない", compose: testCompose(), service: "terraria", wantErr: ErrNotConfigured},
+		{
+			name: "コンテナ情報を取得できない",
+			compose: &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) { return nil, errDocker },
+			},
+			service: "minecraft",
+			wantErr: errDocker,
+		},
+		{
+			name: "環境変数を取得できない",
+			compose: &docker.MockComposeService{
+				ListContainersFunc: func(string) ([]docker.ContainerInfo, error) {
+					return []docker.ContainerInfo{{Name: "mc-1", Service: "minecraft", State: "running"}}, nil
+				},
+				GetContainerEnvFunc: func(string) (map[string]string, error) { return nil, errDocker },
+			},
+			service: "minecraft",
+			wantErr: errDocker,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed := false
+			executor := &MockExecutor{
+				ExecuteFunc: func(context.Context, Target, string) (string, error) {
+					executed = true
+					return "", nil
+				},
+			}
+			_, err := NewRunner(tt.compose, "docker-compose.yml", executor).Run(context.Background(), tt.service, "list")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if executed {
+				t.Error("Run() executed the command")
+			}
+		})
+	}
+}
+
+func TestBroadcaster_Broadcast(t *testing.T) {
+	errRCON := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		formats     BroadcastFormats
+		service     string
+		execErr     error
+		wantCommand string
+		wantErr     bool
+	}{
+		{
+			name:        "デフォルトの書式",
+			service:     "minecraft",
+			wantCommand: "say 5分後にサーバーを再起動します",
+		},
+		{
+			name:        "サービスごとの書式",
+			formats:     formatFunc(func(string) string { return "ServerChat %s" }),
+			service:     "minecraft",
+			wantCommand: "ServerChat 5分後にサーバーを再起動します",
+		},
+		{
+			name:    "RCONを設定していないサービスは何もしない",
+			service: "terraria",
+		},
+		{
+			name:    "稼働していないサービスは何もしない",
+			service: "valheim",
+		},
+		{
+			name:    "送信に失敗",
+			service: "minecraft",
+			execErr: errRCON,
+			wantErr: true,
+			// 失敗した場合もコマンドは送信している
+			wantCommand: "say 5分後にサーバーを再起動します",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var command string
+			executor := &MockExecutor{
+				ExecuteFunc: func(_ context.Context, _ Target, c string) (string, error) {
+					command = c
+					return "", tt.execErr
+				},
+			}
+			b := NewBroadcaster(NewRunner(testCompose(), "docker-compose.yml", executor), tt.formats)
+
+			err := b.Broadcast(context.Background(), tt.service, "5分後にサーバーを再起動します")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Broadcast() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if command != tt.wantCommand {
+				t.Errorf("command = %q, want %q", command, tt.wantCommand)
+			}
+		})
+	}
+
+	var nilBroadcaster *Broadcaster
+	if err := nilBroadcaster.Broadcast(context.Background(), "minecraft", "hello"); err != nil {
+		t.Errorf("nil Broadcaster.Broadcast() error = %v", err)
+	}
+}
+
+func TestBroadcastCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		message string
+		want    string
+	}{
+		{name: "デフォルトの書式", format: "", message: "hello", want: "say hello"},
+		{name: "指定した書式", format: "broadcast %s", message: "hello", want: "broadcast hello"},
+		{name: "改行を空白に置き換える", format: "", message: "line1\nline2  end", want: "say line1 line2 end"},
+		{name: "メッセージの%sは置き換えない", format: "say %s", message: "100%s", want: "say 100%s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BroadcastCommand(tt.format, tt.message); got != tt.want {
+				t.Errorf("BroadcastCommand(%q, %q) = %q, want %q", tt.format, tt.message, got, tt.want)
+			}
+		})
+	}
+}
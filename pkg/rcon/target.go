@@ -0,0 +1,79 @@
+package rcon
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+const (
+	// LabelPort はRCONのポートのラベル
+	LabelPort = "game.rcon.port"
+	// LabelHost はRCONの接続先ホストのラベル（省略時はサービス名）
+	LabelHost = "game.rcon.host"
+	// LabelPassword はRCONのパスワードのラベル（省略時は環境変数RCON_PASSWORD）
+	LabelPassword = "game.rcon.password"
+
+	// EnvPort はRCONのポートを指定するコンテナの環境変数（itzg/minecraft-serverなどと同じ名前）
+	EnvPort = "RCON_PORT"
+	// EnvPassword はRCONのパスワードを指定するコンテナの環境変数
+	EnvPassword = "RCON_PASSWORD"
+
+	// defaultMinecraftPort はMinecraftのRCONのデフォルトのポート
+	defaultMinecraftPort = 25575
+)
+
+// TargetFor はコンテナのラベルと環境変数からRCONの接続先を返す
+// ポートはgame.rcon.port、環境変数RCON_PORTの順に探し、Minecraftでパスワードだけが設定されている場合は25575を使用する
+// パスワードかポートが見つからない場合はfalseを返す
+func TargetFor(container *docker.ContainerInfo, env map[string]string) (Target, bool) {
+	password := container.Labels[LabelPassword]
+	if password == "" {
+		password = env[EnvPassword]
+	}
+	if password == "" {
+		return Target{}, false
+	}
+
+	port, ok := portFor(container, env)
+	if !ok {
+		return Target{}, false
+	}
+
+	host := container.Labels[LabelHost]
+	if host == "" {
+		host = container.Service
+	}
+	if host == "" {
+		host = container.Name
+	}
+
+	return Target{
+		Container: container.Name,
+		Service:   container.Service,
+		Host:      host,
+		Port:      port,
+		Password:  password,
+	}, true
+}
+
+// portFor はラベル、環境変数、ゲームの種類の順にRCONのポートを探す
+func portFor(container *docker.ContainerInfo, env map[string]string) (int, bool) {
+	raw := container.Labels[LabelPort]
+	if raw == "" {
+		raw = env[EnvPort]
+	}
+	if raw == "" {
+		if strings.EqualFold(container.Labels[docker.LabelGameType], "minecraft") {
+			return defaultMinecraftPort, true
+		}
+		return 0, false
+	}
+
+	port, err := strconv.Atoi(raw)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, false
+	}
+	return port, true
+}
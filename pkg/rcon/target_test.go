@@ -0,0 +1,86 @@
+package rcon
+
+import (
+	"testing"
+
+	"github.com/hideA88/game-server-watchdog/pkg/docker"
+)
+
+func TestTargetFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		container docker.ContainerInfo
+		env       map[string]string
+		want      Target
+		wantOK    bool
+	}{
+		{
+			name:      "正常系 - 環境変数から取得",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft"},
+			env:       map[string]string{EnvPort: "25575", EnvPassword: "secret"},
+			want:      Target{Container: "mc-1", Service: "minecraft", Host: "minecraft", Port: 25575, Password: "secret"},
+			wantOK:    true,
+		},
+		{
+			name: "正常系 - ラベルが環境変数より優先",
+			container: docker.ContainerInfo{
+				Name:    "rust-1",
+				Service: "rust",
+				Labels:  map[string]string{LabelPort: "28016", LabelPassword: "label", LabelHost: "host.docker.internal"},
+			},
+			env:    map[string]string{EnvPort: "25575", EnvPassword: "env"},
+			want:   Target{Container: "rust-1", Service: "rust", Host: "host.docker.internal", Port: 28016, Password: "label"},
+			wantOK: true,
+		},
+		{
+			name: "正常系 - Minecraftはポートを省略できる",
+			container: docker.ContainerInfo{
+				Name: "mc-1", Service: "minecraft", Labels: map[string]string{docker.LabelGameType: "minecraft"},
+			},
+			env:    map[string]string{EnvPassword: "secret"},
+			want:   Target{Container: "mc-1", Service: "minecraft", Host: "minecraft", Port: 25575, Password: "secret"},
+			wantOK: true,
+		},
+		{
+			name:      "正常系 - サービス名がない場合はコンテナ名",
+			container: docker.ContainerInfo{Name: "mc-1"},
+			env:       map[string]string{EnvPort: "25575", EnvPassword: "secret"},
+			want:      Target{Container: "mc-1", Host: "mc-1", Port: 25575, Password: "secret"},
+			wantOK:    true,
+		},
+		{
+			name:      "異常系 - パスワードがない",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft"},
+			env:       map[string]string{EnvPort: "25575"},
+		},
+		{
+			name: "異常系 - Minecraft以外でポートがない",
+			container: docker.ContainerInfo{
+				Name: "valheim-1", Service: "valheim", Labels: map[string]string{docker.LabelGameType: "valheim"},
+			},
+			env: map[string]string{EnvPassword: "secret"},
+		},
+		{
+			name:      "異常系 - ポートが不正",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft"},
+			env:       map[string]string{EnvPort: "rcon", EnvPassword: "secret"},
+		},
+		{
+			name:      "異常系 - ポートが範囲外",
+			container: docker.ContainerInfo{Name: "mc-1", Service: "minecraft", Labels: map[string]string{LabelPort: "0"}},
+			env:       map[string]string{EnvPassword: "secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TargetFor(&tt.container, tt.env)
+			if ok != tt.wantOK {
+				t.Fatalf("TargetFor() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("TargetFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package system
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -56,25 +57,28 @@ func (m *DockerAwareMonitor) checkIfInDocker() bool {
 }
 
 // GetSystemInfo はシステム情報を取得
-func (m *DockerAwareMonitor) GetSystemInfo() (*SystemInfo, error) {
+func (m *DockerAwareMonitor) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
 	if m.isInDocker {
-		return m.getHostSystemInfo()
+		return m.getHostSystemInfo(ctx)
 	}
 
 	// Docker外の場合は通常のモニターを使用
 	defaultMonitor := NewDefaultMonitor()
-	return defaultMonitor.GetSystemInfo()
+	return defaultMonitor.GetSystemInfo(ctx)
 }
 
 // getHostSystemInfo はDocker内からホストのシステム情報を取得
-func (m *DockerAwareMonitor) getHostSystemInfo() (*SystemInfo, error) {
+func (m *DockerAwareMonitor) getHostSystemInfo(ctx context.Context) (*SystemInfo, error) {
 	info := &SystemInfo{}
 
 	// CPU情報を取得
-	cpuUsage, err := m.getHostCPUUsage()
+	cpuUsage, err := m.getHostCPUUsage(ctx)
 	if err == nil {
 		info.CPUUsagePercent = cpuUsage
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// メモリ情報を取得
 	memInfo, err := m.getHostMemoryInfo()
@@ -96,7 +100,7 @@ func (m *DockerAwareMonitor) getHostSystemInfo() (*SystemInfo, error) {
 }
 
 // getHostCPUUsage はホストのCPU使用率を取得
-func (m *DockerAwareMonitor) getHostCPUUsage() (float64, error) {
+func (m *DockerAwareMonitor) getHostCPUUsage(ctx context.Context) (float64, error) {
 	// 最初のサンプリング
 	stat1, err := m.readCPUStat()
 	if err != nil {
@@ -104,7 +108,13 @@ func (m *DockerAwareMonitor) getHostCPUUsage() (float64, error) {
 	}
 
 	// 1秒待機
-	time.Sleep(1 * time.Second)
+	timer := time.NewTimer(1 * time.Second)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-timer.C:
+	}
 
 	// 2回目のサンプリング
 	stat2, err := m.readCPUStat()
@@ -4,6 +4,7 @@
 package system
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -113,7 +114,7 @@ func TestDockerAwareMonitor_GetSystemInfo(t *testing.T) {
 				hostSysPath:  "/host/sys",
 			}
 
-			info, err := monitor.GetSystemInfo()
+			info, err := monitor.GetSystemInfo(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetSystemInfo() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -434,7 +435,7 @@ cpu0 505 0 1005 3990 0 0 0 0 0 0
 		}
 	}()
 
-	usage, err := monitor.getHostCPUUsage()
+	usage, err := monitor.getHostCPUUsage(context.Background())
 	if err != nil {
 		t.Errorf("getHostCPUUsage() error = %v", err)
 		return
@@ -494,7 +495,7 @@ cpu0 505 0 1005 3990 0 0 0 0 0 0
 		}
 	}()
 
-	info, err := monitor.getHostSystemInfo()
+	info, err := monitor.getHostSystemInfo(context.Background())
 	if err != nil {
 		t.Errorf("getHostSystemInfo() error = %v", err)
 		return
@@ -687,7 +688,7 @@ cpu0 500 0 1000 3500 0 0 0 0 0 0
 	}
 
 	// ファイルを変更しない（同じ値のまま）
-	usage, err := monitor.getHostCPUUsage()
+	usage, err := monitor.getHostCPUUsage(context.Background())
 	if err != nil {
 		t.Errorf("getHostCPUUsage() error = %v", err)
 		return
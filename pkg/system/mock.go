@@ -1,5 +1,7 @@
 package system
 
+import "context"
+
 // MockMonitor はテスト用のモック実装
 type MockMonitor struct {
 	SystemInfo *SystemInfo
@@ -7,7 +9,7 @@ type MockMonitor struct {
 }
 
 // GetSystemInfo はモックのシステム情報を返す
-func (m *MockMonitor) GetSystemInfo() (*SystemInfo, error) {
+func (m *MockMonitor) GetSystemInfo(_ context.Context) (*SystemInfo, error) {
 	if m.Err != nil {
 		return nil, m.Err
 	}
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"errors"
 	"testing"
 )
@@ -63,7 +64,7 @@ func TestMockMonitor_GetSystemInfo(t *testing.T) {
 				Err:        tt.err,
 			}
 
-			got, err := m.GetSystemInfo()
+			got, err := m.GetSystemInfo(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetSystemInfo() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -101,7 +102,7 @@ func TestMockMonitor_NilReceiver(t *testing.T) {
 
 	var m *MockMonitor
 	// nilレシーバーでパニックすることを確認
-	_, _ = m.GetSystemInfo()
+	_, _ = m.GetSystemInfo(context.Background())
 }
 
 func TestMockMonitor_ConcurrentAccess(t *testing.T) {
@@ -128,7 +129,7 @@ func TestMockMonitor_ConcurrentAccess(t *testing.T) {
 	for i := 0; i < goroutines; i++ {
 		go func() {
 			for j := 0; j < iterations; j++ {
-				info, err := m.GetSystemInfo()
+				info, err := m.GetSystemInfo(context.Background())
 				if err != nil {
 					t.Errorf("Unexpected error: %v", err)
 				}
@@ -163,7 +164,7 @@ func BenchmarkMockMonitor_GetSystemInfo(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := m.GetSystemInfo()
+		_, err := m.GetSystemInfo(context.Background())
 		if err != nil {
 			b.Fatalf("GetSystemInfo() failed: %v", err)
 		}
@@ -178,7 +179,7 @@ func BenchmarkMockMonitor_GetSystemInfo_WithError(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := m.GetSystemInfo()
+		_, err := m.GetSystemInfo(context.Background())
 		if err == nil {
 			b.Fatal("Expected error, got nil")
 		}
@@ -203,7 +204,7 @@ func BenchmarkMockMonitor_GetSystemInfo_Parallel(b *testing.B) {
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			_, err := m.GetSystemInfo()
+			_, err := m.GetSystemInfo(context.Background())
 			if err != nil {
 				b.Errorf("GetSystemInfo() failed: %v", err)
 			}
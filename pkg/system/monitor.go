@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
@@ -17,17 +18,20 @@ func NewDefaultMonitor() *DefaultMonitor {
 }
 
 // GetSystemInfo はシステム情報を取得
-func (m *DefaultMonitor) GetSystemInfo() (*SystemInfo, error) {
+func (m *DefaultMonitor) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
 	info := &SystemInfo{}
 
 	// CPU使用率を取得（1秒間のサンプリング）
-	cpuPercent, err := cpu.Percent(1*time.Second, false)
+	cpuPercent, err := cpu.PercentWithContext(ctx, 1*time.Second, false)
 	if err == nil && len(cpuPercent) > 0 {
 		info.CPUUsagePercent = cpuPercent[0]
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// メモリ情報を取得
-	vmStat, err := mem.VirtualMemory()
+	vmStat, err := mem.VirtualMemoryWithContext(ctx)
 	if err == nil {
 		info.MemoryTotalGB = float64(vmStat.Total) / 1024 / 1024 / 1024
 		info.MemoryUsedGB = float64(vmStat.Used) / 1024 / 1024 / 1024
@@ -35,7 +39,7 @@ func (m *DefaultMonitor) GetSystemInfo() (*SystemInfo, error) {
 	}
 
 	// ディスク情報を取得（ルートパーティション）
-	diskStat, err := disk.Usage("/")
+	diskStat, err := disk.UsageWithContext(ctx, "/")
 	if err == nil {
 		info.DiskTotalGB = float64(diskStat.Total) / 1024 / 1024 / 1024
 		info.DiskFreeGB = float64(diskStat.Free) / 1024 / 1024 / 1024
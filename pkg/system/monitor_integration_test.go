@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -14,7 +15,7 @@ func TestDefaultMonitor_GetSystemInfo_Integration(t *testing.T) {
 	monitor := NewDefaultMonitor()
 
 	start := time.Now()
-	info, err := monitor.GetSystemInfo()
+	info, err := monitor.GetSystemInfo(context.Background())
 	elapsed := time.Since(start)
 
 	// エラーが発生しないこと
@@ -89,7 +90,7 @@ func TestDefaultMonitor_GetSystemInfo_Performance(t *testing.T) {
 
 	for i := 0; i < iterations; i++ {
 		start := time.Now()
-		_, err := monitor.GetSystemInfo()
+		_, err := monitor.GetSystemInfo(context.Background())
 		elapsed := time.Since(start)
 
 		if err != nil {
@@ -125,7 +126,7 @@ func TestDefaultMonitor_GetSystemInfo_MemoryLeak(t *testing.T) {
 	const iterations = 20
 
 	for i := 0; i < iterations; i++ {
-		info, err := monitor.GetSystemInfo()
+		info, err := monitor.GetSystemInfo(context.Background())
 		if err != nil {
 			t.Fatalf("Iteration %d failed: %v", i, err)
 		}
@@ -157,7 +158,7 @@ func TestDefaultMonitor_GetSystemInfo_UnderLoad(t *testing.T) {
 	for g := 0; g < goroutines; g++ {
 		go func(_ int) {
 			for i := 0; i < iterationsPerGoroutine; i++ {
-				info, err := monitor.GetSystemInfo()
+				info, err := monitor.GetSystemInfo(context.Background())
 				if err != nil {
 					results <- err
 					return
@@ -193,7 +194,7 @@ func TestDefaultMonitor_GetSystemInfo_ValueRanges(t *testing.T) {
 	infos := make([]*SystemInfo, samples)
 
 	for i := 0; i < samples; i++ {
-		info, err := monitor.GetSystemInfo()
+		info, err := monitor.GetSystemInfo(context.Background())
 		if err != nil {
 			t.Fatalf("Sample %d failed: %v", i, err)
 		}
@@ -292,7 +293,7 @@ func BenchmarkDefaultMonitor_GetSystemInfo(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := monitor.GetSystemInfo()
+		_, err := monitor.GetSystemInfo(context.Background())
 		if err != nil {
 			b.Fatalf("GetSystemInfo() failed: %v", err)
 		}
@@ -306,7 +307,7 @@ func BenchmarkDefaultMonitor_GetSystemInfo_Parallel(b *testing.B) {
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			_, err := monitor.GetSystemInfo()
+			_, err := monitor.GetSystemInfo(context.Background())
 			if err != nil {
 				b.Errorf("GetSystemInfo() failed: %v", err)
 			}
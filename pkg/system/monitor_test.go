@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"testing"
 )
 
@@ -8,7 +9,7 @@ func TestDefaultMonitor_GetSystemInfo(t *testing.T) {
 	t.Parallel()
 
 	monitor := NewDefaultMonitor()
-	info, err := monitor.GetSystemInfo()
+	info, err := monitor.GetSystemInfo(context.Background())
 
 	if err != nil {
 		t.Fatalf("GetSystemInfo() error = %v", err)
@@ -1,5 +1,7 @@
 package system
 
+import "context"
+
 // SystemInfo はシステム情報を表す構造体
 //
 //nolint:revive // package名とtype名の組み合わせ (system.SystemInfo) は意図的
@@ -15,5 +17,6 @@ type SystemInfo struct {
 
 // Monitor はシステム情報を取得するインターフェース
 type Monitor interface {
-	GetSystemInfo() (*SystemInfo, error)
+	// GetSystemInfo はシステム情報を取得する（CPU使用率のサンプリング中にctxが終了した場合はエラーを返す）
+	GetSystemInfo(ctx context.Context) (*SystemInfo, error)
 }